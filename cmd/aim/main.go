@@ -11,14 +11,24 @@ import (
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/cli"
 	"github.com/slobbe/appimage-manager/internal/infra/appimage"
+	"github.com/slobbe/appimage-manager/internal/infra/autoupdate"
 	"github.com/slobbe/appimage-manager/internal/infra/config"
 	"github.com/slobbe/appimage-manager/internal/infra/desktop"
 	"github.com/slobbe/appimage-manager/internal/infra/download"
+	"github.com/slobbe/appimage-manager/internal/infra/filelock"
 	"github.com/slobbe/appimage-manager/internal/infra/fileutil"
 	"github.com/slobbe/appimage-manager/internal/infra/github"
+	"github.com/slobbe/appimage-manager/internal/infra/hooks"
 	"github.com/slobbe/appimage-manager/internal/infra/icon"
+	"github.com/slobbe/appimage-manager/internal/infra/launcher"
+	"github.com/slobbe/appimage-manager/internal/infra/manifest"
+	"github.com/slobbe/appimage-manager/internal/infra/mime"
+	"github.com/slobbe/appimage-manager/internal/infra/notify"
+	"github.com/slobbe/appimage-manager/internal/infra/sandbox"
 	"github.com/slobbe/appimage-manager/internal/infra/selfupdate"
+	"github.com/slobbe/appimage-manager/internal/infra/shim"
 	"github.com/slobbe/appimage-manager/internal/infra/storage"
+	"github.com/slobbe/appimage-manager/internal/infra/trash"
 	"github.com/slobbe/appimage-manager/internal/infra/xdg"
 )
 
@@ -39,6 +49,19 @@ func main() {
 	}
 
 	storagePath := filepath.Join(xdg.DataDir(dirs), "apps.json")
+	lockDir := filepath.Join(xdg.DataDir(dirs), "locks")
+	repository := storage.NewRepository(storagePath)
+	// Scheduled updates run this binary; without a resolvable path they fall
+	// back to looking aim up on PATH.
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "aim"
+	}
+
+	iconInstaller := icon.NewInstaller(cfg.IconDir)
+	if cfg.RasterizeIcons {
+		iconInstaller.RasterSizes = icon.StandardSizes
+	}
 
 	service, err := app.NewService(app.ServiceDeps{
 		Config:                      cfg,
@@ -46,15 +69,37 @@ func main() {
 		DesktopEntries:              desktop.Discoverer{},
 		Icons:                       icon.Discoverer{},
 		AppImageInstaller:           appimage.NewInstaller(cfg.AppImageDir),
-		IconInstaller:               icon.NewInstaller(cfg.IconDir),
+		AppImageScanner:             appimage.Scanner{},
+		AppImageWatcher:             appimage.NewWatcher(),
+		SandboxProfiles:             sandbox.NewResolver(xdg.SandboxDir(dirs)),
+		Launcher:                    launcher.New(),
+		IconInstaller:               iconInstaller,
+		IconGenerator:               icon.Generator{},
 		DesktopEntryInstaller:       desktop.NewInstaller(cfg.DesktopDir),
+		Commands:                    shim.NewInstaller(cfg.BinDir),
+		MimePackages:                mime.PackageDiscoverer{},
+		MimePackageInstaller:        mime.NewPackageInstaller(xdg.MimeDir(dirs)),
+		MimeAssociations:            mime.NewAssociations(xdg.MimeAppsFile(dirs)),
 		ArtifactRemover:             fileutil.RemoveArtifact,
-		DesktopIntegrationRefresher: desktop.NewRefresher(cfg.DesktopDir, cfg.IconDir),
+		Trash:                       trash.New(xdg.TrashDir(dirs)).Remove,
+		Hooks:                       hooks.NewRunner(os.Stderr),
+		Notifier:                    notify.New("aim"),
+		UpdateScheduler:             autoupdate.NewScheduler(xdg.SystemdUserDir(dirs), executable),
+		DesktopIntegrationRefresher: desktop.NewRefresher(cfg.DesktopDir, cfg.IconDir, xdg.MimeDir(dirs)),
+		ForeignIntegrations:         desktop.NewForeignFinder(cfg.DesktopDir, cfg.IconDir),
+		Manifests:                   manifest.NewLoader(),
+		LockFiles:                   manifest.LockStore{},
+		Snapshots:                   storage.SnapshotStore{},
 		GitHubReleases:              github.NewClient(),
 		Downloads:                   download.Downloader{},
 		SelfUpdater:                 selfupdate.Installer{},
+		AppLocks:                    filelock.NewAppLocker(lockDir),
+		Journal:                     storage.NewJournal(filepath.Join(xdg.DataDir(dirs), "journal")),
+		History:                     storage.NewHistoryLog(filepath.Join(xdg.DataDir(dirs), "history.jsonl")),
+		Undo:                        storage.NewUndoArchive(filepath.Join(xdg.DataDir(dirs), "undo")),
 		CurrentVersion:              version,
-		Apps:                        storage.NewRepository(storagePath),
+		DatabaseBackups:             repository,
+		Apps:                        repository,
 	})
 	if err != nil {
 		exitWithError(err)
@@ -18,6 +18,7 @@ const (
 	ActivityKindCheckingUpdates ActivityKind = "checking-updates"
 	ActivityKindWaiting         ActivityKind = "waiting"
 	ActivityKindDownloading     ActivityKind = "downloading"
+	ActivityKindRepairing       ActivityKind = "repairing"
 )
 
 const (
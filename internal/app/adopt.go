@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) Scan(ctx context.Context, req ScanRequest) (ScanResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ScanResult{}, err
+	}
+	if len(req.Dirs) == 0 {
+		return ScanResult{}, errors.New("scan directory is required")
+	}
+	if s.appImageScanner == nil {
+		return ScanResult{}, errors.New("appimage scanner is required")
+	}
+
+	found, err := s.appImageScanner.Scan(ctx, req.Dirs)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	managed, err := s.managedAppImagePaths(ctx)
+	if err != nil {
+		return ScanResult{}, err
+	}
+
+	items := make([]ScanItem, 0, len(found))
+	for _, appImage := range found {
+		appID, ok := managed[filepath.Clean(appImage.Path)]
+		items = append(items, ScanItem{
+			Path:      appImage.Path,
+			SizeBytes: appImage.SizeBytes,
+			Managed:   ok,
+			AppID:     appID,
+		})
+	}
+
+	return ScanResult{Items: items}, nil
+}
+
+// managedAppImagePaths maps installed AppImage paths and the local files they
+// were integrated from to their app IDs.
+func (s *service) managedAppImagePaths(ctx context.Context) (map[string]string, error) {
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]string, len(apps))
+	for _, installedApp := range apps {
+		if installedApp.Source.Kind == domain.SourceKindLocal && installedApp.Source.LocalFile.Path != "" {
+			paths[filepath.Clean(installedApp.Source.LocalFile.Path)] = installedApp.ID
+		}
+	}
+	for _, installedApp := range apps {
+		if installedApp.AppImagePath != "" {
+			paths[filepath.Clean(installedApp.AppImagePath)] = installedApp.ID
+		}
+	}
+
+	return paths, nil
+}
+
+func (s *service) Adopt(ctx context.Context, req AdoptRequest) (AdoptResult, error) {
+	if err := ctx.Err(); err != nil {
+		return AdoptResult{}, err
+	}
+
+	path := strings.TrimSpace(req.Path)
+	if path == "" {
+		return AdoptResult{}, errors.New("appimage path is required")
+	}
+	path = filepath.Clean(path)
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	return s.adopt(ctx, path, req.Move, activity)
+}
+
+func (s *service) adopt(ctx context.Context, path string, move bool, activity ActivityReporter) (AdoptResult, error) {
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return AdoptResult{}, err
+	}
+	for _, installedApp := range apps {
+		if installedApp.AppImagePath != "" && filepath.Clean(installedApp.AppImagePath) == path {
+			return AdoptResult{}, fmt.Errorf("appimage %q is already managed as %s", path, installedApp.ID)
+		}
+	}
+
+	result, err := s.addLocalWithOptions(ctx, AddRequest{Path: path, Activity: activity}, activity, addLocalOptions{
+		source:          domain.NewLocalSource(path, time.Now()),
+		fallbackVersion: filepath.Base(path),
+		saveApp:         true,
+		inPlace:         !move,
+	})
+	if err != nil {
+		return AdoptResult{}, err
+	}
+	if !move {
+		return AdoptResult{App: result.App}, nil
+	}
+
+	if err := s.artifactRemover(ctx, path); err != nil {
+		return AdoptResult{}, fmt.Errorf("adopted %s but failed to remove original appimage: %w", result.App.ID, err)
+	}
+	return AdoptResult{App: result.App, Moved: true}, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceScanMarksManagedAppImages(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.listApps = []domain.App{installed}
+	deps.AppImageScanner = &fakeAppImageScanner{found: []ScannedAppImage{
+		{Path: "/downloads/example.AppImage", SizeBytes: 10},
+		{Path: "/library/example-app.AppImage", SizeBytes: 10},
+		{Path: "/opt/other/Other.AppImage", SizeBytes: 20},
+	}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Scan(context.Background(), ScanRequest{Dirs: []string{"/downloads", "/library", "/opt"}})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	want := []ScanItem{
+		{Path: "/downloads/example.AppImage", SizeBytes: 10, Managed: true, AppID: installed.ID},
+		{Path: "/library/example-app.AppImage", SizeBytes: 10, Managed: true, AppID: installed.ID},
+		{Path: "/opt/other/Other.AppImage", SizeBytes: 20},
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("Items = %#v, want %#v", result.Items, want)
+	}
+	for i := range want {
+		if result.Items[i] != want[i] {
+			t.Fatalf("Items[%d] = %#v, want %#v", i, result.Items[i], want[i])
+		}
+	}
+}
+
+func TestServiceScanRequiresScanner(t *testing.T) {
+	t.Parallel()
+
+	service, err := NewService(integrationTestDeps().ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if _, err := service.Scan(context.Background(), ScanRequest{Dirs: []string{"/opt"}}); err == nil {
+		t.Fatal("Scan() error = nil, want missing scanner error")
+	}
+}
+
+func TestServiceAdoptIntegratesInPlaceWithEmbeddedGitHubSource(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.appImages.updateInfo = "gh-releases-zsync|owner|repo|latest|Example-*x86_64.AppImage.zsync"
+	path := testAppImagePath(t, "Example.AppImage")
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Adopt(context.Background(), AdoptRequest{Path: path})
+	if err != nil {
+		t.Fatalf("Adopt() error = %v", err)
+	}
+
+	if result.Moved {
+		t.Fatal("Moved = true, want false")
+	}
+	if deps.appImageInstaller.called {
+		t.Fatal("appimage installer Install was called, want in-place adoption")
+	}
+	if got, want := deps.appImageInstaller.adoptedPath, path; got != want {
+		t.Fatalf("adopted path = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.AppImagePath, path; got != want {
+		t.Fatalf("saved AppImagePath = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.UpdateSource.Repo, "owner/repo"; got != want {
+		t.Fatalf("saved UpdateSource.Repo = %q, want %q", got, want)
+	}
+	if !strings.Contains(string(deps.desktopEntryInstaller.content), "Exec="+path+" %U") {
+		t.Fatalf("desktop content = %q, want in-place Exec", deps.desktopEntryInstaller.content)
+	}
+	if len(deps.artifactRemover.paths) != 0 {
+		t.Fatalf("removed paths = %#v, want none", deps.artifactRemover.paths)
+	}
+}
+
+func TestServiceAdoptMoveInstallsAndRemovesOriginal(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	path := testAppImagePath(t, "Example.AppImage")
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Adopt(context.Background(), AdoptRequest{Path: path, Move: true})
+	if err != nil {
+		t.Fatalf("Adopt() error = %v", err)
+	}
+
+	if !result.Moved {
+		t.Fatal("Moved = false, want true")
+	}
+	if got, want := deps.saved.App.AppImagePath, deps.appImageInstaller.path; got != want {
+		t.Fatalf("saved AppImagePath = %q, want %q", got, want)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{path})
+}
+
+func TestServiceAdoptRejectsManagedAppImage(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.listApps = []domain.App{installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Adopt(context.Background(), AdoptRequest{Path: installed.AppImagePath}); err == nil {
+		t.Fatal("Adopt() error = nil, want already managed error")
+	}
+	if deps.appImages.appImagePath != "" {
+		t.Fatal("AppImage was extracted, want early rejection")
+	}
+}
+
+type fakeAppImageScanner struct {
+	dirs  []string
+	found []ScannedAppImage
+	err   error
+}
+
+func (f *fakeAppImageScanner) Scan(ctx context.Context, dirs []string) ([]ScannedAppImage, error) {
+	f.dirs = dirs
+	return f.found, f.err
+}
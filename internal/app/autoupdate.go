@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (s *service) EnableAutoUpdate(ctx context.Context, req EnableAutoUpdateRequest) (AutoUpdateStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return AutoUpdateStatus{}, err
+	}
+	if s.updateScheduler == nil {
+		return AutoUpdateStatus{}, errors.New("update scheduler is required")
+	}
+
+	interval, err := parseUpdateInterval(req.Interval)
+	if err != nil {
+		return AutoUpdateStatus{}, err
+	}
+
+	scheduled, err := s.updateScheduler.Enable(ctx, UpdateSchedule{Interval: interval, CheckOnly: req.CheckOnly})
+	if err != nil {
+		return AutoUpdateStatus{}, err
+	}
+
+	return s.autoUpdateStatus(ctx, scheduled), nil
+}
+
+func (s *service) DisableAutoUpdate(ctx context.Context) (DisableAutoUpdateResult, error) {
+	if err := ctx.Err(); err != nil {
+		return DisableAutoUpdateResult{}, err
+	}
+	if s.updateScheduler == nil {
+		return DisableAutoUpdateResult{}, errors.New("update scheduler is required")
+	}
+
+	disabled, err := s.updateScheduler.Disable(ctx)
+	if err != nil {
+		return DisableAutoUpdateResult{}, err
+	}
+
+	return DisableAutoUpdateResult{Disabled: disabled}, nil
+}
+
+func (s *service) AutoUpdateStatus(ctx context.Context) (AutoUpdateStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return AutoUpdateStatus{}, err
+	}
+	if s.updateScheduler == nil {
+		return AutoUpdateStatus{}, errors.New("update scheduler is required")
+	}
+
+	scheduled, err := s.updateScheduler.Status(ctx)
+	if err != nil {
+		return AutoUpdateStatus{}, err
+	}
+
+	return s.autoUpdateStatus(ctx, scheduled), nil
+}
+
+// autoUpdateStatus adds the most recent update from the history log to
+// scheduled. The history is only informational here, so a log that cannot be
+// read leaves LastUpdate empty.
+func (s *service) autoUpdateStatus(ctx context.Context, scheduled ScheduledUpdates) AutoUpdateStatus {
+	status := AutoUpdateStatus{
+		Enabled: scheduled.Enabled,
+		LastRun: scheduled.LastRun,
+	}
+	if scheduled.Enabled {
+		status.Backend = scheduled.Backend
+		status.Interval = scheduled.Schedule.Interval
+		status.CheckOnly = scheduled.Schedule.CheckOnly
+	}
+
+	if s.history == nil {
+		return status
+	}
+	events, err := s.history.Read(ctx)
+	if err != nil {
+		return status
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Operation == HistoryOperationUpdate {
+			event := events[i]
+			status.LastUpdate = &event
+			break
+		}
+	}
+
+	return status
+}
+
+func parseUpdateInterval(value string) (UpdateInterval, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return UpdateIntervalDaily, nil
+	}
+
+	switch interval := UpdateInterval(value); interval {
+	case UpdateIntervalHourly, UpdateIntervalDaily, UpdateIntervalWeekly, UpdateIntervalMonthly:
+		return interval, nil
+	}
+
+	return "", fmt.Errorf("unsupported update interval %q: use hourly, daily, weekly, or monthly", value)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// UpdateScheduler runs aim update unattended on a schedule.
+//
+// Implementations belong in infrastructure. Enable replaces any existing
+// schedule, Disable reports whether there was one to remove, and Status
+// returns the current schedule with its last run when the scheduler records
+// one.
+type UpdateScheduler interface {
+	Enable(ctx context.Context, schedule UpdateSchedule) (ScheduledUpdates, error)
+	Disable(ctx context.Context) (bool, error)
+	Status(ctx context.Context) (ScheduledUpdates, error)
+}
+
+type UpdateInterval string
+
+const (
+	UpdateIntervalHourly  UpdateInterval = "hourly"
+	UpdateIntervalDaily   UpdateInterval = "daily"
+	UpdateIntervalWeekly  UpdateInterval = "weekly"
+	UpdateIntervalMonthly UpdateInterval = "monthly"
+)
+
+// UpdateSchedule describes an unattended update run. CheckOnly runs only
+// report available updates with a notification instead of applying them.
+type UpdateSchedule struct {
+	Interval  UpdateInterval
+	CheckOnly bool
+}
+
+// ScheduledUpdates is the state of the update schedule. Backend names the
+// scheduler that runs it, such as "systemd" or "cron".
+type ScheduledUpdates struct {
+	Enabled  bool
+	Backend  string
+	Schedule UpdateSchedule
+	LastRun  *ScheduledRun
+}
+
+// ScheduledRun is the outcome of the last scheduled run.
+type ScheduledRun struct {
+	Time      time.Time `json:"time"`
+	Succeeded bool      `json:"succeeded"`
+	Result    string    `json:"result"`
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceEnableAutoUpdateDefaultsToDaily(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	scheduler := &fakeUpdateScheduler{}
+	deps.UpdateScheduler = scheduler
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	status, err := service.EnableAutoUpdate(context.Background(), EnableAutoUpdateRequest{CheckOnly: true})
+	if err != nil {
+		t.Fatalf("EnableAutoUpdate() error = %v", err)
+	}
+
+	if scheduler.enabled != (UpdateSchedule{Interval: UpdateIntervalDaily, CheckOnly: true}) {
+		t.Fatalf("scheduled %#v, want daily check-only", scheduler.enabled)
+	}
+	if !status.Enabled || status.Backend != "systemd" || status.Interval != UpdateIntervalDaily || !status.CheckOnly {
+		t.Fatalf("EnableAutoUpdate() = %#v", status)
+	}
+}
+
+func TestServiceEnableAutoUpdateRejectsUnknownInterval(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	scheduler := &fakeUpdateScheduler{}
+	deps.UpdateScheduler = scheduler
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.EnableAutoUpdate(context.Background(), EnableAutoUpdateRequest{Interval: "fortnightly"})
+	if err == nil || !strings.Contains(err.Error(), `unsupported update interval "fortnightly"`) {
+		t.Fatalf("EnableAutoUpdate() error = %v, want unsupported interval", err)
+	}
+	if scheduler.enabled.Interval != "" {
+		t.Fatalf("scheduled %#v, want nothing", scheduler.enabled)
+	}
+}
+
+func TestServiceAutoUpdateStatusIncludesLastRunAndUpdate(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	lastRun := &ScheduledRun{Time: time.Date(2026, 5, 3, 3, 0, 0, 0, time.UTC), Result: "exit-code"}
+	deps.UpdateScheduler = &fakeUpdateScheduler{status: ScheduledUpdates{
+		Enabled:  true,
+		Backend:  "cron",
+		Schedule: UpdateSchedule{Interval: UpdateIntervalWeekly},
+		LastRun:  lastRun,
+	}}
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	deps.History = &fakeHistoryLog{events: []HistoryEvent{
+		{Time: start, Operation: HistoryOperationUpdate, AppID: "first"},
+		{Time: start.Add(time.Hour), Operation: HistoryOperationUpdate, AppID: "second", Result: HistoryOutcomeFailed},
+		{Time: start.Add(2 * time.Hour), Operation: HistoryOperationRemove, AppID: "first"},
+	}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	status, err := service.AutoUpdateStatus(context.Background())
+	if err != nil {
+		t.Fatalf("AutoUpdateStatus() error = %v", err)
+	}
+
+	if !status.Enabled || status.Backend != "cron" || status.Interval != UpdateIntervalWeekly || status.CheckOnly {
+		t.Fatalf("AutoUpdateStatus() = %#v", status)
+	}
+	if status.LastRun != lastRun {
+		t.Fatalf("LastRun = %#v, want %#v", status.LastRun, lastRun)
+	}
+	if status.LastUpdate == nil || status.LastUpdate.AppID != "second" || status.LastUpdate.Result != HistoryOutcomeFailed {
+		t.Fatalf("LastUpdate = %#v, want failed update of second", status.LastUpdate)
+	}
+}
+
+func TestServiceAutoUpdateRequiresScheduler(t *testing.T) {
+	t.Parallel()
+
+	service, err := NewService(integrationTestDeps().ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.DisableAutoUpdate(context.Background()); err == nil || !strings.Contains(err.Error(), "update scheduler is required") {
+		t.Fatalf("DisableAutoUpdate() error = %v, want missing scheduler", err)
+	}
+}
+
+type fakeUpdateScheduler struct {
+	enabled  UpdateSchedule
+	disabled bool
+	status   ScheduledUpdates
+}
+
+func (f *fakeUpdateScheduler) Enable(ctx context.Context, schedule UpdateSchedule) (ScheduledUpdates, error) {
+	f.enabled = schedule
+	return ScheduledUpdates{Enabled: true, Backend: "systemd", Schedule: schedule}, nil
+}
+
+func (f *fakeUpdateScheduler) Disable(ctx context.Context) (bool, error) {
+	f.disabled = true
+	return f.status.Enabled, nil
+}
+
+func (f *fakeUpdateScheduler) Status(ctx context.Context) (ScheduledUpdates, error) {
+	return f.status, nil
+}
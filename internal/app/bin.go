@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) SetBin(ctx context.Context, req SetBinRequest) (SetBinResult, error) {
+	if err := ctx.Err(); err != nil {
+		return SetBinResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return SetBinResult{}, errors.New("app id is required")
+	}
+	name := strings.TrimSpace(req.Name)
+	if req.Off && name != "" {
+		return SetBinResult{}, errors.New("provide either a command name or --off, not both")
+	}
+	if name != "" && !validBinName(name) {
+		return SetBinResult{}, fmt.Errorf("invalid command name %q", name)
+	}
+
+	installedApp, err := s.apps.Find(ctx, id)
+	if err != nil {
+		return SetBinResult{}, err
+	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return SetBinResult{}, err
+	}
+	defer unlock()
+
+	updatedApp, err := s.setBin(ctx, installedApp, name, req.Off)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationSetBin,
+		AppID:      installedApp.ID,
+		OldVersion: installedApp.Version.String(),
+		NewVersion: installedApp.Version.String(),
+		Source:     firstNonEmpty(updatedApp.BinPath, "none"),
+	}, err)
+	if err != nil {
+		return SetBinResult{}, err
+	}
+
+	return SetBinResult{ID: installedApp.ID, Path: updatedApp.BinPath}, nil
+}
+
+func (s *service) setBin(ctx context.Context, installedApp domain.App, name string, off bool) (domain.App, error) {
+	updatedApp := installedApp
+	if off {
+		if installedApp.BinPath == "" {
+			return installedApp, nil
+		}
+		updatedApp.BinPath = ""
+		if err := s.apps.Save(ctx, updatedApp); err != nil {
+			return domain.App{}, err
+		}
+		if err := s.artifactRemover(ctx, installedApp.BinPath); err != nil {
+			return domain.App{}, fmt.Errorf("stopped tracking the command of %s but failed to remove it: %w", installedApp.ID, err)
+		}
+		return updatedApp, nil
+	}
+
+	if name == "" {
+		name = binName(installedApp)
+	}
+	updatedApp, err := s.installBin(ctx, installedApp, name)
+	if err != nil {
+		return domain.App{}, err
+	}
+	if updatedApp.BinPath == installedApp.BinPath {
+		return updatedApp, nil
+	}
+	if err := s.apps.Save(ctx, updatedApp); err != nil {
+		_ = s.artifactRemover(ctx, updatedApp.BinPath)
+		return domain.App{}, err
+	}
+	if err := s.removeReplacedArtifacts(ctx, installedApp, updatedApp); err != nil {
+		return domain.App{}, fmt.Errorf("installed %s but failed to remove the previous command: %w", updatedApp.BinPath, err)
+	}
+	return updatedApp, nil
+}
+
+// installBin writes the command called name that launches installedApp as
+// configured, and returns the app with the command's path.
+func (s *service) installBin(ctx context.Context, installedApp domain.App, name string) (domain.App, error) {
+	if s.commands == nil {
+		return domain.App{}, errors.New("command installer is required")
+	}
+	if err := s.checkBinNameFree(ctx, installedApp, name); err != nil {
+		return domain.App{}, err
+	}
+
+	path, err := s.commands.Install(ctx, name, installedApp.DesktopCommand())
+	if err != nil {
+		return domain.App{}, err
+	}
+	installedApp.BinPath = path
+	return installedApp, nil
+}
+
+// checkBinNameFree fails when another app's command is called name, since the
+// installer would replace it and leave that app's record pointing at a command
+// that no longer runs it. A record sharing installedApp's command is the same
+// app under its previous ID while set-id moves it.
+func (s *service) checkBinNameFree(ctx context.Context, installedApp domain.App, name string) error {
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range apps {
+		if other.ID == installedApp.ID || other.BinPath == "" || other.BinPath == installedApp.BinPath {
+			continue
+		}
+		if filepath.Base(other.BinPath) == name {
+			return fmt.Errorf("command %q is already used by %s; choose another name", name, other.ID)
+		}
+	}
+	return nil
+}
+
+// refreshBin rewrites the command of installedApp, if it has one, after its
+// AppImage path or launch settings changed. The command keeps its name but is
+// written to the configured bin directory, so the returned path can differ.
+func (s *service) refreshBin(ctx context.Context, installedApp domain.App) (domain.App, error) {
+	if installedApp.BinPath == "" {
+		return installedApp, nil
+	}
+	return s.installBin(ctx, installedApp, filepath.Base(installedApp.BinPath))
+}
+
+// binName is the current name of the app's command, or its ID.
+func binName(installedApp domain.App) string {
+	if installedApp.BinPath != "" {
+		return filepath.Base(installedApp.BinPath)
+	}
+	return installedApp.ID
+}
+
+func validBinName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\x00")
+}
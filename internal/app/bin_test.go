@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddInstallsCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	commands := &fakeCommandInstaller{dir: "/home/user/.local/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage"), Bin: true})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if got, want := result.App.BinPath, "/home/user/.local/bin/example-app"; got != want || deps.saved.App.BinPath != want {
+		t.Fatalf("BinPath = %q, saved %q, want %q", got, deps.saved.App.BinPath, want)
+	}
+	if want := []string{"/library/example-app.AppImage"}; !reflect.DeepEqual(commands.command, want) {
+		t.Fatalf("command = %q, want %q", commands.command, want)
+	}
+}
+
+func TestServiceAddRemovesCommandWhenSaveFails(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Commands = &fakeCommandInstaller{dir: "/bin"}
+	deps.apps.err = errors.New("disk full")
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage"), Bin: true, BinName: "example"}); err == nil {
+		t.Fatal("Add() error = nil, want save failure")
+	}
+	if !strings.Contains(strings.Join(deps.artifactRemover.paths, " "), "/bin/example") {
+		t.Fatalf("removed paths = %q, want command removed", deps.artifactRemover.paths)
+	}
+}
+
+func TestServiceAddRejectsBinNameWithoutBin(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, req := range []AddRequest{
+		{Path: "/downloads/example.AppImage", BinName: "example"},
+		{Path: "/downloads/example.AppImage", Bin: true, BinName: "../example"},
+	} {
+		if _, err := service.Add(context.Background(), req); err == nil {
+			t.Fatalf("Add(%#v) error = nil, want invalid command name", req)
+		}
+	}
+}
+
+func TestServiceSetBinRenamesCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.BinPath = "/bin/example-app"
+	installed.Launch = domain.LaunchOptions{Args: []string{"--no-sandbox"}}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	commands := &fakeCommandInstaller{dir: "/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetBin(context.Background(), SetBinRequest{ID: installed.ID, Name: "example"})
+	if err != nil {
+		t.Fatalf("SetBin() error = %v", err)
+	}
+
+	if result.Path != "/bin/example" || deps.saved.App.BinPath != "/bin/example" {
+		t.Fatalf("result = %#v, saved %q, want /bin/example", result, deps.saved.App.BinPath)
+	}
+	if want := []string{installed.AppImagePath, "--no-sandbox"}; !reflect.DeepEqual(commands.command, want) {
+		t.Fatalf("command = %q, want %q", commands.command, want)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/bin/example-app"})
+}
+
+func TestServiceSetBinRejectsCommandOfAnotherApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	other := testInstalledApp(t)
+	other.ID = "other-app"
+	other.BinPath = "/bin/example"
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed, other.ID: other}
+	deps.apps.listApps = []domain.App{installed, other}
+	commands := &fakeCommandInstaller{dir: "/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.SetBin(context.Background(), SetBinRequest{ID: installed.ID, Name: "example"})
+	if err == nil || !strings.Contains(err.Error(), "already used by other-app") {
+		t.Fatalf("SetBin() error = %v, want command used by other-app", err)
+	}
+	if commands.calls != 0 || deps.saved.App.ID != "" {
+		t.Fatalf("installed %d commands and saved %q, want other-app's command untouched", commands.calls, deps.saved.App.ID)
+	}
+}
+
+func TestServiceAddRejectsCommandOfAnotherApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	other := testInstalledApp(t)
+	other.ID = "other-app"
+	other.BinPath = "/bin/example"
+	deps.apps.listApps = []domain.App{other}
+	commands := &fakeCommandInstaller{dir: "/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage"), Bin: true, BinName: "example"})
+	if err == nil || !strings.Contains(err.Error(), "already used by other-app") {
+		t.Fatalf("Add() error = %v, want command used by other-app", err)
+	}
+	if commands.calls != 0 {
+		t.Fatalf("installed %d commands, want other-app's command untouched", commands.calls)
+	}
+}
+
+func TestServiceSetBinOffRemovesCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.BinPath = "/bin/example-app"
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetBin(context.Background(), SetBinRequest{ID: installed.ID, Off: true})
+	if err != nil {
+		t.Fatalf("SetBin() error = %v", err)
+	}
+
+	if result.Path != "" || deps.saved.App.BinPath != "" || deps.saved.App.ID != installed.ID {
+		t.Fatalf("result = %#v, saved %#v, want command dropped", result, deps.saved.App)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/bin/example-app"})
+}
+
+func TestServiceRemoveRemovesCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.BinPath = "/bin/example-app"
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{installed.BinPath, installed.DesktopEntryPath, installed.IconPath, installed.AppImagePath})
+}
+
+func TestServiceSetIDRewritesCommandForNewAppImage(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.BinPath = "/bin/example"
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.apps.listApps = []domain.App{installed}
+	deps.appImageInstaller.path = "/library/custom-id.AppImage"
+	commands := &fakeCommandInstaller{dir: "/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetID(context.Background(), SetIDRequest{CurrentID: installed.ID, NewID: "custom-id"})
+	if err != nil {
+		t.Fatalf("SetID() error = %v", err)
+	}
+
+	if result.App.BinPath != "/bin/example" || commands.name != "example" {
+		t.Fatalf("BinPath = %q, installed name %q, want /bin/example kept", result.App.BinPath, commands.name)
+	}
+	if want := []string{"/library/custom-id.AppImage"}; !reflect.DeepEqual(commands.command, want) {
+		t.Fatalf("command = %q, want %q", commands.command, want)
+	}
+	for _, path := range deps.artifactRemover.paths {
+		if path == installed.BinPath {
+			t.Fatalf("removed paths = %q, want command kept", deps.artifactRemover.paths)
+		}
+	}
+}
+
+func TestServiceUpdateRewritesCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.BinPath = "/bin/example-app"
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	commands := &fakeCommandInstaller{dir: "/bin"}
+	deps.Commands = commands
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if deps.saved.App.BinPath != installed.BinPath || commands.calls != 1 {
+		t.Fatalf("saved BinPath = %q after %d installs, want %q rewritten once", deps.saved.App.BinPath, commands.calls, installed.BinPath)
+	}
+	if want := []string{"/library/example-app.AppImage"}; !reflect.DeepEqual(commands.command, want) {
+		t.Fatalf("command = %q, want %q", commands.command, want)
+	}
+}
+
+type fakeCommandInstaller struct {
+	dir     string
+	name    string
+	command []string
+	calls   int
+	err     error
+}
+
+func (f *fakeCommandInstaller) Install(ctx context.Context, name string, command []string) (string, error) {
+	f.calls++
+	f.name = name
+	f.command = command
+	if f.err != nil {
+		return "", f.err
+	}
+	return filepath.Join(f.dir, name), nil
+}
@@ -1,8 +1,29 @@
 package app
 
+import "time"
+
 type Config struct {
-	ConfigFile  string
-	AppImageDir string
-	DesktopDir  string
-	IconDir     string
+	ConfigFile   string
+	ManifestFile string
+	AppImageDir  string
+	DesktopDir   string
+	IconDir      string
+	// BinDir holds the commands of apps added with --bin.
+	BinDir string
+	// DownloadDir is the directory aim watch watches when no directory is
+	// given.
+	DownloadDir string
+	Hooks       Hooks
+	// TrashRemovedApps moves the AppImages of removed apps to the trash
+	// instead of deleting them.
+	TrashRemovedApps bool
+	// RasterizeIcons installs icons at the standard hicolor sizes below the
+	// largest bundled one when the AppImage does not ship them.
+	RasterizeIcons bool
+	// Locale picks the translations of app names and descriptions that
+	// list and info show, such as "de_DE.UTF-8".
+	Locale string
+	// UndoRetention is how long replaced and removed artifacts are kept for
+	// aim undo. Zero disables undo.
+	UndoRetention time.Duration
 }
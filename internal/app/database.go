@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+func (s *service) BackupDatabase(ctx context.Context, req BackupDatabaseRequest) (BackupDatabaseResult, error) {
+	if err := ctx.Err(); err != nil {
+		return BackupDatabaseResult{}, err
+	}
+	if s.databaseBackups == nil {
+		return BackupDatabaseResult{}, errors.New("database backups are required")
+	}
+
+	backup, err := s.databaseBackups.Backup(ctx, strings.TrimSpace(req.Path))
+	if err != nil {
+		return BackupDatabaseResult{}, err
+	}
+	return BackupDatabaseResult{Backup: backup}, nil
+}
+
+func (s *service) RestoreDatabase(ctx context.Context, req RestoreDatabaseRequest) (RestoreDatabaseResult, error) {
+	if err := ctx.Err(); err != nil {
+		return RestoreDatabaseResult{}, err
+	}
+	if s.databaseBackups == nil {
+		return RestoreDatabaseResult{}, errors.New("database backups are required")
+	}
+	path := strings.TrimSpace(req.Path)
+	if path == "" {
+		return RestoreDatabaseResult{}, errors.New("backup path is required")
+	}
+
+	backup, err := s.databaseBackups.Inspect(ctx, path)
+	if err != nil {
+		return RestoreDatabaseResult{}, err
+	}
+	if req.Confirmation != nil {
+		confirmed, err := req.Confirmation.ConfirmRestore(ctx, backup)
+		if err != nil {
+			return RestoreDatabaseResult{}, err
+		}
+		if !confirmed {
+			return RestoreDatabaseResult{Applied: false, Restored: backup}, nil
+		}
+	}
+
+	previous, err := s.databaseBackups.Restore(ctx, path)
+	if err != nil {
+		return RestoreDatabaseResult{}, err
+	}
+	return RestoreDatabaseResult{Applied: true, Restored: backup, Previous: previous}, nil
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// DatabaseBackups copies the app database to and from backup files.
+//
+// Implementations belong in infrastructure alongside the AppRepository. Backup
+// writes to a timestamped file next to the database when path is empty.
+// Inspect validates a backup without changing anything. Restore replaces the
+// database with a backup and returns the backup it made of the replaced
+// database, if there was one.
+type DatabaseBackups interface {
+	Backup(ctx context.Context, path string) (DatabaseBackup, error)
+	Inspect(ctx context.Context, path string) (DatabaseBackup, error)
+	Restore(ctx context.Context, path string) (DatabaseBackup, error)
+}
+
+// DatabaseBackup describes an app database file.
+type DatabaseBackup struct {
+	Path          string    `json:"path"`
+	SchemaVersion int       `json:"schema_version"`
+	Apps          int       `json:"apps"`
+	ModifiedAt    time.Time `json:"modified_at"`
+}
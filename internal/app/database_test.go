@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"testing"
+)
+
+func TestServiceRestoreDatabaseAsksBeforeReplacing(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	backups := &fakeDatabaseBackups{inspected: DatabaseBackup{Path: "/backups/apps.json", SchemaVersion: 2, Apps: 3}}
+	deps.DatabaseBackups = backups
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	confirmation := &fakeDatabaseRestoreConfirmation{}
+	result, err := service.RestoreDatabase(context.Background(), RestoreDatabaseRequest{Path: "/backups/apps.json", Confirmation: confirmation})
+	if err != nil {
+		t.Fatalf("RestoreDatabase() error = %v", err)
+	}
+	if result.Applied || backups.restored != "" {
+		t.Fatalf("result = %#v, restored %q; want nothing restored without confirmation", result, backups.restored)
+	}
+	if confirmation.backup.Apps != 3 {
+		t.Fatalf("confirmed backup = %#v, want inspected backup", confirmation.backup)
+	}
+
+	confirmation.confirmed = true
+	result, err = service.RestoreDatabase(context.Background(), RestoreDatabaseRequest{Path: "/backups/apps.json", Confirmation: confirmation})
+	if err != nil {
+		t.Fatalf("RestoreDatabase() error = %v", err)
+	}
+	if !result.Applied || backups.restored != "/backups/apps.json" || result.Previous.Path != "/data/backups/previous.json" {
+		t.Fatalf("result = %#v, restored %q; want restore with previous backup", result, backups.restored)
+	}
+}
+
+func TestServiceBackupDatabase(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	backups := &fakeDatabaseBackups{}
+	deps.DatabaseBackups = backups
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.BackupDatabase(context.Background(), BackupDatabaseRequest{Path: " /tmp/apps.json "})
+	if err != nil {
+		t.Fatalf("BackupDatabase() error = %v", err)
+	}
+	if got, want := result.Backup.Path, "/tmp/apps.json"; got != want {
+		t.Fatalf("Backup.Path = %q, want %q", got, want)
+	}
+}
+
+type fakeDatabaseBackups struct {
+	inspected DatabaseBackup
+	restored  string
+}
+
+func (f *fakeDatabaseBackups) Backup(ctx context.Context, path string) (DatabaseBackup, error) {
+	return DatabaseBackup{Path: path, SchemaVersion: 2}, nil
+}
+
+func (f *fakeDatabaseBackups) Inspect(ctx context.Context, path string) (DatabaseBackup, error) {
+	return f.inspected, nil
+}
+
+func (f *fakeDatabaseBackups) Restore(ctx context.Context, path string) (DatabaseBackup, error) {
+	f.restored = path
+	return DatabaseBackup{Path: "/data/backups/previous.json"}, nil
+}
+
+type fakeDatabaseRestoreConfirmation struct {
+	confirmed bool
+	backup    DatabaseBackup
+}
+
+func (f *fakeDatabaseRestoreConfirmation) ConfirmRestore(ctx context.Context, backup DatabaseBackup) (bool, error) {
+	f.backup = backup
+	return f.confirmed, nil
+}
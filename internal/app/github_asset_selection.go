@@ -38,6 +38,17 @@ func selectGitHubAppImageAssetMatchingPattern(release GitHubRelease, pattern str
 	return candidates[0], nil
 }
 
+func selectGitHubAssetNamed(release GitHubRelease, name string) (GitHubReleaseAsset, error) {
+	name = strings.TrimSpace(name)
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset, nil
+		}
+	}
+
+	return GitHubReleaseAsset{}, fmt.Errorf("release %s has no asset named %q", releaseLabel(release), name)
+}
+
 func selectGitHubAppImageAssetForArch(release GitHubRelease, goarch string) (GitHubReleaseAsset, error) {
 	candidates := appImageAssetCandidates(release.Assets)
 	if len(candidates) == 0 {
@@ -29,6 +29,8 @@ type GitHubReleaseAsset struct {
 	DownloadURL string
 	ContentType string
 	SizeBytes   int64
+	// SHA256 is the hex digest published for the asset, if any.
+	SHA256 string
 }
 
 // AssetDownloader downloads an external asset to a local destination path.
@@ -45,12 +47,15 @@ type DownloadSource struct {
 	FileName string
 	// SizeBytes is the expected byte count when > 0 and should be enforced by download adapters; 0 means unknown.
 	SizeBytes int64
+	// SHA256 is the expected hex digest when set and should be enforced by download adapters; empty means unchecked.
+	SHA256 string
 }
 
 // DownloadedFile describes a completed download.
 type DownloadedFile struct {
 	Path      string
 	SizeBytes int64
+	SHA256    string
 }
 
 // DownloadProgress is an app-defined progress sink for byte downloads.
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) History(ctx context.Context, req HistoryRequest) (HistoryResult, error) {
+	if err := ctx.Err(); err != nil {
+		return HistoryResult{}, err
+	}
+	if s.history == nil {
+		return HistoryResult{}, errors.New("history log is required")
+	}
+
+	events, err := s.history.Read(ctx)
+	if err != nil {
+		return HistoryResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	matched := make([]HistoryEvent, 0, len(events))
+	for _, event := range events {
+		if id != "" && event.AppID != id && event.PreviousID != id {
+			continue
+		}
+		if !req.Since.IsZero() && event.Time.Before(req.Since) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	return HistoryResult{Events: matched}, nil
+}
+
+// recordHistory appends event with the outcome of err to the history log.
+// The log is an audit trail, so failing to write it never fails the operation
+// it describes, and it is written even when ctx was canceled.
+func (s *service) recordHistory(ctx context.Context, event HistoryEvent, err error) {
+	if s.history == nil {
+		return
+	}
+
+	event.Time = time.Now().UTC()
+	event.AimVersion = strings.TrimSpace(s.currentVersion)
+	event.Result = HistoryOutcomeSucceeded
+	if err != nil {
+		event.Result = HistoryOutcomeFailed
+		event.Error = err.Error()
+	}
+	_ = s.history.Append(context.WithoutCancel(ctx), event)
+}
+
+// saveUpdateSource locks installedApp and replaces its update source.
+func (s *service) saveUpdateSource(ctx context.Context, installedApp domain.App, updateSource domain.UpdateSource) error {
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.storeUpdateSource(ctx, installedApp, updateSource)
+}
+
+// storeUpdateSource replaces the update source of installedApp, whose lock the
+// caller holds, and records the change.
+func (s *service) storeUpdateSource(ctx context.Context, installedApp domain.App, updateSource domain.UpdateSource) error {
+	installedApp.UpdateSource = updateSource
+	err := s.apps.Save(ctx, installedApp)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationSetUpdateSource,
+		AppID:      installedApp.ID,
+		OldVersion: installedApp.Version.String(),
+		NewVersion: installedApp.Version.String(),
+		Source:     describeUpdateSource(updateSource),
+	}, err)
+	return err
+}
+
+func describeSource(source domain.Source) string {
+	switch source.Kind {
+	case domain.SourceKindLocal:
+		return source.LocalFile.Path
+	case domain.SourceKindGitHub:
+		if source.GitHubRelease.Tag == "" {
+			return "github:" + source.GitHubRelease.Repo
+		}
+		return "github:" + source.GitHubRelease.Repo + "@" + source.GitHubRelease.Tag
+	case domain.SourceKindURL:
+		return source.URL.URL
+	default:
+		return ""
+	}
+}
+
+func describeUpdateSource(source domain.UpdateSource) string {
+	switch {
+	case source.Kind == domain.UpdateSourceKindUnknown && !source.Embedded:
+		return "none"
+	case source.Embedded:
+		return "embedded:" + string(source.Kind)
+	case source.Kind == domain.UpdateSourceKindGitHub:
+		return "github:" + source.Repo
+	default:
+		return string(source.Kind)
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryLog is an append-only record of operations that changed installed
+// apps.
+//
+// Implementations belong in infrastructure. Append must never rewrite earlier
+// events, and Read returns events in the order they were appended.
+type HistoryLog interface {
+	Append(ctx context.Context, event HistoryEvent) error
+	Read(ctx context.Context) ([]HistoryEvent, error)
+}
+
+type HistoryOperation string
+
+const (
+	HistoryOperationAdd             HistoryOperation = "add"
+	HistoryOperationUpdate          HistoryOperation = "update"
+	HistoryOperationRemove          HistoryOperation = "remove"
+	HistoryOperationSetID           HistoryOperation = "set_id"
+	HistoryOperationSetUpdateSource HistoryOperation = "set_update_source"
+	HistoryOperationSetSandbox      HistoryOperation = "set_sandbox"
+	HistoryOperationConfigureApp    HistoryOperation = "configure_app"
+	HistoryOperationSetBin          HistoryOperation = "set_bin"
+	HistoryOperationSetDefault      HistoryOperation = "set_default"
+	HistoryOperationUndo            HistoryOperation = "undo"
+)
+
+type HistoryOutcome string
+
+const (
+	HistoryOutcomeSucceeded HistoryOutcome = "succeeded"
+	HistoryOutcomeFailed    HistoryOutcome = "failed"
+)
+
+// HistoryEvent describes one operation on one app. PreviousID is set when the
+// operation changed the app ID, and Source describes where the app or its new
+// update source points to; for undo events it names the undone operation.
+type HistoryEvent struct {
+	Time       time.Time        `json:"time"`
+	Operation  HistoryOperation `json:"operation"`
+	AppID      string           `json:"app_id"`
+	PreviousID string           `json:"previous_id,omitempty"`
+	OldVersion string           `json:"old_version,omitempty"`
+	NewVersion string           `json:"new_version,omitempty"`
+	Source     string           `json:"source,omitempty"`
+	Result     HistoryOutcome   `json:"result"`
+	Error      string           `json:"error,omitempty"`
+	AimVersion string           `json:"aim_version,omitempty"`
+}
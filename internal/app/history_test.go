@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceAddAndRemoveRecordHistory(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	history := &fakeHistoryLog{}
+	deps.History = history
+	deps.CurrentVersion = "0.17.0"
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	sourcePath := testAppImagePath(t, "example.AppImage")
+
+	result, err := service.Add(context.Background(), AddRequest{Path: sourcePath})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	deps.apps.findApp = result.App
+	if err := service.Remove(context.Background(), RemoveRequest{Name: result.App.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if len(history.events) != 2 {
+		t.Fatalf("history events = %#v, want add and remove", history.events)
+	}
+	add, remove := history.events[0], history.events[1]
+	if add.Operation != HistoryOperationAdd || add.AppID != "example-app" || add.NewVersion != "1.2.3-beta.1" || add.Source != sourcePath {
+		t.Fatalf("add event = %#v", add)
+	}
+	if add.Result != HistoryOutcomeSucceeded || add.AimVersion != "0.17.0" || add.Time.IsZero() {
+		t.Fatalf("add event = %#v, want succeeded with aim version and time", add)
+	}
+	if remove.Operation != HistoryOperationRemove || remove.OldVersion != "1.2.3-beta.1" || remove.Result != HistoryOutcomeSucceeded {
+		t.Fatalf("remove event = %#v", remove)
+	}
+}
+
+func TestServiceRemoveRecordsFailedHistory(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	history := &fakeHistoryLog{}
+	deps.History = history
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	deps.artifactRemover.err = errors.New("permission denied")
+	deps.artifactRemover.failPath = installed.IconPath
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err == nil {
+		t.Fatal("Remove() error = nil, want failure")
+	}
+
+	if len(history.events) != 1 {
+		t.Fatalf("history events = %#v, want one remove", history.events)
+	}
+	if got := history.events[0]; got.Result != HistoryOutcomeFailed || got.Error == "" {
+		t.Fatalf("remove event = %#v, want failure with error", got)
+	}
+}
+
+func TestServiceUnsetUpdateSourceRecordsHistory(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	history := &fakeHistoryLog{}
+	deps.History = history
+	deps.apps.findApp = testInstalledApp(t)
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.UnsetUpdateSource(context.Background(), UnsetUpdateSourceRequest{ID: "example-app"}); err != nil {
+		t.Fatalf("UnsetUpdateSource() error = %v", err)
+	}
+
+	if len(history.events) != 1 || history.events[0].Operation != HistoryOperationSetUpdateSource || history.events[0].Source != "none" {
+		t.Fatalf("history events = %#v, want update source change to none", history.events)
+	}
+}
+
+func TestServiceHistoryFiltersByIDAndSince(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	deps.History = &fakeHistoryLog{events: []HistoryEvent{
+		{Time: start, Operation: HistoryOperationAdd, AppID: "old-id"},
+		{Time: start.Add(24 * time.Hour), Operation: HistoryOperationSetID, AppID: "example", PreviousID: "old-id"},
+		{Time: start.Add(48 * time.Hour), Operation: HistoryOperationUpdate, AppID: "example"},
+		{Time: start.Add(48 * time.Hour), Operation: HistoryOperationAdd, AppID: "other"},
+	}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.History(context.Background(), HistoryRequest{ID: "old-id"})
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(result.Events) != 2 || result.Events[1].Operation != HistoryOperationSetID {
+		t.Fatalf("History(old-id) = %#v, want add and id change", result.Events)
+	}
+
+	result, err = service.History(context.Background(), HistoryRequest{ID: "example", Since: start.Add(36 * time.Hour)})
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(result.Events) != 1 || result.Events[0].Operation != HistoryOperationUpdate {
+		t.Fatalf("History(example, since) = %#v, want the update", result.Events)
+	}
+}
+
+type fakeHistoryLog struct {
+	events []HistoryEvent
+}
+
+func (f *fakeHistoryLog) Append(ctx context.Context, event HistoryEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeHistoryLog) Read(ctx context.Context) ([]HistoryEvent, error) {
+	return f.events, nil
+}
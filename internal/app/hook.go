@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// runHook runs the configured command for event, if any. Without a
+// HookRunner, hooks are not run.
+func (s *service) runHook(ctx context.Context, event HookEvent, env HookEnv) error {
+	command := s.config.Hooks.command(event)
+	if command == "" || s.hooks == nil {
+		return nil
+	}
+
+	env.Event = event
+	if err := s.hooks.Run(ctx, command, env); err != nil {
+		return fmt.Errorf("%s hook failed: %w", event, err)
+	}
+	return nil
+}
+
+// postHook is a post hook that an operation runs once it has released the app
+// lock, so the hook can itself run aim for the same app.
+type postHook struct {
+	event HookEvent
+	env   HookEnv
+	// done describes the finished operation in the error of a failing hook.
+	done string
+}
+
+// runPostHook runs hook, if the operation set one, and reports its failure in
+// err unless the operation already failed. Operations defer it before taking
+// the app lock so that it runs after the lock is released.
+func (s *service) runPostHook(ctx context.Context, hook *postHook, err *error) {
+	if hook.event == "" || *err != nil {
+		return
+	}
+	if hookErr := s.runHook(ctx, hook.event, hook.env); hookErr != nil {
+		*err = fmt.Errorf("%s but %w", hook.done, hookErr)
+	}
+}
+
+// hookEnvForApp describes installedApp as it is installed.
+func hookEnvForApp(installedApp domain.App) HookEnv {
+	return HookEnv{
+		AppID:            installedApp.ID,
+		AppName:          installedApp.Name,
+		AppImagePath:     installedApp.AppImagePath,
+		DesktopEntryPath: installedApp.DesktopEntryPath,
+		IconPath:         installedApp.IconPath,
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import "context"
+
+// HookRunner runs a lifecycle hook command from the configuration.
+//
+// Implementations belong in infrastructure. They run command with the
+// environment of aim plus the variables from HookEnv.Variables and return an
+// error when it fails.
+type HookRunner interface {
+	Run(ctx context.Context, command string, env HookEnv) error
+}
+
+type HookEvent string
+
+const (
+	HookPreAdd     HookEvent = "pre_add"
+	HookPostAdd    HookEvent = "post_add"
+	HookPreUpdate  HookEvent = "pre_update"
+	HookPostUpdate HookEvent = "post_update"
+	HookPreRemove  HookEvent = "pre_remove"
+	HookPostRemove HookEvent = "post_remove"
+)
+
+// Hooks holds the configured command for each lifecycle event. Empty
+// commands are skipped.
+type Hooks struct {
+	PreAdd     string
+	PostAdd    string
+	PreUpdate  string
+	PostUpdate string
+	PreRemove  string
+	PostRemove string
+}
+
+func (h Hooks) command(event HookEvent) string {
+	switch event {
+	case HookPreAdd:
+		return h.PreAdd
+	case HookPostAdd:
+		return h.PostAdd
+	case HookPreUpdate:
+		return h.PreUpdate
+	case HookPostUpdate:
+		return h.PostUpdate
+	case HookPreRemove:
+		return h.PreRemove
+	case HookPostRemove:
+		return h.PostRemove
+	default:
+		return ""
+	}
+}
+
+// HookEnv describes the app a hook runs for. OldVersion is empty for adds,
+// NewVersion is empty for removals, and StagedAppImagePath is only set for
+// pre_update, where AppImagePath is still the installed AppImage.
+type HookEnv struct {
+	Event              HookEvent
+	AppID              string
+	AppName            string
+	AppImagePath       string
+	StagedAppImagePath string
+	DesktopEntryPath   string
+	IconPath           string
+	OldVersion         string
+	NewVersion         string
+}
+
+// Variables returns the environment variables hooks receive.
+func (e HookEnv) Variables() []string {
+	return []string{
+		"AIM_HOOK=" + string(e.Event),
+		"AIM_APP_ID=" + e.AppID,
+		"AIM_APP_NAME=" + e.AppName,
+		"AIM_APPIMAGE_PATH=" + e.AppImagePath,
+		"AIM_STAGED_APPIMAGE_PATH=" + e.StagedAppImagePath,
+		"AIM_DESKTOP_ENTRY_PATH=" + e.DesktopEntryPath,
+		"AIM_ICON_PATH=" + e.IconPath,
+		"AIM_OLD_VERSION=" + e.OldVersion,
+		"AIM_NEW_VERSION=" + e.NewVersion,
+	}
+}
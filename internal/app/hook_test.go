@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddRollsBackWhenPreAddHookFails(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	hooks := &fakeHookRunner{fail: map[HookEvent]error{HookPreAdd: errors.New("exit status 1")}}
+	deps.Hooks = hooks
+	deps.Config.Hooks = Hooks{PreAdd: "check-app", PostAdd: "register-app"}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err == nil || !strings.Contains(err.Error(), "pre_add hook failed") {
+		t.Fatalf("Add() error = %v, want pre_add hook failure", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{
+		"/desktop/example-app.desktop",
+		"/icons/hicolor/256x256/apps/example-app.png",
+		"/library/example-app.AppImage",
+	})
+	if deps.saved.App.ID != "" {
+		t.Fatalf("saved App.ID = %q, want empty", deps.saved.App.ID)
+	}
+	if len(hooks.runs) != 1 {
+		t.Fatalf("hook runs = %#v, want only pre_add", hooks.runs)
+	}
+	if got := hooks.runs[0]; got.command != "check-app" || got.env.AppID != "example-app" || got.env.AppImagePath != "/library/example-app.AppImage" || got.env.NewVersion != "1.2.3-beta.1" {
+		t.Fatalf("pre_add run = %#v", got)
+	}
+}
+
+func TestServiceRemoveAbortsWhenPreRemoveHookFails(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Hooks = &fakeHookRunner{fail: map[HookEvent]error{HookPreRemove: errors.New("exit status 1")}}
+	deps.Config.Hooks = Hooks{PreRemove: "deregister-app"}
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err == nil {
+		t.Fatal("Remove() error = nil, want pre_remove hook failure")
+	}
+
+	if len(deps.artifactRemover.paths) != 0 || deps.apps.deletedID != "" {
+		t.Fatalf("removed %#v and deleted %q, want nothing removed", deps.artifactRemover.paths, deps.apps.deletedID)
+	}
+}
+
+func TestServiceUpdateRunsHooksWithVersions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	hooks := &fakeHookRunner{}
+	deps.Hooks = hooks
+	deps.Config.Hooks = Hooks{PreUpdate: "verify", PostUpdate: "patch"}
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.findApp = installed
+	deps.desktopEntries.content = []byte("[Desktop Entry]\nName=Example App\nExec=old-exec\nIcon=example-icon\n")
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID, Confirmation: &fakeUpdateConfirmation{confirmed: true}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(result.Failures) != 0 {
+		t.Fatalf("Update().Failures = %#v, want none", result.Failures)
+	}
+
+	if len(hooks.runs) != 2 {
+		t.Fatalf("hook runs = %#v, want pre_update and post_update", hooks.runs)
+	}
+	pre, post := hooks.runs[0].env, hooks.runs[1].env
+	if pre.Event != HookPreUpdate || pre.AppImagePath != installed.AppImagePath || pre.StagedAppImagePath != "/library/example-app-2-0-0.AppImage" {
+		t.Fatalf("pre_update env = %#v", pre)
+	}
+	if post.Event != HookPostUpdate || post.OldVersion != "1.2.3" || post.NewVersion != "2.0.0" || post.AppImagePath != "/library/example-app.AppImage" {
+		t.Fatalf("post_update env = %#v", post)
+	}
+}
+
+func TestServiceRunsPostHooksAfterReleasingAppLock(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	locks := &fakeAppLocker{}
+	deps.AppLocks = locks
+	heldDuring := map[HookEvent]bool{}
+	deps.Hooks = &fakeHookRunner{onRun: func(env HookEnv) {
+		heldDuring[env.Event] = locks.held[env.AppID]
+	}}
+	deps.Config.Hooks = Hooks{PreAdd: "check-app", PostAdd: "register-app", PostRemove: "deregister-app"}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	deps.apps.findApp = result.App
+	if err := service.Remove(context.Background(), RemoveRequest{Name: result.App.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	want := map[HookEvent]bool{HookPreAdd: true, HookPostAdd: false, HookPostRemove: false}
+	if !reflect.DeepEqual(heldDuring, want) {
+		t.Fatalf("app lock held during hooks = %#v, want %#v", heldDuring, want)
+	}
+}
+
+func TestServiceReportsPostRemoveHookFailure(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Hooks = &fakeHookRunner{fail: map[HookEvent]error{HookPostRemove: errors.New("exit status 1")}}
+	deps.Config.Hooks = Hooks{PostRemove: "deregister-app"}
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	err = service.Remove(context.Background(), RemoveRequest{Name: installed.ID})
+	if err == nil || !strings.Contains(err.Error(), "removed example-app but post_remove hook failed") {
+		t.Fatalf("Remove() error = %v, want post_remove hook failure", err)
+	}
+	if deps.apps.deletedID != installed.ID {
+		t.Fatalf("deleted app = %q, want %q", deps.apps.deletedID, installed.ID)
+	}
+}
+
+type fakeHookRun struct {
+	command string
+	env     HookEnv
+}
+
+type fakeHookRunner struct {
+	fail map[HookEvent]error
+	runs []fakeHookRun
+	// onRun is called with each run, for checks on the state hooks see.
+	onRun func(env HookEnv)
+}
+
+func (f *fakeHookRunner) Run(ctx context.Context, command string, env HookEnv) error {
+	f.runs = append(f.runs, fakeHookRun{command: command, env: env})
+	if f.onRun != nil {
+		f.onRun(env)
+	}
+	return f.fail[env.Event]
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// generatedIconFile is the file in an operation workspace that generated
+// icons are drawn to before they are installed.
+const generatedIconFile = "generated-icon.png"
+
+// discoverIcon finds the icon of an extracted AppImage. Without a bundled
+// icon it returns an empty IconFile when an icon generator is configured, so
+// that one can be generated for the app.
+func (s *service) discoverIcon(ctx context.Context, rootDir string, iconName string) (IconFile, error) {
+	iconFile, err := s.icons.Discover(ctx, rootDir, iconName)
+	if errors.Is(err, ErrIconNotFound) && s.iconGenerator != nil {
+		return IconFile{}, nil
+	}
+	return iconFile, err
+}
+
+// iconOrGenerated returns iconFile, or when it is empty, a placeholder icon
+// for the app drawn into workspacePath. It reports whether the icon was
+// generated.
+func (s *service) iconOrGenerated(ctx context.Context, iconFile IconFile, workspacePath string, name string, appID string) (IconFile, bool, error) {
+	if iconFile.Path != "" {
+		return iconFile, false, nil
+	}
+	if s.iconGenerator == nil {
+		return IconFile{}, false, errors.New("icon generator is required")
+	}
+
+	path := filepath.Join(workspacePath, generatedIconFile)
+	if err := s.iconGenerator.Generate(ctx, path, name, appID); err != nil {
+		return IconFile{}, false, fmt.Errorf("generate icon for %s: %w", appID, err)
+	}
+	return IconFile{Path: path}, true, nil
+}
@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddInstallsIconSizes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.icons.sizes = []string{"/extracted/usr/share/icons/hicolor/128x128/apps/example.png", "/extracted/usr/share/icons/hicolor/48x48/apps/example.png"}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if want := slices.Concat([]string{deps.icons.path}, deps.icons.sizes); !reflect.DeepEqual(deps.iconInstaller.sizeSources, want) {
+		t.Fatalf("installed sizes from %q, want %q", deps.iconInstaller.sizeSources, want)
+	}
+	want := []string{"/icons/hicolor/1/apps/example-app.png", "/icons/hicolor/2/apps/example-app.png"}
+	if !reflect.DeepEqual(result.App.IconSizePaths, want) || !reflect.DeepEqual(deps.saved.App.IconSizePaths, want) {
+		t.Fatalf("IconSizePaths = %q, saved %q, want %q", result.App.IconSizePaths, deps.saved.App.IconSizePaths, want)
+	}
+}
+
+func TestServiceRemoveRemovesIconSizes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.IconSizePaths = []string{"/icons/hicolor/48x48/apps/example-app.png"}
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/icons/hicolor/48x48/apps/example-app.png", installed.DesktopEntryPath, installed.IconPath, installed.AppImagePath})
+}
+
+func TestServiceUpdateReplacesIconSizes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.IconSizePaths = []string{"/icons/hicolor/1/apps/example-app.png", "/icons/hicolor/16x16/apps/example-app.png"}
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.icons.sizes = []string{"/extracted/usr/share/icons/hicolor/48x48/apps/example.png"}
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if want := []string{deps.iconInstaller.paths["example-app-2-0-0"], "/icons/hicolor/1/apps/example-app-2-0-0.png"}; !reflect.DeepEqual(deps.iconInstaller.sizeSources, want) {
+		t.Fatalf("promoted sizes from %q, want the staged ones %q", deps.iconInstaller.sizeSources, want)
+	}
+	if want := []string{"/icons/hicolor/1/apps/example-app.png"}; !reflect.DeepEqual(deps.saved.App.IconSizePaths, want) {
+		t.Fatalf("saved IconSizePaths = %q, want %q", deps.saved.App.IconSizePaths, want)
+	}
+	for _, path := range []string{"/icons/hicolor/1/apps/example-app-2-0-0.png", "/icons/hicolor/16x16/apps/example-app.png"} {
+		if !slices.Contains(deps.artifactRemover.paths, path) {
+			t.Fatalf("removed paths = %q, want %s removed", deps.artifactRemover.paths, path)
+		}
+	}
+	if slices.Contains(deps.artifactRemover.paths, "/icons/hicolor/1/apps/example-app.png") {
+		t.Fatalf("removed paths = %q, want the updated icon size kept", deps.artifactRemover.paths)
+	}
+}
+
+func TestServiceAddGeneratesIconWhenNoneIsBundled(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.icons.err = fmt.Errorf("find icon under /extracted: %w", ErrIconNotFound)
+	generator := &fakeIconGenerator{}
+	deps.IconGenerator = generator
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if generator.name != "Example App" || generator.appID != "example-app" {
+		t.Fatalf("generated icon for %q, %q; want Example App, example-app", generator.name, generator.appID)
+	}
+	if filepath.Base(generator.destination) != generatedIconFile || deps.iconInstaller.sourcePath != generator.destination {
+		t.Fatalf("installed icon %q, generated %q", deps.iconInstaller.sourcePath, generator.destination)
+	}
+	if !result.App.GeneratedIcon || !deps.saved.App.GeneratedIcon {
+		t.Fatalf("GeneratedIcon = %t, saved %t, want true", result.App.GeneratedIcon, deps.saved.App.GeneratedIcon)
+	}
+}
+
+func TestServiceAddWithoutIconGeneratorRequiresIcon(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.icons.err = fmt.Errorf("find icon under /extracted: %w", ErrIconNotFound)
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")}); !errors.Is(err, ErrIconNotFound) {
+		t.Fatalf("Add() error = %v, want ErrIconNotFound", err)
+	}
+}
+
+func TestServiceUpdateReplacesGeneratedIcon(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.GeneratedIcon = true
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	generator := &fakeIconGenerator{}
+	deps.IconGenerator = generator
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if generator.destination != "" {
+		t.Fatalf("generated an icon at %q, want the bundled one", generator.destination)
+	}
+	if deps.saved.App.GeneratedIcon {
+		t.Fatal("saved GeneratedIcon = true, want false after an update with an icon")
+	}
+}
+
+func TestServiceUpdateGeneratesIconForUpdatedApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.GeneratedIcon = true
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.icons.err = fmt.Errorf("find icon under /extracted: %w", ErrIconNotFound)
+	generator := &fakeIconGenerator{}
+	deps.IconGenerator = generator
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if generator.appID != installed.ID {
+		t.Fatalf("generated icon for %q, want the updated app %q", generator.appID, installed.ID)
+	}
+	if !deps.saved.App.GeneratedIcon {
+		t.Fatal("saved GeneratedIcon = false, want true")
+	}
+}
+
+type fakeIconGenerator struct {
+	destination string
+	name        string
+	appID       string
+}
+
+func (f *fakeIconGenerator) Generate(ctx context.Context, destination string, name string, appID string) error {
+	f.destination = destination
+	f.name = name
+	f.appID = appID
+	return nil
+}
@@ -1,6 +1,9 @@
 package app
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // AppImageExtractor extracts AppImages into a workspace directory.
 type AppImageExtractor interface {
@@ -24,7 +27,13 @@ type DesktopEntryFile struct {
 	Content []byte
 }
 
+// ErrIconNotFound reports that an extracted AppImage bundles no supported
+// icon.
+var ErrIconNotFound = errors.New("no supported icon found")
+
 // IconDiscoverer finds application icon files in extracted AppImages.
+//
+// Discover wraps ErrIconNotFound when the AppImage bundles no icon.
 type IconDiscoverer interface {
 	Discover(ctx context.Context, rootDir string, iconName string) (IconFile, error)
 }
@@ -32,19 +41,53 @@ type IconDiscoverer interface {
 // IconFile is a discovered icon file.
 type IconFile struct {
 	Path string
+	// Sizes are further icons of the same name in the AppImage's hicolor
+	// theme, such as the 48x48 and 128x128 variants of a 256x256 Path.
+	Sizes []string
 }
 
 // AppImageInstaller installs an AppImage into the app library.
+//
+// Adopt prepares an AppImage outside the library to be integrated where it is
+// and returns the path that should be recorded for it.
 type AppImageInstaller interface {
 	Install(ctx context.Context, sourcePath string, appID string) (string, error)
+	Adopt(ctx context.Context, path string) (string, error)
+}
+
+// AppImageScanner finds AppImage files on disk by their magic bytes.
+type AppImageScanner interface {
+	Scan(ctx context.Context, dirs []string) ([]ScannedAppImage, error)
+}
+
+// ScannedAppImage is an AppImage file found by an AppImageScanner.
+type ScannedAppImage struct {
+	Path      string
+	SizeBytes int64
 }
 
-// ArtifactRemover removes installed files created by aim.
+// ArtifactRemover removes installed files created by aim. Removing a missing
+// file is not an error.
 type ArtifactRemover func(ctx context.Context, path string) error
 
 // IconInstaller installs an icon into the icon directory.
+//
+// Icons are installed into the hicolor size directory that matches their
+// dimensions. InstallSizes installs one icon per size from sourcePaths, which
+// start with the icon passed to Install, the first one of each size winning,
+// and returns the installed paths. It may add smaller sizes rendered from the
+// largest icon.
 type IconInstaller interface {
 	Install(ctx context.Context, sourcePath string, appID string) (string, error)
+	InstallSizes(ctx context.Context, sourcePaths []string, appID string) ([]string, error)
+}
+
+// IconGenerator draws a placeholder icon for apps that bundle none.
+//
+// Generate writes a PNG showing the initials of name to destination. The same
+// name and appID always give the same icon.
+type IconGenerator interface {
+	Generate(ctx context.Context, destination string, name string, appID string) error
 }
 
 // DesktopEntryInstaller installs a desktop entry into the applications directory.
@@ -52,6 +95,15 @@ type DesktopEntryInstaller interface {
 	Install(ctx context.Context, appID string, content []byte) (string, error)
 }
 
+// CommandInstaller installs the commands that put apps on PATH.
+//
+// Install writes a command called name into the bin directory that runs
+// command followed by the arguments it is called with, and returns its path.
+// It replaces commands it created earlier but never other files.
+type CommandInstaller interface {
+	Install(ctx context.Context, name string, command []string) (string, error)
+}
+
 // DesktopIntegrationRefresher refreshes desktop environment caches after
 // installing or removing desktop integration artifacts.
 type DesktopIntegrationRefresher interface {
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// operationJournal tracks one journaled operation. A nil journal is valid and
+// records nothing, so callers do not need to check whether journaling is
+// configured.
+type operationJournal struct {
+	store OperationJournal
+	entry JournalEntry
+}
+
+func (s *service) beginOperation(ctx context.Context, kind JournalKind, appID string) (*operationJournal, error) {
+	if s.journal == nil {
+		return nil, nil
+	}
+
+	startedAt := time.Now().UTC()
+	journal := &operationJournal{
+		store: s.journal,
+		entry: JournalEntry{
+			ID:        fmt.Sprintf("%s-%s-%s", startedAt.Format("20060102T150405.000000000Z"), kind, appID),
+			Kind:      kind,
+			AppID:     appID,
+			StartedAt: startedAt,
+		},
+	}
+	if err := journal.store.Write(ctx, journal.entry); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// created records artifacts the operation wrote so recovery can remove them.
+func (j *operationJournal) created(ctx context.Context, paths ...string) error {
+	if j == nil {
+		return nil
+	}
+	j.entry.Created = appendPaths(j.entry.Created, paths...)
+	return j.store.Write(ctx, j.entry)
+}
+
+// commit marks the point of no return. commit, when set, is the record to
+// save and cleanup lists artifacts to remove when rolling forward.
+func (j *operationJournal) commit(ctx context.Context, commit *domain.App, cleanup ...string) error {
+	if j == nil {
+		return nil
+	}
+	j.entry.Committed = true
+	if commit != nil {
+		j.entry.Commit = commit
+	}
+	j.entry.Cleanup = appendPaths(j.entry.Cleanup, cleanup...)
+	return j.store.Write(ctx, j.entry)
+}
+
+// trashOnCommit records artifacts that rolling forward moves to the trash
+// instead of deleting. They are written with the next commit.
+func (j *operationJournal) trashOnCommit(paths ...string) {
+	if j == nil {
+		return
+	}
+	j.entry.Trash = appendPaths(j.entry.Trash, paths...)
+}
+
+// finish drops the entry once the operation returned, successfully or after an
+// in-process rollback.
+func (j *operationJournal) finish(ctx context.Context) {
+	if j == nil {
+		return
+	}
+	_ = j.store.Delete(context.WithoutCancel(ctx), j.entry.ID)
+}
+
+func appendPaths(paths []string, more ...string) []string {
+	for _, path := range more {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func (s *service) Recover(ctx context.Context, req RecoverRequest) (RecoverResult, error) {
+	if err := ctx.Err(); err != nil {
+		return RecoverResult{}, err
+	}
+	result := RecoverResult{Recovered: make([]RecoveredOperation, 0), Failures: make([]RecoverFailure, 0)}
+	if s.journal == nil {
+		return result, nil
+	}
+
+	entries, err := s.journal.Abandoned(ctx)
+	if err != nil {
+		return RecoverResult{}, err
+	}
+	for _, entry := range entries {
+		recovered, err := s.recoverOperation(ctx, entry)
+		if err != nil {
+			if ctx.Err() != nil {
+				return RecoverResult{}, err
+			}
+			result.Failures = append(result.Failures, RecoverFailure{ID: entry.ID, AppID: entry.AppID, Error: err.Error()})
+			continue
+		}
+		result.Recovered = append(result.Recovered, recovered)
+	}
+
+	return result, nil
+}
+
+func (s *service) recoverOperation(ctx context.Context, entry JournalEntry) (RecoveredOperation, error) {
+	unlock, err := s.lockApp(ctx, entry.AppID)
+	if err != nil {
+		return RecoveredOperation{}, err
+	}
+	defer unlock()
+
+	recovered := RecoveredOperation{ID: entry.ID, Kind: entry.Kind, AppID: entry.AppID}
+	if !entry.Committed {
+		for i := len(entry.Created) - 1; i >= 0; i-- {
+			if err := s.artifactRemover(ctx, entry.Created[i]); err != nil {
+				return RecoveredOperation{}, err
+			}
+		}
+		recovered.Action = RecoveryRolledBack
+	} else {
+		switch {
+		case entry.Commit != nil:
+			if err := s.apps.Save(ctx, *entry.Commit); err != nil {
+				return RecoveredOperation{}, err
+			}
+		case entry.Kind == JournalKindRemove:
+			if err := s.apps.Delete(ctx, entry.AppID); err != nil && !errors.Is(err, ErrAppNotFound) {
+				return RecoveredOperation{}, err
+			}
+		case entry.Kind == JournalKindUpdate:
+			recovered.Note = fmt.Sprintf("the update was interrupted while replacing files; run aim repair %s and aim update %s", entry.AppID, entry.AppID)
+		}
+		for _, path := range entry.Cleanup {
+			if err := s.artifactRemover(ctx, path); err != nil {
+				return RecoveredOperation{}, err
+			}
+		}
+		if len(entry.Trash) > 0 && s.trash == nil {
+			return RecoveredOperation{}, errors.New("trash is required")
+		}
+		for _, path := range entry.Trash {
+			if err := s.trash(ctx, path); err != nil {
+				return RecoveredOperation{}, err
+			}
+		}
+		recovered.Action = RecoveryRolledForward
+	}
+
+	if err := s.journal.Delete(ctx, entry.ID); err != nil {
+		return RecoveredOperation{}, err
+	}
+	return recovered, nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// OperationJournal persists in-flight add, update, and remove operations so
+// they can be rolled back or forward after a crash.
+//
+// Implementations belong in infrastructure and must write entries durably
+// before returning. Abandoned returns only entries whose owning process is no
+// longer running.
+type OperationJournal interface {
+	Write(ctx context.Context, entry JournalEntry) error
+	Delete(ctx context.Context, id string) error
+	Abandoned(ctx context.Context) ([]JournalEntry, error)
+}
+
+type JournalKind string
+
+const (
+	JournalKindAdd    JournalKind = "add"
+	JournalKindUpdate JournalKind = "update"
+	JournalKindRemove JournalKind = "remove"
+)
+
+// JournalEntry records the artifacts one operation touched.
+//
+// Until Committed is set, recovery rolls the operation back by removing
+// Created. Once committed, recovery rolls it forward: Commit is saved when
+// set, removals delete the app record, Cleanup is removed and Trash is moved
+// to the trash.
+type JournalEntry struct {
+	ID        string
+	Kind      JournalKind
+	AppID     string
+	StartedAt time.Time
+	Created   []string
+	Committed bool
+	Commit    *domain.App
+	Cleanup   []string
+	Trash     []string
+}
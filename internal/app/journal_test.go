@@ -0,0 +1,241 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddJournalsArtifactsUntilSaved(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	journal := &fakeOperationJournal{}
+	deps.Journal = journal
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if len(journal.writes) == 0 {
+		t.Fatal("Add() wrote no journal entries")
+	}
+	last := journal.writes[len(journal.writes)-1]
+	if last.Kind != JournalKindAdd || last.AppID != result.App.ID || !last.Committed || last.Commit == nil {
+		t.Fatalf("last journal entry = %#v, want committed add of %s", last, result.App.ID)
+	}
+	wantCreated := []string{result.App.AppImagePath, result.App.IconPath, result.App.DesktopEntryPath}
+	if !reflect.DeepEqual(last.Created, wantCreated) {
+		t.Fatalf("journaled Created = %#v, want %#v", last.Created, wantCreated)
+	}
+	if !reflect.DeepEqual(journal.deleted, []string{last.ID}) {
+		t.Fatalf("deleted journal entries = %#v, want %q", journal.deleted, last.ID)
+	}
+}
+
+func TestServiceUpdateCommitsJournalBeforePromotion(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.Downloads = &fakeAssetDownloader{}
+	journal := &fakeOperationJournal{}
+	deps.Journal = journal
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	var promoting, promoted *JournalEntry
+	for i := range journal.writes {
+		entry := &journal.writes[i]
+		if entry.Committed && entry.Commit == nil && promoting == nil {
+			promoting = entry
+		}
+		if entry.Commit != nil {
+			promoted = entry
+		}
+	}
+	if promoting == nil || promoted == nil {
+		t.Fatalf("journal writes = %#v, want commit before and after promotion", journal.writes)
+	}
+	if promoting.Kind != JournalKindUpdate || len(promoting.Created) != 3 || len(promoting.Cleanup) != 3 {
+		t.Fatalf("commit before promotion = %#v, want staged artifacts created and scheduled for cleanup", promoting)
+	}
+	if got, want := promoted.Commit.ID, installed.ID; got != want {
+		t.Fatalf("journaled commit ID = %q, want %q", got, want)
+	}
+	if len(journal.deleted) != 1 {
+		t.Fatalf("deleted journal entries = %#v, want one", journal.deleted)
+	}
+}
+
+func TestServiceRecoverRollsBackUncommittedOperation(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	journal := &fakeOperationJournal{abandoned: []JournalEntry{{
+		ID:      "op-1",
+		Kind:    JournalKindAdd,
+		AppID:   "example",
+		Created: []string{"/library/example.AppImage", "/icons/example.png"},
+	}}}
+	deps.Journal = journal
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Recover(context.Background(), RecoverRequest{})
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	want := []RecoveredOperation{{ID: "op-1", Kind: JournalKindAdd, AppID: "example", Action: RecoveryRolledBack}}
+	if !reflect.DeepEqual(result.Recovered, want) {
+		t.Fatalf("Recovered = %#v, want %#v", result.Recovered, want)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/icons/example.png", "/library/example.AppImage"})
+	if deps.saved.App.ID != "" || deps.apps.deletedID != "" {
+		t.Fatal("rollback changed the app database")
+	}
+	if !reflect.DeepEqual(journal.deleted, []string{"op-1"}) {
+		t.Fatalf("deleted journal entries = %#v, want op-1", journal.deleted)
+	}
+}
+
+func TestServiceRecoverRollsForwardCommittedOperations(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	updated := testInstalledApp(t)
+	journal := &fakeOperationJournal{abandoned: []JournalEntry{
+		{ID: "op-1", Kind: JournalKindUpdate, AppID: updated.ID, Committed: true, Commit: &updated, Cleanup: []string{"/library/example-app-2-0-0.AppImage"}},
+		{ID: "op-2", Kind: JournalKindRemove, AppID: "extra", Committed: true, Cleanup: []string{"/desktop/extra.desktop"}},
+		{ID: "op-3", Kind: JournalKindUpdate, AppID: "other", Committed: true},
+	}}
+	deps.Journal = journal
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Recover(context.Background(), RecoverRequest{})
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	if len(result.Recovered) != 3 || len(result.Failures) != 0 {
+		t.Fatalf("result = %#v, want three recovered operations", result)
+	}
+	for _, recovered := range result.Recovered {
+		if recovered.Action != RecoveryRolledForward {
+			t.Fatalf("recovered %#v, want rolled forward", recovered)
+		}
+	}
+	if got := deps.saved.App; !reflect.DeepEqual(got, updated) {
+		t.Fatalf("saved app = %#v, want %#v", got, updated)
+	}
+	if got, want := deps.apps.deletedID, "extra"; got != want {
+		t.Fatalf("deleted app = %q, want %q", got, want)
+	}
+	if !strings.Contains(result.Recovered[2].Note, "aim repair other") {
+		t.Fatalf("Note = %q, want repair advice", result.Recovered[2].Note)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/library/example-app-2-0-0.AppImage", "/desktop/extra.desktop"})
+}
+
+func TestServiceRemoveJournalsTrashedAppImage(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	deps.Trash = (&fakeArtifactRemover{}).Remove
+	journal := &fakeOperationJournal{}
+	deps.Journal = journal
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID, Trash: true}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	last := journal.writes[len(journal.writes)-1]
+	if !last.Committed || !reflect.DeepEqual(last.Trash, []string{installed.AppImagePath}) {
+		t.Fatalf("last journal entry = %#v, want AppImage committed to the trash", last)
+	}
+	for _, path := range last.Cleanup {
+		if path == installed.AppImagePath {
+			t.Fatalf("journaled Cleanup = %#v, want AppImage kept out of deletion", last.Cleanup)
+		}
+	}
+}
+
+func TestServiceRecoverMovesTrashedAppImageToTrash(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	trash := &fakeArtifactRemover{}
+	deps.Trash = trash.Remove
+	deps.Journal = &fakeOperationJournal{abandoned: []JournalEntry{{
+		ID:        "op-1",
+		Kind:      JournalKindRemove,
+		AppID:     "extra",
+		Committed: true,
+		Cleanup:   []string{"/desktop/extra.desktop"},
+		Trash:     []string{"/library/extra.AppImage"},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Recover(context.Background(), RecoverRequest{}); err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/desktop/extra.desktop"})
+	assertRemovedPaths(t, trash.paths, []string{"/library/extra.AppImage"})
+}
+
+type fakeOperationJournal struct {
+	writes    []JournalEntry
+	deleted   []string
+	abandoned []JournalEntry
+}
+
+func (f *fakeOperationJournal) Write(ctx context.Context, entry JournalEntry) error {
+	entry.Created = append([]string(nil), entry.Created...)
+	entry.Cleanup = append([]string(nil), entry.Cleanup...)
+	entry.Trash = append([]string(nil), entry.Trash...)
+	f.writes = append(f.writes, entry)
+	return nil
+}
+
+func (f *fakeOperationJournal) Delete(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeOperationJournal) Abandoned(ctx context.Context) ([]JournalEntry, error) {
+	return f.abandoned, nil
+}
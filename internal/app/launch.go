@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) ConfigureApp(ctx context.Context, req ConfigureAppRequest) (ConfigureAppResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ConfigureAppResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return ConfigureAppResult{}, errors.New("app id is required")
+	}
+	if err := validateLaunchOptions(domain.LaunchOptions{Env: req.Env}); err != nil {
+		return ConfigureAppResult{}, err
+	}
+	for _, key := range req.UnsetEnv {
+		if _, ok := req.Env[key]; ok {
+			return ConfigureAppResult{}, fmt.Errorf("environment variable %s is both set and unset", key)
+		}
+	}
+
+	installedApp, err := s.apps.Find(ctx, id)
+	if err != nil {
+		return ConfigureAppResult{}, err
+	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return ConfigureAppResult{}, err
+	}
+	defer unlock()
+
+	launch := domain.LaunchOptions{
+		Args: slices.Clone(installedApp.Launch.Args),
+		Env:  maps.Clone(installedApp.Launch.Env),
+	}
+	if req.Clear {
+		launch = domain.LaunchOptions{}
+	}
+	if req.ReplaceArgs {
+		launch.Args = slices.Clone(req.Args)
+	}
+	if len(req.Env) > 0 && launch.Env == nil {
+		launch.Env = map[string]string{}
+	}
+	maps.Copy(launch.Env, req.Env)
+	for _, key := range req.UnsetEnv {
+		delete(launch.Env, key)
+	}
+	if len(launch.Args) == 0 {
+		launch.Args = nil
+	}
+	if len(launch.Env) == 0 {
+		launch.Env = nil
+	}
+
+	if slices.Equal(launch.Args, installedApp.Launch.Args) && maps.Equal(launch.Env, installedApp.Launch.Env) {
+		return ConfigureAppResult{ID: installedApp.ID, Launch: installedApp.Launch}, nil
+	}
+
+	updatedApp := installedApp
+	updatedApp.Launch = launch
+	err = s.saveLaunchSettings(ctx, installedApp, updatedApp)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationConfigureApp,
+		AppID:      installedApp.ID,
+		OldVersion: installedApp.Version.String(),
+		NewVersion: installedApp.Version.String(),
+		Source:     describeLaunchOptions(launch),
+	}, err)
+	if err != nil {
+		return ConfigureAppResult{}, err
+	}
+
+	return ConfigureAppResult{ID: updatedApp.ID, Launch: launch, Changed: true}, nil
+}
+
+// Run replaces the aim process with the app, so it only returns when the app
+// could not be started.
+func (s *service) Run(ctx context.Context, req RunRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return errors.New("app id is required")
+	}
+	if s.launcher == nil {
+		return errors.New("app launcher is required")
+	}
+
+	installedApp, err := s.apps.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(installedApp.AppImagePath) == "" {
+		return fmt.Errorf("%s has no installed appimage path", installedApp.ID)
+	}
+
+	argv := append(installedApp.LaunchCommand(), req.Args...)
+	if err := s.launcher.Exec(ctx, argv, installedApp.Launch.Environ()); err != nil {
+		return fmt.Errorf("run %s: %w", installedApp.ID, err)
+	}
+	return nil
+}
+
+func validateLaunchOptions(launch domain.LaunchOptions) error {
+	for key := range launch.Env {
+		if !domain.ValidEnvName(key) {
+			return fmt.Errorf("invalid environment variable name %q", key)
+		}
+	}
+	return nil
+}
+
+func describeLaunchOptions(launch domain.LaunchOptions) string {
+	if launch.IsZero() {
+		return "none"
+	}
+	return strings.Join(append(launch.Environ(), launch.Args...), " ")
+}
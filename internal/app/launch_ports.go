@@ -0,0 +1,13 @@
+package app
+
+import "context"
+
+// AppLauncher starts installed apps for aim run.
+//
+// Implementations belong in infrastructure. Exec replaces the aim process
+// with argv, run with aim's environment plus env, where env entries override
+// variables of the same name. It only returns when the app could not be
+// started.
+type AppLauncher interface {
+	Exec(ctx context.Context, argv []string, env []string) error
+}
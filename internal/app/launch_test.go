@@ -0,0 +1,250 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddAppliesLaunchOptions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	launch := domain.LaunchOptions{Args: []string{"--no-sandbox"}, Env: map[string]string{"GDK_SCALE": "2"}}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage"), Launch: launch})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(result.App.Launch, launch) || !reflect.DeepEqual(deps.saved.App.Launch, launch) {
+		t.Fatalf("Launch = %#v, saved %#v, want %#v", result.App.Launch, deps.saved.App.Launch, launch)
+	}
+	desktopContent := string(deps.desktopEntryInstaller.content)
+	if !strings.Contains(desktopContent, "Exec=env GDK_SCALE=2 /library/example-app.AppImage --no-sandbox %U") {
+		t.Fatalf("desktop content = %q, want Exec with launch options", desktopContent)
+	}
+}
+
+func TestServiceAddRejectsInvalidEnvName(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Add(context.Background(), AddRequest{
+		Path:   testAppImagePath(t, "example.AppImage"),
+		Launch: domain.LaunchOptions{Env: map[string]string{"BAD NAME": "1"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "BAD NAME") {
+		t.Fatalf("Add() error = %v, want invalid environment variable name", err)
+	}
+	if len(deps.desktopEntryInstaller.calls) != 0 {
+		t.Fatalf("desktop entry installs = %d, want none", len(deps.desktopEntryInstaller.calls))
+	}
+}
+
+func TestServiceConfigureAppRewritesDesktopEntryExec(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Launch = domain.LaunchOptions{Args: []string{"--old"}, Env: map[string]string{"OLD": "1", "KEEP": "yes"}}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.ConfigureApp(context.Background(), ConfigureAppRequest{
+		ID:          installed.ID,
+		ReplaceArgs: true,
+		Args:        []string{"--ozone-platform=wayland"},
+		Env:         map[string]string{"GDK_SCALE": "2"},
+		UnsetEnv:    []string{"OLD"},
+	})
+	if err != nil {
+		t.Fatalf("ConfigureApp() error = %v", err)
+	}
+
+	want := domain.LaunchOptions{Args: []string{"--ozone-platform=wayland"}, Env: map[string]string{"GDK_SCALE": "2", "KEEP": "yes"}}
+	if !result.Changed || !reflect.DeepEqual(result.Launch, want) || !reflect.DeepEqual(deps.saved.App.Launch, want) {
+		t.Fatalf("result = %#v, saved %#v, want %#v", result, deps.saved.App.Launch, want)
+	}
+	if !reflect.DeepEqual(installed.Launch.Env, map[string]string{"OLD": "1", "KEEP": "yes"}) {
+		t.Fatalf("installed env = %#v, want unchanged", installed.Launch.Env)
+	}
+	content := string(deps.desktopEntryInstaller.content)
+	for _, want := range []string{
+		"Exec=env GDK_SCALE=2 KEEP=yes " + installed.AppImagePath + " --ozone-platform=wayland %U",
+		"Exec=env GDK_SCALE=2 KEEP=yes " + installed.AppImagePath + " --ozone-platform=wayland --new-window %U",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("desktop content = %q, want %q", content, want)
+		}
+	}
+}
+
+func TestServiceConfigureAppWithoutChangesReportsCurrentOptions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Launch = domain.LaunchOptions{Args: []string{"--no-sandbox"}}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.ConfigureApp(context.Background(), ConfigureAppRequest{ID: installed.ID})
+	if err != nil {
+		t.Fatalf("ConfigureApp() error = %v", err)
+	}
+
+	if result.Changed || !reflect.DeepEqual(result.Launch, installed.Launch) {
+		t.Fatalf("result = %#v, want unchanged %#v", result, installed.Launch)
+	}
+	if len(deps.desktopEntryInstaller.calls) != 0 || deps.saved.App.ID != "" {
+		t.Fatal("ConfigureApp() rewrote the app without changes")
+	}
+}
+
+func TestServiceConfigureAppClearRestoresPlainExec(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Launch = domain.LaunchOptions{Args: []string{"--no-sandbox"}, Env: map[string]string{"GDK_SCALE": "2"}}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.ConfigureApp(context.Background(), ConfigureAppRequest{ID: installed.ID, Clear: true}); err != nil {
+		t.Fatalf("ConfigureApp() error = %v", err)
+	}
+
+	if !deps.saved.App.Launch.IsZero() {
+		t.Fatalf("saved launch = %#v, want zero", deps.saved.App.Launch)
+	}
+	if content := string(deps.desktopEntryInstaller.content); !strings.Contains(content, "Exec="+installed.AppImagePath+" %U\n") {
+		t.Fatalf("desktop content = %q, want plain AppImage Exec", content)
+	}
+}
+
+func TestServiceConfigureAppValidatesRequest(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, req := range []ConfigureAppRequest{
+		{Env: map[string]string{"GDK_SCALE": "2"}},
+		{ID: "example-app", Env: map[string]string{"A=B": "2"}},
+		{ID: "example-app", Env: map[string]string{"GDK_SCALE": "2"}, UnsetEnv: []string{"GDK_SCALE"}},
+	} {
+		if _, err := service.ConfigureApp(context.Background(), req); err == nil {
+			t.Fatalf("ConfigureApp(%#v) error = nil, want validation error", req)
+		}
+	}
+}
+
+func TestServiceUpdateKeepsLaunchOptions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.Launch = domain.LaunchOptions{Args: []string{"--no-sandbox"}, Env: map[string]string{"GDK_SCALE": "2"}}
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(deps.saved.App.Launch, installed.Launch) {
+		t.Fatalf("saved launch = %#v, want %#v", deps.saved.App.Launch, installed.Launch)
+	}
+	content := string(deps.desktopEntryInstaller.content)
+	if !strings.Contains(content, "Exec=env GDK_SCALE=2 /library/example-app.AppImage --no-sandbox %U") {
+		t.Fatalf("desktop content = %q, want Exec with launch options", content)
+	}
+}
+
+func TestServiceRunExecsConfiguredLaunchCommand(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Sandbox = domain.Sandbox{Backend: domain.SandboxBackendFirejail, Network: true}
+	installed.Launch = domain.LaunchOptions{Args: []string{"--no-sandbox"}, Env: map[string]string{"GDK_SCALE": "2"}}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	launcher := &fakeAppLauncher{}
+	deps.Launcher = launcher
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Run(context.Background(), RunRequest{ID: installed.ID, Args: []string{"file.txt"}}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantArgv := []string{"firejail", "--appimage", installed.AppImagePath, "--no-sandbox", "file.txt"}
+	if !reflect.DeepEqual(launcher.argv, wantArgv) || !reflect.DeepEqual(launcher.env, []string{"GDK_SCALE=2"}) {
+		t.Fatalf("Exec(%q, %q), want %q, [GDK_SCALE=2]", launcher.argv, launcher.env, wantArgv)
+	}
+}
+
+func TestServiceRunReportsLaunchFailure(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.Launcher = &fakeAppLauncher{err: errors.New("no such file or directory")}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	err = service.Run(context.Background(), RunRequest{ID: installed.ID})
+	if err == nil || !strings.Contains(err.Error(), "run example-app: no such file or directory") {
+		t.Fatalf("Run() error = %v, want launch failure", err)
+	}
+}
+
+type fakeAppLauncher struct {
+	argv []string
+	env  []string
+	err  error
+}
+
+func (f *fakeAppLauncher) Exec(ctx context.Context, argv []string, env []string) error {
+	f.argv = argv
+	f.env = env
+	return f.err
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// lockFileName is the lock file kept next to the manifest.
+const lockFileName = "aim.lock"
+
+func (s *service) Lock(ctx context.Context, req LockRequest) (LockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return LockResult{}, err
+	}
+	if s.manifests == nil {
+		return LockResult{}, errors.New("manifest loader is required")
+	}
+	if s.lockFiles == nil {
+		return LockResult{}, errors.New("lock file store is required")
+	}
+	if err := s.requireGitHubDownloads(); err != nil {
+		return LockResult{}, err
+	}
+
+	refreshID := domain.Slugify(strings.TrimSpace(req.ID))
+	if refreshID != "" && !req.Update {
+		return LockResult{}, errors.New("an app ID requires --update")
+	}
+
+	location := strings.TrimSpace(req.Manifest)
+	if location == "" {
+		location = s.config.ManifestFile
+	}
+	lockPath, err := lockFilePath(location)
+	if err != nil {
+		return LockResult{}, err
+	}
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	manifest, err := s.manifests.Load(ctx, location)
+	if err != nil {
+		return LockResult{}, err
+	}
+	entries, err := validateManifest(manifest)
+	if err != nil {
+		return LockResult{}, fmt.Errorf("invalid manifest %s: %w", location, err)
+	}
+	if refreshID != "" && !manifestDeclares(entries, refreshID) {
+		return LockResult{}, fmt.Errorf("app %s is not declared in %s", refreshID, location)
+	}
+
+	existing := make(map[string]LockedApp)
+	lock, err := s.lockFiles.Read(ctx, lockPath)
+	switch {
+	case errors.Is(err, ErrLockFileNotFound):
+	case err != nil:
+		return LockResult{}, err
+	default:
+		for _, locked := range lock.Apps {
+			existing[locked.ID] = locked
+		}
+	}
+
+	result := LockResult{Path: lockPath, Apps: make([]LockedApp, 0, len(entries)), Changed: make([]string, 0)}
+	for _, entry := range entries {
+		locked, ok := existing[entry.ID]
+		refresh := req.Update && (refreshID == "" || refreshID == entry.ID)
+		if ok && !refresh && lockMatchesEntry(locked, entry) {
+			result.Apps = append(result.Apps, locked)
+			continue
+		}
+
+		resolved, err := s.resolveLockedApp(ctx, entry, activity)
+		if err != nil {
+			return LockResult{}, fmt.Errorf("lock %s: %w", entry.ID, err)
+		}
+		result.Apps = append(result.Apps, resolved)
+		if !ok || resolved != locked {
+			result.Changed = append(result.Changed, entry.ID)
+		}
+	}
+
+	if err := s.lockFiles.Write(ctx, lockPath, LockFile{Apps: result.Apps}); err != nil {
+		return LockResult{}, err
+	}
+	return result, nil
+}
+
+// resolveLockedApp finds the artifact a manifest entry currently resolves to.
+// The digest published by GitHub is used when available; otherwise the
+// artifact is downloaded and hashed.
+func (s *service) resolveLockedApp(ctx context.Context, entry ManifestApp, activity ActivityReporter) (LockedApp, error) {
+	locked := LockedApp{ID: entry.ID, GitHubRepo: entry.GitHubRepo, URL: entry.URL}
+	source := DownloadSource{URL: entry.URL, FileName: "download.AppImage"}
+
+	if entry.GitHubRepo != "" {
+		check := activity.Start(ctx, Activity{Kind: ActivityKindCheckingGitHub, AppID: entry.ID, Repo: entry.GitHubRepo})
+		var release GitHubRelease
+		var err error
+		if entry.Version != "" {
+			release, err = s.githubReleases.ReleaseByTag(ctx, entry.GitHubRepo, entry.Version)
+		} else {
+			release, err = s.githubReleases.LatestRelease(ctx, entry.GitHubRepo, entry.Prerelease)
+		}
+		if err != nil {
+			check.Fail(err)
+			return LockedApp{}, err
+		}
+		check.Done("Checked " + entry.GitHubRepo)
+
+		var asset GitHubReleaseAsset
+		if entry.AssetPattern != "" {
+			asset, err = selectGitHubAppImageAssetMatchingPattern(release, entry.AssetPattern)
+		} else {
+			asset, err = selectGitHubAppImageAsset(release)
+		}
+		if err != nil {
+			return LockedApp{}, err
+		}
+
+		locked.Tag = release.TagName
+		locked.AssetName = asset.Name
+		locked.URL = asset.DownloadURL
+		locked.SizeBytes = asset.SizeBytes
+		locked.SHA256 = strings.ToLower(asset.SHA256)
+		if locked.SHA256 != "" {
+			return locked, nil
+		}
+		source = DownloadSource{URL: asset.DownloadURL, FileName: asset.Name, SizeBytes: asset.SizeBytes}
+	} else if base := filepath.Base(entry.URL); base != "." && base != "/" {
+		source.FileName = base
+	}
+
+	workspacePath, cleanup, err := createWorkspace(ctx)
+	if err != nil {
+		return LockedApp{}, err
+	}
+	defer cleanup()
+
+	downloaded, err := s.downloadAsset(ctx, activity, source, workspacePath, entry.ID, entry.GitHubRepo)
+	if err != nil {
+		return LockedApp{}, err
+	}
+	if downloaded.SHA256 == "" {
+		return LockedApp{}, fmt.Errorf("download %s: no sha256 digest reported", source.FileName)
+	}
+	locked.SizeBytes = downloaded.SizeBytes
+	locked.SHA256 = strings.ToLower(downloaded.SHA256)
+	return locked, nil
+}
+
+// readLocksForSync loads the lock file for a manifest and requires an
+// up-to-date entry for every declared app.
+func (s *service) readLocksForSync(ctx context.Context, location string, entries []ManifestApp) (map[string]LockedApp, error) {
+	if s.lockFiles == nil {
+		return nil, errors.New("lock file store is required")
+	}
+	lockPath, err := lockFilePath(location)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := s.lockFiles.Read(ctx, lockPath)
+	if err != nil {
+		if errors.Is(err, ErrLockFileNotFound) {
+			return nil, fmt.Errorf("%w: %s; run aim lock first", ErrLockFileNotFound, lockPath)
+		}
+		return nil, err
+	}
+
+	locks := make(map[string]LockedApp, len(lock.Apps))
+	for _, locked := range lock.Apps {
+		locks[locked.ID] = locked
+	}
+	for _, entry := range entries {
+		locked, ok := locks[entry.ID]
+		if !ok || !lockMatchesEntry(locked, entry) {
+			return nil, fmt.Errorf("app %s is not locked in %s; run aim lock first", entry.ID, lockPath)
+		}
+		if locked.SHA256 == "" {
+			return nil, fmt.Errorf("app %s has no sha256 in %s", entry.ID, lockPath)
+		}
+	}
+	return locks, nil
+}
+
+// lockFilePath returns the lock file path for a local manifest.
+func lockFilePath(manifestLocation string) (string, error) {
+	if manifestLocation == "" {
+		return "", errors.New("manifest location is required")
+	}
+	if strings.HasPrefix(manifestLocation, "https://") || strings.HasPrefix(manifestLocation, "http://") {
+		return "", errors.New("lock files require a local manifest")
+	}
+	return filepath.Join(filepath.Dir(manifestLocation), lockFileName), nil
+}
+
+// lockMatchesEntry reports whether a locked artifact still satisfies its
+// manifest entry.
+func lockMatchesEntry(locked LockedApp, entry ManifestApp) bool {
+	if entry.GitHubRepo == "" {
+		return locked.GitHubRepo == "" && locked.URL == entry.URL
+	}
+	if !strings.EqualFold(locked.GitHubRepo, entry.GitHubRepo) || locked.Tag == "" || locked.AssetName == "" {
+		return false
+	}
+	return entry.Version == "" || locked.Tag == entry.Version
+}
+
+func manifestDeclares(entries []ManifestApp, id string) bool {
+	for _, entry := range entries {
+		if entry.ID == id {
+			return true
+		}
+	}
+	return false
+}
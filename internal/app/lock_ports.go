@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLockFileNotFound reports that no lock file exists at the requested path.
+var ErrLockFileNotFound = errors.New("lock file not found")
+
+// LockFileStore reads and writes the lock file that pins manifest apps to
+// exact artifacts.
+//
+// Implementations belong in infrastructure. Read wraps ErrLockFileNotFound
+// when the file does not exist.
+type LockFileStore interface {
+	Read(ctx context.Context, path string) (LockFile, error)
+	Write(ctx context.Context, path string, lock LockFile) error
+}
+
+// LockFile records the artifact resolved for each manifest app.
+type LockFile struct {
+	Apps []LockedApp
+}
+
+// LockedApp pins one app to a downloadable artifact and its SHA-256 digest.
+// Tag and AssetName are empty for apps declared by URL.
+type LockedApp struct {
+	ID         string `json:"id"`
+	GitHubRepo string `json:"github_repo,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	AssetName  string `json:"asset,omitempty"`
+	URL        string `json:"url"`
+	SizeBytes  int64  `json:"size_bytes"`
+	SHA256     string `json:"sha256"`
+}
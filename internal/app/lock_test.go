@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServiceLockResolvesManifestApps(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	release := testGitHubReleaseWithTag("v2.0.0", "Tracked-2.0.0.AppImage")
+	release.Assets[0].SizeBytes = 42
+	release.Assets[0].SHA256 = "ABC123"
+	deps.GitHubReleases = &fakeGitHubReleaseFinder{release: release}
+	downloads := &fakeAssetDownloader{}
+	deps.Downloads = downloads
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "tracked", GitHubRepo: "owner/tracked", AssetPattern: "Tracked-*.AppImage"},
+		{ID: "example", URL: "https://example.test/dl/Example.AppImage"},
+	}}}
+	locks := &fakeLockFileStore{readErr: ErrLockFileNotFound}
+	deps.LockFiles = locks
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Lock(context.Background(), LockRequest{Manifest: "/team/manifest.toml"})
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	want := []LockedApp{
+		{ID: "tracked", GitHubRepo: "owner/tracked", Tag: "v2.0.0", AssetName: "Tracked-2.0.0.AppImage", URL: "https://example.test/Tracked-2.0.0.AppImage", SizeBytes: 42, SHA256: "abc123"},
+		{ID: "example", URL: "https://example.test/dl/Example.AppImage", SizeBytes: int64(len("appimage")), SHA256: testDownloadSHA256},
+	}
+	if !reflect.DeepEqual(result.Apps, want) {
+		t.Fatalf("Apps = %#v, want %#v", result.Apps, want)
+	}
+	if got, want := result.Path, "/team/aim.lock"; got != want || locks.writePath != want {
+		t.Fatalf("Path = %q, written to %q, want %q", got, locks.writePath, want)
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"tracked", "example"}) {
+		t.Fatalf("Changed = %#v, want both apps", result.Changed)
+	}
+	if got, want := downloads.source.URL, "https://example.test/dl/Example.AppImage"; got != want {
+		t.Fatalf("downloaded %q, want only the URL app without a published digest", got)
+	}
+}
+
+func TestServiceLockKeepsEntriesUnlessUpdated(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	releases := &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.GitHubReleases = releases
+	deps.Downloads = &fakeAssetDownloader{}
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "example", GitHubRepo: "owner/repo"},
+	}}}
+	locked := LockedApp{ID: "example", GitHubRepo: "owner/repo", Tag: "v1.0.0", AssetName: "Example.AppImage", URL: "https://example.test/Example.AppImage", SHA256: "old"}
+	deps.LockFiles = &fakeLockFileStore{lock: LockFile{Apps: []LockedApp{locked}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Lock(context.Background(), LockRequest{Manifest: "manifest.toml"})
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if releases.method != "" || len(result.Changed) != 0 || result.Apps[0] != locked {
+		t.Fatalf("result = %#v, release lookup %q; want existing entry kept", result, releases.method)
+	}
+
+	result, err = service.Lock(context.Background(), LockRequest{Manifest: "manifest.toml", Update: true, ID: "example"})
+	if err != nil {
+		t.Fatalf("Lock(update) error = %v", err)
+	}
+	if got, want := result.Apps[0].Tag, "v2.0.0"; got != want {
+		t.Fatalf("updated Tag = %q, want %q", got, want)
+	}
+	if got, want := result.Apps[0].SHA256, testDownloadSHA256; got != want {
+		t.Fatalf("updated SHA256 = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(result.Changed, []string{"example"}) {
+		t.Fatalf("Changed = %#v, want example", result.Changed)
+	}
+}
+
+func TestServiceLockRejectsRemoteManifest(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.GitHubReleases = &fakeGitHubReleaseFinder{}
+	deps.Downloads = &fakeAssetDownloader{}
+	deps.Manifests = &fakeManifestLoader{}
+	deps.LockFiles = &fakeLockFileStore{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Lock(context.Background(), LockRequest{Manifest: "https://example.test/manifest.toml"})
+	if err == nil || !strings.Contains(err.Error(), "local manifest") {
+		t.Fatalf("Lock() error = %v, want local manifest error", err)
+	}
+}
+
+func TestServiceSyncLockedInstallsLockedArtifact(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	releases := &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.0.0", "Example-x86_64.AppImage", "Example-arm64.AppImage")}
+	deps.GitHubReleases = releases
+	downloads := &fakeAssetDownloader{}
+	deps.Downloads = downloads
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "example", GitHubRepo: "owner/repo"},
+	}}}
+	deps.LockFiles = &fakeLockFileStore{lock: LockFile{Apps: []LockedApp{
+		{ID: "example", GitHubRepo: "owner/repo", Tag: "v1.0.0", AssetName: "Example-arm64.AppImage", URL: "https://example.test/Example-arm64.AppImage", SHA256: testDownloadSHA256},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Locked: true})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Failures) != 0 {
+		t.Fatalf("Failures = %#v, want none", result.Failures)
+	}
+	if releases.method != "tag" || releases.tag != "v1.0.0" {
+		t.Fatalf("release lookup = %s %q, want tag v1.0.0", releases.method, releases.tag)
+	}
+	if got, want := downloads.source.FileName, "Example-arm64.AppImage"; got != want {
+		t.Fatalf("downloaded %q, want %q", got, want)
+	}
+	if got, want := downloads.source.SHA256, testDownloadSHA256; got != want {
+		t.Fatalf("download SHA256 = %q, want %q", got, want)
+	}
+	if got := deps.saved.App.UpdateSource.ReleaseTag; got != "" {
+		t.Fatalf("saved ReleaseTag = %q, want unpinned update source", got)
+	}
+}
+
+func TestServiceSyncLockedRejectsHashMismatch(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.0.0", "Example.AppImage")}
+	deps.Downloads = &fakeAssetDownloader{}
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "example", GitHubRepo: "owner/repo"},
+	}}}
+	deps.LockFiles = &fakeLockFileStore{lock: LockFile{Apps: []LockedApp{
+		{ID: "example", GitHubRepo: "owner/repo", Tag: "v1.0.0", AssetName: "Example.AppImage", SHA256: "deadbeef"},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Locked: true})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Failures) != 1 || !strings.Contains(result.Failures[0].Error, "sha256 mismatch") {
+		t.Fatalf("Failures = %#v, want sha256 mismatch", result.Failures)
+	}
+	if deps.saved.App.ID != "" {
+		t.Fatalf("saved %#v after hash mismatch", deps.saved.App)
+	}
+}
+
+func TestServiceSyncLockedRequiresLockEntries(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "example", GitHubRepo: "owner/repo"},
+	}}}
+	deps.LockFiles = &fakeLockFileStore{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Locked: true, DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "not locked") {
+		t.Fatalf("Sync() error = %v, want not locked error", err)
+	}
+
+	deps.LockFiles.(*fakeLockFileStore).readErr = ErrLockFileNotFound
+	_, err = service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Locked: true, DryRun: true})
+	if !errors.Is(err, ErrLockFileNotFound) {
+		t.Fatalf("Sync() error = %v, want ErrLockFileNotFound", err)
+	}
+}
+
+type fakeLockFileStore struct {
+	lock      LockFile
+	readErr   error
+	writePath string
+	written   LockFile
+}
+
+func (f *fakeLockFileStore) Read(ctx context.Context, path string) (LockFile, error) {
+	if f.readErr != nil {
+		return LockFile{}, f.readErr
+	}
+	return f.lock, nil
+}
+
+func (f *fakeLockFileStore) Write(ctx context.Context, path string, lock LockFile) error {
+	f.writePath = path
+	f.written = lock
+	return nil
+}
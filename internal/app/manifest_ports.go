@@ -0,0 +1,30 @@
+package app
+
+import "context"
+
+// ManifestLoader reads a declarative app manifest.
+//
+// Location is either a filesystem path or an http(s) URL. Implementations
+// belong in infrastructure and only decode the manifest; the sync use case
+// validates entries and plans changes against the app repository.
+type ManifestLoader interface {
+	Load(ctx context.Context, location string) (Manifest, error)
+}
+
+// Manifest lists the apps a workstation should have integrated.
+type Manifest struct {
+	Apps []ManifestApp
+}
+
+// ManifestApp declares one app by ID and either a GitHub repository or a
+// direct AppImage URL.
+type ManifestApp struct {
+	ID           string
+	GitHubRepo   string
+	URL          string
+	AssetPattern string
+	Prerelease   bool
+	// Version pins a GitHub app to a release tag; empty follows the latest
+	// release.
+	Version string
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) Migrate(ctx context.Context, req MigrateRequest) (MigrateResult, error) {
+	if err := ctx.Err(); err != nil {
+		return MigrateResult{}, err
+	}
+
+	tool, err := parseMigrationTool(req.From)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	if s.foreignIntegrations == nil {
+		return MigrateResult{}, errors.New("foreign integration finder is required")
+	}
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	integrations, err := s.foreignIntegrations.Find(ctx, tool)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	candidates := make([]MigrationCandidate, 0, len(integrations))
+	for _, integration := range integrations {
+		candidates = append(candidates, migrationCandidate(integration))
+	}
+	if len(candidates) == 0 {
+		return MigrateResult{Applied: true, Candidates: candidates}, nil
+	}
+
+	if req.Confirmation != nil {
+		confirmed, err := req.Confirmation.ConfirmMigration(ctx, candidates)
+		if err != nil {
+			return MigrateResult{}, err
+		}
+		if !confirmed {
+			return MigrateResult{Applied: false, Candidates: candidates}, nil
+		}
+	}
+
+	migrated := make([]MigratedApp, 0, len(integrations))
+	failures := make([]MigrationFailure, 0)
+	for _, integration := range integrations {
+		result, err := s.migrateIntegration(ctx, integration, req, activity)
+		if err != nil {
+			if ctx.Err() != nil {
+				return MigrateResult{}, err
+			}
+			failures = append(failures, MigrationFailure{AppImagePath: integration.AppImagePath, Error: err.Error()})
+			continue
+		}
+		migrated = append(migrated, MigratedApp{
+			ID:           result.App.ID,
+			Name:         result.App.Name,
+			AppImagePath: result.App.AppImagePath,
+			Moved:        result.Moved,
+		})
+	}
+
+	if len(migrated) > 0 && s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+
+	return MigrateResult{Applied: true, Candidates: candidates, Migrated: migrated, Failures: failures}, nil
+}
+
+// migrateIntegration adopts the AppImage of a foreign integration and then
+// removes the other tool's desktop entry and icons so the app is not listed
+// twice.
+func (s *service) migrateIntegration(ctx context.Context, integration ForeignIntegration, req MigrateRequest, activity ActivityReporter) (AdoptResult, error) {
+	if strings.TrimSpace(integration.AppImagePath) == "" {
+		return AdoptResult{}, errors.New("appimage path is required")
+	}
+
+	result, err := s.adopt(ctx, filepath.Clean(integration.AppImagePath), req.Move, activity)
+	if err != nil {
+		return AdoptResult{}, err
+	}
+	if req.KeepArtifacts {
+		return result, nil
+	}
+
+	artifacts := append([]string{integration.DesktopEntryPath}, integration.IconPaths...)
+	for _, path := range artifacts {
+		if err := removeInstalledArtifact(ctx, path, s.artifactRemover); err != nil {
+			return AdoptResult{}, fmt.Errorf("migrated %s but failed to remove %s artifacts: %w", result.App.ID, integration.Tool, err)
+		}
+	}
+
+	return result, nil
+}
+
+func migrationCandidate(integration ForeignIntegration) MigrationCandidate {
+	candidate := MigrationCandidate{
+		Tool:             string(integration.Tool),
+		Name:             filepath.Base(integration.AppImagePath),
+		AppImagePath:     integration.AppImagePath,
+		DesktopEntryPath: integration.DesktopEntryPath,
+		IconPaths:        integration.IconPaths,
+	}
+
+	entry, err := domain.ParseDesktopEntry(integration.DesktopEntry)
+	if err != nil {
+		return candidate
+	}
+	if entry.Name != "" {
+		candidate.Name = entry.Name
+	}
+	candidate.Version = entry.Version.String()
+	return candidate
+}
+
+func parseMigrationTool(value string) (MigrationTool, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", errors.New("migration source is required")
+	}
+
+	names := make([]string, 0, len(MigrationTools))
+	for _, tool := range MigrationTools {
+		if string(tool) == value {
+			return tool, nil
+		}
+		names = append(names, string(tool))
+	}
+
+	return "", fmt.Errorf("unsupported migration source %q (supported: %s)", value, strings.Join(names, ", "))
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceMigrateAdoptsAppImagesAndRemovesForeignArtifacts(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	path := testAppImagePath(t, "Example_abc123.AppImage")
+	finder := &fakeForeignIntegrationFinder{found: []ForeignIntegration{{
+		Tool:             MigrationToolAppImageLauncher,
+		DesktopEntryPath: "/desktop/appimagekit_abc123-Example.desktop",
+		DesktopEntry:     []byte("[Desktop Entry]\nName=Example\nX-AppImage-Version=1.0.0\n"),
+		AppImagePath:     path,
+		IconPaths:        []string{"/icons/hicolor/256x256/apps/appimagekit_abc123_example.png"},
+	}}}
+	deps.ForeignIntegrations = finder
+	confirmation := &fakeMigrationConfirmation{confirmed: true}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Migrate(context.Background(), MigrateRequest{From: " AppImageLauncher ", Confirmation: confirmation})
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if got, want := finder.tool, MigrationToolAppImageLauncher; got != want {
+		t.Fatalf("finder tool = %q, want %q", got, want)
+	}
+	if len(confirmation.candidates) != 1 || confirmation.candidates[0].Name != "Example" || confirmation.candidates[0].Version != "1.0.0" {
+		t.Fatalf("confirmed candidates = %#v, want Example 1.0.0", confirmation.candidates)
+	}
+	if !result.Applied || len(result.Migrated) != 1 || len(result.Failures) != 0 {
+		t.Fatalf("result = %#v, want one migrated app", result)
+	}
+	if got, want := deps.saved.App.AppImagePath, path; got != want {
+		t.Fatalf("saved AppImagePath = %q, want %q", got, want)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{
+		"/desktop/appimagekit_abc123-Example.desktop",
+		"/icons/hicolor/256x256/apps/appimagekit_abc123_example.png",
+	})
+}
+
+func TestServiceMigrateKeepsForeignArtifactsWhenRequested(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	path := testAppImagePath(t, "Example.AppImage")
+	deps.ForeignIntegrations = &fakeForeignIntegrationFinder{found: []ForeignIntegration{{
+		Tool:             MigrationToolGearLever,
+		DesktopEntryPath: "/desktop/gearlever_example_abc.desktop",
+		AppImagePath:     path,
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Migrate(context.Background(), MigrateRequest{From: "gearlever", Move: true, KeepArtifacts: true}); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{path})
+}
+
+func TestServiceMigrateDoesNotApplyWhenConfirmationRejects(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.ForeignIntegrations = &fakeForeignIntegrationFinder{found: []ForeignIntegration{{
+		Tool:             MigrationToolAppImaged,
+		DesktopEntryPath: "/desktop/appimagekit_abc.desktop",
+		AppImagePath:     "/home/user/Applications/Example.AppImage",
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Migrate(context.Background(), MigrateRequest{From: "appimaged", Confirmation: &fakeMigrationConfirmation{}})
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if result.Applied || len(result.Candidates) != 1 {
+		t.Fatalf("result = %#v, want unapplied candidate", result)
+	}
+	if deps.appImages.appImagePath != "" || len(deps.artifactRemover.paths) != 0 {
+		t.Fatal("migration touched files after confirmation was rejected")
+	}
+}
+
+func TestServiceMigrateRecordsFailuresAndContinues(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.listApps = []domain.App{installed}
+	path := testAppImagePath(t, "Other.AppImage")
+	deps.ForeignIntegrations = &fakeForeignIntegrationFinder{found: []ForeignIntegration{
+		{Tool: MigrationToolAppImaged, DesktopEntryPath: "/desktop/appimagekit_1.desktop", AppImagePath: installed.AppImagePath},
+		{Tool: MigrationToolAppImaged, DesktopEntryPath: "/desktop/appimagekit_2.desktop", AppImagePath: path},
+	}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Migrate(context.Background(), MigrateRequest{From: "appimaged"})
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].AppImagePath != installed.AppImagePath {
+		t.Fatalf("Failures = %#v, want already managed failure", result.Failures)
+	}
+	if len(result.Migrated) != 1 || result.Migrated[0].AppImagePath != path {
+		t.Fatalf("Migrated = %#v, want %s", result.Migrated, path)
+	}
+}
+
+func TestServiceMigrateValidatesSource(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	finder := &fakeForeignIntegrationFinder{}
+	deps.ForeignIntegrations = finder
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, from := range []string{"", "snapd"} {
+		if _, err := service.Migrate(context.Background(), MigrateRequest{From: from}); err == nil {
+			t.Fatalf("Migrate(%q) error = nil, want validation error", from)
+		}
+	}
+	if finder.tool != "" {
+		t.Fatal("finder was called for an invalid source")
+	}
+}
+
+func TestServiceMigrateReturnsFinderFailure(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	wantErr := errors.New("read applications")
+	deps.ForeignIntegrations = &fakeForeignIntegrationFinder{err: wantErr}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Migrate(context.Background(), MigrateRequest{From: "gearlever"}); !errors.Is(err, wantErr) {
+		t.Fatalf("Migrate() error = %v, want %v", err, wantErr)
+	}
+}
+
+type fakeForeignIntegrationFinder struct {
+	tool  MigrationTool
+	found []ForeignIntegration
+	err   error
+}
+
+func (f *fakeForeignIntegrationFinder) Find(ctx context.Context, tool MigrationTool) ([]ForeignIntegration, error) {
+	f.tool = tool
+	return f.found, f.err
+}
+
+type fakeMigrationConfirmation struct {
+	candidates []MigrationCandidate
+	confirmed  bool
+}
+
+func (f *fakeMigrationConfirmation) ConfirmMigration(ctx context.Context, candidates []MigrationCandidate) (bool, error) {
+	f.candidates = candidates
+	return f.confirmed, nil
+}
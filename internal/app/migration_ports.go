@@ -0,0 +1,38 @@
+package app
+
+import "context"
+
+// MigrationTool identifies another AppImage integration tool whose desktop
+// integration aim can take over.
+type MigrationTool string
+
+const (
+	MigrationToolAppImageLauncher MigrationTool = "appimagelauncher"
+	MigrationToolGearLever        MigrationTool = "gearlever"
+	MigrationToolAppImaged        MigrationTool = "appimaged"
+)
+
+// MigrationTools lists the supported migration sources.
+var MigrationTools = []MigrationTool{
+	MigrationToolAppImageLauncher,
+	MigrationToolGearLever,
+	MigrationToolAppImaged,
+}
+
+// ForeignIntegrationFinder finds AppImages integrated by another tool.
+//
+// Implementations belong in infrastructure. They report the AppImage location
+// and every artifact the other tool created so the app layer can decide what
+// to take over and what to remove.
+type ForeignIntegrationFinder interface {
+	Find(ctx context.Context, tool MigrationTool) ([]ForeignIntegration, error)
+}
+
+// ForeignIntegration is an AppImage integrated by another tool.
+type ForeignIntegration struct {
+	Tool             MigrationTool
+	DesktopEntryPath string
+	DesktopEntry     []byte
+	AppImagePath     string
+	IconPaths        []string
+}
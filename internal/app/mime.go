@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) SetDefault(ctx context.Context, req SetDefaultRequest) (SetDefaultResult, error) {
+	if err := ctx.Err(); err != nil {
+		return SetDefaultResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return SetDefaultResult{}, errors.New("app id is required")
+	}
+	var mimeTypes []string
+	for _, mimeType := range req.MimeTypes {
+		mimeType = strings.TrimSpace(mimeType)
+		if !domain.ValidMimeType(mimeType) {
+			return SetDefaultResult{}, fmt.Errorf("invalid MIME type %q; use type/subtype, such as text/html or x-scheme-handler/https", mimeType)
+		}
+		if !slices.Contains(mimeTypes, mimeType) {
+			mimeTypes = append(mimeTypes, mimeType)
+		}
+	}
+	if s.mimeAssociations == nil {
+		return SetDefaultResult{}, errors.New("mime associations are required")
+	}
+
+	installedApp, err := s.apps.Find(ctx, id)
+	if err != nil {
+		return SetDefaultResult{}, err
+	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return SetDefaultResult{}, err
+	}
+	defer unlock()
+
+	if strings.TrimSpace(installedApp.DesktopEntryPath) == "" {
+		return SetDefaultResult{}, fmt.Errorf("%s has no desktop entry; run aim repair %s", installedApp.ID, installedApp.ID)
+	}
+	if len(mimeTypes) == 0 {
+		metadata, err := s.inspectInstalledAppImageForID(ctx, installedApp.AppImagePath)
+		if err != nil {
+			return SetDefaultResult{}, err
+		}
+		for _, mimeType := range metadata.desktopEntry.MimeTypes() {
+			if domain.ValidMimeType(mimeType) && !slices.Contains(mimeTypes, mimeType) {
+				mimeTypes = append(mimeTypes, mimeType)
+			}
+		}
+		if len(mimeTypes) == 0 {
+			return SetDefaultResult{}, fmt.Errorf("the desktop entry of %s lists no MIME types; pass them with --mime", installedApp.ID)
+		}
+	}
+
+	desktopFile := filepath.Base(installedApp.DesktopEntryPath)
+	err = s.mimeAssociations.SetDefault(ctx, desktopFile, mimeTypes)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationSetDefault,
+		AppID:      installedApp.ID,
+		OldVersion: installedApp.Version.String(),
+		NewVersion: installedApp.Version.String(),
+		Source:     strings.Join(mimeTypes, ";"),
+	}, err)
+	if err != nil {
+		return SetDefaultResult{}, err
+	}
+
+	return SetDefaultResult{ID: installedApp.ID, DesktopFile: desktopFile, MimeTypes: mimeTypes}, nil
+}
+
+// discoverMimePackages returns the MIME packages bundled in the AppImage
+// extracted at rootDir, or none when MIME registration is not configured.
+func (s *service) discoverMimePackages(ctx context.Context, rootDir string) ([]string, error) {
+	if s.mimePackages == nil || s.mimePackageInstaller == nil {
+		return nil, nil
+	}
+	return s.mimePackages.Discover(ctx, rootDir)
+}
+
+// installMimePackages installs sourcePaths as the MIME packages of appID.
+func (s *service) installMimePackages(ctx context.Context, appID string, sourcePaths []string) ([]string, error) {
+	if len(sourcePaths) == 0 || s.mimePackageInstaller == nil {
+		return nil, nil
+	}
+	return s.mimePackageInstaller.Install(ctx, appID, sourcePaths)
+}
+
+// renameMimeAssociations moves the default applications and associations of
+// previous to next after its desktop entry was renamed.
+func (s *service) renameMimeAssociations(ctx context.Context, previous domain.App, next domain.App) error {
+	if s.mimeAssociations == nil || previous.DesktopEntryPath == "" || next.DesktopEntryPath == "" {
+		return nil
+	}
+	oldDesktopFile, newDesktopFile := filepath.Base(previous.DesktopEntryPath), filepath.Base(next.DesktopEntryPath)
+	if oldDesktopFile == newDesktopFile {
+		return nil
+	}
+	return s.mimeAssociations.Rename(ctx, oldDesktopFile, newDesktopFile)
+}
+
+// forgetMimeAssociations removes a removed app from the default applications
+// and associations.
+func (s *service) forgetMimeAssociations(ctx context.Context, removed domain.App) error {
+	if s.mimeAssociations == nil || removed.DesktopEntryPath == "" {
+		return nil
+	}
+	return s.mimeAssociations.Forget(ctx, filepath.Base(removed.DesktopEntryPath))
+}
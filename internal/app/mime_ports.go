@@ -0,0 +1,25 @@
+package app
+
+import "context"
+
+// MimePackageDiscoverer finds the shared-mime-info packages an extracted
+// AppImage bundles in usr/share/mime/packages.
+type MimePackageDiscoverer interface {
+	Discover(ctx context.Context, rootDir string) ([]string, error)
+}
+
+// MimePackageInstaller installs MIME packages into the user's shared MIME
+// database. Install returns the installed paths in the order of sourcePaths;
+// the database itself is rebuilt by the DesktopIntegrationRefresher.
+type MimePackageInstaller interface {
+	Install(ctx context.Context, appID string, sourcePaths []string) ([]string, error)
+}
+
+// MimeAssociations edits the user's default applications for MIME types,
+// including x-scheme-handler/* URL schemes. Desktop files are given by file
+// name, such as example.desktop.
+type MimeAssociations interface {
+	SetDefault(ctx context.Context, desktopFile string, mimeTypes []string) error
+	Rename(ctx context.Context, oldDesktopFile string, newDesktopFile string) error
+	Forget(ctx context.Context, desktopFile string) error
+}
@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceAddInstallsMimePackages(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	packages := &fakeMimePackageInstaller{dir: "/mime/packages"}
+	deps.MimePackages = &fakeMimePackageDiscoverer{paths: []string{"/extracted/usr/share/mime/packages/example.xml"}}
+	deps.MimePackageInstaller = packages
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	want := []string{"/mime/packages/example-app.xml"}
+	if !reflect.DeepEqual(result.App.MimePackagePaths, want) || !reflect.DeepEqual(deps.saved.App.MimePackagePaths, want) {
+		t.Fatalf("MimePackagePaths = %q, saved %q, want %q", result.App.MimePackagePaths, deps.saved.App.MimePackagePaths, want)
+	}
+	if !reflect.DeepEqual(packages.sources, []string{"/extracted/usr/share/mime/packages/example.xml"}) {
+		t.Fatalf("installed sources = %q", packages.sources)
+	}
+	if !deps.desktopIntegrationRefresher.called {
+		t.Fatal("desktop integration refresher was not called")
+	}
+}
+
+func TestServiceRemoveRemovesMimePackagesAndAssociations(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.MimePackagePaths = []string{"/mime/packages/example-app.xml"}
+	deps.apps.findApp = installed
+	associations := &fakeMimeAssociations{}
+	deps.MimeAssociations = associations
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/mime/packages/example-app.xml", installed.DesktopEntryPath, installed.IconPath, installed.AppImagePath})
+	if associations.forgotten != "example-app.desktop" {
+		t.Fatalf("forgotten desktop file = %q, want example-app.desktop", associations.forgotten)
+	}
+	if !deps.desktopIntegrationRefresher.called {
+		t.Fatal("desktop integration refresher was not called")
+	}
+}
+
+func TestServiceSetIDMovesMimePackagesAndAssociations(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.MimePackagePaths = []string{"/mime/packages/example-app.xml"}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.desktopEntryInstaller.path = "/desktop/custom-id.desktop"
+	packages := &fakeMimePackageInstaller{dir: "/mime/packages"}
+	deps.MimePackages = &fakeMimePackageDiscoverer{}
+	deps.MimePackageInstaller = packages
+	associations := &fakeMimeAssociations{}
+	deps.MimeAssociations = associations
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetID(context.Background(), SetIDRequest{CurrentID: installed.ID, NewID: "custom-id"})
+	if err != nil {
+		t.Fatalf("SetID() error = %v", err)
+	}
+
+	if want := []string{"/mime/packages/custom-id.xml"}; !reflect.DeepEqual(result.App.MimePackagePaths, want) {
+		t.Fatalf("MimePackagePaths = %q, want %q", result.App.MimePackagePaths, want)
+	}
+	if !slices.Contains(deps.artifactRemover.paths, "/mime/packages/example-app.xml") {
+		t.Fatalf("removed paths = %q, want previous MIME package removed", deps.artifactRemover.paths)
+	}
+	if want := [2]string{"example-app.desktop", "custom-id.desktop"}; associations.renamed != want {
+		t.Fatalf("renamed = %q, want %q", associations.renamed, want)
+	}
+}
+
+func TestServiceUpdateReinstallsMimePackages(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.MimePackagePaths = []string{"/mime/packages/example-app.xml", "/mime/packages/example-app.2.xml"}
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	deps.MimePackages = &fakeMimePackageDiscoverer{paths: []string{"/extracted/usr/share/mime/packages/example.xml"}}
+	deps.MimePackageInstaller = &fakeMimePackageInstaller{dir: "/mime/packages"}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if want := []string{"/mime/packages/example-app.xml"}; !reflect.DeepEqual(deps.saved.App.MimePackagePaths, want) {
+		t.Fatalf("saved MimePackagePaths = %q, want %q", deps.saved.App.MimePackagePaths, want)
+	}
+	removed := strings.Join(deps.artifactRemover.paths, " ")
+	for _, path := range []string{"/mime/packages/example-app-2-0-0.xml", "/mime/packages/example-app.2.xml"} {
+		if !strings.Contains(removed, path) {
+			t.Fatalf("removed paths = %q, want %s removed", deps.artifactRemover.paths, path)
+		}
+	}
+	if slices.Contains(deps.artifactRemover.paths, "/mime/packages/example-app.xml") {
+		t.Fatalf("removed paths = %q, want updated MIME package kept", deps.artifactRemover.paths)
+	}
+}
+
+func TestServiceSetDefaultUsesGivenMimeTypes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	associations := &fakeMimeAssociations{}
+	deps.MimeAssociations = associations
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetDefault(context.Background(), SetDefaultRequest{ID: installed.ID, MimeTypes: []string{"text/html", " x-scheme-handler/https ", "text/html"}})
+	if err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+
+	want := []string{"text/html", "x-scheme-handler/https"}
+	if !reflect.DeepEqual(result.MimeTypes, want) || !reflect.DeepEqual(associations.mimeTypes, want) {
+		t.Fatalf("result = %#v, set %q, want %q", result, associations.mimeTypes, want)
+	}
+	if result.DesktopFile != "example-app.desktop" || associations.desktopFile != "example-app.desktop" {
+		t.Fatalf("desktop file = %q, set %q, want example-app.desktop", result.DesktopFile, associations.desktopFile)
+	}
+	if deps.appImages.appImagePath != "" {
+		t.Fatalf("extracted %q, want no extraction with explicit MIME types", deps.appImages.appImagePath)
+	}
+}
+
+func TestServiceSetDefaultUsesDesktopEntryMimeTypes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.desktopEntries.content = []byte("[Desktop Entry]\nName=Example App\nExec=example %U\nMimeType=text/html;x-scheme-handler/http;\n")
+	associations := &fakeMimeAssociations{}
+	deps.MimeAssociations = associations
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.SetDefault(context.Background(), SetDefaultRequest{ID: installed.ID}); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+
+	if want := []string{"text/html", "x-scheme-handler/http"}; !reflect.DeepEqual(associations.mimeTypes, want) {
+		t.Fatalf("set %q, want %q", associations.mimeTypes, want)
+	}
+}
+
+func TestServiceSetDefaultRejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	associations := &fakeMimeAssociations{}
+	deps.MimeAssociations = associations
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, req := range []SetDefaultRequest{
+		{MimeTypes: []string{"text/html"}},
+		{ID: installed.ID, MimeTypes: []string{"html"}},
+		// The default desktop entry lists no MIME types.
+		{ID: installed.ID},
+	} {
+		if _, err := service.SetDefault(context.Background(), req); err == nil {
+			t.Fatalf("SetDefault(%#v) error = nil, want error", req)
+		}
+	}
+	if associations.desktopFile != "" {
+		t.Fatalf("SetDefault called with %q for invalid requests", associations.desktopFile)
+	}
+}
+
+type fakeMimePackageDiscoverer struct {
+	paths []string
+}
+
+func (f *fakeMimePackageDiscoverer) Discover(ctx context.Context, rootDir string) ([]string, error) {
+	return f.paths, nil
+}
+
+type fakeMimePackageInstaller struct {
+	dir     string
+	sources []string
+}
+
+func (f *fakeMimePackageInstaller) Install(ctx context.Context, appID string, sourcePaths []string) ([]string, error) {
+	f.sources = sourcePaths
+	installed := make([]string, len(sourcePaths))
+	for i := range sourcePaths {
+		installed[i] = filepath.Join(f.dir, appID+".xml")
+		if i > 0 {
+			installed[i] = filepath.Join(f.dir, fmt.Sprintf("%s.%d.xml", appID, i+1))
+		}
+	}
+	return installed, nil
+}
+
+type fakeMimeAssociations struct {
+	desktopFile string
+	mimeTypes   []string
+	renamed     [2]string
+	forgotten   string
+}
+
+func (f *fakeMimeAssociations) SetDefault(ctx context.Context, desktopFile string, mimeTypes []string) error {
+	f.desktopFile = desktopFile
+	f.mimeTypes = mimeTypes
+	return nil
+}
+
+func (f *fakeMimeAssociations) Rename(ctx context.Context, oldDesktopFile string, newDesktopFile string) error {
+	f.renamed = [2]string{oldDesktopFile, newDesktopFile}
+	return nil
+}
+
+func (f *fakeMimeAssociations) Forget(ctx context.Context, desktopFile string) error {
+	f.forgotten = desktopFile
+	return nil
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// notificationTimeout bounds showing a notification that is sent in the
+// background, so a notification daemon that hangs cannot stall aim.
+const notificationTimeout = 5 * time.Second
+
+// notifyAvailableUpdates shows the updates found by a check. Nothing is shown
+// when every app is up to date.
+func (s *service) notifyAvailableUpdates(ctx context.Context, candidates []UpdateCandidate) error {
+	if s.notifier == nil {
+		return errors.New("notifier is required")
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	summary := "1 app update available"
+	if len(candidates) > 1 {
+		summary = fmt.Sprintf("%d app updates available", len(candidates))
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationTimeout)
+	defer cancel()
+	if err := s.notifier.Notify(ctx, Notification{
+		Summary: summary,
+		Body:    describeUpdateCandidates(candidates),
+		Urgency: NotificationUrgencyNormal,
+	}); err != nil {
+		return fmt.Errorf("notify available updates: %w", err)
+	}
+
+	return nil
+}
+
+// notifyAppliedUpdates summarizes the updates applied by a run and the apps
+// that failed to update. The updates are already done at this point, so a
+// notification that cannot be shown is not an error.
+func (s *service) notifyAppliedUpdates(ctx context.Context, candidates []UpdateCandidate, failures []UpdateFailure) {
+	if s.notifier == nil {
+		return
+	}
+
+	failed := make(map[string]bool, len(failures))
+	for _, failure := range failures {
+		failed[failure.AppID] = true
+	}
+	applied := make([]UpdateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !failed[candidate.ID] {
+			applied = append(applied, candidate)
+		}
+	}
+	if len(applied) == 0 && len(failures) == 0 {
+		return
+	}
+
+	var summary []string
+	switch len(applied) {
+	case 0:
+	case 1:
+		summary = append(summary, "Updated 1 app")
+	default:
+		summary = append(summary, fmt.Sprintf("Updated %d apps", len(applied)))
+	}
+	switch len(failures) {
+	case 0:
+	case 1:
+		summary = append(summary, "1 app failed to update")
+	default:
+		summary = append(summary, fmt.Sprintf("%d apps failed to update", len(failures)))
+	}
+
+	body := describeUpdateCandidates(applied)
+	for _, failure := range failures {
+		if body != "" {
+			body += "\n"
+		}
+		body += fmt.Sprintf("%s: %s", failure.AppID, failure.Error)
+	}
+
+	urgency := NotificationUrgencyNormal
+	if len(failures) > 0 {
+		urgency = NotificationUrgencyCritical
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationTimeout)
+	defer cancel()
+	_ = s.notifier.Notify(ctx, Notification{
+		Summary: strings.Join(summary, ", "),
+		Body:    body,
+		Urgency: urgency,
+	})
+}
+
+func describeUpdateCandidates(candidates []UpdateCandidate) string {
+	lines := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		lines = append(lines, fmt.Sprintf("%s %s → %s", candidate.ID, candidate.CurrentVersion, candidate.NewVersion))
+	}
+	return strings.Join(lines, "\n")
+}
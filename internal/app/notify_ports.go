@@ -0,0 +1,37 @@
+package app
+
+import "context"
+
+// Notifier shows desktop notifications.
+//
+// Implementations belong in infrastructure. Notify returns an error when the
+// notification could not be delivered, for example because no notification
+// server is reachable. Ask shows notification with action buttons and waits
+// until the user invokes one, returning its key, or dismisses the
+// notification, returning an empty key.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+	Ask(ctx context.Context, notification Notification, actions []NotificationAction) (string, error)
+}
+
+type NotificationUrgency byte
+
+const (
+	NotificationUrgencyLow NotificationUrgency = iota
+	NotificationUrgencyNormal
+	NotificationUrgencyCritical
+)
+
+// Notification is a short desktop notification. Body may span several lines.
+type Notification struct {
+	Summary string
+	Body    string
+	Urgency NotificationUrgency
+}
+
+// NotificationAction is a button on a notification. Key identifies the action
+// to the caller and Label is shown to the user.
+type NotificationAction struct {
+	Key   string
+	Label string
+}
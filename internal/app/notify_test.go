@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceUpdateCheckOnlyNotifiesAvailableUpdates(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	notifier := &fakeNotifier{}
+	deps.Notifier = notifier
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{CheckOnly: true, Notify: true}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	want := []Notification{{
+		Summary: "1 app update available",
+		Body:    "example-app 1.2.3 → 2.0.0",
+		Urgency: NotificationUrgencyNormal,
+	}}
+	assertNotifications(t, notifier.notifications, want)
+	if !notifier.deadlines[0] {
+		t.Fatal("notification has no deadline, want one so a hung daemon cannot stall the check")
+	}
+}
+
+func TestServiceUpdateCheckOnlyDoesNotNotifyWhenUpToDate(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	notifier := &fakeNotifier{}
+	deps.Notifier = notifier
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.2.3", "Example.AppImage")}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{CheckOnly: true, Notify: true}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	assertNotifications(t, notifier.notifications, nil)
+}
+
+func TestServiceUpdateCheckOnlyReturnsNotificationFailure(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Notifier = &fakeNotifier{err: errors.New("no session bus")}
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Update(context.Background(), UpdateRequest{CheckOnly: true, Notify: true})
+	if err == nil || !strings.Contains(err.Error(), "notify available updates: no session bus") {
+		t.Fatalf("Update() error = %v, want notification failure", err)
+	}
+}
+
+func TestServiceUpdateNotifiesAppliedUpdatesAndFailures(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	notifier := &fakeNotifier{err: errors.New("no session bus")}
+	deps.Notifier = notifier
+	broken := testInstalledApp(t)
+	broken.ID = "localsend"
+	broken.UpdateSource = domain.NewGitHubUpdateSource("owner/localsend", false)
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/example", false)
+	deps.apps.listApps = []domain.App{broken, installed}
+	deps.desktopEntries.content = []byte("[Desktop Entry]\nName=Example App\nExec=old-exec\nIcon=example-icon\n")
+	configureUpdateArtifactPaths(&deps, installed.ID, "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{
+		releases: map[string]GitHubRelease{
+			"owner/localsend": testGitHubReleaseWithTag("v2.0.0", "LocalSend.deb"),
+			"owner/example":   testGitHubReleaseWithTag("v2.0.0", "Example.AppImage"),
+		},
+	}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{Notify: true, Confirmation: &fakeUpdateConfirmation{confirmed: true}})
+	if err != nil {
+		t.Fatalf("Update() error = %v, want notification failure ignored", err)
+	}
+	if len(result.Failures) != 1 {
+		t.Fatalf("Update().Failures = %#v, want one failure", result.Failures)
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("notifications = %#v, want one", notifier.notifications)
+	}
+	got := notifier.notifications[0]
+	if got.Summary != "Updated 1 app, 1 app failed to update" || got.Urgency != NotificationUrgencyCritical {
+		t.Fatalf("notification = %#v", got)
+	}
+	if !strings.HasPrefix(got.Body, "example-app 1.2.3 → 2.0.0\nlocalsend: ") {
+		t.Fatalf("notification body = %q", got.Body)
+	}
+	if !notifier.deadlines[0] {
+		t.Fatal("notification has no deadline, want one so a hung daemon cannot stall the update")
+	}
+}
+
+func TestServiceUpdateDoesNotNotifyWhenNothingApplied(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	notifier := &fakeNotifier{}
+	deps.Notifier = notifier
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{Notify: true, Confirmation: &fakeUpdateConfirmation{confirmed: false}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if result.Applied {
+		t.Fatal("Update().Applied = true, want false")
+	}
+	assertNotifications(t, notifier.notifications, nil)
+}
+
+func TestServiceUpdateDoesNotNotifyUnlessAsked(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	notifier := &fakeNotifier{}
+	deps.Notifier = notifier
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	deps.desktopEntries.content = []byte("[Desktop Entry]\nName=Example App\nExec=old-exec\nIcon=example-icon\n")
+	configureUpdateArtifactPaths(&deps, installed.ID, "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{Confirmation: &fakeUpdateConfirmation{confirmed: true}})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !result.Applied || len(result.Updates) != 1 {
+		t.Fatalf("Update() = %#v, want one applied update", result)
+	}
+	assertNotifications(t, notifier.notifications, nil)
+}
+
+type fakeNotifier struct {
+	mu            sync.Mutex
+	notifications []Notification
+	// deadlines records whether each notification had a deadline.
+	deadlines []bool
+	actions   [][]NotificationAction
+	answer    string
+	err       error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, notification Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.notifications = append(f.notifications, notification)
+	_, ok := ctx.Deadline()
+	f.deadlines = append(f.deadlines, ok)
+	return f.err
+}
+
+func (f *fakeNotifier) Ask(ctx context.Context, notification Notification, actions []NotificationAction) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.notifications = append(f.notifications, notification)
+	f.actions = append(f.actions, actions)
+	return f.answer, f.err
+}
+
+func assertNotifications(t *testing.T, got []Notification, want []Notification) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("notifications = %#v, want %#v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("notification[%d] = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
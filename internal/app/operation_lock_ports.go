@@ -0,0 +1,17 @@
+package app
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBusy reports that another aim process holds a lock an operation needs.
+var ErrBusy = errors.New("another aim is running")
+
+// AppLocker serializes operations on the same app across processes.
+//
+// Implementations belong in infrastructure and should give up with ErrBusy
+// after a bounded wait. The returned function releases the lock.
+type AppLocker interface {
+	LockApp(ctx context.Context, id string) (func(), error)
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceRemoveReturnsBusyWhenAppIsLocked(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	deps.AppLocks = &fakeAppLocker{busy: map[string]bool{installed.ID: true}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	err = service.Remove(context.Background(), RemoveRequest{Name: installed.ID})
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("Remove() error = %v, want ErrBusy", err)
+	}
+	if len(deps.artifactRemover.paths) != 0 || deps.apps.deletedID != "" {
+		t.Fatalf("removed %v and deleted %q while the app was locked", deps.artifactRemover.paths, deps.apps.deletedID)
+	}
+}
+
+func TestServiceAddLocksAppWhileIntegrating(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	locks := &fakeAppLocker{}
+	deps.AppLocks = locks
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Add(context.Background(), AddRequest{Path: testAppImagePath(t, "example.AppImage")})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if got, want := strings.Join(locks.locked, ","), result.App.ID; got != want {
+		t.Fatalf("locked apps = %q, want %q", got, want)
+	}
+	if len(locks.held) != 0 {
+		t.Fatalf("locks still held after Add(): %v", locks.held)
+	}
+}
+
+func TestServiceUpdateSkipsAppChangedWhileWaitingForLock(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	changed := installed
+	changed.Version, _ = domain.ParseVersion("2.0.0")
+	deps.apps.listApps = []domain.App{installed}
+	deps.apps.findApp = changed
+	deps.AppLocks = &fakeAppLocker{}
+	deps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	downloads := &fakeAssetDownloader{}
+	deps.Downloads = downloads
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(result.Failures) != 1 || !strings.Contains(result.Failures[0].Error, "changed by another aim") {
+		t.Fatalf("Failures = %#v, want changed-while-waiting failure", result.Failures)
+	}
+	if downloads.source.URL != "" {
+		t.Fatalf("downloaded %q for an app changed by another process", downloads.source.URL)
+	}
+}
+
+func TestServiceRepairKeepsUpdateFinishedWhileWaitingForLock(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	updated := installed
+	updated.AppImagePath = "/apps/example-app-2.0.0.AppImage"
+	updated.Version, _ = domain.ParseVersion("2.0.0")
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	locks := &fakeAppLocker{onLock: func(id string) { deps.apps.findApps[id] = updated }}
+	deps.AppLocks = locks
+	deps.iconInstaller.path = "/icons/hicolor/scalable/apps/example-app.svg"
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Repair(context.Background(), RepairRequest{ID: installed.ID}); err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	if got, want := deps.saved.App.AppImagePath, updated.AppImagePath; got != want {
+		t.Fatalf("saved AppImagePath = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.Version.String(), "2.0.0"; got != want {
+		t.Fatalf("saved Version = %q, want %q", got, want)
+	}
+	if len(locks.held) != 0 {
+		t.Fatalf("locks still held after Repair(): %v", locks.held)
+	}
+}
+
+func TestServiceSetUpdateSourceKeepsChangesMadeWhileWaitingForLock(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	updated := installed
+	updated.Version, _ = domain.ParseVersion("2.0.0")
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.AppLocks = &fakeAppLocker{onLock: func(id string) { deps.apps.findApps[id] = updated }}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.SetUpdateSource(context.Background(), SetUpdateSourceRequest{ID: installed.ID, GitHubRepo: "owner/repo"}); err != nil {
+		t.Fatalf("SetUpdateSource() error = %v", err)
+	}
+
+	if got, want := deps.saved.App.Version.String(), "2.0.0"; got != want {
+		t.Fatalf("saved Version = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.UpdateSource.Repo, "owner/repo"; got != want {
+		t.Fatalf("saved UpdateSource.Repo = %q, want %q", got, want)
+	}
+}
+
+func TestServiceUnsetUpdateSourceLocksApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.findApp = installed
+	deps.AppLocks = &fakeAppLocker{busy: map[string]bool{installed.ID: true}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	err = service.UnsetUpdateSource(context.Background(), UnsetUpdateSourceRequest{ID: installed.ID})
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("UnsetUpdateSource() error = %v, want ErrBusy", err)
+	}
+	if deps.saved.App.ID != "" {
+		t.Fatalf("saved %q while the app was locked", deps.saved.App.ID)
+	}
+}
+
+// fakeAppLocker fails on re-entrant locking so nested operations on the same
+// app surface in tests instead of deadlocking against a real file lock.
+type fakeAppLocker struct {
+	busy   map[string]bool
+	held   map[string]bool
+	locked []string
+	// onLock runs once the lock is taken, standing in for another process
+	// that changed the app while this one waited.
+	onLock func(id string)
+}
+
+func (f *fakeAppLocker) LockApp(ctx context.Context, id string) (func(), error) {
+	if f.busy[id] {
+		return nil, fmt.Errorf("lock app %s: %w", id, ErrBusy)
+	}
+	if f.held == nil {
+		f.held = map[string]bool{}
+	}
+	if f.held[id] {
+		return nil, fmt.Errorf("lock app %s: already held", id)
+	}
+	f.held[id] = true
+	f.locked = append(f.locked, id)
+	if f.onLock != nil {
+		f.onLock(id)
+	}
+	return func() { delete(f.held, id) }, nil
+}
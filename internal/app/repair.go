@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) Repair(ctx context.Context, req RepairRequest) (RepairResult, error) {
+	if err := ctx.Err(); err != nil {
+		return RepairResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id != "" && req.All {
+		return RepairResult{}, errors.New("provide either app id or --all, not both")
+	}
+	if id == "" && !req.All {
+		return RepairResult{}, errors.New("app id is required unless --all is used")
+	}
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	apps, err := s.updateScope(ctx, id)
+	if err != nil {
+		return RepairResult{}, err
+	}
+
+	repaired := make([]string, 0, len(apps))
+	failures := make([]RepairFailure, 0)
+	for _, installedApp := range apps {
+		if err := ctx.Err(); err != nil {
+			return RepairResult{}, err
+		}
+
+		task := activity.Start(ctx, Activity{Kind: ActivityKindRepairing, AppID: installedApp.ID})
+		if err := s.repairApp(ctx, installedApp); err != nil {
+			task.Fail(err)
+			if !req.All || ctx.Err() != nil {
+				return RepairResult{}, err
+			}
+			failures = append(failures, RepairFailure{AppID: installedApp.ID, Error: err.Error()})
+			continue
+		}
+		task.Done("Repaired " + installedApp.Name)
+		repaired = append(repaired, installedApp.ID)
+	}
+
+	if len(repaired) > 0 && s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+
+	return RepairResult{Repaired: repaired, Failures: failures}, nil
+}
+
+// repairApp rewrites the desktop entry, icons, MIME packages, and command of
+// an installed app from its AppImage, and refreshes the translated names and
+// descriptions it stores from the desktop entry. The AppImage itself and the
+// stored sources are left untouched.
+func (s *service) repairApp(ctx context.Context, installedApp domain.App) error {
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if strings.TrimSpace(installedApp.AppImagePath) == "" {
+		return errors.New("installed appimage path is required")
+	}
+
+	workspacePath, cleanup, err := createWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	metadata, err := s.inspectInstalledAppImageInWorkspace(ctx, installedApp.AppImagePath, workspacePath)
+	if err != nil {
+		return err
+	}
+	iconFile, err := s.discoverIcon(ctx, metadata.rootDir, metadata.desktopEntry.Icon)
+	if err != nil {
+		return err
+	}
+	iconFile, generatedIcon, err := s.iconOrGenerated(ctx, iconFile, workspacePath, installedApp.Name, installedApp.ID)
+	if err != nil {
+		return err
+	}
+
+	installedIconPath, err := s.iconInstaller.Install(ctx, iconFile.Path, installedApp.ID)
+	if err != nil {
+		return err
+	}
+	installedIconSizePaths, err := s.installIconSizes(ctx, iconFile.Path, iconFile.Sizes, installedApp.ID, installedIconPath)
+	if err != nil {
+		return err
+	}
+	mimePackages, err := s.discoverMimePackages(ctx, metadata.rootDir)
+	if err != nil {
+		return err
+	}
+	installedMimePackagePaths, err := s.installMimePackages(ctx, installedApp.ID, mimePackages)
+	if err != nil {
+		return err
+	}
+	updatedDesktopEntry := metadata.desktopEntry.
+		WithExec(desktopExec(installedApp)).
+		WithIcon(installedIconPath)
+	installedDesktopEntryPath, err := s.desktopEntryInstaller.Install(ctx, installedApp.ID, updatedDesktopEntry.Bytes())
+	if err != nil {
+		return err
+	}
+
+	repairedApp := installedApp
+	repairedApp.IconPath = installedIconPath
+	repairedApp.DesktopEntryPath = installedDesktopEntryPath
+	repairedApp.IconSizePaths = installedIconSizePaths
+	repairedApp.GeneratedIcon = generatedIcon
+	described := domain.NewAppFromDesktopEntry(metadata.desktopEntry, domain.AppInput{})
+	repairedApp.NameTranslations = described.NameTranslations
+	repairedApp.GenericName = described.GenericName
+	repairedApp.Comment = described.Comment
+	if s.mimePackageInstaller != nil {
+		repairedApp.MimePackagePaths = installedMimePackagePaths
+	}
+	repairedApp, err = s.refreshBin(ctx, repairedApp)
+	if err != nil {
+		return err
+	}
+	if repairedApp.IconPath == installedApp.IconPath && repairedApp.GeneratedIcon == installedApp.GeneratedIcon &&
+		repairedApp.DesktopEntryPath == installedApp.DesktopEntryPath && repairedApp.BinPath == installedApp.BinPath &&
+		slices.Equal(repairedApp.IconSizePaths, installedApp.IconSizePaths) && slices.Equal(repairedApp.MimePackagePaths, installedApp.MimePackagePaths) &&
+		maps.Equal(repairedApp.NameTranslations, installedApp.NameTranslations) &&
+		repairedApp.GenericName.Equal(installedApp.GenericName) && repairedApp.Comment.Equal(installedApp.Comment) {
+		return nil
+	}
+	if err := s.apps.Save(ctx, repairedApp); err != nil {
+		return err
+	}
+
+	return s.removeReplacedArtifacts(ctx, installedApp, repairedApp)
+}
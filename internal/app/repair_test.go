@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceRepairReinstallsDesktopEntryAndIcon(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Repair(context.Background(), RepairRequest{ID: installed.ID})
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	if got, want := strings.Join(result.Repaired, ","), installed.ID; got != want {
+		t.Fatalf("Repaired = %q, want %q", got, want)
+	}
+	if got, want := deps.appImages.appImagePath, installed.AppImagePath; got != want {
+		t.Fatalf("extracted appimage = %q, want %q", got, want)
+	}
+	if deps.appImageInstaller.called {
+		t.Fatal("appimage installer was called, want AppImage untouched")
+	}
+	assertInstallCalls(t, deps.iconInstaller.calls, []fakeInstallCall{{sourcePath: "/extracted/example.png", appID: installed.ID}})
+	content := string(deps.desktopEntryInstaller.content)
+	if !strings.Contains(content, "Exec="+installed.AppImagePath+" %U") {
+		t.Fatalf("desktop content = %q, want installed AppImage Exec", content)
+	}
+	if !strings.Contains(content, "Icon="+installed.IconPath) {
+		t.Fatalf("desktop content = %q, want installed Icon", content)
+	}
+	if deps.saved.App.ID != "" {
+		t.Fatalf("saved app = %#v, want no save when paths are unchanged", deps.saved.App)
+	}
+	if len(deps.artifactRemover.paths) != 0 {
+		t.Fatalf("removed paths = %#v, want none", deps.artifactRemover.paths)
+	}
+	if !deps.desktopIntegrationRefresher.called {
+		t.Fatal("desktop integration refresher was not called")
+	}
+}
+
+func TestServiceRepairRefreshesTranslations(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Config.Locale = "de_DE.UTF-8"
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.desktopEntries.content = []byte("[Desktop Entry]\nName=Example App\nName[de]=Beispiel-App\nComment=Edit text\nComment[de]=Text bearbeiten\nExec=old-exec\nIcon=example-icon\n")
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Repair(context.Background(), RepairRequest{ID: installed.ID}); err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	saved := deps.saved.App
+	if got, want := saved.LocalizedName(deps.Config.Locale), "Beispiel-App"; got != want {
+		t.Fatalf("saved LocalizedName() = %q, want %q", got, want)
+	}
+	if got, want := saved.Comment.Localize(deps.Config.Locale), "Text bearbeiten"; got != want {
+		t.Fatalf("saved Comment = %q, want %q", got, want)
+	}
+	if saved.AppImagePath != installed.AppImagePath || saved.IconPath != installed.IconPath {
+		t.Fatalf("saved paths = %q/%q, want unchanged", saved.AppImagePath, saved.IconPath)
+	}
+}
+
+func TestServiceRepairSavesChangedArtifactPathsAndKeepsSources(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.iconInstaller.path = "/icons/hicolor/scalable/apps/example-app.svg"
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Repair(context.Background(), RepairRequest{ID: installed.ID}); err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	if got, want := deps.saved.App.IconPath, "/icons/hicolor/scalable/apps/example-app.svg"; got != want {
+		t.Fatalf("saved IconPath = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.AppImagePath, installed.AppImagePath; got != want {
+		t.Fatalf("saved AppImagePath = %q, want %q", got, want)
+	}
+	if deps.saved.App.Source != installed.Source || deps.saved.App.UpdateSource != installed.UpdateSource {
+		t.Fatalf("saved sources = %#v/%#v, want unchanged", deps.saved.App.Source, deps.saved.App.UpdateSource)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{installed.IconPath})
+}
+
+func TestServiceRepairAllCollectsFailures(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	broken := testInstalledApp(t)
+	broken.ID = "broken-app"
+	broken.AppImagePath = ""
+	deps.apps.listApps = []domain.App{broken, installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Repair(context.Background(), RepairRequest{All: true})
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	if got, want := strings.Join(result.Repaired, ","), installed.ID; got != want {
+		t.Fatalf("Repaired = %q, want %q", got, want)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].AppID != broken.ID {
+		t.Fatalf("Failures = %#v, want failure for %s", result.Failures, broken.ID)
+	}
+}
+
+func TestServiceRepairTargetReturnsFailure(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	wantErr := errors.New("icon install failed")
+	deps.iconInstaller.err = wantErr
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Repair(context.Background(), RepairRequest{ID: installed.ID})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Repair() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestServiceRepairValidatesTarget(t *testing.T) {
+	t.Parallel()
+
+	service, err := NewService(integrationTestDeps().ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, req := range []RepairRequest{{}, {ID: "example-app", All: true}} {
+		if _, err := service.Repair(context.Background(), req); err == nil {
+			t.Fatalf("Repair(%#v) error = nil, want validation error", req)
+		}
+	}
+}
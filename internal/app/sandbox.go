@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) SetSandbox(ctx context.Context, req SetSandboxRequest) (SetSandboxResult, error) {
+	if err := ctx.Err(); err != nil {
+		return SetSandboxResult{}, err
+	}
+
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return SetSandboxResult{}, errors.New("app id is required")
+	}
+	profile := strings.TrimSpace(req.Profile)
+	if req.Off && (profile != "" || req.Backend != domain.SandboxBackendNone) {
+		return SetSandboxResult{}, errors.New("provide either a sandbox profile or --off, not both")
+	}
+	if !req.Off && profile == "" {
+		return SetSandboxResult{}, errors.New("sandbox profile is required unless --off is used")
+	}
+	if !req.Off && s.sandboxProfiles == nil {
+		return SetSandboxResult{}, errors.New("sandbox profile resolver is required")
+	}
+
+	installedApp, err := s.apps.Find(ctx, id)
+	if err != nil {
+		return SetSandboxResult{}, err
+	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return SetSandboxResult{}, err
+	}
+	defer unlock()
+
+	var sandbox domain.Sandbox
+	if !req.Off {
+		sandbox, err = s.sandboxProfiles.Resolve(ctx, installedApp.ID, profile, req.Backend)
+		if err != nil {
+			return SetSandboxResult{}, err
+		}
+	}
+
+	updatedApp := installedApp
+	updatedApp.Sandbox = sandbox
+	err = s.saveLaunchSettings(ctx, installedApp, updatedApp)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationSetSandbox,
+		AppID:      installedApp.ID,
+		OldVersion: installedApp.Version.String(),
+		NewVersion: installedApp.Version.String(),
+		Source:     describeSandbox(sandbox),
+	}, err)
+	if err != nil {
+		return SetSandboxResult{}, err
+	}
+
+	return SetSandboxResult{ID: updatedApp.ID, Sandbox: sandbox}, nil
+}
+
+// saveLaunchSettings saves updatedApp, whose launch settings differ from
+// installedApp, and rewrites its desktop entry and command to launch it that
+// way. The previous ones are written back when the app cannot be saved.
+func (s *service) saveLaunchSettings(ctx context.Context, installedApp domain.App, updatedApp domain.App) error {
+	if err := s.rewriteDesktopEntry(ctx, updatedApp); err != nil {
+		return err
+	}
+	updatedApp, err := s.refreshBin(ctx, updatedApp)
+	if err != nil {
+		_ = s.rewriteDesktopEntry(ctx, installedApp)
+		return err
+	}
+	if err := s.apps.Save(ctx, updatedApp); err != nil {
+		_ = s.rewriteDesktopEntry(ctx, installedApp)
+		_, _ = s.refreshBin(ctx, installedApp)
+		return err
+	}
+	if err := s.removeReplacedArtifacts(ctx, installedApp, updatedApp); err != nil {
+		return err
+	}
+	if s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+
+	return nil
+}
+
+// rewriteDesktopEntry regenerates the desktop entry of installedApp from its
+// AppImage at the path it is installed at.
+func (s *service) rewriteDesktopEntry(ctx context.Context, installedApp domain.App) error {
+	if strings.TrimSpace(installedApp.AppImagePath) == "" {
+		return errors.New("installed appimage path is required")
+	}
+	if strings.TrimSpace(installedApp.DesktopEntryPath) == "" {
+		return errors.New("installed desktop entry path is required")
+	}
+
+	workspacePath, cleanup, err := createWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	metadata, err := s.inspectInstalledAppImageInWorkspace(ctx, installedApp.AppImagePath, workspacePath)
+	if err != nil {
+		return err
+	}
+
+	updatedDesktopEntry := metadata.desktopEntry.
+		WithExec(desktopExec(installedApp)).
+		WithIcon(installedApp.IconPath)
+	_, err = s.desktopEntryInstaller.Install(ctx, installedApp.ID, updatedDesktopEntry.Bytes())
+	return err
+}
+
+func describeSandbox(sandbox domain.Sandbox) string {
+	if !sandbox.Enabled() {
+		return "none"
+	}
+	return sandbox.Profile + " (" + string(sandbox.Backend) + ")"
+}
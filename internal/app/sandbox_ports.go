@@ -0,0 +1,17 @@
+package app
+
+import (
+	"context"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// SandboxProfileResolver turns a sandbox profile into the sandbox of an app.
+//
+// Implementations belong in infrastructure. Resolve accepts the built-in
+// profiles strict and network-only or the path of a TOML profile, picks an
+// installed backend unless backend or the profile names one, and creates the
+// private home directory the sandbox uses for appID.
+type SandboxProfileResolver interface {
+	Resolve(ctx context.Context, appID string, profile string, backend domain.SandboxBackend) (domain.Sandbox, error)
+}
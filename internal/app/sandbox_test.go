@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceSetSandboxRewritesDesktopEntryExec(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	profiles := &fakeSandboxProfileResolver{sandbox: domain.Sandbox{Profile: "strict", Backend: domain.SandboxBackendFirejail, Home: "/sandbox/example-app"}}
+	deps.SandboxProfiles = profiles
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.SetSandbox(context.Background(), SetSandboxRequest{ID: installed.ID, Profile: "strict"})
+	if err != nil {
+		t.Fatalf("SetSandbox() error = %v", err)
+	}
+
+	if profiles.appID != installed.ID || profiles.profile != "strict" {
+		t.Fatalf("Resolve(%q, %q), want %q, strict", profiles.appID, profiles.profile, installed.ID)
+	}
+	if result.Sandbox.Backend != domain.SandboxBackendFirejail || deps.saved.App.Sandbox.Profile != "strict" {
+		t.Fatalf("result = %#v, saved sandbox = %#v", result, deps.saved.App.Sandbox)
+	}
+	content := string(deps.desktopEntryInstaller.content)
+	for _, want := range []string{
+		"Exec=firejail --appimage --net=none --private=/sandbox/example-app " + installed.AppImagePath + " %U",
+		"Exec=firejail --appimage --net=none --private=/sandbox/example-app " + installed.AppImagePath + " --new-window %U",
+		"Icon=" + installed.IconPath,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("desktop content = %q, want %q", content, want)
+		}
+	}
+	if !deps.desktopIntegrationRefresher.called {
+		t.Fatal("desktop integration refresher was not called")
+	}
+}
+
+func TestServiceSetSandboxOffRestoresPlainExec(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Sandbox = domain.Sandbox{Profile: "strict", Backend: domain.SandboxBackendBubblewrap}
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.SetSandbox(context.Background(), SetSandboxRequest{ID: installed.ID, Off: true}); err != nil {
+		t.Fatalf("SetSandbox() error = %v", err)
+	}
+
+	if deps.saved.App.Sandbox.Enabled() {
+		t.Fatalf("saved sandbox = %#v, want disabled", deps.saved.App.Sandbox)
+	}
+	content := string(deps.desktopEntryInstaller.content)
+	if !strings.Contains(content, "Exec="+installed.AppImagePath+" %U\n") || strings.Contains(content, "bwrap") {
+		t.Fatalf("desktop content = %q, want plain AppImage Exec", content)
+	}
+}
+
+func TestServiceSetSandboxRestoresDesktopEntryWhenSaveFails(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApps = map[string]domain.App{installed.ID: installed}
+	deps.apps.err = errors.New("disk full")
+	deps.SandboxProfiles = &fakeSandboxProfileResolver{sandbox: domain.Sandbox{Profile: "strict", Backend: domain.SandboxBackendBubblewrap}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.SetSandbox(context.Background(), SetSandboxRequest{ID: installed.ID, Profile: "strict"}); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("SetSandbox() error = %v, want save failure", err)
+	}
+	if len(deps.desktopEntryInstaller.calls) != 2 {
+		t.Fatalf("desktop entry installs = %d, want sandboxed then restored", len(deps.desktopEntryInstaller.calls))
+	}
+	if restored := string(deps.desktopEntryInstaller.content); strings.Contains(restored, "bwrap") {
+		t.Fatalf("restored desktop content = %q, want plain Exec", restored)
+	}
+}
+
+func TestServiceSetSandboxValidatesRequest(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.SandboxProfiles = &fakeSandboxProfileResolver{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	for _, req := range []SetSandboxRequest{
+		{Profile: "strict"},
+		{ID: "example-app"},
+		{ID: "example-app", Profile: "strict", Off: true},
+	} {
+		if _, err := service.SetSandbox(context.Background(), req); err == nil {
+			t.Fatalf("SetSandbox(%#v) error = nil, want validation error", req)
+		}
+	}
+}
+
+func TestServiceUpdateKeepsSandbox(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.Sandbox = domain.Sandbox{Profile: "network-only", Backend: domain.SandboxBackendFirejail, Network: true}
+	deps.apps.findApp = installed
+	configureUpdateArtifactPaths(&deps, "example-app", "example-app-2-0-0")
+	deps.ServiceDeps.GitHubReleases = &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v2.0.0", "Example.AppImage")}
+	deps.ServiceDeps.Downloads = &fakeAssetDownloader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Update(context.Background(), UpdateRequest{Target: installed.ID}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if got := deps.saved.App.Sandbox; got.Profile != "network-only" || got.Backend != domain.SandboxBackendFirejail {
+		t.Fatalf("saved sandbox = %#v, want kept", got)
+	}
+	content := string(deps.desktopEntryInstaller.content)
+	if !strings.Contains(content, "Exec=firejail --appimage /library/example-app.AppImage %U") {
+		t.Fatalf("desktop content = %q, want sandboxed Exec", content)
+	}
+}
+
+type fakeSandboxProfileResolver struct {
+	appID   string
+	profile string
+	backend domain.SandboxBackend
+	sandbox domain.Sandbox
+	err     error
+}
+
+func (f *fakeSandboxProfileResolver) Resolve(ctx context.Context, appID string, profile string, backend domain.SandboxBackend) (domain.Sandbox, error) {
+	f.appID = appID
+	f.profile = profile
+	f.backend = backend
+	return f.sandbox, f.err
+}
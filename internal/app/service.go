@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,9 +26,31 @@ type service struct {
 	desktopEntries              DesktopEntryDiscoverer
 	icons                       IconDiscoverer
 	appImageInstaller           AppImageInstaller
+	appImageScanner             AppImageScanner
+	foreignIntegrations         ForeignIntegrationFinder
+	manifests                   ManifestLoader
+	snapshots                   SnapshotStore
+	lockFiles                   LockFileStore
+	appLocks                    AppLocker
+	journal                     OperationJournal
+	databaseBackups             DatabaseBackups
+	history                     HistoryLog
+	undo                        UndoArchive
 	iconInstaller               IconInstaller
+	iconGenerator               IconGenerator
 	desktopEntryInstaller       DesktopEntryInstaller
+	commands                    CommandInstaller
+	mimePackages                MimePackageDiscoverer
+	mimePackageInstaller        MimePackageInstaller
+	mimeAssociations            MimeAssociations
 	artifactRemover             ArtifactRemover
+	trash                       ArtifactRemover
+	hooks                       HookRunner
+	notifier                    Notifier
+	updateScheduler             UpdateScheduler
+	appImageWatcher             AppImageWatcher
+	sandboxProfiles             SandboxProfileResolver
+	launcher                    AppLauncher
 	desktopIntegrationRefresher DesktopIntegrationRefresher
 	githubReleases              GitHubReleaseFinder
 	downloads                   AssetDownloader
@@ -34,14 +59,48 @@ type service struct {
 }
 
 type ServiceDeps struct {
-	Config                      Config
-	AppImages                   AppImageExtractor
-	DesktopEntries              DesktopEntryDiscoverer
-	Icons                       IconDiscoverer
-	AppImageInstaller           AppImageInstaller
-	IconInstaller               IconInstaller
-	DesktopEntryInstaller       DesktopEntryInstaller
-	ArtifactRemover             ArtifactRemover
+	Config              Config
+	AppImages           AppImageExtractor
+	DesktopEntries      DesktopEntryDiscoverer
+	Icons               IconDiscoverer
+	AppImageInstaller   AppImageInstaller
+	AppImageScanner     AppImageScanner
+	ForeignIntegrations ForeignIntegrationFinder
+	Manifests           ManifestLoader
+	Snapshots           SnapshotStore
+	LockFiles           LockFileStore
+	AppLocks            AppLocker
+	Journal             OperationJournal
+	DatabaseBackups     DatabaseBackups
+	History             HistoryLog
+	Undo                UndoArchive
+	IconInstaller       IconInstaller
+	// IconGenerator draws icons for AppImages that bundle none; without it
+	// adding them fails.
+	IconGenerator         IconGenerator
+	DesktopEntryInstaller DesktopEntryInstaller
+	// Commands installs the commands of apps added with --bin; without it
+	// such adds fail.
+	Commands CommandInstaller
+	// MimePackages and MimePackageInstaller register the MIME types apps
+	// bundle; without them bundled MIME packages are ignored.
+	MimePackages         MimePackageDiscoverer
+	MimePackageInstaller MimePackageInstaller
+	// MimeAssociations sets default applications; without it aim default
+	// fails and removed apps are left in mimeapps.list.
+	MimeAssociations MimeAssociations
+	ArtifactRemover  ArtifactRemover
+	// Trash moves removed AppImages to the trash when requested; without it
+	// such removals fail.
+	Trash ArtifactRemover
+	Hooks HookRunner
+	// Notifier reports updates for requests with Notify; without it applied
+	// updates are not reported and check mode with Notify fails.
+	Notifier                    Notifier
+	UpdateScheduler             UpdateScheduler
+	AppImageWatcher             AppImageWatcher
+	SandboxProfiles             SandboxProfileResolver
+	Launcher                    AppLauncher
 	DesktopIntegrationRefresher DesktopIntegrationRefresher
 	GitHubReleases              GitHubReleaseFinder
 	Downloads                   AssetDownloader
@@ -58,9 +117,31 @@ func NewService(deps ServiceDeps) (Service, error) {
 		desktopEntries:              deps.DesktopEntries,
 		icons:                       deps.Icons,
 		appImageInstaller:           deps.AppImageInstaller,
+		appImageScanner:             deps.AppImageScanner,
+		foreignIntegrations:         deps.ForeignIntegrations,
+		manifests:                   deps.Manifests,
+		snapshots:                   deps.Snapshots,
+		lockFiles:                   deps.LockFiles,
+		appLocks:                    deps.AppLocks,
+		journal:                     deps.Journal,
+		databaseBackups:             deps.DatabaseBackups,
+		history:                     deps.History,
+		undo:                        deps.Undo,
 		iconInstaller:               deps.IconInstaller,
+		iconGenerator:               deps.IconGenerator,
 		desktopEntryInstaller:       deps.DesktopEntryInstaller,
+		commands:                    deps.Commands,
+		mimePackages:                deps.MimePackages,
+		mimePackageInstaller:        deps.MimePackageInstaller,
+		mimeAssociations:            deps.MimeAssociations,
 		artifactRemover:             deps.ArtifactRemover,
+		trash:                       deps.Trash,
+		hooks:                       deps.Hooks,
+		notifier:                    deps.Notifier,
+		updateScheduler:             deps.UpdateScheduler,
+		appImageWatcher:             deps.AppImageWatcher,
+		sandboxProfiles:             deps.SandboxProfiles,
+		launcher:                    deps.Launcher,
 		desktopIntegrationRefresher: deps.DesktopIntegrationRefresher,
 		githubReleases:              deps.GitHubReleases,
 		downloads:                   deps.Downloads,
@@ -106,6 +187,12 @@ func (s *service) Add(ctx context.Context, req AddRequest) (AddResult, error) {
 	if strings.TrimSpace(req.AssetPattern) != "" && strings.TrimSpace(req.GitHubRepo) == "" {
 		return AddResult{}, errors.New("asset pattern requires github repo")
 	}
+	if err := validateLaunchOptions(req.Launch); err != nil {
+		return AddResult{}, err
+	}
+	if name := strings.TrimSpace(req.BinName); name != "" && (!req.Bin || !validBinName(name)) {
+		return AddResult{}, fmt.Errorf("invalid command name %q", req.BinName)
+	}
 	if strings.TrimSpace(req.GitHubRepo) != "" {
 		return s.addFromGitHub(ctx, req, activity)
 	}
@@ -121,6 +208,18 @@ type addLocalOptions struct {
 	fallbackVersion string
 	appID           string
 	saveApp         bool
+	// inPlace integrates req.Path where it is instead of copying it into the
+	// AppImage library.
+	inPlace bool
+	// updateSource replaces the update source derived from the AppImage and
+	// request when set.
+	updateSource domain.UpdateSource
+	// journal records created artifacts for an enclosing operation; saved
+	// apps without one are journaled as an add.
+	journal *operationJournal
+	// iconAppID replaces the app ID that a generated icon is drawn for, so
+	// that staged updates draw the icon of the app they update.
+	iconAppID string
 }
 
 func (s *service) addLocal(ctx context.Context, req AddRequest, activity ActivityReporter) (AddResult, error) {
@@ -133,7 +232,7 @@ func (s *service) addLocal(ctx context.Context, req AddRequest, activity Activit
 
 func (s *service) addLocalWithOptions(ctx context.Context, req AddRequest, activity ActivityReporter, options addLocalOptions) (AddResult, error) {
 	task := activity.Start(ctx, Activity{Kind: ActivityKindIntegrating, Path: req.Path, AppID: options.appID})
-	result, err := s.integrateLocal(ctx, req, options.source, options.fallbackVersion, options.appID, options.saveApp)
+	result, err := s.integrateLocal(ctx, req, options)
 	if err != nil {
 		task.Fail(err)
 		return AddResult{}, err
@@ -158,8 +257,33 @@ func (s *service) addFromGitHub(ctx context.Context, req AddRequest, activity Ac
 		return AddResult{}, errors.New("asset downloader is required")
 	}
 
-	check := activity.Start(ctx, Activity{Kind: ActivityKindCheckingGitHub, Repo: repo})
-	release, err := s.githubReleases.LatestRelease(ctx, repo, req.Prerelease)
+	return s.addGitHubRelease(ctx, req, activity, githubAddOptions{})
+}
+
+// githubAddOptions adjusts which release addGitHubRelease installs and how the
+// resulting app is recorded.
+type githubAddOptions struct {
+	appID string
+	// tag installs this release instead of the latest one.
+	tag string
+	// assetName installs the asset with exactly this name.
+	assetName string
+	// updateSource replaces the update source derived from the request.
+	updateSource domain.UpdateSource
+	// sha256 is the digest the downloaded asset must match.
+	sha256 string
+}
+
+func (s *service) addGitHubRelease(ctx context.Context, req AddRequest, activity ActivityReporter, options githubAddOptions) (AddResult, error) {
+	repo := strings.TrimSpace(req.GitHubRepo)
+	check := activity.Start(ctx, Activity{Kind: ActivityKindCheckingGitHub, AppID: options.appID, Repo: repo})
+	var release GitHubRelease
+	var err error
+	if tag := strings.TrimSpace(options.tag); tag != "" {
+		release, err = s.githubReleases.ReleaseByTag(ctx, repo, tag)
+	} else {
+		release, err = s.githubReleases.LatestRelease(ctx, repo, req.Prerelease)
+	}
 	if err != nil {
 		check.Fail(err)
 		return AddResult{}, err
@@ -167,7 +291,9 @@ func (s *service) addFromGitHub(ctx context.Context, req AddRequest, activity Ac
 	check.Done("Checked " + repo)
 
 	var asset GitHubReleaseAsset
-	if strings.TrimSpace(req.AssetPattern) != "" {
+	if strings.TrimSpace(options.assetName) != "" {
+		asset, err = selectGitHubAssetNamed(release, options.assetName)
+	} else if strings.TrimSpace(req.AssetPattern) != "" {
 		asset, err = selectGitHubAppImageAssetMatchingPattern(release, req.AssetPattern)
 	} else {
 		asset, err = selectGitHubAppImageAsset(release)
@@ -175,6 +301,9 @@ func (s *service) addFromGitHub(ctx context.Context, req AddRequest, activity Ac
 	if err != nil {
 		return AddResult{}, err
 	}
+	if options.sha256 != "" {
+		asset.SHA256 = options.sha256
+	}
 
 	workspacePath, cleanup, err := createWorkspace(ctx)
 	if err != nil {
@@ -182,51 +311,109 @@ func (s *service) addFromGitHub(ctx context.Context, req AddRequest, activity Ac
 	}
 	defer cleanup()
 
-	downloadPath := filepath.Join(workspacePath, filepath.Base(asset.Name))
-	download := activity.Start(ctx, Activity{
-		Kind:      ActivityKindDownloading,
-		Repo:      repo,
-		AssetName: asset.Name,
-		Total:     asset.SizeBytes,
-		Unit:      ActivityUnitBytes,
-	})
-	downloaded, err := s.downloads.Download(ctx, DownloadSource{
+	downloaded, err := s.downloadAsset(ctx, activity, DownloadSource{
 		URL:       asset.DownloadURL,
 		FileName:  asset.Name,
 		SizeBytes: asset.SizeBytes,
-	}, downloadPath, download)
+		SHA256:    asset.SHA256,
+	}, workspacePath, options.appID, repo)
 	if err != nil {
-		download.Fail(err)
 		return AddResult{}, err
 	}
-	download.Done("Downloaded " + asset.Name)
-
-	integratePath := downloaded.Path
-	if integratePath == "" {
-		integratePath = downloadPath
-	}
 
 	source := domain.NewGitHubReleaseSource(repo, release.TagName, asset.Name, asset.DownloadURL, asset.SizeBytes, time.Now())
 	return s.addLocalWithOptions(ctx, AddRequest{
-		Path:         integratePath,
+		Path:         downloaded.Path,
 		GitHubRepo:   repo,
 		AssetPattern: req.AssetPattern,
 		Prerelease:   req.Prerelease,
+		Launch:       req.Launch,
+		Bin:          req.Bin,
+		BinName:      req.BinName,
 		Activity:     activity,
 	}, activity, addLocalOptions{
 		source:          source,
 		fallbackVersion: release.TagName,
+		appID:           options.appID,
+		updateSource:    options.updateSource,
 		saveApp:         true,
 	})
 }
 
-func (s *service) integrateLocal(ctx context.Context, req AddRequest, source domain.Source, fallbackVersion string, appID string, saveApp bool) (AddResult, error) {
+func (s *service) addFromURL(ctx context.Context, rawURL string, appID string, updateSource domain.UpdateSource, sha256 string, activity ActivityReporter) (AddResult, error) {
+	if s.downloads == nil {
+		return AddResult{}, errors.New("asset downloader is required")
+	}
+
+	fileName := "download.AppImage"
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if base := path.Base(parsed.Path); base != "." && base != "/" {
+			fileName = base
+		}
+	}
+
+	workspacePath, cleanup, err := createWorkspace(ctx)
+	if err != nil {
+		return AddResult{}, err
+	}
+	defer cleanup()
+
+	downloaded, err := s.downloadAsset(ctx, activity, DownloadSource{URL: rawURL, FileName: fileName, SHA256: sha256}, workspacePath, appID, "")
+	if err != nil {
+		return AddResult{}, err
+	}
+
+	return s.addLocalWithOptions(ctx, AddRequest{Path: downloaded.Path, Activity: activity}, activity, addLocalOptions{
+		source:          domain.NewURLSource(rawURL, time.Now()),
+		fallbackVersion: fileName,
+		appID:           appID,
+		updateSource:    updateSource,
+		saveApp:         true,
+	})
+}
+
+// downloadAsset downloads source into workspacePath and rejects the download
+// when an expected SHA-256 is given and does not match.
+func (s *service) downloadAsset(ctx context.Context, activity ActivityReporter, source DownloadSource, workspacePath string, appID string, repo string) (DownloadedFile, error) {
+	downloadPath := filepath.Join(workspacePath, filepath.Base(source.FileName))
+	task := activity.Start(ctx, Activity{
+		Kind:      ActivityKindDownloading,
+		AppID:     appID,
+		Repo:      repo,
+		AssetName: source.FileName,
+		Total:     source.SizeBytes,
+		Unit:      ActivityUnitBytes,
+	})
+	downloaded, err := s.downloads.Download(ctx, source, downloadPath, task)
+	if err == nil && source.SHA256 != "" && !strings.EqualFold(downloaded.SHA256, source.SHA256) {
+		err = fmt.Errorf("download %s: sha256 mismatch: expected %s, got %s", source.FileName, strings.ToLower(source.SHA256), downloaded.SHA256)
+	}
+	if err != nil {
+		task.Fail(err)
+		return DownloadedFile{}, err
+	}
+	task.Done("Downloaded " + source.FileName)
+
+	if downloaded.Path == "" {
+		downloaded.Path = downloadPath
+	}
+	return downloaded, nil
+}
+
+func (s *service) integrateLocal(ctx context.Context, req AddRequest, options addLocalOptions) (_ AddResult, err error) {
+	var post postHook
+	defer s.runPostHook(ctx, &post, &err)
 	var rollback rollbackStack
 	committed := false
+	journal := options.journal
+	ownJournal := false
 	defer func() {
 		if !committed {
 			rollback.run(ctx)
 		}
+		if ownJournal {
+			journal.finish(ctx)
+		}
 	}()
 
 	workspacePath, cleanup, err := createWorkspace(ctx)
@@ -235,30 +422,102 @@ func (s *service) integrateLocal(ctx context.Context, req AddRequest, source dom
 	}
 	defer cleanup()
 
-	metadata, err := s.inspectLocalAppImageInWorkspace(ctx, req, source, fallbackVersion, appID, integrationSource, workspacePath)
+	metadata, err := s.inspectLocalAppImageInWorkspace(ctx, req, options.source, options.fallbackVersion, options.appID, integrationSource, workspacePath)
 	if err != nil {
 		return AddResult{}, err
 	}
 
+	if options.updateSource.Kind != domain.UpdateSourceKindUnknown || options.updateSource.Embedded {
+		metadata.updateSource = options.updateSource
+	}
+
 	provisionalApp := metadata.app
-	installedAppImagePath, err := s.appImageInstaller.Install(ctx, req.Path, provisionalApp.ID)
+	if options.saveApp {
+		var unlock func()
+		unlock, err = s.lockApp(ctx, provisionalApp.ID)
+		if err != nil {
+			return AddResult{}, err
+		}
+		defer unlock()
+		defer func() {
+			s.recordHistory(ctx, HistoryEvent{
+				Operation:  HistoryOperationAdd,
+				AppID:      provisionalApp.ID,
+				NewVersion: provisionalApp.Version.String(),
+				Source:     describeSource(options.source),
+			}, err)
+		}()
+
+		if journal == nil {
+			journal, err = s.beginOperation(ctx, JournalKindAdd, provisionalApp.ID)
+			if err != nil {
+				return AddResult{}, err
+			}
+			ownJournal = true
+		}
+	}
+
+	var installedAppImagePath string
+	if options.inPlace {
+		installedAppImagePath, err = s.appImageInstaller.Adopt(ctx, req.Path)
+		if err != nil {
+			return AddResult{}, err
+		}
+	} else {
+		installedAppImagePath, err = s.appImageInstaller.Install(ctx, req.Path, provisionalApp.ID)
+		if err != nil {
+			return AddResult{}, err
+		}
+		rollback.add(func(ctx context.Context) error {
+			return s.artifactRemover(ctx, installedAppImagePath)
+		})
+		if err := journal.created(ctx, installedAppImagePath); err != nil {
+			return AddResult{}, err
+		}
+	}
+
+	iconFile, generatedIcon, err := s.iconOrGenerated(ctx, metadata.iconFile, workspacePath, provisionalApp.Name, firstNonEmpty(options.iconAppID, provisionalApp.ID))
 	if err != nil {
 		return AddResult{}, err
 	}
-	rollback.add(func(ctx context.Context) error {
-		return s.artifactRemover(ctx, installedAppImagePath)
-	})
-
-	installedIconPath, err := s.iconInstaller.Install(ctx, metadata.iconFile.Path, provisionalApp.ID)
+	installedIconPath, err := s.iconInstaller.Install(ctx, iconFile.Path, provisionalApp.ID)
 	if err != nil {
 		return AddResult{}, err
 	}
 	rollback.add(func(ctx context.Context) error {
 		return s.artifactRemover(ctx, installedIconPath)
 	})
+	if err := journal.created(ctx, installedIconPath); err != nil {
+		return AddResult{}, err
+	}
+	installedIconSizePaths, err := s.installIconSizes(ctx, iconFile.Path, iconFile.Sizes, provisionalApp.ID, installedIconPath)
+	if err != nil {
+		return AddResult{}, err
+	}
+	for _, path := range installedIconSizePaths {
+		rollback.add(func(ctx context.Context) error {
+			return s.artifactRemover(ctx, path)
+		})
+		if err := journal.created(ctx, path); err != nil {
+			return AddResult{}, err
+		}
+	}
+
+	installedMimePackagePaths, err := s.installMimePackages(ctx, provisionalApp.ID, metadata.mimePackages)
+	if err != nil {
+		return AddResult{}, err
+	}
+	for _, path := range installedMimePackagePaths {
+		rollback.add(func(ctx context.Context) error {
+			return s.artifactRemover(ctx, path)
+		})
+		if err := journal.created(ctx, path); err != nil {
+			return AddResult{}, err
+		}
+	}
 
 	updatedDesktopEntry := metadata.desktopEntry.
-		WithExec(installedAppImagePath).
+		WithExec(desktopExec(domain.App{AppImagePath: installedAppImagePath, Launch: req.Launch})).
 		WithIcon(installedIconPath)
 	installedDesktopEntryPath, err := s.desktopEntryInstaller.Install(ctx, provisionalApp.ID, updatedDesktopEntry.Bytes())
 	if err != nil {
@@ -267,6 +526,9 @@ func (s *service) integrateLocal(ctx context.Context, req AddRequest, source dom
 	rollback.add(func(ctx context.Context) error {
 		return s.artifactRemover(ctx, installedDesktopEntryPath)
 	})
+	if err := journal.created(ctx, installedDesktopEntryPath); err != nil {
+		return AddResult{}, err
+	}
 
 	if s.desktopIntegrationRefresher != nil {
 		_ = s.desktopIntegrationRefresher.Refresh(ctx)
@@ -277,16 +539,50 @@ func (s *service) integrateLocal(ctx context.Context, req AddRequest, source dom
 		AppImagePath:     installedAppImagePath,
 		DesktopEntryPath: installedDesktopEntryPath,
 		IconPath:         installedIconPath,
-		Source:           source,
+		IconSizePaths:    installedIconSizePaths,
+		GeneratedIcon:    generatedIcon,
+		MimePackagePaths: installedMimePackagePaths,
+		Source:           options.source,
 		UpdateSource:     metadata.updateSource,
+		Launch:           req.Launch,
 	})
-	if saveApp {
+	if req.Bin && options.saveApp {
+		finalApp, err = s.installBin(ctx, finalApp, firstNonEmpty(strings.TrimSpace(req.BinName), finalApp.ID))
+		if err != nil {
+			return AddResult{}, err
+		}
+		installedBinPath := finalApp.BinPath
+		rollback.add(func(ctx context.Context) error {
+			return s.artifactRemover(ctx, installedBinPath)
+		})
+		if err := journal.created(ctx, installedBinPath); err != nil {
+			return AddResult{}, err
+		}
+	}
+	if options.saveApp {
+		env := hookEnvForApp(finalApp)
+		env.NewVersion = finalApp.Version.String()
+		if err := s.runHook(ctx, HookPreAdd, env); err != nil {
+			return AddResult{}, err
+		}
+		if err := journal.commit(ctx, &finalApp); err != nil {
+			return AddResult{}, err
+		}
 		if err := s.apps.Save(ctx, finalApp); err != nil {
 			return AddResult{}, err
 		}
 	}
 	committed = true
 
+	if options.saveApp {
+		if err := s.recordAddForUndo(ctx, finalApp); err != nil {
+			return AddResult{}, fmt.Errorf("added %s but failed to record it for undo: %w", finalApp.ID, err)
+		}
+		env := hookEnvForApp(finalApp)
+		env.NewVersion = finalApp.Version.String()
+		post = postHook{event: HookPostAdd, env: env, done: "added " + finalApp.ID}
+	}
+
 	return AddResult{App: finalApp}, nil
 }
 
@@ -294,6 +590,8 @@ type localAppImageMetadata struct {
 	app          domain.App
 	desktopEntry domain.DesktopEntry
 	iconFile     IconFile
+	// mimePackages are bundled MIME packages inside the extraction workspace.
+	mimePackages []string
 	updateSource domain.UpdateSource
 }
 
@@ -329,7 +627,12 @@ func (s *service) inspectLocalAppImageInWorkspace(ctx context.Context, req AddRe
 	}
 	desktopEntry = withFallbackVersion(desktopEntry, fallbackVersion)
 
-	iconFile, err := s.icons.Discover(ctx, extraction.RootDir, desktopEntry.Icon)
+	iconFile, err := s.discoverIcon(ctx, extraction.RootDir, desktopEntry.Icon)
+	if err != nil {
+		return localAppImageMetadata{}, err
+	}
+
+	mimePackages, err := s.discoverMimePackages(ctx, extraction.RootDir)
 	if err != nil {
 		return localAppImageMetadata{}, err
 	}
@@ -346,6 +649,7 @@ func (s *service) inspectLocalAppImageInWorkspace(ctx context.Context, req AddRe
 		app:          app,
 		desktopEntry: desktopEntry,
 		iconFile:     iconFile,
+		mimePackages: mimePackages,
 		updateSource: updateSource,
 	}, nil
 }
@@ -369,7 +673,7 @@ func (s *service) Remove(ctx context.Context, req RemoveRequest) error {
 	}
 
 	task := activity.Start(ctx, Activity{Kind: ActivityKindRemoving, AppID: installedApp.ID})
-	if err := s.removeInstalledApp(ctx, installedApp); err != nil {
+	if err := s.removeInstalledApp(ctx, installedApp, req.Trash || s.config.TrashRemovedApps); err != nil {
 		task.Fail(err)
 		return err
 	}
@@ -378,18 +682,120 @@ func (s *service) Remove(ctx context.Context, req RemoveRequest) error {
 	return nil
 }
 
-func (s *service) removeInstalledApp(ctx context.Context, installedApp domain.App) error {
+// removeInstalledApp removes the artifacts and record of installedApp. With
+// trash, the AppImage is moved to the trash so it can be restored from a file
+// manager; the desktop entry and icon are always deleted.
+func (s *service) removeInstalledApp(ctx context.Context, installedApp domain.App, trash bool) (err error) {
+	removeAppImage := s.artifactRemover
+	if trash {
+		if s.trash == nil {
+			return errors.New("trash is required")
+		}
+		removeAppImage = s.trash
+	}
+
+	var post postHook
+	defer s.runPostHook(ctx, &post, &err)
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer func() {
+		s.recordHistory(ctx, HistoryEvent{
+			Operation:  HistoryOperationRemove,
+			AppID:      installedApp.ID,
+			OldVersion: installedApp.Version.String(),
+			Source:     describeSource(installedApp.Source),
+		}, err)
+	}()
+
+	journal, err := s.beginOperation(ctx, JournalKindRemove, installedApp.ID)
+	if err != nil {
+		return err
+	}
+	defer journal.finish(ctx)
+	hookEnv := hookEnvForApp(installedApp)
+	hookEnv.OldVersion = installedApp.Version.String()
+	if err := s.runHook(ctx, HookPreRemove, hookEnv); err != nil {
+		return err
+	}
+	// The command is not retained: undo writes it again from the app record.
+	undo, err := s.retainForUndo(ctx, HistoryOperationRemove, installedApp.ID, installedApp, append([]string{installedApp.DesktopEntryPath, installedApp.IconPath, installedApp.AppImagePath}, listedArtifactPaths(installedApp)...)...)
+	if err != nil {
+		return err
+	}
+	defer undo.discard(ctx)
+	cleanup := append([]string{installedApp.BinPath, installedApp.DesktopEntryPath, installedApp.IconPath}, listedArtifactPaths(installedApp)...)
+	if trash {
+		journal.trashOnCommit(installedApp.AppImagePath)
+	} else {
+		cleanup = append(cleanup, installedApp.AppImagePath)
+	}
+	if err := journal.commit(ctx, nil, cleanup...); err != nil {
+		return err
+	}
+
+	if err := removeInstalledArtifact(ctx, installedApp.BinPath, s.artifactRemover); err != nil {
+		return err
+	}
+	for _, path := range listedArtifactPaths(installedApp) {
+		if err := removeInstalledArtifact(ctx, path, s.artifactRemover); err != nil {
+			return err
+		}
+	}
 	if err := removeInstalledArtifact(ctx, installedApp.DesktopEntryPath, s.artifactRemover); err != nil {
 		return err
 	}
 	if err := removeInstalledArtifact(ctx, installedApp.IconPath, s.artifactRemover); err != nil {
 		return err
 	}
-	if err := removeInstalledArtifact(ctx, installedApp.AppImagePath, s.artifactRemover); err != nil {
+	if err := removeInstalledArtifact(ctx, installedApp.AppImagePath, removeAppImage); err != nil {
+		return err
+	}
+
+	if err := s.apps.Delete(ctx, installedApp.ID); err != nil {
 		return err
 	}
+	if err := undo.save(ctx, domain.App{}); err != nil {
+		return fmt.Errorf("removed %s but failed to record it for undo: %w", installedApp.ID, err)
+	}
+	if err := s.forgetMimeAssociations(ctx, installedApp); err != nil {
+		return fmt.Errorf("removed %s but failed to remove it from the default applications: %w", installedApp.ID, err)
+	}
+	if len(installedApp.MimePackagePaths) > 0 && s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+	post = postHook{event: HookPostRemove, env: hookEnv, done: "removed " + installedApp.ID}
+	return nil
+}
 
-	return s.apps.Delete(ctx, installedApp.ID)
+// lockApp holds the operation lock for id until the returned function is
+// called. Without an AppLocker, operations are not serialized.
+func (s *service) lockApp(ctx context.Context, id string) (func(), error) {
+	if s.appLocks == nil {
+		return func() {}, nil
+	}
+	return s.appLocks.LockApp(ctx, id)
+}
+
+// lockInstalledApp locks an installed app and reloads its record so changes
+// another process made while this one waited are not overwritten.
+func (s *service) lockInstalledApp(ctx context.Context, installedApp domain.App) (domain.App, func(), error) {
+	unlock, err := s.lockApp(ctx, installedApp.ID)
+	if err != nil {
+		return domain.App{}, nil, err
+	}
+	if s.appLocks == nil {
+		return installedApp, unlock, nil
+	}
+
+	current, err := s.apps.Find(ctx, installedApp.ID)
+	if err != nil {
+		unlock()
+		return domain.App{}, nil, err
+	}
+	return current, unlock, nil
 }
 
 func removeInstalledArtifact(ctx context.Context, path string, remove func(context.Context, string) error) error {
@@ -415,9 +821,17 @@ func (s *service) Update(ctx context.Context, req UpdateRequest) (UpdateResult,
 		return UpdateResult{}, err
 	}
 	if req.CheckOnly {
+		if req.Notify {
+			if err := s.notifyAvailableUpdates(ctx, candidates); err != nil {
+				return UpdateResult{}, err
+			}
+		}
 		return UpdateResult{Applied: false, Updates: candidates, Failures: failures}, nil
 	}
 	if len(plans) == 0 {
+		if req.Notify {
+			s.notifyAppliedUpdates(ctx, nil, failures)
+		}
 		return UpdateResult{Applied: true, Failures: failures}, nil
 	}
 
@@ -440,6 +854,9 @@ func (s *service) Update(ctx context.Context, req UpdateRequest) (UpdateResult,
 			failures = append(failures, updateFailure(plan.app.ID, err))
 		}
 	}
+	if req.Notify {
+		s.notifyAppliedUpdates(ctx, candidates, failures)
+	}
 
 	return UpdateResult{Applied: true, Updates: candidates, Failures: failures}, nil
 }
@@ -521,9 +938,12 @@ func updateFailure(appID string, err error) UpdateFailure {
 }
 
 func (s *service) githubReleaseForUpdateSource(ctx context.Context, source domain.UpdateSource) (GitHubRelease, error) {
-	if !source.Embedded || strings.TrimSpace(source.ReleaseTag) == "" {
+	if strings.TrimSpace(source.ReleaseTag) == "" {
 		return s.githubReleases.LatestRelease(ctx, source.Repo, source.Prerelease)
 	}
+	if !source.Embedded {
+		return s.githubReleases.ReleaseByTag(ctx, source.Repo, source.ReleaseTag)
+	}
 
 	switch source.ReleaseTag {
 	case "latest":
@@ -557,45 +977,61 @@ func (s *service) updateScope(ctx context.Context, target string) ([]domain.App,
 	return []domain.App{installedApp}, nil
 }
 
-func (s *service) applyGitHubUpdate(ctx context.Context, activity ActivityReporter, plan githubUpdatePlan) error {
+func (s *service) applyGitHubUpdate(ctx context.Context, activity ActivityReporter, plan githubUpdatePlan) (err error) {
 	if s.downloads == nil {
 		return errors.New("asset downloader is required")
 	}
 
+	var post postHook
+	defer s.runPostHook(ctx, &post, &err)
+	current, unlock, err := s.lockInstalledApp(ctx, plan.app)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	defer func() {
+		s.recordHistory(ctx, HistoryEvent{
+			Operation:  HistoryOperationUpdate,
+			AppID:      plan.app.ID,
+			OldVersion: current.Version.String(),
+			NewVersion: plan.version.String(),
+			Source:     describeSource(domain.NewGitHubReleaseSource(plan.app.UpdateSource.Repo, plan.release.TagName, plan.asset.Name, plan.asset.DownloadURL, plan.asset.SizeBytes, time.Time{})),
+		}, err)
+	}()
+	if current.Version.String() != plan.app.Version.String() || current.AppImagePath != plan.app.AppImagePath {
+		return fmt.Errorf("%s was changed by another aim while waiting; check for updates again", plan.app.ID)
+	}
+
+	journal, err := s.beginOperation(ctx, JournalKindUpdate, plan.app.ID)
+	if err != nil {
+		return err
+	}
+	defer journal.finish(ctx)
+	undo, err := s.retainForUndo(ctx, HistoryOperationUpdate, current.ID, current, append([]string{current.DesktopEntryPath, current.IconPath, current.AppImagePath}, listedArtifactPaths(current)...)...)
+	if err != nil {
+		return err
+	}
+	defer undo.discard(ctx)
+
 	workspacePath, cleanup, err := createWorkspace(ctx)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
-	downloadPath := filepath.Join(workspacePath, filepath.Base(plan.asset.Name))
-	download := activity.Start(ctx, Activity{
-		Kind:      ActivityKindDownloading,
-		AppID:     plan.app.ID,
-		Repo:      plan.app.UpdateSource.Repo,
-		AssetName: plan.asset.Name,
-		Total:     plan.asset.SizeBytes,
-		Unit:      ActivityUnitBytes,
-	})
-	downloaded, err := s.downloads.Download(ctx, DownloadSource{
+	downloaded, err := s.downloadAsset(ctx, activity, DownloadSource{
 		URL:       plan.asset.DownloadURL,
 		FileName:  plan.asset.Name,
 		SizeBytes: plan.asset.SizeBytes,
-	}, downloadPath, download)
+		SHA256:    plan.asset.SHA256,
+	}, workspacePath, plan.app.ID, plan.app.UpdateSource.Repo)
 	if err != nil {
-		download.Fail(err)
 		return err
 	}
-	download.Done("Downloaded " + plan.asset.Name)
-
-	integratePath := downloaded.Path
-	if integratePath == "" {
-		integratePath = downloadPath
-	}
 	source := domain.NewGitHubReleaseSource(plan.app.UpdateSource.Repo, plan.release.TagName, plan.asset.Name, plan.asset.DownloadURL, plan.asset.SizeBytes, time.Now())
 	stageID := updateArtifactID(plan.app.ID, plan.version)
 	result, err := s.addLocalWithOptions(ctx, AddRequest{
-		Path:       integratePath,
+		Path:       downloaded.Path,
 		GitHubRepo: plan.app.UpdateSource.Repo,
 		Prerelease: plan.app.UpdateSource.Prerelease,
 		Activity:   activity,
@@ -604,6 +1040,8 @@ func (s *service) applyGitHubUpdate(ctx context.Context, activity ActivityReport
 		fallbackVersion: plan.release.TagName,
 		appID:           stageID,
 		saveApp:         false,
+		journal:         journal,
+		iconAppID:       plan.app.ID,
 	})
 	if err != nil {
 		return err
@@ -619,15 +1057,34 @@ func (s *service) applyGitHubUpdate(ctx context.Context, activity ActivityReport
 	}()
 	addAppRollback(&rollback, s, stagedApp)
 
-	updatedApp, err := s.promoteStagedUpdate(ctx, stagedApp, plan.app.ID, plan.app.UpdateSource)
+	preUpdate := hookEnvForApp(current)
+	preUpdate.StagedAppImagePath = stagedApp.AppImagePath
+	preUpdate.OldVersion = current.Version.String()
+	preUpdate.NewVersion = stagedApp.Version.String()
+	if err := s.runHook(ctx, HookPreUpdate, preUpdate); err != nil {
+		return err
+	}
+
+	// Promotion overwrites the installed artifacts, so from here on recovery
+	// finishes the update instead of rolling it back.
+	if err := journal.commit(ctx, nil, append([]string{stagedApp.DesktopEntryPath, stagedApp.IconPath, stagedApp.AppImagePath}, listedArtifactPaths(stagedApp)...)...); err != nil {
+		return err
+	}
+	updatedApp, err := s.promoteStagedUpdate(ctx, stagedApp, current)
 	if err != nil {
 		return err
 	}
+	if err := journal.commit(ctx, &updatedApp, replacedArtifactPaths(plan.app, updatedApp)...); err != nil {
+		return err
+	}
 
 	if err := s.apps.Save(ctx, updatedApp); err != nil {
 		return err
 	}
 	committed = true
+	if err := undo.save(ctx, updatedApp); err != nil {
+		return fmt.Errorf("updated %s but failed to record it for undo: %w", plan.app.ID, err)
+	}
 
 	if err := s.removeInstalledAppArtifacts(ctx, stagedApp); err != nil {
 		return fmt.Errorf("updated %s but failed to remove staged artifacts: %w", plan.app.ID, err)
@@ -635,6 +1092,15 @@ func (s *service) applyGitHubUpdate(ctx context.Context, activity ActivityReport
 	if err := s.removeReplacedArtifacts(ctx, plan.app, updatedApp); err != nil {
 		return fmt.Errorf("updated %s but failed to remove replaced artifacts: %w", plan.app.ID, err)
 	}
+	// The staged MIME packages were registered under the staging ID.
+	if len(stagedApp.MimePackagePaths) > 0 && s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+
+	postUpdate := hookEnvForApp(updatedApp)
+	postUpdate.OldVersion = current.Version.String()
+	postUpdate.NewVersion = updatedApp.Version.String()
+	post = postHook{event: HookPostUpdate, env: postUpdate, done: "updated " + plan.app.ID}
 
 	return nil
 }
@@ -696,41 +1162,56 @@ func pathWithin(path string, dir string) bool {
 	return rel == "." || (rel != "" && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
 }
 
-func (s *service) promoteStagedUpdate(ctx context.Context, stagedApp domain.App, targetID string, updateSource domain.UpdateSource) (domain.App, error) {
+// promoteStagedUpdate installs stagedApp as current, keeping the ID, update
+// source and launch settings of current.
+func (s *service) promoteStagedUpdate(ctx context.Context, stagedApp domain.App, current domain.App) (domain.App, error) {
 	metadata, err := s.inspectInstalledAppImageForID(ctx, stagedApp.AppImagePath)
 	if err != nil {
 		return domain.App{}, err
 	}
 
-	installedAppImagePath, err := s.appImageInstaller.Install(ctx, stagedApp.AppImagePath, targetID)
+	installedAppImagePath, err := s.appImageInstaller.Install(ctx, stagedApp.AppImagePath, current.ID)
 	if err != nil {
 		return domain.App{}, err
 	}
-	installedIconPath, err := s.iconInstaller.Install(ctx, stagedApp.IconPath, targetID)
+	installedIconPath, err := s.iconInstaller.Install(ctx, stagedApp.IconPath, current.ID)
 	if err != nil {
 		return domain.App{}, err
 	}
-
-	updatedDesktopEntry := metadata.desktopEntry.
-		WithExec(installedAppImagePath).
-		WithIcon(installedIconPath)
-	installedDesktopEntryPath, err := s.desktopEntryInstaller.Install(ctx, targetID, updatedDesktopEntry.Bytes())
+	installedIconSizePaths, err := s.installIconSizes(ctx, stagedApp.IconPath, stagedApp.IconSizePaths, current.ID, installedIconPath)
+	if err != nil {
+		return domain.App{}, err
+	}
+	installedMimePackagePaths, err := s.installMimePackages(ctx, current.ID, stagedApp.MimePackagePaths)
 	if err != nil {
 		return domain.App{}, err
 	}
 
 	updatedApp := domain.NewAppFromDesktopEntry(metadata.desktopEntry, domain.AppInput{
-		ID:               targetID,
+		ID:               current.ID,
 		AppImagePath:     installedAppImagePath,
-		DesktopEntryPath: installedDesktopEntryPath,
 		IconPath:         installedIconPath,
+		IconSizePaths:    installedIconSizePaths,
+		GeneratedIcon:    stagedApp.GeneratedIcon,
+		MimePackagePaths: installedMimePackagePaths,
 		Source:           stagedApp.Source,
-		UpdateSource:     updateSource,
+		UpdateSource:     current.UpdateSource,
+		Sandbox:          current.Sandbox,
+		Launch:           current.Launch,
 	})
 	if updatedApp.Version.IsZero() {
 		updatedApp.Version = stagedApp.Version
 	}
-	return updatedApp, nil
+
+	updatedDesktopEntry := metadata.desktopEntry.
+		WithExec(desktopExec(updatedApp)).
+		WithIcon(installedIconPath)
+	updatedApp.DesktopEntryPath, err = s.desktopEntryInstaller.Install(ctx, current.ID, updatedDesktopEntry.Bytes())
+	if err != nil {
+		return domain.App{}, err
+	}
+	updatedApp.BinPath = current.BinPath
+	return s.refreshBin(ctx, updatedApp)
 }
 
 func addAppRollback(rollback *rollbackStack, s *service, installedApp domain.App) {
@@ -743,6 +1224,21 @@ func addAppRollback(rollback *rollbackStack, s *service, installedApp domain.App
 	rollback.add(func(ctx context.Context) error {
 		return removeInstalledArtifact(ctx, installedApp.DesktopEntryPath, s.artifactRemover)
 	})
+	for _, path := range listedArtifactPaths(installedApp) {
+		rollback.add(func(ctx context.Context) error {
+			return removeInstalledArtifact(ctx, path, s.artifactRemover)
+		})
+	}
+}
+
+// installIconSizes installs further sizes of the icon of appID next to the
+// one installed from iconSourcePath at iconPath, which it leaves out.
+func (s *service) installIconSizes(ctx context.Context, iconSourcePath string, sourcePaths []string, appID string, iconPath string) ([]string, error) {
+	installed, err := s.iconInstaller.InstallSizes(ctx, slices.Concat([]string{iconSourcePath}, sourcePaths), appID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(installed, func(path string) bool { return path == iconPath }), nil
 }
 
 func updateArtifactID(appID string, version domain.Version) string {
@@ -788,11 +1284,33 @@ func (s *service) SetID(ctx context.Context, req SetIDRequest) (SetIDResult, err
 	return result, nil
 }
 
-func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string) (SetIDResult, error) {
+func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string) (result SetIDResult, err error) {
 	installedApp, err := s.apps.Find(ctx, currentID)
 	if err != nil {
 		return SetIDResult{}, err
 	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return SetIDResult{}, err
+	}
+	defer unlock()
+	defer func() {
+		if err == nil && !result.Changed {
+			return
+		}
+		event := HistoryEvent{
+			Operation:  HistoryOperationSetID,
+			AppID:      installedApp.ID,
+			OldVersion: installedApp.Version.String(),
+			Source:     describeSource(installedApp.Source),
+		}
+		if result.Changed {
+			event.AppID = result.ID
+			event.PreviousID = result.PreviousID
+			event.NewVersion = result.App.Version.String()
+		}
+		s.recordHistory(ctx, event, err)
+	}()
 	if strings.TrimSpace(installedApp.AppImagePath) == "" {
 		return SetIDResult{}, errors.New("installed appimage path is required")
 	}
@@ -819,6 +1337,12 @@ func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string)
 		return SetIDResult{}, err
 	}
 
+	undo, err := s.retainForUndo(ctx, HistoryOperationSetID, installedApp.ID, installedApp, append([]string{installedApp.DesktopEntryPath, installedApp.IconPath, installedApp.AppImagePath}, listedArtifactPaths(installedApp)...)...)
+	if err != nil {
+		return SetIDResult{}, err
+	}
+	defer undo.discard(ctx)
+
 	var rollback rollbackStack
 	committed := false
 	defer func() {
@@ -835,7 +1359,21 @@ func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string)
 		return removeInstalledArtifact(ctx, installedAppImagePath, s.artifactRemover)
 	})
 
-	installedIconPath, err := s.iconInstaller.Install(ctx, installedApp.IconPath, targetID)
+	iconFile := IconFile{Path: installedApp.IconPath, Sizes: installedApp.IconSizePaths}
+	if installedApp.GeneratedIcon && s.iconGenerator != nil {
+		// Generated icons are drawn for the app ID, so the new ID gets a new
+		// one.
+		workspacePath, cleanup, err := createWorkspace(ctx)
+		if err != nil {
+			return SetIDResult{}, err
+		}
+		defer cleanup()
+		iconFile, _, err = s.iconOrGenerated(ctx, IconFile{}, workspacePath, installedApp.Name, targetID)
+		if err != nil {
+			return SetIDResult{}, err
+		}
+	}
+	installedIconPath, err := s.iconInstaller.Install(ctx, iconFile.Path, targetID)
 	if err != nil {
 		return SetIDResult{}, err
 	}
@@ -843,25 +1381,56 @@ func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string)
 		return removeInstalledArtifact(ctx, installedIconPath, s.artifactRemover)
 	})
 
-	updatedDesktopEntry := metadata.desktopEntry.
-		WithExec(installedAppImagePath).
-		WithIcon(installedIconPath)
-	installedDesktopEntryPath, err := s.desktopEntryInstaller.Install(ctx, targetID, updatedDesktopEntry.Bytes())
+	installedIconSizePaths, err := s.installIconSizes(ctx, iconFile.Path, iconFile.Sizes, targetID, installedIconPath)
 	if err != nil {
 		return SetIDResult{}, err
 	}
-	rollback.add(func(ctx context.Context) error {
-		return removeInstalledArtifact(ctx, installedDesktopEntryPath, s.artifactRemover)
-	})
+	installedMimePackagePaths, err := s.installMimePackages(ctx, targetID, installedApp.MimePackagePaths)
+	if err != nil {
+		return SetIDResult{}, err
+	}
+	for _, path := range slices.Concat(installedIconSizePaths, installedMimePackagePaths) {
+		rollback.add(func(ctx context.Context) error {
+			return removeInstalledArtifact(ctx, path, s.artifactRemover)
+		})
+	}
 
 	updatedApp := domain.NewAppFromDesktopEntry(metadata.desktopEntry, domain.AppInput{
 		ID:               targetID,
 		AppImagePath:     installedAppImagePath,
-		DesktopEntryPath: installedDesktopEntryPath,
 		IconPath:         installedIconPath,
+		IconSizePaths:    installedIconSizePaths,
+		GeneratedIcon:    installedApp.GeneratedIcon,
+		MimePackagePaths: installedMimePackagePaths,
 		Source:           installedApp.Source,
 		UpdateSource:     installedApp.UpdateSource,
+		Sandbox:          installedApp.Sandbox,
+		Launch:           installedApp.Launch,
 	})
+	updatedDesktopEntry := metadata.desktopEntry.
+		WithExec(desktopExec(updatedApp)).
+		WithIcon(installedIconPath)
+	installedDesktopEntryPath, err := s.desktopEntryInstaller.Install(ctx, targetID, updatedDesktopEntry.Bytes())
+	if err != nil {
+		return SetIDResult{}, err
+	}
+	rollback.add(func(ctx context.Context) error {
+		return removeInstalledArtifact(ctx, installedDesktopEntryPath, s.artifactRemover)
+	})
+	updatedApp.DesktopEntryPath = installedDesktopEntryPath
+
+	// The command keeps its name, so it is rewritten in place for the new
+	// AppImage path.
+	updatedApp.BinPath = installedApp.BinPath
+	updatedApp, err = s.refreshBin(ctx, updatedApp)
+	if err != nil {
+		return SetIDResult{}, err
+	}
+	rollback.add(func(ctx context.Context) error {
+		_, err := s.refreshBin(ctx, installedApp)
+		return err
+	})
+
 	rollback.add(func(ctx context.Context) error {
 		return s.apps.Delete(ctx, updatedApp.ID)
 	})
@@ -872,10 +1441,16 @@ func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string)
 		return SetIDResult{}, err
 	}
 	committed = true
+	if err := undo.save(ctx, updatedApp); err != nil {
+		return SetIDResult{}, fmt.Errorf("updated id from %s to %s but failed to record it for undo: %w", installedApp.ID, updatedApp.ID, err)
+	}
 
 	if err := s.removeReplacedArtifacts(ctx, installedApp, updatedApp); err != nil {
 		return SetIDResult{}, fmt.Errorf("updated id from %s to %s but failed to remove replaced artifacts: %w", installedApp.ID, updatedApp.ID, err)
 	}
+	if err := s.renameMimeAssociations(ctx, installedApp, updatedApp); err != nil {
+		return SetIDResult{}, fmt.Errorf("updated id from %s to %s but failed to update the default applications: %w", installedApp.ID, updatedApp.ID, err)
+	}
 	if s.desktopIntegrationRefresher != nil {
 		_ = s.desktopIntegrationRefresher.Refresh(ctx)
 	}
@@ -886,6 +1461,7 @@ func (s *service) setID(ctx context.Context, req SetIDRequest, currentID string)
 type installedAppImageIDMetadata struct {
 	app          domain.App
 	desktopEntry domain.DesktopEntry
+	rootDir      string
 }
 
 func (s *service) inspectInstalledAppImageForID(ctx context.Context, appImagePath string) (installedAppImageIDMetadata, error) {
@@ -895,6 +1471,10 @@ func (s *service) inspectInstalledAppImageForID(ctx context.Context, appImagePat
 	}
 	defer cleanup()
 
+	return s.inspectInstalledAppImageInWorkspace(ctx, appImagePath, workspacePath)
+}
+
+func (s *service) inspectInstalledAppImageInWorkspace(ctx context.Context, appImagePath string, workspacePath string) (installedAppImageIDMetadata, error) {
 	extraction, err := s.appImages.Extract(ctx, appImagePath, filepath.Join(workspacePath, "extract"))
 	if err != nil {
 		return installedAppImageIDMetadata{}, err
@@ -908,10 +1488,18 @@ func (s *service) inspectInstalledAppImageForID(ctx context.Context, appImagePat
 		return installedAppImageIDMetadata{}, err
 	}
 	app := domain.NewAppFromDesktopEntry(desktopEntry, domain.AppInput{AppImagePath: appImagePath})
-	return installedAppImageIDMetadata{app: app, desktopEntry: desktopEntry}, nil
+	return installedAppImageIDMetadata{app: app, desktopEntry: desktopEntry, rootDir: extraction.RootDir}, nil
 }
 
 func (s *service) removeInstalledAppArtifacts(ctx context.Context, installedApp domain.App) error {
+	if err := removeInstalledArtifact(ctx, installedApp.BinPath, s.artifactRemover); err != nil {
+		return err
+	}
+	for _, path := range listedArtifactPaths(installedApp) {
+		if err := removeInstalledArtifact(ctx, path, s.artifactRemover); err != nil {
+			return err
+		}
+	}
 	if err := removeInstalledArtifact(ctx, installedApp.DesktopEntryPath, s.artifactRemover); err != nil {
 		return err
 	}
@@ -925,23 +1513,43 @@ func (s *service) removeInstalledAppArtifacts(ctx context.Context, installedApp
 }
 
 func (s *service) removeReplacedArtifacts(ctx context.Context, previous domain.App, next domain.App) error {
-	if previous.DesktopEntryPath != "" && previous.DesktopEntryPath != next.DesktopEntryPath {
-		if err := s.artifactRemover(ctx, previous.DesktopEntryPath); err != nil {
+	for _, path := range replacedArtifactPaths(previous, next) {
+		if err := s.artifactRemover(ctx, path); err != nil {
 			return err
 		}
 	}
-	if previous.IconPath != "" && previous.IconPath != next.IconPath {
-		if err := s.artifactRemover(ctx, previous.IconPath); err != nil {
-			return err
+
+	return nil
+}
+
+// listedArtifactPaths returns the artifacts installedApp tracks in lists
+// rather than in fields of their own.
+func listedArtifactPaths(installedApp domain.App) []string {
+	return slices.Concat(installedApp.IconSizePaths, installedApp.MimePackagePaths)
+}
+
+// replacedArtifactPaths lists artifacts of previous that next no longer uses.
+func replacedArtifactPaths(previous domain.App, next domain.App) []string {
+	nextListed := listedArtifactPaths(next)
+	paths := make([]string, 0, 4+len(previous.IconSizePaths)+len(previous.MimePackagePaths))
+	if previous.BinPath != "" && previous.BinPath != next.BinPath {
+		paths = append(paths, previous.BinPath)
+	}
+	for _, path := range listedArtifactPaths(previous) {
+		if path != "" && path != next.IconPath && !slices.Contains(nextListed, path) {
+			paths = append(paths, path)
 		}
 	}
+	if previous.DesktopEntryPath != "" && previous.DesktopEntryPath != next.DesktopEntryPath {
+		paths = append(paths, previous.DesktopEntryPath)
+	}
+	if previous.IconPath != "" && previous.IconPath != next.IconPath && !slices.Contains(nextListed, previous.IconPath) {
+		paths = append(paths, previous.IconPath)
+	}
 	if previous.AppImagePath != "" && previous.AppImagePath != next.AppImagePath {
-		if err := s.artifactRemover(ctx, previous.AppImagePath); err != nil {
-			return err
-		}
+		paths = append(paths, previous.AppImagePath)
 	}
-
-	return nil
+	return paths
 }
 
 func updateVersion(release GitHubRelease, asset GitHubReleaseAsset) (domain.Version, bool) {
@@ -974,6 +1582,11 @@ func (s *service) SetUpdateSource(ctx context.Context, req SetUpdateSourceReques
 	if err != nil {
 		return SetUpdateSourceResult{}, err
 	}
+	installedApp, unlock, err := s.lockInstalledApp(ctx, installedApp)
+	if err != nil {
+		return SetUpdateSourceResult{}, err
+	}
+	defer unlock()
 
 	var updateSource domain.UpdateSource
 	if strings.TrimSpace(req.GitHubRepo) != "" {
@@ -990,8 +1603,7 @@ func (s *service) SetUpdateSource(ctx context.Context, req SetUpdateSourceReques
 		}
 	}
 
-	installedApp.UpdateSource = updateSource
-	if err := s.apps.Save(ctx, installedApp); err != nil {
+	if err := s.storeUpdateSource(ctx, installedApp, updateSource); err != nil {
 		return SetUpdateSourceResult{}, err
 	}
 
@@ -1034,8 +1646,7 @@ func (s *service) UnsetUpdateSource(ctx context.Context, req UnsetUpdateSourceRe
 	if err != nil {
 		return err
 	}
-	installedApp.UpdateSource = domain.UpdateSource{}
-	return s.apps.Save(ctx, installedApp)
+	return s.saveUpdateSource(ctx, installedApp, domain.UpdateSource{})
 }
 
 func (s *service) List(ctx context.Context, req ListRequest) (ListResult, error) {
@@ -1051,9 +1662,12 @@ func (s *service) List(ctx context.Context, req ListRequest) (ListResult, error)
 	items := make([]ListItem, 0, len(apps))
 	for _, app := range apps {
 		items = append(items, ListItem{
-			ID:      app.ID,
-			Name:    app.Name,
-			Version: app.Version.String(),
+			ID:           app.ID,
+			Name:         app.LocalizedName(s.config.Locale),
+			GenericName:  app.GenericName.Localize(s.config.Locale),
+			Comment:      app.Comment.Localize(s.config.Locale),
+			Version:      app.Version.String(),
+			Translations: translationsFromApp(app),
 		})
 	}
 
@@ -1078,7 +1692,7 @@ func (s *service) Info(ctx context.Context, req InfoRequest) (InfoResult, error)
 		return InfoResult{}, err
 	}
 
-	return infoResultFromApp(app, true, "installed"), nil
+	return infoResultFromApp(app, s.config.Locale, true, "installed"), nil
 }
 
 func (s *service) infoLocal(ctx context.Context, path string) (InfoResult, error) {
@@ -1087,13 +1701,16 @@ func (s *service) infoLocal(ctx context.Context, path string) (InfoResult, error
 		return InfoResult{}, err
 	}
 
-	return infoResultFromApp(metadata.app, false, "local_path"), nil
+	return infoResultFromApp(metadata.app, s.config.Locale, false, "local_path"), nil
 }
 
-func infoResultFromApp(app domain.App, installed bool, targetKind string) InfoResult {
+func infoResultFromApp(app domain.App, locale string, installed bool, targetKind string) InfoResult {
 	return InfoResult{
 		ID:           app.ID,
-		Name:         app.Name,
+		Name:         app.LocalizedName(locale),
+		GenericName:  app.GenericName.Localize(locale),
+		Comment:      app.Comment.Localize(locale),
+		Translations: translationsFromApp(app),
 		Version:      app.Version.String(),
 		ExecPath:     app.AppImagePath,
 		Installed:    installed,
@@ -1103,6 +1720,14 @@ func infoResultFromApp(app domain.App, installed bool, targetKind string) InfoRe
 	}
 }
 
+func translationsFromApp(app domain.App) Translations {
+	return Translations{
+		Name:        app.NameTranslations,
+		GenericName: app.GenericName.Translations,
+		Comment:     app.Comment.Translations,
+	}
+}
+
 func looksLikeLocalAppImagePath(target string) bool {
 	return filepath.IsAbs(target) ||
 		strings.HasPrefix(target, "."+string(filepath.Separator)) ||
@@ -1218,9 +1843,16 @@ func (s *service) Paths(ctx context.Context, req PathsRequest) (PathsResult, err
 		AppImageDir: s.config.AppImageDir,
 		DesktopDir:  s.config.DesktopDir,
 		IconDir:     s.config.IconDir,
+		BinDir:      s.config.BinDir,
 	}, nil
 }
 
+// desktopExec returns the desktop entry Exec command that launches app as
+// configured.
+func desktopExec(app domain.App) string {
+	return domain.DesktopExec(app.DesktopCommand())
+}
+
 func withFallbackVersion(entry domain.DesktopEntry, fallbackVersion string) domain.DesktopEntry {
 	if !entry.Version.IsZero() {
 		return entry
@@ -1261,6 +1893,9 @@ func (s *service) validate() error {
 	if s.appImageInstaller == nil {
 		return fmt.Errorf("appimage installer is required")
 	}
+	if (s.mimePackages == nil) != (s.mimePackageInstaller == nil) {
+		return fmt.Errorf("mime package discoverer and installer are required together")
+	}
 	if s.iconInstaller == nil {
 		return fmt.Errorf("icon installer is required")
 	}
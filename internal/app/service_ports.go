@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/slobbe/appimage-manager/internal/domain"
 )
@@ -13,6 +14,28 @@ type Service interface {
 	SetUpdateSource(ctx context.Context, req SetUpdateSourceRequest) (SetUpdateSourceResult, error)
 	UnsetUpdateSource(ctx context.Context, req UnsetUpdateSourceRequest) error
 	SetID(ctx context.Context, req SetIDRequest) (SetIDResult, error)
+	SetSandbox(ctx context.Context, req SetSandboxRequest) (SetSandboxResult, error)
+	ConfigureApp(ctx context.Context, req ConfigureAppRequest) (ConfigureAppResult, error)
+	Run(ctx context.Context, req RunRequest) error
+	SetBin(ctx context.Context, req SetBinRequest) (SetBinResult, error)
+	SetDefault(ctx context.Context, req SetDefaultRequest) (SetDefaultResult, error)
+	Repair(ctx context.Context, req RepairRequest) (RepairResult, error)
+	Scan(ctx context.Context, req ScanRequest) (ScanResult, error)
+	Adopt(ctx context.Context, req AdoptRequest) (AdoptResult, error)
+	Migrate(ctx context.Context, req MigrateRequest) (MigrateResult, error)
+	Sync(ctx context.Context, req SyncRequest) (SyncResult, error)
+	Lock(ctx context.Context, req LockRequest) (LockResult, error)
+	Recover(ctx context.Context, req RecoverRequest) (RecoverResult, error)
+	BackupDatabase(ctx context.Context, req BackupDatabaseRequest) (BackupDatabaseResult, error)
+	RestoreDatabase(ctx context.Context, req RestoreDatabaseRequest) (RestoreDatabaseResult, error)
+	History(ctx context.Context, req HistoryRequest) (HistoryResult, error)
+	Undo(ctx context.Context, req UndoRequest) (UndoResult, error)
+	EnableAutoUpdate(ctx context.Context, req EnableAutoUpdateRequest) (AutoUpdateStatus, error)
+	DisableAutoUpdate(ctx context.Context) (DisableAutoUpdateResult, error)
+	AutoUpdateStatus(ctx context.Context) (AutoUpdateStatus, error)
+	Watch(ctx context.Context, req WatchRequest) error
+	Export(ctx context.Context, req ExportRequest) (ExportResult, error)
+	Import(ctx context.Context, req ImportRequest) (ImportResult, error)
 	List(ctx context.Context, req ListRequest) (ListResult, error)
 	Info(ctx context.Context, req InfoRequest) (InfoResult, error)
 	SelfUpdate(ctx context.Context, req SelfUpdateRequest) (SelfUpdateResult, error)
@@ -24,7 +47,13 @@ type AddRequest struct {
 	GitHubRepo   string
 	AssetPattern string
 	Prerelease   bool
-	Activity     ActivityReporter
+	// Launch sets the arguments and environment the app is launched with.
+	Launch domain.LaunchOptions
+	// Bin installs a command for the app into Config.BinDir, called BinName
+	// or the app ID.
+	Bin      bool
+	BinName  string
+	Activity ActivityReporter
 }
 
 type AddResult struct {
@@ -32,13 +61,19 @@ type AddResult struct {
 }
 
 type RemoveRequest struct {
-	Name     string
+	Name string
+	// Trash moves the AppImage to the trash instead of deleting it, as if
+	// Config.TrashRemovedApps were set.
+	Trash    bool
 	Activity ActivityReporter
 }
 
 type UpdateRequest struct {
-	Target       string
-	CheckOnly    bool
+	Target    string
+	CheckOnly bool
+	// Notify reports the updates found in check mode, or the updates applied
+	// and failed otherwise, with a desktop notification.
+	Notify       bool
 	Activity     ActivityReporter
 	Confirmation UpdateConfirmation
 }
@@ -95,25 +130,415 @@ type SetIDResult struct {
 	Changed    bool
 }
 
+// SetSandboxRequest confines app ID with Profile, or runs it unconfined again
+// with Off. Backend overrides the backend the profile would use.
+type SetSandboxRequest struct {
+	ID      string
+	Profile string
+	Backend domain.SandboxBackend
+	Off     bool
+}
+
+type SetSandboxResult struct {
+	ID      string
+	Sandbox domain.Sandbox
+}
+
+// ConfigureAppRequest changes the arguments and environment app ID is
+// launched with. Clear drops the current ones first; Args replaces the
+// arguments when ReplaceArgs is set; Env sets variables and UnsetEnv removes
+// them. A request without changes reports the current settings.
+type ConfigureAppRequest struct {
+	ID          string
+	Clear       bool
+	ReplaceArgs bool
+	Args        []string
+	Env         map[string]string
+	UnsetEnv    []string
+}
+
+type ConfigureAppResult struct {
+	ID      string
+	Launch  domain.LaunchOptions
+	Changed bool
+}
+
+// SetBinRequest installs the command of app ID called Name, the current name,
+// or the app ID, in that order, or removes it with Off.
+type SetBinRequest struct {
+	ID   string
+	Name string
+	Off  bool
+}
+
+type SetBinResult struct {
+	ID string
+	// Path is the installed command, or empty after Off.
+	Path string
+}
+
+// SetDefaultRequest makes app ID the default application for MimeTypes, or
+// for every MIME type its desktop entry lists when MimeTypes is empty.
+type SetDefaultRequest struct {
+	ID        string
+	MimeTypes []string
+}
+
+type SetDefaultResult struct {
+	ID          string
+	DesktopFile string
+	MimeTypes   []string
+}
+
+// RunRequest launches app ID with its configured arguments, environment, and
+// sandbox, followed by Args.
+type RunRequest struct {
+	ID   string
+	Args []string
+}
+
+type RepairRequest struct {
+	ID       string
+	All      bool
+	Activity ActivityReporter
+}
+
+type RepairFailure struct {
+	AppID string `json:"app_id"`
+	Error string `json:"error"`
+}
+
+type RepairResult struct {
+	Repaired []string
+	Failures []RepairFailure
+}
+
+type ScanRequest struct {
+	Dirs []string
+}
+
+type ScanResult struct {
+	Items []ScanItem `json:"items"`
+}
+
+type ScanItem struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	Managed   bool   `json:"managed"`
+	AppID     string `json:"app_id,omitempty"`
+}
+
+type AdoptRequest struct {
+	Path     string
+	Move     bool
+	Activity ActivityReporter
+}
+
+type AdoptResult struct {
+	App   domain.App
+	Moved bool
+}
+
+type MigrateRequest struct {
+	From          string
+	Move          bool
+	KeepArtifacts bool
+	Activity      ActivityReporter
+	Confirmation  MigrationConfirmation
+}
+
+type MigrationConfirmation interface {
+	ConfirmMigration(ctx context.Context, candidates []MigrationCandidate) (bool, error)
+}
+
+type MigrationCandidate struct {
+	Tool             string   `json:"tool"`
+	Name             string   `json:"name"`
+	Version          string   `json:"version,omitempty"`
+	AppImagePath     string   `json:"app_image_path"`
+	DesktopEntryPath string   `json:"desktop_entry_path"`
+	IconPaths        []string `json:"icon_paths,omitempty"`
+}
+
+type MigrationFailure struct {
+	AppImagePath string `json:"app_image_path"`
+	Error        string `json:"error"`
+}
+
+type MigratedApp struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	AppImagePath string `json:"app_image_path"`
+	Moved        bool   `json:"moved"`
+}
+
+type MigrateResult struct {
+	Applied    bool
+	Candidates []MigrationCandidate
+	Migrated   []MigratedApp
+	Failures   []MigrationFailure
+}
+
+type SyncRequest struct {
+	Manifest string
+	Prune    bool
+	DryRun   bool
+	// Locked installs the artifacts recorded in the lock file next to the
+	// manifest and rejects downloads whose SHA-256 differs.
+	Locked       bool
+	Activity     ActivityReporter
+	Confirmation SyncConfirmation
+}
+
+type SyncConfirmation interface {
+	ConfirmSync(ctx context.Context, actions []SyncAction) (bool, error)
+}
+
+type SyncActionKind string
+
+const (
+	SyncActionInstall         SyncActionKind = "install"
+	SyncActionInstallVersion  SyncActionKind = "install_version"
+	SyncActionSetUpdateSource SyncActionKind = "set_update_source"
+	SyncActionRemove          SyncActionKind = "remove"
+)
+
+type SyncAction struct {
+	Kind       SyncActionKind `json:"kind"`
+	AppID      string         `json:"app_id"`
+	GitHubRepo string         `json:"github_repo,omitempty"`
+	URL        string         `json:"url,omitempty"`
+	Version    string         `json:"version,omitempty"`
+}
+
+type SyncFailure struct {
+	AppID string `json:"app_id"`
+	Error string `json:"error"`
+}
+
+type SyncResult struct {
+	Manifest string
+	Applied  bool
+	Actions  []SyncAction
+	Failures []SyncFailure
+}
+
+type LockRequest struct {
+	Manifest string
+	// Update re-resolves locked apps; with ID set only that app is refreshed.
+	Update   bool
+	ID       string
+	Activity ActivityReporter
+}
+
+type LockResult struct {
+	Path    string
+	Apps    []LockedApp
+	Changed []string
+}
+
+type RecoverRequest struct{}
+
+type RecoveryAction string
+
+const (
+	RecoveryRolledBack    RecoveryAction = "rolled_back"
+	RecoveryRolledForward RecoveryAction = "rolled_forward"
+)
+
+type RecoveredOperation struct {
+	ID     string         `json:"id"`
+	Kind   JournalKind    `json:"kind"`
+	AppID  string         `json:"app_id"`
+	Action RecoveryAction `json:"action"`
+	Note   string         `json:"note,omitempty"`
+}
+
+type RecoverFailure struct {
+	ID    string `json:"id"`
+	AppID string `json:"app_id"`
+	Error string `json:"error"`
+}
+
+type RecoverResult struct {
+	Recovered []RecoveredOperation
+	Failures  []RecoverFailure
+}
+
+type BackupDatabaseRequest struct {
+	Path string
+}
+
+type BackupDatabaseResult struct {
+	Backup DatabaseBackup
+}
+
+type RestoreDatabaseRequest struct {
+	Path         string
+	Confirmation DatabaseRestoreConfirmation
+}
+
+type DatabaseRestoreConfirmation interface {
+	ConfirmRestore(ctx context.Context, backup DatabaseBackup) (bool, error)
+}
+
+type RestoreDatabaseResult struct {
+	Applied  bool
+	Restored DatabaseBackup
+	// Previous is the backup made of the replaced database; its Path is empty
+	// when there was no database to replace.
+	Previous DatabaseBackup
+}
+
+// HistoryRequest filters the history log. ID matches the current or previous
+// app ID of an event, and a zero Since returns the whole log.
+type HistoryRequest struct {
+	ID    string
+	Since time.Time
+}
+
+type HistoryResult struct {
+	Events []HistoryEvent
+}
+
+type UndoRequest struct {
+	Confirmation UndoConfirmation
+}
+
+type UndoConfirmation interface {
+	ConfirmUndo(ctx context.Context, operation UndoneOperation) (bool, error)
+}
+
+type UndoResult struct {
+	Applied   bool
+	Operation UndoneOperation
+}
+
+// UndoneOperation describes the operation undo reverts. AppID and Version are
+// what the operation left installed, and RestoredID and RestoredVersion what
+// undo brings back; either side is empty for adds and removals.
+type UndoneOperation struct {
+	Operation       HistoryOperation `json:"operation"`
+	At              time.Time        `json:"at"`
+	AppID           string           `json:"app_id,omitempty"`
+	Version         string           `json:"version,omitempty"`
+	RestoredID      string           `json:"restored_id,omitempty"`
+	RestoredVersion string           `json:"restored_version,omitempty"`
+}
+
+// EnableAutoUpdateRequest schedules unattended updates. An empty Interval
+// means daily.
+type EnableAutoUpdateRequest struct {
+	Interval  string
+	CheckOnly bool
+}
+
+type DisableAutoUpdateResult struct {
+	Disabled bool
+}
+
+// AutoUpdateStatus is the update schedule together with what it last did.
+// LastRun comes from the scheduler when it records runs, and LastUpdate is
+// the most recent update in the history log, scheduled or not.
+type AutoUpdateStatus struct {
+	Enabled    bool           `json:"enabled"`
+	Backend    string         `json:"backend,omitempty"`
+	Interval   UpdateInterval `json:"interval,omitempty"`
+	CheckOnly  bool           `json:"check_only"`
+	LastRun    *ScheduledRun  `json:"last_run,omitempty"`
+	LastUpdate *HistoryEvent  `json:"last_update,omitempty"`
+}
+
+// WatchRequest watches Dirs, or the download directory when it is empty, for
+// new AppImages. AutoAdd integrates them right away; otherwise a notification
+// offers to integrate each one.
+type WatchRequest struct {
+	Dirs    []string
+	AutoAdd bool
+	Events  WatchReporter
+}
+
+// WatchReporter receives what Watch does. Its methods may be called
+// concurrently.
+type WatchReporter interface {
+	Watching(dirs []string)
+	Found(path string)
+	Added(path string, app domain.App)
+	Failed(path string, err error)
+}
+
+type ExportRequest struct {
+	Path string
+}
+
+type ExportResult struct {
+	Path string
+	Apps []string
+}
+
+type ImportRequest struct {
+	Path     string
+	Activity ActivityReporter
+}
+
+type ImportedApp struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+type ImportSkip struct {
+	AppID  string `json:"app_id"`
+	Reason string `json:"reason"`
+}
+
+type ImportFailure struct {
+	AppID string `json:"app_id"`
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	Imported []ImportedApp
+	Skipped  []ImportSkip
+	Failures []ImportFailure
+}
+
 type ListRequest struct{}
 
 type ListResult struct {
 	Items []ListItem `json:"items"`
 }
 
+// ListItem shows the name, generic name and comment of an app translated for
+// Config.Locale, with every translation in Translations.
 type ListItem struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	GenericName  string       `json:"generic_name,omitempty"`
+	Comment      string       `json:"comment,omitempty"`
+	Version      string       `json:"version"`
+	Translations Translations `json:"translations"`
+}
+
+// Translations are the translations of an app's desktop entry values by
+// locale, such as "de" or "pt_BR".
+type Translations struct {
+	Name        map[string]string `json:"name,omitempty"`
+	GenericName map[string]string `json:"generic_name,omitempty"`
+	Comment     map[string]string `json:"comment,omitempty"`
 }
 
 type InfoRequest struct {
 	Target string
 }
 
+// InfoResult shows the name, generic name and comment of an app translated for
+// Config.Locale, with every translation in Translations.
 type InfoResult struct {
 	ID           string
 	Name         string
+	GenericName  string
+	Comment      string
+	Translations Translations
 	Version      string
 	ExecPath     string
 	Installed    bool
@@ -151,4 +576,5 @@ type PathsResult struct {
 	AppImageDir string `json:"appimage_dir"`
 	DesktopDir  string `json:"desktop_dir"`
 	IconDir     string `json:"icon_dir"`
+	BinDir      string `json:"bin_dir"`
 }
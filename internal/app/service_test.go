@@ -3,8 +3,10 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -374,6 +376,27 @@ func TestServiceRemoveRemovesArtifactsAndDeletesApp(t *testing.T) {
 	}
 }
 
+func TestServiceRemoveMovesAppImageToTrash(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	trash := &fakeArtifactRemover{}
+	deps.Trash = trash.Remove
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID, Trash: true}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	assertRemovedPaths(t, trash.paths, []string{installed.AppImagePath})
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{installed.DesktopEntryPath, installed.IconPath})
+}
+
 func TestServiceRemoveSkipsEmptyArtifactPaths(t *testing.T) {
 	t.Parallel()
 
@@ -1604,13 +1627,44 @@ func TestServiceListReturnsInstalledApps(t *testing.T) {
 		{ID: "example-app", Name: "Example App", Version: "1.2.3"},
 		{ID: "another-app", Name: "Another App", Version: "2.0.0"},
 	}
-	if len(result.Items) != len(want) {
+	if !reflect.DeepEqual(result.Items, want) {
 		t.Fatalf("List() items = %#v, want %#v", result.Items, want)
 	}
-	for i := range want {
-		if result.Items[i] != want[i] {
-			t.Fatalf("List() items = %#v, want %#v", result.Items, want)
-		}
+}
+
+func TestServiceListLocalizesNamesAndDescriptions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Config.Locale = "de_DE.UTF-8"
+	localized := testInstalledApp(t)
+	localized.NameTranslations = map[string]string{"de": "Beispiel-App", "fr": "Application exemple"}
+	localized.GenericName = domain.LocalizedString{Value: "Text Editor", Translations: map[string]string{"de": "Texteditor"}}
+	localized.Comment = domain.LocalizedString{Value: "Edit text"}
+	deps.apps.listApps = []domain.App{localized}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.List(context.Background(), ListRequest{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := []ListItem{{
+		ID:          "example-app",
+		Name:        "Beispiel-App",
+		GenericName: "Texteditor",
+		Comment:     "Edit text",
+		Version:     "1.2.3",
+		Translations: Translations{
+			Name:        map[string]string{"de": "Beispiel-App", "fr": "Application exemple"},
+			GenericName: map[string]string{"de": "Texteditor"},
+		},
+	}}
+	if !reflect.DeepEqual(result.Items, want) {
+		t.Fatalf("List() items = %#v, want %#v", result.Items, want)
 	}
 }
 
@@ -1676,6 +1730,40 @@ func TestServiceInfoReturnsInstalledApp(t *testing.T) {
 	}
 }
 
+func TestServiceInfoLocalizesNamesAndDescriptions(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.Config.Locale = "pt_BR.UTF-8"
+	installed := testInstalledApp(t)
+	installed.NameTranslations = map[string]string{"pt": "Aplicação de exemplo", "pt_BR": "Aplicativo de exemplo"}
+	installed.Comment = domain.LocalizedString{Value: "An example app", Translations: map[string]string{"pt": "Uma aplicação de exemplo"}}
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Info(context.Background(), InfoRequest{Target: "example-app"})
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	if got, want := result.Name, "Aplicativo de exemplo"; got != want {
+		t.Fatalf("Info().Name = %q, want %q", got, want)
+	}
+	if got, want := result.Comment, "Uma aplicação de exemplo"; got != want {
+		t.Fatalf("Info().Comment = %q, want %q", got, want)
+	}
+	if got := result.GenericName; got != "" {
+		t.Fatalf("Info().GenericName = %q, want empty", got)
+	}
+	want := Translations{Name: installed.NameTranslations, Comment: installed.Comment.Translations}
+	if !reflect.DeepEqual(result.Translations, want) {
+		t.Fatalf("Info().Translations = %#v, want %#v", result.Translations, want)
+	}
+}
+
 func TestServiceInfoInspectsLocalAppImageWithoutInstalling(t *testing.T) {
 	t.Parallel()
 
@@ -1802,6 +1890,7 @@ func TestServicePathsReturnsConfiguredPaths(t *testing.T) {
 		AppImageDir: "/data/aim/appimages",
 		DesktopDir:  "/data/applications",
 		IconDir:     "/data/icons",
+		BinDir:      "/home/bin",
 	}
 	service, err := NewService(deps.ServiceDeps)
 	if err != nil {
@@ -1825,6 +1914,9 @@ func TestServicePathsReturnsConfiguredPaths(t *testing.T) {
 	if got, want := result.IconDir, deps.ServiceDeps.Config.IconDir; got != want {
 		t.Fatalf("Paths().IconDir = %q, want %q", got, want)
 	}
+	if got, want := result.BinDir, deps.ServiceDeps.Config.BinDir; got != want {
+		t.Fatalf("Paths().BinDir = %q, want %q", got, want)
+	}
 }
 
 func TestServiceAddFromGitHubIntegratesDownloadedAppImage(t *testing.T) {
@@ -2231,6 +2323,7 @@ type fakeIconDiscoverer struct {
 	rootDir  string
 	iconName string
 	path     string
+	sizes    []string
 	err      error
 }
 
@@ -2241,7 +2334,7 @@ func (f *fakeIconDiscoverer) Discover(ctx context.Context, rootDir string, iconN
 	if f.err != nil {
 		return IconFile{}, f.err
 	}
-	return IconFile{Path: f.path}, nil
+	return IconFile{Path: f.path, Sizes: f.sizes}, nil
 }
 
 type fakeInstallCall struct {
@@ -2262,6 +2355,8 @@ type fakeAppImageInstaller struct {
 	paths      map[string]string
 	calls      []fakeInstallCall
 	err        error
+
+	adoptedPath string
 }
 
 func (f *fakeAppImageInstaller) Install(ctx context.Context, sourcePath string, appID string) (string, error) {
@@ -2278,13 +2373,25 @@ func (f *fakeAppImageInstaller) Install(ctx context.Context, sourcePath string,
 	return f.path, nil
 }
 
+func (f *fakeAppImageInstaller) Adopt(ctx context.Context, path string) (string, error) {
+	f.adoptedPath = path
+	if f.err != nil {
+		return "", f.err
+	}
+	return path, nil
+}
+
 type fakeIconInstaller struct {
 	sourcePath string
 	appID      string
 	path       string
 	paths      map[string]string
 	calls      []fakeInstallCall
-	err        error
+	// sizeSources are the sources of the last InstallSizes call. The first
+	// one is installed like Install does, the nth further one as
+	// /icons/hicolor/<n>/apps/<appID>.png.
+	sizeSources []string
+	err         error
 }
 
 func (f *fakeIconInstaller) Install(ctx context.Context, sourcePath string, appID string) (string, error) {
@@ -2300,6 +2407,24 @@ func (f *fakeIconInstaller) Install(ctx context.Context, sourcePath string, appI
 	return f.path, nil
 }
 
+func (f *fakeIconInstaller) InstallSizes(ctx context.Context, sourcePaths []string, appID string) ([]string, error) {
+	f.sizeSources = sourcePaths
+	if f.err != nil {
+		return nil, f.err
+	}
+	installed := make([]string, len(sourcePaths))
+	for i := range sourcePaths {
+		installed[i] = fmt.Sprintf("/icons/hicolor/%d/apps/%s.png", i, appID)
+	}
+	if len(installed) > 0 {
+		installed[0] = f.path
+		if path := f.paths[appID]; path != "" {
+			installed[0] = path
+		}
+	}
+	return installed, nil
+}
+
 type fakeDesktopEntryInstaller struct {
 	appID   string
 	content []byte
@@ -2500,6 +2625,9 @@ func (f *fakeGitHubReleaseFinder) ReleaseByTag(ctx context.Context, repo string,
 	return f.release, nil
 }
 
+// testDownloadSHA256 is the SHA-256 of the content fakeAssetDownloader writes.
+const testDownloadSHA256 = "8b408ed68dfd56d503752ff2ee2ecb3c0ffa55a26f6fa107bd4444c3943ee6e1"
+
 type fakeAssetDownloader struct {
 	source          DownloadSource
 	destinationPath string
@@ -2521,6 +2649,10 @@ func (f *fakeAssetDownloader) Download(ctx context.Context, source DownloadSourc
 	if err := os.WriteFile(f.downloaded.Path, []byte("appimage"), 0o755); err != nil {
 		return DownloadedFile{}, err
 	}
+	if f.downloaded.SHA256 == "" {
+		f.downloaded.SHA256 = testDownloadSHA256
+		f.downloaded.SizeBytes = int64(len("appimage"))
+	}
 
 	return f.downloaded, nil
 }
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) Export(ctx context.Context, req ExportRequest) (ExportResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ExportResult{}, err
+	}
+	path := strings.TrimSpace(req.Path)
+	if path == "" {
+		return ExportResult{}, errors.New("export path is required")
+	}
+	if s.snapshots == nil {
+		return ExportResult{}, errors.New("snapshot store is required")
+	}
+
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	snapshot := Snapshot{Apps: make([]SnapshotApp, 0, len(apps))}
+	ids := make([]string, 0, len(apps))
+	for _, installedApp := range apps {
+		snapshot.Apps = append(snapshot.Apps, SnapshotApp{
+			ID:           installedApp.ID,
+			Name:         installedApp.Name,
+			Version:      installedApp.Version.String(),
+			Source:       installedApp.Source,
+			UpdateSource: installedApp.UpdateSource,
+		})
+		ids = append(ids, installedApp.ID)
+	}
+	if err := s.snapshots.Write(ctx, path, snapshot); err != nil {
+		return ExportResult{}, err
+	}
+
+	return ExportResult{Path: path, Apps: ids}, nil
+}
+
+func (s *service) Import(ctx context.Context, req ImportRequest) (ImportResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ImportResult{}, err
+	}
+	path := strings.TrimSpace(req.Path)
+	if path == "" {
+		return ImportResult{}, errors.New("import path is required")
+	}
+	if s.snapshots == nil {
+		return ImportResult{}, errors.New("snapshot store is required")
+	}
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	snapshot, err := s.snapshots.Read(ctx, path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	installed := make(map[string]bool, len(apps))
+	for _, installedApp := range apps {
+		installed[installedApp.ID] = true
+	}
+
+	result := ImportResult{
+		Imported: make([]ImportedApp, 0, len(snapshot.Apps)),
+		Skipped:  make([]ImportSkip, 0),
+		Failures: make([]ImportFailure, 0),
+	}
+	for _, snapshotApp := range snapshot.Apps {
+		id := domain.Slugify(snapshotApp.ID)
+		if id == "" {
+			result.Failures = append(result.Failures, ImportFailure{AppID: snapshotApp.ID, Error: "app id is required"})
+			continue
+		}
+		if installed[id] {
+			result.Skipped = append(result.Skipped, ImportSkip{AppID: id, Reason: "already installed"})
+			continue
+		}
+		if snapshotApp.Source.Kind == domain.SourceKindLocal || snapshotApp.Source.Kind == domain.SourceKindUnknown {
+			result.Skipped = append(result.Skipped, ImportSkip{AppID: id, Reason: "integrated from a local file"})
+			continue
+		}
+
+		added, err := s.importSnapshotApp(ctx, id, snapshotApp, activity)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ImportResult{}, err
+			}
+			result.Failures = append(result.Failures, ImportFailure{AppID: id, Error: err.Error()})
+			continue
+		}
+		installed[id] = true
+		result.Imported = append(result.Imported, ImportedApp{ID: added.App.ID, Version: added.App.Version.String()})
+	}
+
+	return result, nil
+}
+
+// importSnapshotApp reinstalls the exact release or URL an app was installed
+// from and restores its update source unchanged.
+func (s *service) importSnapshotApp(ctx context.Context, id string, snapshotApp SnapshotApp, activity ActivityReporter) (AddResult, error) {
+	switch snapshotApp.Source.Kind {
+	case domain.SourceKindGitHub:
+		release := snapshotApp.Source.GitHubRelease
+		if !validGitHubRepo(release.Repo) || strings.TrimSpace(release.Tag) == "" {
+			return AddResult{}, errors.New("github source requires repo and release tag")
+		}
+		if err := s.requireGitHubDownloads(); err != nil {
+			return AddResult{}, err
+		}
+		return s.addGitHubRelease(ctx, AddRequest{
+			GitHubRepo:   release.Repo,
+			AssetPattern: snapshotApp.UpdateSource.AssetPattern,
+			Activity:     activity,
+		}, activity, githubAddOptions{
+			appID:        id,
+			tag:          release.Tag,
+			assetName:    release.Asset,
+			updateSource: snapshotApp.UpdateSource,
+		})
+	case domain.SourceKindURL:
+		if strings.TrimSpace(snapshotApp.Source.URL.URL) == "" {
+			return AddResult{}, errors.New("url source requires a URL")
+		}
+		return s.addFromURL(ctx, snapshotApp.Source.URL.URL, id, snapshotApp.UpdateSource, "", activity)
+	default:
+		return AddResult{}, fmt.Errorf("unsupported source kind %q", snapshotApp.Source.Kind)
+	}
+}
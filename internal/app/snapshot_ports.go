@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// SnapshotStore writes and reads snapshots of the installed app set.
+//
+// Implementations belong in infrastructure and own the file format. The app
+// layer decides what is exported and how a snapshot is reinstalled.
+type SnapshotStore interface {
+	Write(ctx context.Context, path string, snapshot Snapshot) error
+	Read(ctx context.Context, path string) (Snapshot, error)
+}
+
+// Snapshot records where each installed app came from so the same releases
+// can be installed on another machine.
+type Snapshot struct {
+	Apps []SnapshotApp
+}
+
+// SnapshotApp is one exported app. Installation paths are deliberately left
+// out because they are machine specific.
+type SnapshotApp struct {
+	ID           string
+	Name         string
+	Version      string
+	Source       domain.Source
+	UpdateSource domain.UpdateSource
+}
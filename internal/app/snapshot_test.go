@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceExportWritesInstalledSources(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.Source = domain.NewGitHubReleaseSource("owner/repo", "v1.2.3", "Example.AppImage", "https://example.test/Example.AppImage", 10, testSourceTime())
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	deps.apps.listApps = []domain.App{installed}
+	snapshots := &fakeSnapshotStore{}
+	deps.Snapshots = snapshots
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Export(context.Background(), ExportRequest{Path: "/tmp/aim-export.json"})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if got, want := snapshots.writtenPath, "/tmp/aim-export.json"; got != want {
+		t.Fatalf("written path = %q, want %q", got, want)
+	}
+	if len(result.Apps) != 1 || result.Apps[0] != installed.ID {
+		t.Fatalf("Apps = %#v, want %s", result.Apps, installed.ID)
+	}
+	want := SnapshotApp{ID: installed.ID, Name: installed.Name, Version: "1.2.3", Source: installed.Source, UpdateSource: installed.UpdateSource}
+	if len(snapshots.written.Apps) != 1 || snapshots.written.Apps[0] != want {
+		t.Fatalf("written snapshot = %#v, want %#v", snapshots.written.Apps, want)
+	}
+}
+
+func TestServiceImportReinstallsExactRelease(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	releases := &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.0.0", "Example-x86_64.AppImage", "Example-aarch64.AppImage")}
+	downloads := &fakeAssetDownloader{}
+	deps.GitHubReleases = releases
+	deps.Downloads = downloads
+	updateSource := domain.NewEmbeddedUpdateSource("gh-releases-zsync|owner|repo|latest|Example-*x86_64.AppImage.zsync")
+	deps.Snapshots = &fakeSnapshotStore{snapshot: Snapshot{Apps: []SnapshotApp{{
+		ID:           "example",
+		Source:       domain.NewGitHubReleaseSource("owner/repo", "v1.0.0", "Example-aarch64.AppImage", "", 0, testSourceTime()),
+		UpdateSource: updateSource,
+	}}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Import(context.Background(), ImportRequest{Path: "export.json"})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if releases.method != "tag" || releases.tag != "v1.0.0" {
+		t.Fatalf("release lookup = %s %q, want tag v1.0.0", releases.method, releases.tag)
+	}
+	if got, want := downloads.source.FileName, "Example-aarch64.AppImage"; got != want {
+		t.Fatalf("downloaded asset = %q, want %q", got, want)
+	}
+	if len(result.Imported) != 1 || result.Imported[0].ID != "example" {
+		t.Fatalf("Imported = %#v, want example", result.Imported)
+	}
+	if got := deps.saved.App.UpdateSource; got != updateSource {
+		t.Fatalf("saved UpdateSource = %#v, want %#v", got, updateSource)
+	}
+	if got, want := deps.saved.App.Source.GitHubRelease.Tag, "v1.0.0"; got != want {
+		t.Fatalf("saved Source tag = %q, want %q", got, want)
+	}
+}
+
+func TestServiceImportSkipsInstalledAndLocalApps(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	deps.apps.listApps = []domain.App{installed}
+	deps.Snapshots = &fakeSnapshotStore{snapshot: Snapshot{Apps: []SnapshotApp{
+		{ID: installed.ID, Source: domain.NewGitHubReleaseSource("owner/repo", "v1", "Example.AppImage", "", 0, testSourceTime())},
+		{ID: "local", Source: domain.NewLocalSource("/downloads/Local.AppImage", testSourceTime())},
+		{ID: "broken", Source: domain.NewGitHubReleaseSource("owner/repo", "", "Example.AppImage", "", 0, testSourceTime())},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Import(context.Background(), ImportRequest{Path: "export.json"})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if len(result.Imported) != 0 {
+		t.Fatalf("Imported = %#v, want none", result.Imported)
+	}
+	wantSkipped := []ImportSkip{
+		{AppID: installed.ID, Reason: "already installed"},
+		{AppID: "local", Reason: "integrated from a local file"},
+	}
+	if len(result.Skipped) != len(wantSkipped) || result.Skipped[0] != wantSkipped[0] || result.Skipped[1] != wantSkipped[1] {
+		t.Fatalf("Skipped = %#v, want %#v", result.Skipped, wantSkipped)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].AppID != "broken" {
+		t.Fatalf("Failures = %#v, want broken", result.Failures)
+	}
+}
+
+func TestServiceImportInstallsURLApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	downloads := &fakeAssetDownloader{}
+	deps.Downloads = downloads
+	deps.Snapshots = &fakeSnapshotStore{snapshot: Snapshot{Apps: []SnapshotApp{
+		{ID: "example", Source: domain.NewURLSource("https://example.test/Example.AppImage", testSourceTime())},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Import(context.Background(), ImportRequest{Path: "export.json"}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got, want := downloads.source.URL, "https://example.test/Example.AppImage"; got != want {
+		t.Fatalf("download URL = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.ID, "example"; got != want {
+		t.Fatalf("saved ID = %q, want %q", got, want)
+	}
+}
+
+type fakeSnapshotStore struct {
+	writtenPath string
+	written     Snapshot
+	snapshot    Snapshot
+	err         error
+}
+
+func (f *fakeSnapshotStore) Write(ctx context.Context, path string, snapshot Snapshot) error {
+	f.writtenPath = path
+	f.written = snapshot
+	return f.err
+}
+
+func (f *fakeSnapshotStore) Read(ctx context.Context, path string) (Snapshot, error) {
+	return f.snapshot, f.err
+}
@@ -0,0 +1,303 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func (s *service) Sync(ctx context.Context, req SyncRequest) (SyncResult, error) {
+	if err := ctx.Err(); err != nil {
+		return SyncResult{}, err
+	}
+	if s.manifests == nil {
+		return SyncResult{}, errors.New("manifest loader is required")
+	}
+
+	location := strings.TrimSpace(req.Manifest)
+	if location == "" {
+		location = s.config.ManifestFile
+	}
+	if location == "" {
+		return SyncResult{}, errors.New("manifest location is required")
+	}
+
+	activity := req.Activity
+	if activity == nil {
+		activity = NoopActivityReporter{}
+	}
+
+	manifest, err := s.manifests.Load(ctx, location)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	entries, err := validateManifest(manifest)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("invalid manifest %s: %w", location, err)
+	}
+
+	var locks map[string]LockedApp
+	if req.Locked {
+		locks, err = s.readLocksForSync(ctx, location, entries)
+		if err != nil {
+			return SyncResult{}, err
+		}
+	}
+
+	steps, err := s.planSync(ctx, entries, locks, req.Prune)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	actions := make([]SyncAction, 0, len(steps))
+	for _, step := range steps {
+		actions = append(actions, step.action)
+	}
+	if req.DryRun {
+		return SyncResult{Manifest: location, Applied: false, Actions: actions}, nil
+	}
+	if len(steps) == 0 {
+		return SyncResult{Manifest: location, Applied: true, Actions: actions}, nil
+	}
+
+	if req.Confirmation != nil {
+		confirmed, err := req.Confirmation.ConfirmSync(ctx, actions)
+		if err != nil {
+			return SyncResult{}, err
+		}
+		if !confirmed {
+			return SyncResult{Manifest: location, Applied: false, Actions: actions}, nil
+		}
+	}
+
+	failures := make([]SyncFailure, 0)
+	for _, step := range steps {
+		if err := s.applySyncStep(ctx, step, activity); err != nil {
+			if ctx.Err() != nil {
+				return SyncResult{}, err
+			}
+			failures = append(failures, SyncFailure{AppID: step.action.AppID, Error: err.Error()})
+		}
+	}
+
+	return SyncResult{Manifest: location, Applied: true, Actions: actions, Failures: failures}, nil
+}
+
+type syncStep struct {
+	action    SyncAction
+	entry     ManifestApp
+	installed domain.App
+	// locked is set when syncing from a lock file.
+	locked *LockedApp
+}
+
+// planSync compares the manifest with the app repository. Installed apps are
+// matched by ID; GitHub entries are brought to the declared update source and,
+// when pinned or locked, to the declared release.
+func (s *service) planSync(ctx context.Context, entries []ManifestApp, locks map[string]LockedApp, prune bool) ([]syncStep, error) {
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]domain.App, len(apps))
+	for _, installedApp := range apps {
+		installed[installedApp.ID] = installedApp
+	}
+
+	steps := make([]syncStep, 0)
+	declared := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		declared[entry.ID] = true
+		action := SyncAction{AppID: entry.ID, GitHubRepo: entry.GitHubRepo, URL: entry.URL, Version: entry.Version}
+		var locked *LockedApp
+		if lockedApp, ok := locks[entry.ID]; ok {
+			locked = &lockedApp
+			action.Version = lockedApp.Tag
+		}
+
+		installedApp, ok := installed[entry.ID]
+		if !ok {
+			action.Kind = SyncActionInstall
+			steps = append(steps, syncStep{action: action, entry: entry, locked: locked})
+			continue
+		}
+		if entry.GitHubRepo == "" {
+			continue
+		}
+
+		switch {
+		case locked != nil && !installedFromLockedRelease(installedApp, *locked):
+			action.Kind = SyncActionInstallVersion
+		case locked == nil && entry.Version != "" && !installedFromRelease(installedApp, entry.GitHubRepo, entry.Version):
+			action.Kind = SyncActionInstallVersion
+		case installedApp.UpdateSource != manifestUpdateSource(entry):
+			action.Kind = SyncActionSetUpdateSource
+		default:
+			continue
+		}
+		steps = append(steps, syncStep{action: action, entry: entry, installed: installedApp, locked: locked})
+	}
+
+	if prune {
+		for _, installedApp := range apps {
+			if declared[installedApp.ID] {
+				continue
+			}
+			steps = append(steps, syncStep{
+				action:    SyncAction{Kind: SyncActionRemove, AppID: installedApp.ID},
+				installed: installedApp,
+			})
+		}
+	}
+
+	return steps, nil
+}
+
+func (s *service) applySyncStep(ctx context.Context, step syncStep, activity ActivityReporter) error {
+	entry := step.entry
+	release := githubAddOptions{appID: entry.ID, tag: entry.Version, updateSource: manifestUpdateSource(entry)}
+	if step.locked != nil {
+		release.tag = step.locked.Tag
+		release.assetName = step.locked.AssetName
+		release.sha256 = step.locked.SHA256
+	}
+
+	switch step.action.Kind {
+	case SyncActionInstall:
+		if entry.URL != "" {
+			_, err := s.addFromURL(ctx, entry.URL, entry.ID, domain.UpdateSource{}, release.sha256, activity)
+			return err
+		}
+		if err := s.requireGitHubDownloads(); err != nil {
+			return err
+		}
+		_, err := s.addGitHubRelease(ctx, AddRequest{
+			GitHubRepo:   entry.GitHubRepo,
+			AssetPattern: entry.AssetPattern,
+			Prerelease:   entry.Prerelease,
+			Activity:     activity,
+		}, activity, release)
+		return err
+	case SyncActionInstallVersion:
+		if err := s.requireGitHubDownloads(); err != nil {
+			return err
+		}
+		return s.installSyncVersion(ctx, step.installed, entry, release, activity)
+	case SyncActionSetUpdateSource:
+		return s.saveUpdateSource(ctx, step.installed, manifestUpdateSource(entry))
+	case SyncActionRemove:
+		task := activity.Start(ctx, Activity{Kind: ActivityKindRemoving, AppID: step.installed.ID})
+		if err := s.removeInstalledApp(ctx, step.installed, s.config.TrashRemovedApps); err != nil {
+			task.Fail(err)
+			return err
+		}
+		task.Done("Removed " + step.installed.Name)
+		return nil
+	default:
+		return fmt.Errorf("unsupported sync action %q", step.action.Kind)
+	}
+}
+
+// installSyncVersion replaces an installed app with the pinned or locked
+// release using the same staging and promotion as a regular update.
+func (s *service) installSyncVersion(ctx context.Context, installedApp domain.App, entry ManifestApp, options githubAddOptions, activity ActivityReporter) error {
+	check := activity.Start(ctx, Activity{Kind: ActivityKindCheckingGitHub, AppID: installedApp.ID, Repo: entry.GitHubRepo})
+	release, err := s.githubReleases.ReleaseByTag(ctx, entry.GitHubRepo, options.tag)
+	if err != nil {
+		check.Fail(err)
+		return err
+	}
+	check.Done("Checked " + entry.GitHubRepo)
+
+	var asset GitHubReleaseAsset
+	if options.assetName != "" {
+		asset, err = selectGitHubAssetNamed(release, options.assetName)
+	} else {
+		asset, err = selectGitHubUpdateAsset(release, options.updateSource)
+	}
+	if err != nil {
+		return err
+	}
+	if options.sha256 != "" {
+		asset.SHA256 = options.sha256
+	}
+	version, _ := updateVersion(release, asset)
+
+	installedApp.UpdateSource = options.updateSource
+	return s.applyGitHubUpdate(ctx, activity, githubUpdatePlan{app: installedApp, release: release, asset: asset, version: version})
+}
+
+func (s *service) requireGitHubDownloads() error {
+	if s.githubReleases == nil {
+		return errors.New("github release finder is required")
+	}
+	if s.downloads == nil {
+		return errors.New("asset downloader is required")
+	}
+	return nil
+}
+
+func installedFromRelease(installedApp domain.App, repo string, tag string) bool {
+	source := installedApp.Source
+	return source.Kind == domain.SourceKindGitHub &&
+		strings.EqualFold(source.GitHubRelease.Repo, repo) &&
+		source.GitHubRelease.Tag == tag
+}
+
+func installedFromLockedRelease(installedApp domain.App, locked LockedApp) bool {
+	return installedFromRelease(installedApp, locked.GitHubRepo, locked.Tag) &&
+		installedApp.Source.GitHubRelease.Asset == locked.AssetName
+}
+
+func manifestUpdateSource(entry ManifestApp) domain.UpdateSource {
+	updateSource := domain.NewGitHubUpdateSource(entry.GitHubRepo, entry.Prerelease)
+	updateSource.AssetPattern = entry.AssetPattern
+	updateSource.ReleaseTag = entry.Version
+	return updateSource
+}
+
+// validateManifest normalizes manifest entries and rejects ambiguous ones
+// before anything is planned.
+func validateManifest(manifest Manifest) ([]ManifestApp, error) {
+	entries := make([]ManifestApp, 0, len(manifest.Apps))
+	seen := make(map[string]bool, len(manifest.Apps))
+	for i, entry := range manifest.Apps {
+		entry.ID = domain.Slugify(strings.TrimSpace(entry.ID))
+		entry.GitHubRepo = strings.TrimSpace(entry.GitHubRepo)
+		entry.URL = strings.TrimSpace(entry.URL)
+		entry.AssetPattern = strings.TrimSpace(entry.AssetPattern)
+		entry.Version = strings.TrimSpace(entry.Version)
+
+		if entry.ID == "" {
+			return nil, fmt.Errorf("app %d: id is required", i+1)
+		}
+		if seen[entry.ID] {
+			return nil, fmt.Errorf("app %s: declared more than once", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		switch {
+		case entry.GitHubRepo != "" && entry.URL != "":
+			return nil, fmt.Errorf("app %s: provide either github or url, not both", entry.ID)
+		case entry.GitHubRepo != "":
+			if !validGitHubRepo(entry.GitHubRepo) {
+				return nil, fmt.Errorf("app %s: github repo must be in owner/repo format", entry.ID)
+			}
+		case entry.URL != "":
+			if !strings.HasPrefix(entry.URL, "https://") && !strings.HasPrefix(entry.URL, "http://") {
+				return nil, fmt.Errorf("app %s: url must be an http or https URL", entry.ID)
+			}
+			if entry.AssetPattern != "" || entry.Prerelease || entry.Version != "" {
+				return nil, fmt.Errorf("app %s: asset, prerelease, and version require github", entry.ID)
+			}
+		default:
+			return nil, fmt.Errorf("app %s: github or url is required", entry.ID)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
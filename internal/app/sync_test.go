@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceSyncDryRunPlansWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	tracked := testInstalledApp(t)
+	tracked.ID = "tracked"
+	pinned := testInstalledApp(t)
+	pinned.ID = "pinned"
+	pinned.Source = domain.NewGitHubReleaseSource("owner/pinned", "v1.0.0", "Pinned.AppImage", "https://example.test/Pinned.AppImage", 10, testSourceTime())
+	unchanged := testInstalledApp(t)
+	unchanged.ID = "unchanged"
+	unchanged.UpdateSource = domain.NewGitHubUpdateSource("owner/unchanged", false)
+	extra := testInstalledApp(t)
+	extra.ID = "extra"
+	deps.apps.listApps = []domain.App{extra, pinned, tracked, unchanged}
+	manifests := &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "missing", URL: "https://example.test/Missing.AppImage"},
+		{ID: "tracked", GitHubRepo: "owner/tracked", AssetPattern: "Tracked-*.AppImage"},
+		{ID: "pinned", GitHubRepo: "owner/pinned", Version: "v2.0.0"},
+		{ID: "unchanged", GitHubRepo: "owner/unchanged"},
+	}}}
+	deps.Manifests = manifests
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{Manifest: "team.toml", Prune: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	want := []SyncAction{
+		{Kind: SyncActionInstall, AppID: "missing", URL: "https://example.test/Missing.AppImage"},
+		{Kind: SyncActionSetUpdateSource, AppID: "tracked", GitHubRepo: "owner/tracked"},
+		{Kind: SyncActionInstallVersion, AppID: "pinned", GitHubRepo: "owner/pinned", Version: "v2.0.0"},
+		{Kind: SyncActionRemove, AppID: "extra"},
+	}
+	if result.Applied || len(result.Actions) != len(want) {
+		t.Fatalf("result = %#v, want unapplied %#v", result, want)
+	}
+	for i := range want {
+		if result.Actions[i] != want[i] {
+			t.Fatalf("Actions[%d] = %#v, want %#v", i, result.Actions[i], want[i])
+		}
+	}
+	if got, want := manifests.location, "team.toml"; got != want {
+		t.Fatalf("manifest location = %q, want %q", got, want)
+	}
+	if deps.saved.App.ID != "" || deps.apps.deletedID != "" || len(deps.artifactRemover.paths) != 0 {
+		t.Fatal("dry run changed installed apps")
+	}
+}
+
+func TestServiceSyncInstallsPinnedGitHubAppWithManifestID(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	releases := &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.5.0", "Example.AppImage")}
+	deps.GitHubReleases = releases
+	deps.Downloads = &fakeAssetDownloader{}
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: " Team Example ", GitHubRepo: "owner/repo", Version: "v1.5.0"},
+	}}}
+	deps.Config.ManifestFile = "/config/aim/manifest.toml"
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if got, want := result.Manifest, "/config/aim/manifest.toml"; got != want {
+		t.Fatalf("Manifest = %q, want %q", got, want)
+	}
+	if !result.Applied || len(result.Failures) != 0 {
+		t.Fatalf("result = %#v, want applied without failures", result)
+	}
+	if releases.method != "tag" || releases.tag != "v1.5.0" {
+		t.Fatalf("release lookup = %s %q, want tag v1.5.0", releases.method, releases.tag)
+	}
+	if got, want := deps.saved.App.ID, "team-example"; got != want {
+		t.Fatalf("saved ID = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.UpdateSource.ReleaseTag, "v1.5.0"; got != want {
+		t.Fatalf("saved ReleaseTag = %q, want %q", got, want)
+	}
+}
+
+func TestServiceSyncInstallsURLApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	downloads := &fakeAssetDownloader{}
+	deps.Downloads = downloads
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: "example", URL: "https://example.test/dl/Example.AppImage"},
+	}}}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml"}); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if got, want := downloads.source.URL, "https://example.test/dl/Example.AppImage"; got != want {
+		t.Fatalf("download URL = %q, want %q", got, want)
+	}
+	if got, want := downloads.source.FileName, "Example.AppImage"; got != want {
+		t.Fatalf("download file name = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.Source.Kind, domain.SourceKindURL; got != want {
+		t.Fatalf("saved Source.Kind = %q, want %q", got, want)
+	}
+	if got, want := deps.saved.App.Source.URL.URL, "https://example.test/dl/Example.AppImage"; got != want {
+		t.Fatalf("saved Source.URL = %q, want %q", got, want)
+	}
+}
+
+func TestServiceSyncAdjustsUpdateSourceAndPrunes(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	extra := testInstalledApp(t)
+	extra.ID = "extra"
+	extra.AppImagePath = "/library/extra.AppImage"
+	extra.DesktopEntryPath = "/desktop/extra.desktop"
+	extra.IconPath = "/icons/extra.png"
+	deps.apps.listApps = []domain.App{installed, extra}
+	deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: []ManifestApp{
+		{ID: installed.ID, GitHubRepo: "owner/repo", Prerelease: true},
+	}}}
+	confirmation := &fakeSyncConfirmation{confirmed: true}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Prune: true, Confirmation: confirmation})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if len(confirmation.actions) != 2 || !result.Applied {
+		t.Fatalf("confirmed actions = %#v, result = %#v", confirmation.actions, result)
+	}
+	want := domain.NewGitHubUpdateSource("owner/repo", true)
+	if got := deps.saved.App.UpdateSource; got != want {
+		t.Fatalf("saved UpdateSource = %#v, want %#v", got, want)
+	}
+	if got, want := deps.apps.deletedID, "extra"; got != want {
+		t.Fatalf("deleted ID = %q, want %q", got, want)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{"/desktop/extra.desktop", "/icons/extra.png", "/library/extra.AppImage"})
+}
+
+func TestServiceSyncDoesNotApplyWhenConfirmationRejects(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.apps.listApps = []domain.App{testInstalledApp(t)}
+	deps.Manifests = &fakeManifestLoader{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", Prune: true, Confirmation: &fakeSyncConfirmation{}})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Applied || len(result.Actions) != 1 {
+		t.Fatalf("result = %#v, want unapplied removal", result)
+	}
+	if deps.apps.deletedID != "" {
+		t.Fatal("sync removed an app after confirmation was rejected")
+	}
+}
+
+func TestServiceSyncRejectsInvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	for _, apps := range [][]ManifestApp{
+		{{GitHubRepo: "owner/repo"}},
+		{{ID: "example"}},
+		{{ID: "example", GitHubRepo: "owner/repo", URL: "https://example.test/a.AppImage"}},
+		{{ID: "example", GitHubRepo: "repo"}},
+		{{ID: "example", URL: "ftp://example.test/a.AppImage"}},
+		{{ID: "example", URL: "https://example.test/a.AppImage", Version: "v1"}},
+		{{ID: "example", GitHubRepo: "owner/a"}, {ID: "Example", GitHubRepo: "owner/b"}},
+	} {
+		deps := integrationTestDeps()
+		deps.Manifests = &fakeManifestLoader{manifest: Manifest{Apps: apps}}
+		service, err := NewService(deps.ServiceDeps)
+		if err != nil {
+			t.Fatalf("NewService() error = %v", err)
+		}
+
+		_, err = service.Sync(context.Background(), SyncRequest{Manifest: "manifest.toml", DryRun: true})
+		if err == nil || !strings.Contains(err.Error(), "invalid manifest") {
+			t.Fatalf("Sync(%#v) error = %v, want invalid manifest error", apps, err)
+		}
+	}
+}
+
+func TestServiceUpdateUsesPinnedReleaseTag(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.UpdateSource = domain.NewGitHubUpdateSource("owner/repo", false)
+	installed.UpdateSource.ReleaseTag = "v1.2.3"
+	deps.apps.listApps = []domain.App{installed}
+	releases := &fakeGitHubReleaseFinder{release: testGitHubReleaseWithTag("v1.2.3", "Example.AppImage")}
+	deps.GitHubReleases = releases
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	result, err := service.Update(context.Background(), UpdateRequest{CheckOnly: true})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if releases.method != "tag" || releases.tag != "v1.2.3" {
+		t.Fatalf("release lookup = %s %q, want tag v1.2.3", releases.method, releases.tag)
+	}
+	if len(result.Updates) != 0 {
+		t.Fatalf("Updates = %#v, want none for pinned app", result.Updates)
+	}
+}
+
+type fakeManifestLoader struct {
+	location string
+	manifest Manifest
+	err      error
+}
+
+func (f *fakeManifestLoader) Load(ctx context.Context, location string) (Manifest, error) {
+	f.location = location
+	return f.manifest, f.err
+}
+
+type fakeSyncConfirmation struct {
+	actions   []SyncAction
+	confirmed bool
+}
+
+func (f *fakeSyncConfirmation) ConfirmSync(ctx context.Context, actions []SyncAction) (bool, error) {
+	f.actions = actions
+	return f.confirmed, nil
+}
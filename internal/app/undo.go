@@ -0,0 +1,358 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// undoRecord collects what one operation needs to be undone. A nil record is
+// valid and keeps nothing, so callers do not need to check whether undo is
+// configured.
+type undoRecord struct {
+	archive UndoArchive
+	entry   UndoEntry
+	saved   bool
+}
+
+// retainForUndo keeps copies of paths before operation replaces or removes
+// them. The record must be saved once the operation succeeded and discarded
+// otherwise.
+func (s *service) retainForUndo(ctx context.Context, operation HistoryOperation, appID string, previous domain.App, paths ...string) (*undoRecord, error) {
+	if s.undo == nil || s.config.UndoRetention <= 0 {
+		return nil, nil
+	}
+	_ = s.undo.Prune(ctx, time.Now().Add(-s.config.UndoRetention))
+
+	createdAt := time.Now().UTC()
+	id := fmt.Sprintf("%s-%s-%s", createdAt.Format("20060102T150405.000000000Z"), operation, appID)
+	artifacts, err := s.undo.Retain(ctx, id, appendPaths(nil, paths...))
+	if err != nil {
+		_ = s.undo.Delete(context.WithoutCancel(ctx), id)
+		return nil, fmt.Errorf("keep %s for undo: %w", appID, err)
+	}
+
+	return &undoRecord{
+		archive: s.undo,
+		entry: UndoEntry{
+			ID:        id,
+			Operation: operation,
+			CreatedAt: createdAt,
+			Previous:  previous,
+			Artifacts: artifacts,
+		},
+	}, nil
+}
+
+// save records the completed operation with the app it left behind.
+func (u *undoRecord) save(ctx context.Context, current domain.App) error {
+	if u == nil {
+		return nil
+	}
+	u.entry.Current = current
+	if err := u.archive.Save(ctx, u.entry); err != nil {
+		return err
+	}
+	u.saved = true
+	return nil
+}
+
+// discard drops retained artifacts of an operation that was not saved.
+func (u *undoRecord) discard(ctx context.Context) {
+	if u == nil || u.saved {
+		return
+	}
+	_ = u.archive.Delete(context.WithoutCancel(ctx), u.entry.ID)
+}
+
+// recordAddForUndo saves an add, which retains nothing because undoing it
+// only removes what it installed.
+func (s *service) recordAddForUndo(ctx context.Context, installedApp domain.App) error {
+	undo, err := s.retainForUndo(ctx, HistoryOperationAdd, installedApp.ID, domain.App{})
+	if err != nil {
+		return err
+	}
+	defer undo.discard(ctx)
+	return undo.save(ctx, installedApp)
+}
+
+func (s *service) Undo(ctx context.Context, req UndoRequest) (UndoResult, error) {
+	if err := ctx.Err(); err != nil {
+		return UndoResult{}, err
+	}
+	if s.undo == nil {
+		return UndoResult{}, errors.New("undo archive is required")
+	}
+	if s.config.UndoRetention > 0 {
+		_ = s.undo.Prune(ctx, time.Now().Add(-s.config.UndoRetention))
+	}
+
+	entry, err := s.undo.Latest(ctx)
+	if err != nil {
+		return UndoResult{}, err
+	}
+	operation := undoneOperation(entry)
+	if req.Confirmation != nil {
+		confirmed, err := req.Confirmation.ConfirmUndo(ctx, operation)
+		if err != nil {
+			return UndoResult{}, err
+		}
+		if !confirmed {
+			return UndoResult{Applied: false, Operation: operation}, nil
+		}
+	}
+
+	err = s.undoEntry(ctx, entry)
+	s.recordHistory(ctx, HistoryEvent{
+		Operation:  HistoryOperationUndo,
+		AppID:      firstNonEmpty(operation.RestoredID, operation.AppID),
+		PreviousID: undoPreviousID(operation),
+		OldVersion: operation.Version,
+		NewVersion: operation.RestoredVersion,
+		Source:     string(entry.Operation),
+	}, err)
+	if err != nil {
+		return UndoResult{}, err
+	}
+	if err := s.undo.Delete(ctx, entry.ID); err != nil {
+		return UndoResult{}, fmt.Errorf("undid %s of %s but failed to drop it from the undo archive: %w", entry.Operation, firstNonEmpty(operation.AppID, operation.RestoredID), err)
+	}
+
+	return UndoResult{Applied: true, Operation: operation}, nil
+}
+
+func (s *service) undoEntry(ctx context.Context, entry UndoEntry) error {
+	switch entry.Operation {
+	case HistoryOperationAdd:
+		return s.undoAdd(ctx, entry)
+	case HistoryOperationRemove:
+		return s.undoRemove(ctx, entry)
+	case HistoryOperationUpdate:
+		return s.undoUpdate(ctx, entry)
+	case HistoryOperationSetID:
+		return s.undoSetID(ctx, entry)
+	default:
+		return fmt.Errorf("cannot undo %q operations", entry.Operation)
+	}
+}
+
+func (s *service) undoAdd(ctx context.Context, entry UndoEntry) error {
+	unlock, err := s.lockApp(ctx, entry.Current.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := s.requireUnchanged(ctx, entry.Current, "added"); err != nil {
+		return err
+	}
+
+	if err := s.removeInstalledAppArtifacts(ctx, entry.Current); err != nil {
+		return err
+	}
+	return s.apps.Delete(ctx, entry.Current.ID)
+}
+
+func (s *service) undoRemove(ctx context.Context, entry UndoEntry) error {
+	unlock, err := s.lockApp(ctx, entry.Previous.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := s.requireNotInstalled(ctx, entry.Previous.ID); err != nil {
+		return err
+	}
+
+	var rollback rollbackStack
+	committed := false
+	defer func() {
+		if !committed {
+			rollback.run(ctx)
+		}
+	}()
+	if err := s.restoreRetainedArtifacts(ctx, entry.Artifacts, &rollback); err != nil {
+		return err
+	}
+	if err := s.apps.Save(ctx, entry.Previous); err != nil {
+		return err
+	}
+	committed = true
+
+	if err := s.restoreBin(ctx, entry.Previous); err != nil {
+		return err
+	}
+	s.refreshDesktopIntegration(ctx)
+	return nil
+}
+
+func (s *service) undoUpdate(ctx context.Context, entry UndoEntry) error {
+	unlock, err := s.lockApp(ctx, entry.Current.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := s.requireUnchanged(ctx, entry.Current, "updated"); err != nil {
+		return err
+	}
+
+	// The retained artifacts overwrite the updated ones in place, so there is
+	// nothing to roll back to once the first one is restored.
+	if err := s.restoreRetainedArtifacts(ctx, entry.Artifacts, nil); err != nil {
+		return fmt.Errorf("%w; run aim repair %s", err, entry.Current.ID)
+	}
+	if err := s.apps.Save(ctx, entry.Previous); err != nil {
+		return err
+	}
+	if err := s.removeReplacedArtifacts(ctx, entry.Current, entry.Previous); err != nil {
+		return fmt.Errorf("restored %s but failed to remove the updated artifacts: %w", entry.Previous.ID, err)
+	}
+
+	s.refreshDesktopIntegration(ctx)
+	return nil
+}
+
+func (s *service) undoSetID(ctx context.Context, entry UndoEntry) error {
+	unlockCurrent, err := s.lockApp(ctx, entry.Current.ID)
+	if err != nil {
+		return err
+	}
+	defer unlockCurrent()
+	unlockPrevious, err := s.lockApp(ctx, entry.Previous.ID)
+	if err != nil {
+		return err
+	}
+	defer unlockPrevious()
+	if err := s.requireUnchanged(ctx, entry.Current, "renamed"); err != nil {
+		return err
+	}
+	if err := s.requireNotInstalled(ctx, entry.Previous.ID); err != nil {
+		return err
+	}
+
+	var rollback rollbackStack
+	committed := false
+	defer func() {
+		if !committed {
+			rollback.run(ctx)
+		}
+	}()
+	if err := s.restoreRetainedArtifacts(ctx, entry.Artifacts, &rollback); err != nil {
+		return err
+	}
+	rollback.add(func(ctx context.Context) error {
+		return s.apps.Delete(ctx, entry.Previous.ID)
+	})
+	if err := s.apps.Save(ctx, entry.Previous); err != nil {
+		return err
+	}
+	if err := s.apps.Delete(ctx, entry.Current.ID); err != nil {
+		return err
+	}
+	committed = true
+
+	if err := s.removeReplacedArtifacts(ctx, entry.Current, entry.Previous); err != nil {
+		return fmt.Errorf("restored id %s but failed to remove artifacts of %s: %w", entry.Previous.ID, entry.Current.ID, err)
+	}
+	if err := s.renameMimeAssociations(ctx, entry.Current, entry.Previous); err != nil {
+		return fmt.Errorf("restored id %s but failed to update the default applications: %w", entry.Previous.ID, err)
+	}
+	if err := s.restoreBin(ctx, entry.Previous); err != nil {
+		return err
+	}
+	s.refreshDesktopIntegration(ctx)
+	return nil
+}
+
+// restoreBin writes the command of a restored app again, since commands are
+// not retained for undo.
+func (s *service) restoreBin(ctx context.Context, restored domain.App) error {
+	if restored.BinPath == "" {
+		return nil
+	}
+	refreshed, err := s.refreshBin(ctx, restored)
+	if err == nil && refreshed.BinPath != restored.BinPath {
+		err = s.apps.Save(ctx, refreshed)
+	}
+	if err != nil {
+		return fmt.Errorf("restored %s but failed to restore its command; run aim bin %s: %w", restored.ID, restored.ID, err)
+	}
+	return nil
+}
+
+// restoreRetainedArtifacts copies retained artifacts back into place. When
+// rollback is set, restored artifacts are removed again if the undo fails.
+func (s *service) restoreRetainedArtifacts(ctx context.Context, artifacts []RetainedArtifact, rollback *rollbackStack) error {
+	for _, artifact := range artifacts {
+		if err := s.undo.Restore(ctx, artifact); err != nil {
+			return err
+		}
+		if rollback != nil {
+			path := artifact.Path
+			rollback.add(func(ctx context.Context) error {
+				return s.artifactRemover(ctx, path)
+			})
+		}
+	}
+	return nil
+}
+
+// requireUnchanged fails unless app is still installed as the operation left
+// it, so undo never reverts over a later change.
+func (s *service) requireUnchanged(ctx context.Context, app domain.App, verb string) error {
+	installed, err := s.apps.Find(ctx, app.ID)
+	if errors.Is(err, ErrAppNotFound) {
+		return fmt.Errorf("%s is no longer installed; nothing was undone", app.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if installed.AppImagePath != app.AppImagePath || installed.Version.String() != app.Version.String() {
+		return fmt.Errorf("%s changed after it was %s; nothing was undone", app.ID, verb)
+	}
+	return nil
+}
+
+func (s *service) requireNotInstalled(ctx context.Context, id string) error {
+	_, err := s.apps.Find(ctx, id)
+	if err == nil {
+		return fmt.Errorf("%s is installed again; nothing was undone", id)
+	}
+	if !errors.Is(err, ErrAppNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *service) refreshDesktopIntegration(ctx context.Context) {
+	if s.desktopIntegrationRefresher != nil {
+		_ = s.desktopIntegrationRefresher.Refresh(ctx)
+	}
+}
+
+func undoneOperation(entry UndoEntry) UndoneOperation {
+	return UndoneOperation{
+		Operation:       entry.Operation,
+		At:              entry.CreatedAt,
+		AppID:           entry.Current.ID,
+		Version:         entry.Current.Version.String(),
+		RestoredID:      entry.Previous.ID,
+		RestoredVersion: entry.Previous.Version.String(),
+	}
+}
+
+func undoPreviousID(operation UndoneOperation) string {
+	if operation.RestoredID == "" || operation.AppID == "" || operation.RestoredID == operation.AppID {
+		return ""
+	}
+	return operation.AppID
+}
+
+func firstNonEmpty(values ...string) string {
+	if i := slices.IndexFunc(values, func(value string) bool { return value != "" }); i >= 0 {
+		return values[i]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// UndoArchive keeps what destructive operations replace or delete so the most
+// recent one can be undone.
+//
+// Implementations belong in infrastructure. Retain must keep copies that stay
+// intact when the originals are later overwritten or removed, Latest returns
+// ErrNothingToUndo when no saved entry is left, and Prune drops entries and
+// unsaved retained artifacts older than before.
+type UndoArchive interface {
+	Retain(ctx context.Context, id string, paths []string) ([]RetainedArtifact, error)
+	Save(ctx context.Context, entry UndoEntry) error
+	Latest(ctx context.Context) (UndoEntry, error)
+	Restore(ctx context.Context, artifact RetainedArtifact) error
+	Delete(ctx context.Context, id string) error
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// RetainedArtifact is a kept copy of the artifact that was installed at Path.
+type RetainedArtifact struct {
+	Path         string
+	RetainedPath string
+}
+
+// UndoEntry describes one completed operation. Previous is the app before the
+// operation and Current the app it left behind; Previous is zero for adds and
+// Current is zero for removals.
+type UndoEntry struct {
+	ID        string
+	Operation HistoryOperation
+	CreatedAt time.Time
+	Previous  domain.App
+	Current   domain.App
+	Artifacts []RetainedArtifact
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceUndoRestoresRemovedApp(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	archive := &fakeUndoArchive{}
+	deps.Undo = archive
+	deps.Config.UndoRetention = time.Hour
+	installed := testInstalledApp(t)
+	deps.apps.findApp = installed
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Remove(context.Background(), RemoveRequest{Name: installed.ID}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got, want := archive.retained, []string{installed.DesktopEntryPath, installed.IconPath, installed.AppImagePath}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("retained = %#v, want %#v", got, want)
+	}
+
+	deps.apps.findApp = domain.App{}
+	deps.apps.App = domain.App{}
+	result, err := service.Undo(context.Background(), UndoRequest{})
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+
+	if !result.Applied || result.Operation.Operation != HistoryOperationRemove || result.Operation.RestoredID != installed.ID {
+		t.Fatalf("Undo() = %#v, want the removal undone", result)
+	}
+	if got, want := archive.restored, []string{installed.DesktopEntryPath, installed.IconPath, installed.AppImagePath}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("restored = %#v, want %#v", got, want)
+	}
+	if got := deps.apps.App; got.ID != installed.ID || got.AppImagePath != installed.AppImagePath {
+		t.Fatalf("saved app = %#v, want %#v", got, installed)
+	}
+	if len(archive.entries) != 0 {
+		t.Fatalf("entries = %#v, want undone entry dropped", archive.entries)
+	}
+}
+
+func TestServiceUndoRevertsIDChange(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	previous := testInstalledApp(t)
+	current := previous
+	current.ID = "renamed"
+	current.AppImagePath = "/library/renamed.AppImage"
+	current.DesktopEntryPath = "/desktop/renamed.desktop"
+	current.IconPath = "/icons/hicolor/256x256/apps/renamed.png"
+	archive := &fakeUndoArchive{entries: []UndoEntry{{
+		ID:        "set-id",
+		Operation: HistoryOperationSetID,
+		Previous:  previous,
+		Current:   current,
+		Artifacts: []RetainedArtifact{{Path: previous.AppImagePath, RetainedPath: "/undo/set-id/0-example-app.AppImage"}},
+	}}}
+	deps.Undo = archive
+	deps.apps.findApps = map[string]domain.App{current.ID: current}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Undo(context.Background(), UndoRequest{}); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+
+	if got := deps.apps.App.ID; got != previous.ID {
+		t.Fatalf("saved app ID = %q, want %q", got, previous.ID)
+	}
+	if got := deps.apps.deletedID; got != current.ID {
+		t.Fatalf("deleted app ID = %q, want %q", got, current.ID)
+	}
+	assertRemovedPaths(t, deps.artifactRemover.paths, []string{current.DesktopEntryPath, current.IconPath, current.AppImagePath})
+}
+
+func TestServiceUndoRefusesWhenAppChangedSinceUpdate(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	previous := testInstalledApp(t)
+	current := previous
+	current.Version, _ = domain.ParseVersion("2.0.0")
+	later := previous
+	later.Version, _ = domain.ParseVersion("3.0.0")
+	archive := &fakeUndoArchive{entries: []UndoEntry{{
+		ID:        "update",
+		Operation: HistoryOperationUpdate,
+		Previous:  previous,
+		Current:   current,
+		Artifacts: []RetainedArtifact{{Path: previous.AppImagePath, RetainedPath: "/undo/update/0-example-app.AppImage"}},
+	}}}
+	deps.Undo = archive
+	deps.apps.findApp = later
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	_, err = service.Undo(context.Background(), UndoRequest{})
+	if err == nil || !strings.Contains(err.Error(), "changed after it was updated") {
+		t.Fatalf("Undo() error = %v, want changed app error", err)
+	}
+	if len(archive.restored) != 0 || len(archive.entries) != 1 {
+		t.Fatalf("restored = %#v, entries = %d; want nothing undone", archive.restored, len(archive.entries))
+	}
+}
+
+func TestServiceUndoAsksForConfirmation(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	archive := &fakeUndoArchive{}
+	deps.Undo = archive
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if _, err := service.Undo(context.Background(), UndoRequest{}); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Undo() error = %v, want ErrNothingToUndo", err)
+	}
+
+	archive.entries = []UndoEntry{{ID: "add", Operation: HistoryOperationAdd, Current: testInstalledApp(t)}}
+	result, err := service.Undo(context.Background(), UndoRequest{Confirmation: declineUndo{}})
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if result.Applied || result.Operation.AppID != "example-app" || len(archive.entries) != 1 {
+		t.Fatalf("Undo() = %#v, want the add of example-app left in place", result)
+	}
+}
+
+type declineUndo struct{}
+
+func (declineUndo) ConfirmUndo(ctx context.Context, operation UndoneOperation) (bool, error) {
+	return false, nil
+}
+
+type fakeUndoArchive struct {
+	entries  []UndoEntry
+	retained []string
+	restored []string
+}
+
+func (f *fakeUndoArchive) Retain(ctx context.Context, id string, paths []string) ([]RetainedArtifact, error) {
+	artifacts := make([]RetainedArtifact, 0, len(paths))
+	for _, path := range paths {
+		f.retained = append(f.retained, path)
+		artifacts = append(artifacts, RetainedArtifact{Path: path, RetainedPath: "/undo/" + id + path})
+	}
+	return artifacts, nil
+}
+
+func (f *fakeUndoArchive) Save(ctx context.Context, entry UndoEntry) error {
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeUndoArchive) Latest(ctx context.Context) (UndoEntry, error) {
+	if len(f.entries) == 0 {
+		return UndoEntry{}, ErrNothingToUndo
+	}
+	return f.entries[len(f.entries)-1], nil
+}
+
+func (f *fakeUndoArchive) Restore(ctx context.Context, artifact RetainedArtifact) error {
+	f.restored = append(f.restored, artifact.Path)
+	return nil
+}
+
+func (f *fakeUndoArchive) Delete(ctx context.Context, id string) error {
+	for i, entry := range f.entries {
+		if entry.ID == id {
+			f.entries = append(f.entries[:i], f.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeUndoArchive) Prune(ctx context.Context, before time.Time) error {
+	return nil
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+const watchAddAction = "add"
+
+// Watch integrates or offers to integrate AppImages that appear in the
+// watched directories until ctx is done. Stopping it by canceling ctx is not
+// an error.
+func (s *service) Watch(ctx context.Context, req WatchRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.appImageWatcher == nil {
+		return errors.New("appimage watcher is required")
+	}
+	if !req.AutoAdd && s.notifier == nil {
+		return errors.New("notifier is required")
+	}
+
+	events := req.Events
+	if events == nil {
+		events = noopWatchReporter{}
+	}
+
+	dirs := make([]string, 0, len(req.Dirs))
+	for _, dir := range req.Dirs {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, filepath.Clean(dir))
+		}
+	}
+	if len(dirs) == 0 {
+		if s.config.DownloadDir == "" {
+			return errors.New("no directory to watch")
+		}
+		dirs = []string{s.config.DownloadDir}
+	}
+
+	// Offers wait for the user, so each runs on its own and Watch waits for
+	// them to be withdrawn when it stops.
+	var offers sync.WaitGroup
+	defer offers.Wait()
+
+	events.Watching(dirs)
+	err := s.appImageWatcher.Watch(ctx, dirs, func(path string) {
+		if s.managesAppImage(ctx, path) {
+			return
+		}
+
+		events.Found(path)
+		if req.AutoAdd {
+			s.addWatched(ctx, path, events)
+			return
+		}
+
+		offers.Add(1)
+		go func() {
+			defer offers.Done()
+			s.offerToAdd(ctx, path, events)
+		}()
+	})
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
+
+// managesAppImage reports whether path is an AppImage aim installed, so aim's
+// own installs into a watched directory are not offered again.
+func (s *service) managesAppImage(ctx context.Context, path string) bool {
+	if s.config.AppImageDir != "" && isWithinDir(path, s.config.AppImageDir) {
+		return true
+	}
+
+	apps, err := s.apps.List(ctx)
+	if err != nil {
+		return false
+	}
+	for _, installed := range apps {
+		if installed.AppImagePath == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) offerToAdd(ctx context.Context, path string, events WatchReporter) {
+	key, err := s.notifier.Ask(ctx, Notification{
+		Summary: "New AppImage downloaded",
+		Body:    filepath.Base(path),
+		Urgency: NotificationUrgencyNormal,
+	}, []NotificationAction{{Key: watchAddAction, Label: "Integrate"}})
+	if err != nil {
+		if ctx.Err() == nil {
+			events.Failed(path, err)
+		}
+		return
+	}
+	if key != watchAddAction {
+		return
+	}
+
+	s.addWatched(ctx, path, events)
+}
+
+// addWatched integrates path and reports the result, with a notification
+// since nobody may be looking at the terminal.
+func (s *service) addWatched(ctx context.Context, path string, events WatchReporter) {
+	result, err := s.Add(ctx, AddRequest{Path: path})
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		events.Failed(path, err)
+		s.notifyWatched(ctx, Notification{
+			Summary: "Could not integrate " + filepath.Base(path),
+			Body:    err.Error(),
+			Urgency: NotificationUrgencyCritical,
+		})
+		return
+	}
+
+	events.Added(path, result.App)
+	s.notifyWatched(ctx, Notification{
+		Summary: fmt.Sprintf("Integrated %s", describeWatchedApp(result.App)),
+		Body:    filepath.Base(path),
+		Urgency: NotificationUrgencyNormal,
+	})
+}
+
+func (s *service) notifyWatched(ctx context.Context, notification Notification) {
+	if s.notifier == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationTimeout)
+	defer cancel()
+	_ = s.notifier.Notify(ctx, notification)
+}
+
+func describeWatchedApp(app domain.App) string {
+	name := firstNonEmpty(app.Name, app.ID)
+	if version := app.Version.String(); version != "" {
+		return name + " " + version
+	}
+	return name
+}
+
+func isWithinDir(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+type noopWatchReporter struct{}
+
+func (noopWatchReporter) Watching(dirs []string)            {}
+func (noopWatchReporter) Found(path string)                 {}
+func (noopWatchReporter) Added(path string, app domain.App) {}
+func (noopWatchReporter) Failed(path string, err error)     {}
@@ -0,0 +1,13 @@
+package app
+
+import "context"
+
+// AppImageWatcher reports AppImages that appear in directories.
+//
+// Implementations belong in infrastructure. Watch blocks until ctx is done
+// and calls found, one call at a time, for each file created in or moved into
+// one of dirs once it is fully written and starts with the AppImage magic
+// bytes.
+type AppImageWatcher interface {
+	Watch(ctx context.Context, dirs []string, found func(path string)) error
+}
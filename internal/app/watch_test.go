@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestServiceWatchAutoAddsNewAppImages(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	path := testAppImagePath(t, "example.AppImage")
+	deps.Config.AppImageDir = "/library"
+	watcher := &fakeAppImageWatcher{paths: []string{"/library/other.AppImage", path}}
+	deps.AppImageWatcher = watcher
+	notifier := &fakeNotifier{}
+	deps.Notifier = notifier
+	events := &fakeWatchReporter{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Watch(context.Background(), WatchRequest{Dirs: []string{"/downloads/"}, AutoAdd: true, Events: events}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(watcher.dirs, []string{"/downloads"}) {
+		t.Fatalf("watched dirs = %#v, want /downloads", watcher.dirs)
+	}
+	if got, want := events.log, []string{"watching /downloads", "found " + path, "added " + path + " as example-app"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %#v, want %#v", got, want)
+	}
+	if deps.saved.App.ID != "example-app" {
+		t.Fatalf("saved App.ID = %q, want example-app", deps.saved.App.ID)
+	}
+	want := []Notification{{Summary: "Integrated Example App 1.2.3-beta.1", Body: "example.AppImage", Urgency: NotificationUrgencyNormal}}
+	assertNotifications(t, notifier.notifications, want)
+	if !reflect.DeepEqual(notifier.deadlines, []bool{true}) {
+		t.Fatalf("notification deadlines = %v, want a deadline so a hung daemon cannot stall watch", notifier.deadlines)
+	}
+}
+
+func TestServiceWatchOffersToAddWithNotification(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name   string
+		answer string
+		added  bool
+	}{
+		{name: "integrate", answer: "add", added: true},
+		{name: "dismiss", answer: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := integrationTestDeps()
+			path := testAppImagePath(t, "example.AppImage")
+			deps.Config.DownloadDir = "/home/user/Downloads"
+			watcher := &fakeAppImageWatcher{paths: []string{path}}
+			deps.AppImageWatcher = watcher
+			notifier := &fakeNotifier{answer: tt.answer}
+			deps.Notifier = notifier
+			service, err := NewService(deps.ServiceDeps)
+			if err != nil {
+				t.Fatalf("NewService() error = %v", err)
+			}
+
+			if err := service.Watch(context.Background(), WatchRequest{}); err != nil {
+				t.Fatalf("Watch() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(watcher.dirs, []string{"/home/user/Downloads"}) {
+				t.Fatalf("watched dirs = %#v, want download dir", watcher.dirs)
+			}
+			if len(notifier.actions) != 1 || !reflect.DeepEqual(notifier.actions[0], []NotificationAction{{Key: "add", Label: "Integrate"}}) {
+				t.Fatalf("notification actions = %#v, want one integrate action", notifier.actions)
+			}
+			if notifier.notifications[0].Summary != "New AppImage downloaded" || notifier.notifications[0].Body != "example.AppImage" {
+				t.Fatalf("offer notification = %#v", notifier.notifications[0])
+			}
+			if added := deps.saved.App.ID != ""; added != tt.added {
+				t.Fatalf("added = %v, want %v", added, tt.added)
+			}
+		})
+	}
+}
+
+func TestServiceWatchSkipsInstalledAppImages(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	installed := testInstalledApp(t)
+	installed.AppImagePath = "/downloads/example.AppImage"
+	deps.apps.listApps = []domain.App{installed}
+	deps.AppImageWatcher = &fakeAppImageWatcher{paths: []string{installed.AppImagePath}}
+	notifier := &fakeNotifier{answer: "add"}
+	deps.Notifier = notifier
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	if err := service.Watch(context.Background(), WatchRequest{Dirs: []string{"/downloads"}}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	assertNotifications(t, notifier.notifications, nil)
+}
+
+func TestServiceWatchRequiresDirectory(t *testing.T) {
+	t.Parallel()
+
+	deps := integrationTestDeps()
+	deps.AppImageWatcher = &fakeAppImageWatcher{}
+	service, err := NewService(deps.ServiceDeps)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	err = service.Watch(context.Background(), WatchRequest{AutoAdd: true})
+	if err == nil || !strings.Contains(err.Error(), "no directory to watch") {
+		t.Fatalf("Watch() error = %v, want missing directory", err)
+	}
+}
+
+type fakeAppImageWatcher struct {
+	dirs  []string
+	paths []string
+}
+
+func (f *fakeAppImageWatcher) Watch(ctx context.Context, dirs []string, found func(path string)) error {
+	f.dirs = dirs
+	for _, path := range f.paths {
+		found(path)
+	}
+	return nil
+}
+
+type fakeWatchReporter struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (f *fakeWatchReporter) Watching(dirs []string) {
+	f.record("watching " + strings.Join(dirs, ", "))
+}
+
+func (f *fakeWatchReporter) Found(path string) {
+	f.record("found " + path)
+}
+
+func (f *fakeWatchReporter) Added(path string, app domain.App) {
+	f.record("added " + path + " as " + app.ID)
+}
+
+func (f *fakeWatchReporter) Failed(path string, err error) {
+	f.record("failed " + path + ": " + err.Error())
+}
+
+func (f *fakeWatchReporter) record(entry string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = append(f.log, entry)
+}
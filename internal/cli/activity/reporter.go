@@ -172,6 +172,8 @@ func activityTitle(activity app.Activity) string {
 		return "Removing " + activity.AppID + " ..."
 	case app.ActivityKindCheckingUpdates:
 		return "Checking for updates ..."
+	case app.ActivityKindRepairing:
+		return "Repairing " + activity.AppID + " ..."
 	case app.ActivityKindWaiting:
 		return "[" + activity.AppID + "]"
 	case app.ActivityKindDownloading:
@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/cli/activity"
 	"github.com/slobbe/appimage-manager/internal/cli/clienv"
 	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+	"github.com/slobbe/appimage-manager/internal/domain"
 
 	"github.com/spf13/cobra"
 )
@@ -21,6 +21,10 @@ const (
 	reset = "\033[0m"
 )
 
+// binNameFromID is the value of a bare --bin, which names the command after
+// the app ID.
+const binNameFromID = "<id>"
+
 type service interface {
 	Add(ctx context.Context, req app.AddRequest) (app.AddResult, error)
 }
@@ -29,6 +33,9 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 	var githubRepo string
 	var assetPattern string
 	var prerelease bool
+	var launchArgs []string
+	var launchEnv []string
+	var binName string
 
 	cmd := &cobra.Command{
 		Use:     "add <appimage-path>",
@@ -61,8 +68,23 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 				GitHubRepo:   githubRepo,
 				AssetPattern: assetPattern,
 				Prerelease:   prerelease,
+				Launch:       domain.LaunchOptions{Args: launchArgs},
+				Bin:          cmd.Flags().Changed("bin"),
 				Activity:     reporter,
 			}
+			if binName != binNameFromID {
+				req.BinName = binName
+			}
+			for _, assignment := range launchEnv {
+				key, value, err := domain.ParseEnv(assignment)
+				if err != nil {
+					return err
+				}
+				if req.Launch.Env == nil {
+					req.Launch.Env = map[string]string{}
+				}
+				req.Launch.Env[key] = value
+			}
 			if len(args) == 1 {
 				path, err := normalizeLocalAppImagePath(args[0])
 				if err != nil {
@@ -107,32 +129,18 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 	cmd.Flags().StringVar(&githubRepo, "github", "", "download and add an AppImage from a GitHub repository in owner/repo format")
 	cmd.Flags().StringVar(&assetPattern, "asset", "", "match the GitHub AppImage asset name using filepath.Match syntax")
 	cmd.Flags().BoolVar(&prerelease, "prerelease", false, "include GitHub prereleases when adding from --github")
+	cmd.Flags().StringArrayVar(&launchArgs, "arg", nil, "argument to launch the app with; repeatable, see aim config-app")
+	cmd.Flags().StringArrayVar(&launchEnv, "env", nil, "environment variable to launch the app with, as KEY=VALUE; repeatable")
+	cmd.Flags().StringVar(&binName, "bin", "", "install a command for the app into the bin directory, named after the app ID or as given with --bin=name")
+	cmd.Flags().Lookup("bin").NoOptDefVal = binNameFromID
 
 	return cmd
 }
 
 func normalizeLocalAppImagePath(path string) (string, error) {
-	path = strings.TrimSpace(path)
-	if path == "" {
+	if strings.TrimSpace(path) == "" {
 		return "", fmt.Errorf("appimage path is required")
 	}
 
-	if path == "~" || strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("resolve home directory: %w", err)
-		}
-		if path == "~" {
-			path = home
-		} else {
-			path = filepath.Join(home, strings.TrimPrefix(path, "~/"))
-		}
-	}
-
-	absolutePath, err := filepath.Abs(path)
-	if err != nil {
-		return "", fmt.Errorf("resolve appimage path %q: %w", path, err)
-	}
-
-	return absolutePath, nil
+	return userpath.Abs(path)
 }
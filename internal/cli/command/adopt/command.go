@@ -0,0 +1,101 @@
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Adopt(ctx context.Context, req app.AdoptRequest) (app.AdoptResult, error)
+}
+
+type adoptedJSON struct {
+	Path         string `json:"path"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	AppImagePath string `json:"app_image_path"`
+	Moved        bool   `json:"moved"`
+	UpdateSource string `json:"update_source,omitempty"`
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var move bool
+
+	cmd := &cobra.Command{
+		Use:   "adopt <appimage-path>...",
+		Short: "Manage AppImages already on disk",
+		Long:  "Integrate AppImages that are already on disk. By default AppImages stay where they are; use --move to move them into the aim AppImage directory. Update sources are inferred from embedded update information where possible.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := make([]string, 0, len(args))
+			for _, arg := range args {
+				path, err := userpath.Abs(arg)
+				if err != nil {
+					return err
+				}
+				paths = append(paths, path)
+			}
+
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+			adopted := make([]adoptedJSON, 0, len(paths))
+			for _, path := range paths {
+				result, err := service.Adopt(cmd.Context(), app.AdoptRequest{
+					Path:     path,
+					Move:     move,
+					Activity: reporter,
+				})
+				if err != nil {
+					reporter.Wait()
+					return err
+				}
+				adopted = append(adopted, adoptedJSON{
+					Path:         path,
+					ID:           result.App.ID,
+					Name:         result.App.Name,
+					AppImagePath: result.App.AppImagePath,
+					Moved:        result.Moved,
+					UpdateSource: string(result.App.UpdateSource.Kind),
+				})
+			}
+			reporter.Wait()
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status string        `json:"status"`
+					Action string        `json:"action"`
+					Apps   []adoptedJSON `json:"apps"`
+				}{
+					Status: "ok",
+					Action: "adopt",
+					Apps:   adopted,
+				},
+				func(w io.Writer) error {
+					for _, item := range adopted {
+						fmt.Fprintf(w, "%sSuccessfully adopted %s [%s]!%s\n", green, item.Name, item.ID, reset)
+					}
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().BoolVar(&move, "move", false, "move AppImages into the aim AppImage directory instead of integrating them in place")
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package adopt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandRequiresPath(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	service := &fakeService{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("ExecuteContext() error = nil, want arg validation error")
+	}
+	if len(service.reqs) != 0 {
+		t.Fatal("service.Adopt called for invalid args")
+	}
+}
+
+func TestCommandAdoptsEachPathAndPrintsTextSuccess(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/opt/One.AppImage", "/opt/Two.AppImage", "--move"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if len(service.reqs) != 2 {
+		t.Fatalf("Adopt calls = %d, want 2", len(service.reqs))
+	}
+	if service.reqs[0].Path != "/opt/One.AppImage" || !service.reqs[0].Move || service.reqs[0].Activity == nil {
+		t.Fatalf("AdoptRequest = %#v, want path, move and reporter", service.reqs[0])
+	}
+	if !strings.Contains(stdout.String(), "Successfully adopted One [one]!") || !strings.Contains(stdout.String(), "Successfully adopted Two [two]!") {
+		t.Fatalf("stdout = %q, want success messages", stdout.String())
+	}
+}
+
+func TestCommandPrintsJSON(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/opt/One.AppImage"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status string        `json:"status"`
+		Action string        `json:"action"`
+		Apps   []adoptedJSON `json:"apps"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "adopt" || len(payload.Apps) != 1 || payload.Apps[0].ID != "one" {
+		t.Fatalf("payload = %#v, want adopted app", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want no activity output in JSON mode", stderr.String())
+	}
+}
+
+func TestCommandReturnsServiceError(t *testing.T) {
+	wantErr := errors.New("adopt failed")
+	service := &fakeService{err: wantErr}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/opt/One.AppImage"})
+
+	if err := cmd.ExecuteContext(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, wantErr)
+	}
+}
+
+type fakeService struct {
+	reqs []app.AdoptRequest
+	err  error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Adopt(ctx context.Context, req app.AdoptRequest) (app.AdoptResult, error) {
+	s.reqs = append(s.reqs, req)
+	if s.err != nil {
+		return app.AdoptResult{}, s.err
+	}
+	name := strings.TrimSuffix(req.Path[strings.LastIndex(req.Path, "/")+1:], ".AppImage")
+	result := app.AdoptResult{Moved: req.Move}
+	result.App.ID = strings.ToLower(name)
+	result.App.Name = name
+	result.App.AppImagePath = req.Path
+	return result, nil
+}
@@ -0,0 +1,186 @@
+// Package autoupdate provides the autoupdate command, which schedules
+// unattended aim update runs.
+package autoupdate
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	EnableAutoUpdate(ctx context.Context, req app.EnableAutoUpdateRequest) (app.AutoUpdateStatus, error)
+	DisableAutoUpdate(ctx context.Context) (app.DisableAutoUpdateResult, error)
+	AutoUpdateStatus(ctx context.Context) (app.AutoUpdateStatus, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autoupdate",
+		Short: "Schedule unattended updates",
+		Long:  "Run aim update on a schedule with a systemd user timer, or with a crontab entry where systemd is not available.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newEnableCommand(rt, service))
+	cmd.AddCommand(newDisableCommand(rt, service))
+	cmd.AddCommand(newStatusCommand(rt, service))
+
+	return cmd
+}
+
+func newEnableCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var interval string
+	var checkOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Update apps on a schedule",
+		Long:  "Write and start a systemd user service and timer in $XDG_CONFIG_HOME/systemd/user that run aim update --yes --notify, or add a crontab entry when systemd is not available. With --check-only, the scheduled runs only report available updates with a desktop notification. Enabling again replaces the schedule.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := service.EnableAutoUpdate(cmd.Context(), app.EnableAutoUpdateRequest{
+				Interval:  interval,
+				CheckOnly: checkOnly,
+			})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status     string               `json:"status"`
+					Action     string               `json:"action"`
+					AutoUpdate app.AutoUpdateStatus `json:"autoupdate"`
+				}{
+					Status:     "ok",
+					Action:     "autoupdate_enable",
+					AutoUpdate: status,
+				},
+				func(w io.Writer) error {
+					_, err := fmt.Fprintf(w, "%sEnabled %s with %s%s\n", green, describeSchedule(status), status.Backend, reset)
+					return err
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&interval, "interval", "daily", "how often to run: hourly, daily, weekly, or monthly")
+	cmd.Flags().BoolVar(&checkOnly, "check-only", false, "only notify about available updates instead of applying them")
+
+	return cmd
+}
+
+func newDisableCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Stop scheduled updates",
+		Long:  "Stop and remove the systemd user timer and service or the crontab entry written by aim autoupdate enable.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := service.DisableAutoUpdate(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status   string `json:"status"`
+					Action   string `json:"action"`
+					Disabled bool   `json:"disabled"`
+				}{
+					Status:   "ok",
+					Action:   "autoupdate_disable",
+					Disabled: result.Disabled,
+				},
+				func(w io.Writer) error {
+					if !result.Disabled {
+						_, err := fmt.Fprintln(w, "Automatic updates were not enabled")
+						return err
+					}
+					_, err := fmt.Fprintf(w, "%sDisabled automatic updates%s\n", green, reset)
+					return err
+				},
+			)
+		},
+	}
+}
+
+func newStatusCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the update schedule and its last run",
+		Long:  "Show whether scheduled updates are enabled, the result of the last scheduled run as recorded by systemd, and the most recent update from the history log.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := service.AutoUpdateStatus(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status     string               `json:"status"`
+					Action     string               `json:"action"`
+					AutoUpdate app.AutoUpdateStatus `json:"autoupdate"`
+				}{
+					Status:     "ok",
+					Action:     "autoupdate_status",
+					AutoUpdate: status,
+				},
+				func(w io.Writer) error {
+					return writeStatus(w, status)
+				},
+			)
+		},
+	}
+}
+
+func writeStatus(w io.Writer, status app.AutoUpdateStatus) error {
+	if status.Enabled {
+		fmt.Fprintf(w, "Enabled: %s with %s\n", describeSchedule(status), status.Backend)
+	} else {
+		fmt.Fprintln(w, "Disabled")
+	}
+
+	if run := status.LastRun; run != nil {
+		result := "succeeded"
+		if !run.Succeeded {
+			result = "failed: " + run.Result
+		}
+		fmt.Fprintf(w, "Last run: %s, %s\n", run.Time.Local().Format("2006-01-02 15:04"), result)
+	}
+	if event := status.LastUpdate; event != nil {
+		result := string(event.Result)
+		if event.Error != "" {
+			result += ": " + event.Error
+		}
+		fmt.Fprintf(w, "Last update: %s, %s %s -> %s, %s\n", event.Time.Local().Format("2006-01-02 15:04"), event.AppID, event.OldVersion, event.NewVersion, result)
+	}
+
+	return nil
+}
+
+func describeSchedule(status app.AutoUpdateStatus) string {
+	if status.CheckOnly {
+		return fmt.Sprintf("%s update checks", status.Interval)
+	}
+	return fmt.Sprintf("%s updates", status.Interval)
+}
@@ -0,0 +1,142 @@
+package autoupdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestEnableCommandPassesIntervalAndCheckOnly(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"enable", "--interval", "weekly", "--check-only"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if service.enableReq != (app.EnableAutoUpdateRequest{Interval: "weekly", CheckOnly: true}) {
+		t.Fatalf("EnableAutoUpdateRequest = %#v", service.enableReq)
+	}
+	if got := stdout.String(); !strings.Contains(got, "Enabled weekly update checks with systemd") {
+		t.Fatalf("stdout = %q, want enabled summary", got)
+	}
+}
+
+func TestDisableCommandReportsNothingToDisable(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, &bytes.Buffer{}), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"disable"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got := stdout.String(); got != "Automatic updates were not enabled\n" {
+		t.Fatalf("stdout = %q, want not enabled message", got)
+	}
+}
+
+func TestStatusCommandPrintsLastRunAndUpdate(t *testing.T) {
+	at := time.Date(2026, 5, 3, 3, 0, 0, 0, time.UTC)
+	service := &fakeService{status: app.AutoUpdateStatus{
+		Enabled:  true,
+		Backend:  "systemd",
+		Interval: app.UpdateIntervalDaily,
+		LastRun:  &app.ScheduledRun{Time: at, Result: "exit-code (status 1)"},
+		LastUpdate: &app.HistoryEvent{
+			Time:       at,
+			Operation:  app.HistoryOperationUpdate,
+			AppID:      "example-app",
+			OldVersion: "1.0.0",
+			NewVersion: "1.1.0",
+			Result:     app.HistoryOutcomeSucceeded,
+		},
+	}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, &bytes.Buffer{}), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"status"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Enabled: daily updates with systemd\n",
+		"failed: exit-code (status 1)\n",
+		"example-app 1.0.0 -> 1.1.0, succeeded\n",
+	} {
+		if got := stdout.String(); !strings.Contains(got, want) {
+			t.Fatalf("stdout = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestStatusCommandWritesJSON(t *testing.T) {
+	service := &fakeService{status: app.AutoUpdateStatus{Enabled: true, Backend: "cron", Interval: app.UpdateIntervalHourly, CheckOnly: true}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"status"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var got struct {
+		Status     string `json:"status"`
+		Action     string `json:"action"`
+		AutoUpdate struct {
+			Enabled   bool   `json:"enabled"`
+			Backend   string `json:"backend"`
+			Interval  string `json:"interval"`
+			CheckOnly bool   `json:"check_only"`
+		} `json:"autoupdate"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if got.Status != "ok" || got.Action != "autoupdate_status" || !got.AutoUpdate.Enabled || got.AutoUpdate.Backend != "cron" || got.AutoUpdate.Interval != "hourly" || !got.AutoUpdate.CheckOnly {
+		t.Fatalf("payload = %#v", got)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty JSON stderr", stderr.String())
+	}
+}
+
+type fakeService struct {
+	enableReq app.EnableAutoUpdateRequest
+	status    app.AutoUpdateStatus
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) EnableAutoUpdate(ctx context.Context, req app.EnableAutoUpdateRequest) (app.AutoUpdateStatus, error) {
+	s.enableReq = req
+	return app.AutoUpdateStatus{Enabled: true, Backend: "systemd", Interval: app.UpdateInterval(req.Interval), CheckOnly: req.CheckOnly}, nil
+}
+
+func (s *fakeService) DisableAutoUpdate(ctx context.Context) (app.DisableAutoUpdateResult, error) {
+	return app.DisableAutoUpdateResult{Disabled: s.status.Enabled}, nil
+}
+
+func (s *fakeService) AutoUpdateStatus(ctx context.Context) (app.AutoUpdateStatus, error) {
+	return s.status, nil
+}
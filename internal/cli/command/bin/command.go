@@ -0,0 +1,85 @@
+// Package bin provides the bin command, which puts an app on PATH.
+package bin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	SetBin(ctx context.Context, req app.SetBinRequest) (app.SetBinResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var name string
+	var off bool
+
+	cmd := &cobra.Command{
+		Use:   "bin <id> [--name <name> | --off]",
+		Short: "Install a command for an app",
+		Long: "Install a command that launches an app into the bin directory, ~/.local/bin unless bin_dir is set in config.toml. " +
+			"The command is named after the app ID unless --name is given, follows the app across updates and ID changes, and is removed with the app. " +
+			"--off removes it.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("requires exactly one app id")
+			}
+			if off && strings.TrimSpace(name) != "" {
+				return fmt.Errorf("provide either --name or --off, not both")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := service.SetBin(cmd.Context(), app.SetBinRequest{
+				ID:   args[0],
+				Name: name,
+				Off:  off,
+			})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status string `json:"status"`
+					Action string `json:"action"`
+					ID     string `json:"id"`
+					Path   string `json:"path"`
+				}{
+					Status: "ok",
+					Action: "set_bin",
+					ID:     result.ID,
+					Path:   result.Path,
+				},
+				func(w io.Writer) error {
+					if result.Path == "" {
+						_, err := fmt.Fprintf(w, "%s%s has no command%s\n", green, result.ID, reset)
+						return err
+					}
+					_, err := fmt.Fprintf(w, "%sInstalled %s for %s%s\n", green, result.Path, result.ID, reset)
+					return err
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "name of the command (default: the current name or the app ID)")
+	cmd.Flags().BoolVar(&off, "off", false, "remove the command")
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package bin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPassesNameAndPrintsPath(t *testing.T) {
+	service := &fakeService{result: app.SetBinResult{ID: "neovim", Path: "/home/user/.local/bin/nvim"}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"neovim", "--name", "nvim"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if want := (app.SetBinRequest{ID: "neovim", Name: "nvim"}); service.req != want {
+		t.Fatalf("SetBinRequest = %#v, want %#v", service.req, want)
+	}
+	if !strings.Contains(stdout.String(), "Installed /home/user/.local/bin/nvim for neovim") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestCommandOffPrintsJSON(t *testing.T) {
+	service := &fakeService{result: app.SetBinResult{ID: "neovim"}}
+	stdout := &bytes.Buffer{}
+	rt := clienv.New(stdout, stdout)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"neovim", "--off"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !service.req.Off {
+		t.Fatalf("SetBinRequest = %#v, want off", service.req)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload["action"] != "set_bin" || payload["path"] != "" {
+		t.Fatalf("payload = %#v, want set_bin without path", payload)
+	}
+}
+
+func TestCommandRejectsNameWithOff(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SetArgs([]string{"neovim", "--name", "nvim", "--off"})
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("ExecuteContext() error = nil, want validation error")
+	}
+	if service.called {
+		t.Fatal("service.SetBin called for invalid flags")
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.SetBinRequest
+	result app.SetBinResult
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) SetBin(ctx context.Context, req app.SetBinRequest) (app.SetBinResult, error) {
+	s.called = true
+	s.req = req
+	return s.result, nil
+}
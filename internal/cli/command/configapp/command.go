@@ -0,0 +1,144 @@
+// Package configapp provides the config-app command, which sets the arguments
+// and environment variables an app is launched with.
+package configapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	ConfigureApp(ctx context.Context, req app.ConfigureAppRequest) (app.ConfigureAppResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var args []string
+	var env []string
+	var unsetEnv []string
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "config-app <id>",
+		Short: "Set the arguments and environment an app is launched with",
+		Long: "Set the arguments and environment variables an app is launched with, such as --ozone-platform=wayland or GDK_SCALE=2. " +
+			"They are written to the Exec lines of the app's desktop entry and kept across updates. " +
+			"--arg replaces the current arguments, --env and --unset-env change single variables, and --clear drops all of them first. " +
+			"Without flags the current settings are shown.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, positional []string) error {
+			req := app.ConfigureAppRequest{
+				ID:          positional[0],
+				Clear:       clear,
+				ReplaceArgs: cmd.Flags().Changed("arg"),
+				Args:        args,
+				UnsetEnv:    unsetEnv,
+			}
+			vars, err := parseEnv(env)
+			if err != nil {
+				return err
+			}
+			req.Env = vars
+
+			result, err := service.ConfigureApp(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			environ := result.Launch.Environ()
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status  string            `json:"status"`
+					Action  string            `json:"action"`
+					ID      string            `json:"id"`
+					Changed bool              `json:"changed"`
+					Args    []string          `json:"args"`
+					Env     map[string]string `json:"env"`
+				}{
+					Status:  "ok",
+					Action:  "configure_app",
+					ID:      result.ID,
+					Changed: result.Changed,
+					Args:    nonNil(result.Launch.Args),
+					Env:     nonNilMap(result.Launch.Env),
+				},
+				func(w io.Writer) error {
+					if result.Changed {
+						if _, err := fmt.Fprintf(w, "%sUpdated launch settings of %s%s\n", green, result.ID, reset); err != nil {
+							return err
+						}
+					}
+					if result.Launch.IsZero() {
+						_, err := fmt.Fprintf(w, "%s launches without extra arguments or environment variables\n", result.ID)
+						return err
+					}
+					if len(result.Launch.Args) > 0 {
+						if _, err := fmt.Fprintf(w, "Arguments:   %s\n", strings.Join(result.Launch.Args, " ")); err != nil {
+							return err
+						}
+					}
+					if len(environ) > 0 {
+						if _, err := fmt.Fprintf(w, "Environment: %s\n", strings.Join(environ, " ")); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&args, "arg", nil, "argument to launch the app with; repeat for several, replacing the current ones")
+	cmd.Flags().StringArrayVar(&env, "env", nil, "environment variable to launch the app with, as KEY=VALUE; repeatable")
+	cmd.Flags().StringArrayVar(&unsetEnv, "unset-env", nil, "environment variable to stop setting; repeatable")
+	cmd.Flags().BoolVar(&clear, "clear", false, "drop all current arguments and environment variables first")
+
+	return cmd
+}
+
+// parseEnv parses KEY=VALUE flag values into a map; later values of a key
+// win.
+func parseEnv(assignments []string) (map[string]string, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	env := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		key, value, err := domain.ParseEnv(assignment)
+		if err != nil {
+			return nil, err
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nonNilMap(values map[string]string) map[string]string {
+	if values == nil {
+		return map[string]string{}
+	}
+	return values
+}
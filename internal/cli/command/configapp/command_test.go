@@ -0,0 +1,107 @@
+package configapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestCommandPassesLaunchOptionChanges(t *testing.T) {
+	service := &fakeService{result: app.ConfigureAppResult{
+		ID:      "chat",
+		Changed: true,
+		Launch:  domain.LaunchOptions{Args: []string{"--ozone-platform=wayland", "--no-sandbox"}, Env: map[string]string{"GDK_SCALE": "2"}},
+	}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"chat", "--arg", "--ozone-platform=wayland", "--arg", "--no-sandbox", "--env", "GDK_SCALE=2", "--unset-env", "OLD"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want := app.ConfigureAppRequest{
+		ID:          "chat",
+		ReplaceArgs: true,
+		Args:        []string{"--ozone-platform=wayland", "--no-sandbox"},
+		Env:         map[string]string{"GDK_SCALE": "2"},
+		UnsetEnv:    []string{"OLD"},
+	}
+	if !reflect.DeepEqual(service.req, want) {
+		t.Fatalf("ConfigureAppRequest = %#v, want %#v", service.req, want)
+	}
+	for _, line := range []string{"Updated launch settings of chat", "Arguments:   --ozone-platform=wayland --no-sandbox", "Environment: GDK_SCALE=2"} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Fatalf("stdout = %q, want %q", stdout.String(), line)
+		}
+	}
+}
+
+func TestCommandWithoutFlagsShowsCurrentOptionsAsJSON(t *testing.T) {
+	service := &fakeService{result: app.ConfigureAppResult{ID: "chat"}}
+	stdout := &bytes.Buffer{}
+	rt := clienv.New(stdout, stdout)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"chat"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if service.req.ReplaceArgs || service.req.Clear || service.req.Env != nil {
+		t.Fatalf("ConfigureAppRequest = %#v, want no changes", service.req)
+	}
+	var payload struct {
+		Action  string            `json:"action"`
+		Changed bool              `json:"changed"`
+		Args    []string          `json:"args"`
+		Env     map[string]string `json:"env"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Action != "configure_app" || payload.Changed || payload.Args == nil || payload.Env == nil {
+		t.Fatalf("payload = %#v, want unchanged configure_app with empty args and env", payload)
+	}
+}
+
+func TestCommandRejectsMalformedEnv(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SetArgs([]string{"chat", "--env", "GDK_SCALE"})
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil || !strings.Contains(err.Error(), "KEY=VALUE") {
+		t.Fatalf("ExecuteContext() error = %v, want KEY=VALUE error", err)
+	}
+	if service.called {
+		t.Fatal("service.ConfigureApp called for malformed env")
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.ConfigureAppRequest
+	result app.ConfigureAppResult
+	err    error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) ConfigureApp(ctx context.Context, req app.ConfigureAppRequest) (app.ConfigureAppResult, error) {
+	s.called = true
+	s.req = req
+	return s.result, s.err
+}
@@ -0,0 +1,160 @@
+// Package db provides the db command, which backs up and restores the app
+// database.
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/prompt"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	BackupDatabase(ctx context.Context, req app.BackupDatabaseRequest) (app.BackupDatabaseResult, error)
+	RestoreDatabase(ctx context.Context, req app.RestoreDatabaseRequest) (app.RestoreDatabaseResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Back up and restore the app database",
+		Long:  "Back up and restore the database of installed apps. aim also backs the database up automatically before migrating it to a newer schema.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newBackupCommand(rt, service))
+	cmd.AddCommand(newRestoreCommand(rt, service))
+
+	return cmd
+}
+
+func newBackupCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup [<file>]",
+		Short: "Copy the app database to a backup file",
+		Long:  "Copy the app database to the given file, or to a timestamped file in the backups directory next to the database when no file is given.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) == 1 {
+				var err error
+				path, err = userpath.Abs(args[0])
+				if err != nil {
+					return err
+				}
+			}
+
+			result, err := service.BackupDatabase(cmd.Context(), app.BackupDatabaseRequest{Path: path})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status string             `json:"status"`
+					Action string             `json:"action"`
+					Backup app.DatabaseBackup `json:"backup"`
+				}{
+					Status: "ok",
+					Action: "db_backup",
+					Backup: result.Backup,
+				},
+				func(w io.Writer) error {
+					_, err := fmt.Fprintf(w, "%sBacked up %d apps to %s%s\n", green, result.Backup.Apps, result.Backup.Path, reset)
+					return err
+				},
+			)
+		},
+	}
+}
+
+func newRestoreCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Replace the app database with a backup",
+		Long:  "Replace the app database with a backup file after confirmation. The current database is backed up first, so a restore can itself be undone.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := userpath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := service.RestoreDatabase(cmd.Context(), app.RestoreDatabaseRequest{
+				Path: path,
+				Confirmation: restorePrompter{
+					in:          cmd.InOrStdin(),
+					out:         cmd.OutOrStdout(),
+					autoConfirm: yes || rt.Config.JSON,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status   string             `json:"status"`
+					Action   string             `json:"action"`
+					Applied  bool               `json:"applied"`
+					Restored app.DatabaseBackup `json:"restored"`
+					Previous app.DatabaseBackup `json:"previous"`
+				}{
+					Status:   "ok",
+					Action:   "db_restore",
+					Applied:  result.Applied,
+					Restored: result.Restored,
+					Previous: result.Previous,
+				},
+				func(w io.Writer) error {
+					if !result.Applied {
+						fmt.Fprintln(w, "Restore canceled")
+						return nil
+					}
+					fmt.Fprintf(w, "%sRestored %d apps from %s%s\n", green, result.Restored.Apps, result.Restored.Path, reset)
+					if result.Previous.Path != "" {
+						fmt.Fprintf(w, "Previous database saved to %s\n", result.Previous.Path)
+					}
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "restore without asking for confirmation")
+
+	return cmd
+}
+
+type restorePrompter struct {
+	in          io.Reader
+	out         io.Writer
+	autoConfirm bool
+}
+
+func (p restorePrompter) ConfirmRestore(ctx context.Context, backup app.DatabaseBackup) (bool, error) {
+	if !p.autoConfirm {
+		fmt.Fprintf(p.out, "Backup %s has %d apps (schema version %d, modified %s).\n", backup.Path, backup.Apps, backup.SchemaVersion, backup.ModifiedAt.Format("2006-01-02 15:04"))
+		fmt.Fprintln(p.out, "It will replace the current app database.")
+		fmt.Fprintln(p.out)
+	}
+	return prompt.ConfirmYesNo(ctx, p.in, p.out, "Restore this backup? (y/n) ", p.autoConfirm)
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestBackupCommandPrintsBackup(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"backup", "/tmp/apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got, want := service.backupReq.Path, "/tmp/apps.json"; got != want {
+		t.Fatalf("BackupDatabaseRequest.Path = %q, want %q", got, want)
+	}
+	if got := stdout.String(); !strings.Contains(got, "Backed up 2 apps to /tmp/apps.json") {
+		t.Fatalf("stdout = %q, want backup summary", got)
+	}
+}
+
+func TestRestoreCommandPromptsAndPrintsCanceled(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"restore", "/tmp/apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Backup /tmp/apps.json has 2 apps (schema version 1",
+		"Restore this backup? (y/n) ",
+		"Restore canceled",
+	} {
+		if got := stdout.String(); !strings.Contains(got, want) {
+			t.Fatalf("stdout = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestRestoreCommandWritesJSONWithoutPrompt(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"restore", "/tmp/apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var got struct {
+		Status   string `json:"status"`
+		Action   string `json:"action"`
+		Applied  bool   `json:"applied"`
+		Restored struct {
+			Path string `json:"path"`
+			Apps int    `json:"apps"`
+		} `json:"restored"`
+		Previous struct {
+			Path string `json:"path"`
+		} `json:"previous"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if got.Status != "ok" || got.Action != "db_restore" || !got.Applied {
+		t.Fatalf("JSON = %#v, want applied db_restore", got)
+	}
+	if got.Restored.Path != "/tmp/apps.json" || got.Restored.Apps != 2 || got.Previous.Path != "/data/backups/previous.json" {
+		t.Fatalf("JSON = %#v, want restored and previous backups", got)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty", stderr.String())
+	}
+}
+
+type fakeService struct {
+	backupReq  app.BackupDatabaseRequest
+	restoreReq app.RestoreDatabaseRequest
+}
+
+var _ service = (*fakeService)(nil)
+
+func (f *fakeService) BackupDatabase(ctx context.Context, req app.BackupDatabaseRequest) (app.BackupDatabaseResult, error) {
+	f.backupReq = req
+	return app.BackupDatabaseResult{Backup: app.DatabaseBackup{Path: req.Path, SchemaVersion: 2, Apps: 2}}, nil
+}
+
+func (f *fakeService) RestoreDatabase(ctx context.Context, req app.RestoreDatabaseRequest) (app.RestoreDatabaseResult, error) {
+	f.restoreReq = req
+	backup := app.DatabaseBackup{Path: req.Path, SchemaVersion: 1, Apps: 2, ModifiedAt: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	confirmed, err := req.Confirmation.ConfirmRestore(ctx, backup)
+	if err != nil || !confirmed {
+		return app.RestoreDatabaseResult{Restored: backup}, err
+	}
+	return app.RestoreDatabaseResult{Applied: true, Restored: backup, Previous: app.DatabaseBackup{Path: "/data/backups/previous.json"}}, nil
+}
@@ -0,0 +1,74 @@
+// Package defaultapp provides the default command, which makes an app the
+// default application for MIME types and URL schemes.
+package defaultapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	SetDefault(ctx context.Context, req app.SetDefaultRequest) (app.SetDefaultResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var mimeTypes []string
+
+	cmd := &cobra.Command{
+		Use:   "default <id> [--mime <type>]...",
+		Short: "Make an app the default for MIME types",
+		Long: "Make an app the default application for MIME types and URL schemes in mimeapps.list, " +
+			"for example --mime text/html or --mime x-scheme-handler/https. " +
+			"Without --mime, the app becomes the default for every MIME type its desktop entry lists. " +
+			"Removing the app removes it from mimeapps.list again.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := service.SetDefault(cmd.Context(), app.SetDefaultRequest{
+				ID:        args[0],
+				MimeTypes: mimeTypes,
+			})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status      string   `json:"status"`
+					Action      string   `json:"action"`
+					ID          string   `json:"id"`
+					DesktopFile string   `json:"desktop_file"`
+					MimeTypes   []string `json:"mime_types"`
+				}{
+					Status:      "ok",
+					Action:      "set_default",
+					ID:          result.ID,
+					DesktopFile: result.DesktopFile,
+					MimeTypes:   result.MimeTypes,
+				},
+				func(w io.Writer) error {
+					_, err := fmt.Fprintf(w, "%s%s is now the default for %s%s\n", green, result.ID, strings.Join(result.MimeTypes, ", "), reset)
+					return err
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&mimeTypes, "mime", nil, "MIME type or x-scheme-handler/<scheme> to open with the app (repeatable)")
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+package defaultapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPassesMimeTypesAndPrintsResult(t *testing.T) {
+	service := &fakeService{result: app.SetDefaultResult{ID: "firefox", DesktopFile: "firefox.desktop", MimeTypes: []string{"text/html", "x-scheme-handler/https"}}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"firefox", "--mime", "text/html", "--mime", "x-scheme-handler/https"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want := app.SetDefaultRequest{ID: "firefox", MimeTypes: []string{"text/html", "x-scheme-handler/https"}}
+	if !reflect.DeepEqual(service.req, want) {
+		t.Fatalf("SetDefaultRequest = %#v, want %#v", service.req, want)
+	}
+	if !strings.Contains(stdout.String(), "firefox is now the default for text/html, x-scheme-handler/https") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestCommandWithoutMimePrintsJSON(t *testing.T) {
+	service := &fakeService{result: app.SetDefaultResult{ID: "firefox", DesktopFile: "firefox.desktop", MimeTypes: []string{"text/html"}}}
+	stdout := &bytes.Buffer{}
+	rt := clienv.New(stdout, stdout)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"firefox"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if len(service.req.MimeTypes) != 0 {
+		t.Fatalf("SetDefaultRequest = %#v, want no MIME types", service.req)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload["action"] != "set_default" || payload["desktop_file"] != "firefox.desktop" {
+		t.Fatalf("payload = %#v, want set_default for firefox.desktop", payload)
+	}
+}
+
+type fakeService struct {
+	req    app.SetDefaultRequest
+	result app.SetDefaultResult
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) SetDefault(ctx context.Context, req app.SetDefaultRequest) (app.SetDefaultResult, error) {
+	s.req = req
+	return s.result, nil
+}
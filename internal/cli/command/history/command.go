@@ -0,0 +1,157 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	bold  = "\033[1m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	History(ctx context.Context, req app.HistoryRequest) (app.HistoryResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "history [<id>]",
+		Short: "Show when apps were added, updated, and removed",
+		Long:  "Show the log of adds, updates, removals, ID changes, and update source changes, with the versions involved, the result, and the aim version that made them. Use --since with a duration such as 7d or 12h, or with a date such as 2026-05-01.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := app.HistoryRequest{}
+			if len(args) == 1 {
+				req.ID = args[0]
+			}
+			if strings.TrimSpace(since) != "" {
+				parsed, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				req.Since = parsed
+			}
+
+			result, err := service.History(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			events := result.Events
+			if events == nil {
+				events = []app.HistoryEvent{}
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status string             `json:"status"`
+					Action string             `json:"action"`
+					Events []app.HistoryEvent `json:"events"`
+				}{
+					Status: "ok",
+					Action: "history",
+					Events: events,
+				},
+				func(w io.Writer) error {
+					if len(events) == 0 {
+						fmt.Fprintln(w, "No history")
+						return nil
+					}
+					return writeTable(w, events)
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only show events after a duration ago (7d, 12h) or a date (2026-05-01)")
+
+	return cmd
+}
+
+// parseSince accepts a duration relative to now, with d for days in addition
+// to the units of time.ParseDuration, or an absolute date or RFC 3339 time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if count, err := strconv.Atoi(days); err == nil && count >= 0 {
+			return now.AddDate(0, 0, -count), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration such as 7d or 12h, or a date such as 2026-05-01", value)
+}
+
+func writeTable(w io.Writer, events []app.HistoryEvent) error {
+	rows := make([][5]string, 0, len(events))
+	widths := [4]int{len("Time"), len("Operation"), len("App"), len("Version")}
+	for _, event := range events {
+		row := [5]string{
+			event.Time.Local().Format("2006-01-02 15:04"),
+			string(event.Operation),
+			appLabel(event),
+			versionLabel(event),
+			resultLabel(event),
+		}
+		for i := range widths {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+		rows = append(rows, row)
+	}
+
+	const gap = 2
+	format := fmt.Sprintf("%%-%ds%%-%ds%%-%ds%%-%ds%%s\n", widths[0]+gap, widths[1]+gap, widths[2]+gap, widths[3]+gap)
+
+	fmt.Fprintf(w, bold+format+reset, "Time", "Operation", "App", "Version", "Result")
+	for _, row := range rows {
+		fmt.Fprintf(w, format, row[0], row[1], row[2], row[3], row[4])
+	}
+
+	return nil
+}
+
+func appLabel(event app.HistoryEvent) string {
+	if event.PreviousID != "" {
+		return event.PreviousID + " -> " + event.AppID
+	}
+	return event.AppID
+}
+
+func versionLabel(event app.HistoryEvent) string {
+	switch {
+	case event.OldVersion != "" && event.NewVersion != "" && event.OldVersion != event.NewVersion:
+		return event.OldVersion + " -> " + event.NewVersion
+	case event.NewVersion != "":
+		return event.NewVersion
+	default:
+		return event.OldVersion
+	}
+}
+
+func resultLabel(event app.HistoryEvent) string {
+	if event.Result == app.HistoryOutcomeFailed && event.Error != "" {
+		return "failed: " + event.Error
+	}
+	return string(event.Result)
+}
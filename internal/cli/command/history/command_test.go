@@ -0,0 +1,111 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPrintsTable(t *testing.T) {
+	service := &fakeService{result: app.HistoryResult{Events: []app.HistoryEvent{
+		{Time: time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local), Operation: app.HistoryOperationUpdate, AppID: "example", OldVersion: "1.0.0", NewVersion: "1.1.0", Result: app.HistoryOutcomeSucceeded},
+		{Time: time.Date(2026, 5, 2, 10, 0, 0, 0, time.Local), Operation: app.HistoryOperationSetID, AppID: "renamed", PreviousID: "example", Result: app.HistoryOutcomeFailed, Error: "permission denied"},
+	}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"example"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got, want := service.req.ID, "example"; got != want {
+		t.Fatalf("HistoryRequest.ID = %q, want %q", got, want)
+	}
+	for _, want := range []string{
+		"2026-05-01 10:00  update",
+		"1.0.0 -> 1.1.0  succeeded",
+		"example -> renamed",
+		"failed: permission denied",
+	} {
+		if got := stdout.String(); !strings.Contains(got, want) {
+			t.Fatalf("stdout = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCommandWritesJSON(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--since", "2026-05-01"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if want := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local); !service.req.Since.Equal(want) {
+		t.Fatalf("HistoryRequest.Since = %v, want %v", service.req.Since, want)
+	}
+	var got struct {
+		Status string            `json:"status"`
+		Action string            `json:"action"`
+		Events []json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if got.Status != "ok" || got.Action != "history" || got.Events == nil {
+		t.Fatalf("JSON = %#v, want history with empty events", got)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty", stderr.String())
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"7d":                   now.AddDate(0, 0, -7),
+		"12h":                  now.Add(-12 * time.Hour),
+		"2026-05-01T08:00:00Z": time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := parseSince(value, now)
+		if err != nil {
+			t.Fatalf("parseSince(%q) error = %v", value, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("parseSince(%q) = %v, want %v", value, got, want)
+		}
+	}
+	if _, err := parseSince("last week", now); err == nil {
+		t.Fatal("parseSince(last week) error = nil, want invalid value")
+	}
+}
+
+type fakeService struct {
+	req    app.HistoryRequest
+	result app.HistoryResult
+}
+
+var _ service = (*fakeService)(nil)
+
+func (f *fakeService) History(ctx context.Context, req app.HistoryRequest) (app.HistoryResult, error) {
+	f.req = req
+	return f.result, nil
+}
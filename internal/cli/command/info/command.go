@@ -54,6 +54,12 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 
 func writeInfo(w io.Writer, result app.InfoResult) {
 	fmt.Fprintf(w, "%s%s%s\n", bold, title(result), reset)
+	if result.GenericName != "" {
+		fmt.Fprintf(w, "%-17s %s\n", "Generic name:", result.GenericName)
+	}
+	if result.Comment != "" {
+		fmt.Fprintf(w, "%-17s %s\n", "Comment:", result.Comment)
+	}
 	writeInstallationStatus(w, result)
 	fmt.Fprintf(w, "%-17s %s\n", "Exec path:", result.ExecPath)
 	writeSource(w, result)
@@ -102,6 +108,11 @@ func writeSource(w io.Writer, info app.InfoResult) {
 		if !source.GitHubRelease.DownloadedAt.IsZero() {
 			fmt.Fprintf(w, "%-17s %s\n", "Downloaded at:", output.FormatSourceTime(source.GitHubRelease.DownloadedAt))
 		}
+	case "url":
+		fmt.Fprintf(w, "%-17s %s\n", "URL:", source.URL.URL)
+		if !source.URL.DownloadedAt.IsZero() {
+			fmt.Fprintf(w, "%-17s %s\n", "Downloaded at:", output.FormatSourceTime(source.URL.DownloadedAt))
+		}
 	}
 }
 
@@ -78,6 +78,39 @@ func TestCommandPassesTargetAndPrintsTextInfo(t *testing.T) {
 	}
 }
 
+func TestCommandPrintsGenericNameAndComment(t *testing.T) {
+	service := &fakeService{
+		infoResult: app.InfoResult{
+			ID:          "example-app",
+			Name:        "Beispiel-App",
+			GenericName: "Texteditor",
+			Comment:     "Text bearbeiten",
+			Version:     "1.2.3",
+		},
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"example-app"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	output := stdout.String()
+	for _, want := range []string{
+		"[example-app] Beispiel-App (v1.2.3)",
+		"Generic name:     Texteditor",
+		"Comment:          Text bearbeiten",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("stdout = %q, want it to contain %q", output, want)
+		}
+	}
+}
+
 func TestCommandPrintsPreservedNonGitHubUpdateSourceStatus(t *testing.T) {
 	result := app.InfoResult{
 		Name:       "Example App",
@@ -122,6 +155,10 @@ func TestCommandPrintsJSONInfo(t *testing.T) {
 			ExecPath:   "/apps/example-app.AppImage",
 			Installed:  true,
 			TargetKind: "installed",
+			Comment:    "An example app",
+			Translations: app.Translations{
+				Name: map[string]string{"de": "Beispiel-App"},
+			},
 		},
 	}
 	stdout := &bytes.Buffer{}
@@ -150,6 +187,10 @@ func TestCommandPrintsJSONInfo(t *testing.T) {
 		UpdateSource struct {
 			Kind string `json:"kind"`
 		} `json:"update_source"`
+		Comment      string `json:"comment"`
+		Translations struct {
+			Name map[string]string `json:"name"`
+		} `json:"translations"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
 		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
@@ -157,6 +198,9 @@ func TestCommandPrintsJSONInfo(t *testing.T) {
 	if payload.ID != "example-app" || payload.Name != "Example App" || payload.Version != "1.2.3" || payload.ExecPath != "/apps/example-app.AppImage" || !payload.Installed || payload.TargetKind != "installed" {
 		t.Fatalf("payload = %#v, want example app info", payload)
 	}
+	if payload.Comment != "An example app" || payload.Translations.Name["de"] != "Beispiel-App" {
+		t.Fatalf("payload = %#v, want comment and name translations", payload)
+	}
 	if !jsonContainsTopLevelField(t, stdout.Bytes(), "source") {
 		t.Fatalf("stdout = %q, want top-level source field", stdout.String())
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"unicode/utf8"
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/cli/clienv"
@@ -54,7 +55,8 @@ func writeTable(w io.Writer, items []app.ListItem) error {
 
 	for _, item := range items {
 		idWidth = max(idWidth, len(item.ID))
-		nameWidth = max(nameWidth, len(item.Name))
+		// Localized names are often not ASCII; fmt pads by runes, not bytes.
+		nameWidth = max(nameWidth, utf8.RuneCountInString(item.Name))
 	}
 
 	const gap = 2
@@ -41,6 +41,32 @@ func TestCommandCallsServiceAndPrintsTextList(t *testing.T) {
 	}
 }
 
+func TestCommandAlignsLocalizedNames(t *testing.T) {
+	service := &fakeService{
+		listResult: app.ListResult{Items: []app.ListItem{
+			{ID: "example-app", Name: "Пример", Version: "1.2.3"},
+			{ID: "other", Name: "Other", Version: "unknown"},
+		}},
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	output := stdout.String()
+	for _, want := range []string{"example-app  Пример  1.2.3\n", "other        Other   unknown\n"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("stdout = %q, want it to contain %q", output, want)
+		}
+	}
+}
+
 func TestCommandPrintsJSONList(t *testing.T) {
 	service := &fakeService{
 		listResult: app.ListResult{Items: []app.ListItem{
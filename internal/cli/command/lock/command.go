@@ -0,0 +1,105 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+type service interface {
+	Lock(ctx context.Context, req app.LockRequest) (app.LockResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var manifest string
+	var update bool
+
+	cmd := &cobra.Command{
+		Use:   "lock [<id>]",
+		Short: "Pin manifest apps to exact artifacts in aim.lock",
+		Long:  "Resolve every manifest app to a release tag, asset, download URL, size, and SHA-256 and record them in aim.lock next to the manifest. Existing entries are kept unless --update is given; pass an app ID with --update to refresh only that app. Use aim sync --locked to install the pinned artifacts.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := ""
+			if len(args) == 1 {
+				id = args[0]
+			}
+			location := strings.TrimSpace(manifest)
+			if location != "" {
+				var err error
+				location, err = userpath.Abs(location)
+				if err != nil {
+					return err
+				}
+			}
+
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+			result, err := service.Lock(cmd.Context(), app.LockRequest{
+				Manifest: location,
+				Update:   update,
+				ID:       id,
+				Activity: reporter,
+			})
+			reporter.Wait()
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status  string          `json:"status"`
+					Action  string          `json:"action"`
+					Path    string          `json:"path"`
+					Apps    []app.LockedApp `json:"apps"`
+					Changed []string        `json:"changed"`
+				}{
+					Status:  "ok",
+					Action:  "lock",
+					Path:    result.Path,
+					Apps:    result.Apps,
+					Changed: result.Changed,
+				},
+				func(w io.Writer) error {
+					changed := make(map[string]bool, len(result.Changed))
+					for _, id := range result.Changed {
+						changed[id] = true
+					}
+					for _, locked := range result.Apps {
+						if changed[locked.ID] {
+							fmt.Fprintf(w, "Locked %s to %s\n", locked.ID, describeArtifact(locked))
+						}
+					}
+					if len(result.Changed) == 0 {
+						fmt.Fprintf(w, "%s is up to date\n", result.Path)
+						return nil
+					}
+					fmt.Fprintf(w, "Wrote %s\n", result.Path)
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&manifest, "manifest", "", "manifest file path")
+	cmd.Flags().BoolVar(&update, "update", false, "re-resolve locked apps to the artifacts the manifest selects now")
+
+	return cmd
+}
+
+func describeArtifact(locked app.LockedApp) string {
+	if locked.Tag == "" {
+		return locked.URL
+	}
+	return locked.Tag + " (" + locked.AssetName + ")"
+}
@@ -0,0 +1,116 @@
+package lock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPrintsChangedApps(t *testing.T) {
+	service := &fakeService{result: app.LockResult{
+		Path: "/team/aim.lock",
+		Apps: []app.LockedApp{
+			{ID: "obsidian", Tag: "v1.6.7", AssetName: "Obsidian-1.6.7.AppImage", SHA256: "abc"},
+			{ID: "example", URL: "https://example.test/Example.AppImage", SHA256: "def"},
+		},
+		Changed: []string{"obsidian", "example"},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--manifest", "team.toml", "--update", "obsidian"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !filepath.IsAbs(service.req.Manifest) || filepath.Base(service.req.Manifest) != "team.toml" {
+		t.Fatalf("LockRequest.Manifest = %q, want absolute team.toml", service.req.Manifest)
+	}
+	if !service.req.Update || service.req.ID != "obsidian" {
+		t.Fatalf("LockRequest = %#v, want update of obsidian", service.req)
+	}
+	want := "Locked obsidian to v1.6.7 (Obsidian-1.6.7.AppImage)\n" +
+		"Locked example to https://example.test/Example.AppImage\n" +
+		"Wrote /team/aim.lock\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestCommandReportsUpToDate(t *testing.T) {
+	service := &fakeService{result: app.LockResult{Path: "/team/aim.lock"}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if service.req.Manifest != "" {
+		t.Fatalf("LockRequest.Manifest = %q, want default", service.req.Manifest)
+	}
+	if got, want := stdout.String(), "/team/aim.lock is up to date\n"; got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestCommandWritesJSON(t *testing.T) {
+	service := &fakeService{result: app.LockResult{
+		Path:    "/team/aim.lock",
+		Apps:    []app.LockedApp{{ID: "example", URL: "https://example.test/Example.AppImage", SizeBytes: 8, SHA256: "def"}},
+		Changed: []string{"example"},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status  string          `json:"status"`
+		Action  string          `json:"action"`
+		Path    string          `json:"path"`
+		Apps    []app.LockedApp `json:"apps"`
+		Changed []string        `json:"changed"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "lock" || payload.Path != "/team/aim.lock" || len(payload.Apps) != 1 || payload.Apps[0].SHA256 != "def" {
+		t.Fatalf("payload = %#v, want lock result", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty in JSON mode", stderr.String())
+	}
+}
+
+type fakeService struct {
+	req    app.LockRequest
+	result app.LockResult
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Lock(ctx context.Context, req app.LockRequest) (app.LockResult, error) {
+	s.req = req
+	return s.result, nil
+}
@@ -0,0 +1,151 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/prompt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Migrate(ctx context.Context, req app.MigrateRequest) (app.MigrateResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var from string
+	var move bool
+	var keepArtifacts bool
+	var yes bool
+
+	tools := make([]string, 0, len(app.MigrationTools))
+	for _, tool := range app.MigrationTools {
+		tools = append(tools, string(tool))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from <tool>",
+		Short: "Take over AppImages integrated by another tool",
+		Long:  "Find AppImages integrated by AppImageLauncher, Gear Lever or appimaged, integrate them with aim, and remove the other tool's desktop entries and icons after confirmation. Supported tools: " + strings.Join(tools, ", ") + ".",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(from) == "" {
+				return fmt.Errorf("--from is required")
+			}
+
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+			result, err := service.Migrate(cmd.Context(), app.MigrateRequest{
+				From:          from,
+				Move:          move,
+				KeepArtifacts: keepArtifacts,
+				Activity:      reporter,
+				Confirmation: migrationPrompter{
+					in:            cmd.InOrStdin(),
+					out:           cmd.OutOrStdout(),
+					autoConfirm:   yes || rt.Config.JSON,
+					keepArtifacts: keepArtifacts,
+				},
+			})
+			if err != nil {
+				reporter.Wait()
+				return err
+			}
+			reporter.Wait()
+			if !rt.Config.JSON {
+				writeMigrationFailures(cmd.ErrOrStderr(), result.Failures)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status     string                   `json:"status"`
+					Action     string                   `json:"action"`
+					From       string                   `json:"from"`
+					Applied    bool                     `json:"applied"`
+					Candidates []app.MigrationCandidate `json:"candidates"`
+					Migrated   []app.MigratedApp        `json:"migrated"`
+					Failures   []app.MigrationFailure   `json:"failures"`
+				}{
+					Status:     "ok",
+					Action:     "migrate",
+					From:       from,
+					Applied:    result.Applied,
+					Candidates: result.Candidates,
+					Migrated:   result.Migrated,
+					Failures:   result.Failures,
+				},
+				func(w io.Writer) error {
+					if len(result.Candidates) == 0 {
+						fmt.Fprintf(w, "No AppImages integrated by %s found\n", from)
+						return nil
+					}
+					if !result.Applied {
+						fmt.Fprintln(w, "Migration canceled")
+						return nil
+					}
+					for _, migrated := range result.Migrated {
+						fmt.Fprintf(w, "%sMigrated %s [%s]%s\n", green, migrated.Name, migrated.ID, reset)
+					}
+					if len(result.Failures) > 0 {
+						fmt.Fprintf(w, "Finished migrating; %d migration errors.\n", len(result.Failures))
+					}
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "tool to migrate from ("+strings.Join(tools, ", ")+")")
+	cmd.Flags().BoolVar(&move, "move", false, "move AppImages into the aim AppImage directory")
+	cmd.Flags().BoolVar(&keepArtifacts, "keep", false, "keep the other tool's desktop entries and icons")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "migrate without asking for confirmation")
+
+	return cmd
+}
+
+type migrationPrompter struct {
+	in            io.Reader
+	out           io.Writer
+	autoConfirm   bool
+	keepArtifacts bool
+}
+
+func (p migrationPrompter) ConfirmMigration(ctx context.Context, candidates []app.MigrationCandidate) (bool, error) {
+	if !p.autoConfirm {
+		writeMigrationCandidates(p.out, candidates, p.keepArtifacts)
+		fmt.Fprintln(p.out)
+	}
+	return prompt.ConfirmYesNo(ctx, p.in, p.out, "Migrate these apps? (y/n) ", p.autoConfirm)
+}
+
+func writeMigrationCandidates(w io.Writer, candidates []app.MigrationCandidate, keepArtifacts bool) {
+	for _, candidate := range candidates {
+		fmt.Fprintf(w, "[%s] %s\n", candidate.Name, candidate.AppImagePath)
+		if keepArtifacts {
+			continue
+		}
+		fmt.Fprintf(w, "  remove %s\n", candidate.DesktopEntryPath)
+		for _, icon := range candidate.IconPaths {
+			fmt.Fprintf(w, "  remove %s\n", icon)
+		}
+	}
+}
+
+func writeMigrationFailures(w io.Writer, failures []app.MigrationFailure) {
+	for _, failure := range failures {
+		fmt.Fprintf(w, "Migration error [%s]: %s\n", failure.AppImagePath, failure.Error)
+	}
+}
@@ -0,0 +1,132 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandRequiresFrom(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	service := &fakeService{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("ExecuteContext() error = nil, want missing --from error")
+	}
+	if service.called {
+		t.Fatal("service.Migrate called without --from")
+	}
+}
+
+func TestCommandPromptsWithArtifactsAndPrintsCanceled(t *testing.T) {
+	service := &fakeService{candidates: []app.MigrationCandidate{{
+		Tool:             "appimagelauncher",
+		Name:             "Example",
+		AppImagePath:     "/home/user/Applications/Example.AppImage",
+		DesktopEntryPath: "/home/user/.local/share/applications/appimagekit_abc-Example.desktop",
+		IconPaths:        []string{"/home/user/.local/share/icons/hicolor/128x128/apps/appimagekit_abc_example.png"},
+	}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"--from", "appimagelauncher", "--move"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got, want := service.req.From, "appimagelauncher"; got != want {
+		t.Fatalf("MigrateRequest.From = %q, want %q", got, want)
+	}
+	if !service.req.Move || service.req.KeepArtifacts {
+		t.Fatalf("MigrateRequest = %#v, want move without keep", service.req)
+	}
+	for _, want := range []string{
+		"[Example] /home/user/Applications/Example.AppImage",
+		"remove /home/user/.local/share/applications/appimagekit_abc-Example.desktop",
+		"remove /home/user/.local/share/icons/hicolor/128x128/apps/appimagekit_abc_example.png",
+		"Migrate these apps? (y/n)",
+		"Migration canceled\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout = %q, want it to contain %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestCommandAutoConfirmsJSON(t *testing.T) {
+	service := &fakeService{
+		candidates: []app.MigrationCandidate{{Tool: "gearlever", Name: "Example", AppImagePath: "/apps/example.appimage"}},
+		migrated:   []app.MigratedApp{{ID: "example", Name: "Example", AppImagePath: "/apps/example.appimage"}},
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--from", "gearlever", "--keep"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status   string            `json:"status"`
+		Action   string            `json:"action"`
+		Applied  bool              `json:"applied"`
+		Migrated []app.MigratedApp `json:"migrated"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "migrate" || !payload.Applied || len(payload.Migrated) != 1 {
+		t.Fatalf("payload = %#v, want applied migration", payload)
+	}
+	if !service.req.KeepArtifacts {
+		t.Fatal("MigrateRequest.KeepArtifacts = false, want true")
+	}
+}
+
+type fakeService struct {
+	called     bool
+	req        app.MigrateRequest
+	candidates []app.MigrationCandidate
+	migrated   []app.MigratedApp
+	err        error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Migrate(ctx context.Context, req app.MigrateRequest) (app.MigrateResult, error) {
+	s.called = true
+	s.req = req
+	if s.err != nil {
+		return app.MigrateResult{}, s.err
+	}
+	if len(s.candidates) == 0 {
+		return app.MigrateResult{Applied: true}, nil
+	}
+	confirmed, err := req.Confirmation.ConfirmMigration(ctx, s.candidates)
+	if err != nil {
+		return app.MigrateResult{}, err
+	}
+	if !confirmed {
+		return app.MigrateResult{Applied: false, Candidates: s.candidates}, nil
+	}
+	return app.MigrateResult{Applied: true, Candidates: s.candidates, Migrated: s.migrated}, nil
+}
@@ -20,7 +20,7 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "paths",
 		Short: "Show aim paths",
-		Long:  "Show the config, AppImage, desktop entry, and icon paths used by aim.",
+		Long:  "Show the config, AppImage, desktop entry, icon, and command paths used by aim.",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			result, err := service.Paths(cmd.Context(), app.PathsRequest{})
@@ -37,6 +37,7 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 					fmt.Fprintf(w, "AppImage dir: %s\n", result.AppImageDir)
 					fmt.Fprintf(w, "Desktop dir:  %s\n", result.DesktopDir)
 					fmt.Fprintf(w, "Icon dir:     %s\n", result.IconDir)
+					fmt.Fprintf(w, "Bin dir:      %s\n", result.BinDir)
 					return nil
 				},
 			)
@@ -34,6 +34,7 @@ func TestCommandCallsServiceAndPrintsTextPaths(t *testing.T) {
 		"AppImage dir: /home/user/Applications",
 		"Desktop dir:  /home/user/.local/share/applications",
 		"Icon dir:     /home/user/.local/share/icons/hicolor/256x256/apps",
+		"Bin dir:      /home/user/.local/bin",
 	} {
 		if !strings.Contains(output, want) {
 			t.Fatalf("stdout = %q, want it to contain %q", output, want)
@@ -93,6 +94,7 @@ func samplePathsResult() app.PathsResult {
 		AppImageDir: "/home/user/Applications",
 		DesktopDir:  "/home/user/.local/share/applications",
 		IconDir:     "/home/user/.local/share/icons/hicolor/256x256/apps",
+		BinDir:      "/home/user/.local/bin",
 	}
 }
 
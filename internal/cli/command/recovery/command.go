@@ -0,0 +1,99 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+type service interface {
+	Recover(ctx context.Context, req app.RecoverRequest) (app.RecoverResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Finish or roll back operations interrupted by a crash",
+		Long:  "Replay the operation journal: interrupted adds and updates that had not replaced anything yet are rolled back, and operations past their point of no return are completed. aim also does this automatically before each command.",
+		Args:  cobra.NoArgs,
+		// Skip the automatic recovery of the root command; this command
+		// reports the result itself.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := service.Recover(cmd.Context(), app.RecoverRequest{})
+			if err != nil {
+				return err
+			}
+			if !rt.Config.JSON {
+				writeRecoverFailures(cmd.ErrOrStderr(), result.Failures)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status    string                   `json:"status"`
+					Action    string                   `json:"action"`
+					Recovered []app.RecoveredOperation `json:"recovered"`
+					Failures  []app.RecoverFailure     `json:"failures"`
+				}{
+					Status:    "ok",
+					Action:    "recover",
+					Recovered: result.Recovered,
+					Failures:  result.Failures,
+				},
+				func(w io.Writer) error {
+					if len(result.Recovered) == 0 && len(result.Failures) == 0 {
+						fmt.Fprintln(w, "Nothing to recover")
+						return nil
+					}
+					writeRecovered(w, result.Recovered)
+					return nil
+				},
+			)
+		},
+	}
+
+	return cmd
+}
+
+// RecoverInterrupted replays the operation journal before a command runs.
+// Problems are reported on stderr in text mode and never stop the command.
+func RecoverInterrupted(cmd *cobra.Command, rt *clienv.Runtime, service service) {
+	result, err := service.Recover(cmd.Context(), app.RecoverRequest{})
+	if rt.Config.JSON {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Recover error: %v\n", err)
+		return
+	}
+	writeRecovered(cmd.ErrOrStderr(), result.Recovered)
+	writeRecoverFailures(cmd.ErrOrStderr(), result.Failures)
+}
+
+func writeRecovered(w io.Writer, recovered []app.RecoveredOperation) {
+	for _, operation := range recovered {
+		switch operation.Action {
+		case app.RecoveryRolledBack:
+			fmt.Fprintf(w, "Rolled back interrupted %s of %s\n", operation.Kind, operation.AppID)
+		default:
+			fmt.Fprintf(w, "Completed interrupted %s of %s\n", operation.Kind, operation.AppID)
+		}
+		if operation.Note != "" {
+			fmt.Fprintf(w, "  %s\n", operation.Note)
+		}
+	}
+}
+
+func writeRecoverFailures(w io.Writer, failures []app.RecoverFailure) {
+	for _, failure := range failures {
+		fmt.Fprintf(w, "Recover error [%s]: %s\n", failure.AppID, failure.Error)
+	}
+}
@@ -0,0 +1,101 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCommandPrintsRecoveredOperations(t *testing.T) {
+	service := &fakeService{result: app.RecoverResult{
+		Recovered: []app.RecoveredOperation{
+			{ID: "op-1", Kind: app.JournalKindAdd, AppID: "example", Action: app.RecoveryRolledBack},
+			{ID: "op-2", Kind: app.JournalKindUpdate, AppID: "other", Action: app.RecoveryRolledForward, Note: "run aim repair other"},
+		},
+		Failures: []app.RecoverFailure{{ID: "op-3", AppID: "broken", Error: "permission denied"}},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want := "Rolled back interrupted add of example\n" +
+		"Completed interrupted update of other\n" +
+		"  run aim repair other\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "Recover error [broken]: permission denied\n"; got != want {
+		t.Fatalf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestCommandWritesJSON(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status    string                   `json:"status"`
+		Action    string                   `json:"action"`
+		Recovered []app.RecoveredOperation `json:"recovered"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "recover" {
+		t.Fatalf("payload = %#v, want recover result", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty in JSON mode", stderr.String())
+	}
+}
+
+func TestRecoverInterruptedReportsWithoutFailing(t *testing.T) {
+	service := &fakeService{err: errors.New("journal unreadable")}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.SetErr(stderr)
+
+	RecoverInterrupted(cmd, clienv.New(stdout, stderr), service)
+
+	if got, want := stderr.String(), "Recover error: journal unreadable\n"; got != want {
+		t.Fatalf("stderr = %q, want %q", got, want)
+	}
+}
+
+type fakeService struct {
+	result app.RecoverResult
+	err    error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Recover(ctx context.Context, req app.RecoverRequest) (app.RecoverResult, error) {
+	return s.result, s.err
+}
@@ -18,17 +18,20 @@ type service interface {
 }
 
 func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var trash bool
+
 	cmd := &cobra.Command{
 		Use:     "remove <appimage>",
 		Aliases: []string{"rm"},
 		Short:   "Remove an AppImage",
-		Long:    "Remove an AppImage.",
+		Long:    "Remove an AppImage. With --trash, or trash_removed_apps = true in config.toml, the AppImage is moved to the trash so it can be restored from a file manager. The removed files are also kept for aim undo for the undo_retention set in config.toml, 7 days by default, so removing frees no disk space until then.",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
 
 			req := app.RemoveRequest{
 				Name:     args[0],
+				Trash:    trash,
 				Activity: reporter,
 			}
 
@@ -58,5 +61,7 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&trash, "trash", false, "move the AppImage to the trash instead of deleting it")
+
 	return cmd
 }
@@ -63,6 +63,24 @@ func TestCommandPassesNameAndPrintsTextSuccess(t *testing.T) {
 	}
 }
 
+func TestCommandPassesTrashFlag(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--trash", "example-app"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !service.removeReq.Trash {
+		t.Fatal("RemoveRequest.Trash = false, want true")
+	}
+}
+
 func TestCommandPrintsJSONAndSuppressesActivityNoise(t *testing.T) {
 	service := &fakeService{}
 	stdout := &bytes.Buffer{}
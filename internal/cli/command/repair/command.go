@@ -0,0 +1,111 @@
+package repair
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Repair(ctx context.Context, req app.RepairRequest) (app.RepairResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "repair [<app-id> | --all]",
+		Short: "Regenerate desktop entries and icons",
+		Long:  "Regenerate the desktop entry and icon of integrated apps from their installed AppImages. The AppImages and stored update sources are left untouched.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return fmt.Errorf("provide either <app-id> or --all, not both")
+			}
+			if !all && len(args) != 1 {
+				return fmt.Errorf("requires exactly one app id unless --all is used")
+			}
+			if len(args) == 1 && strings.TrimSpace(args[0]) == "" {
+				return fmt.Errorf("app id is required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+
+			req := app.RepairRequest{
+				All:      all,
+				Activity: reporter,
+			}
+			if len(args) == 1 {
+				req.ID = args[0]
+			}
+
+			result, err := service.Repair(cmd.Context(), req)
+			if err != nil {
+				reporter.Wait()
+				return err
+			}
+			reporter.Wait()
+			if !rt.Config.JSON {
+				writeRepairFailures(cmd.ErrOrStderr(), result.Failures)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status   string              `json:"status"`
+					Action   string              `json:"action"`
+					Target   string              `json:"target,omitempty"`
+					Repaired []string            `json:"repaired"`
+					Failures []app.RepairFailure `json:"failures"`
+				}{
+					Status:   "ok",
+					Action:   "repair",
+					Target:   req.ID,
+					Repaired: result.Repaired,
+					Failures: result.Failures,
+				},
+				func(w io.Writer) error {
+					if len(result.Repaired) == 0 {
+						fmt.Fprintln(w, "No apps repaired")
+						return nil
+					}
+					if req.ID != "" {
+						fmt.Fprintf(w, "%sSuccessfully repaired %s!%s\n", green, req.ID, reset)
+						return nil
+					}
+					if len(result.Failures) > 0 {
+						fmt.Fprintf(w, "%sRepaired %d apps; %d repair errors.%s\n", green, len(result.Repaired), len(result.Failures), reset)
+						return nil
+					}
+					fmt.Fprintf(w, "%sSuccessfully repaired all apps!%s\n", green, reset)
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "repair all integrated apps")
+
+	return cmd
+}
+
+func writeRepairFailures(w io.Writer, failures []app.RepairFailure) {
+	for _, failure := range failures {
+		fmt.Fprintf(w, "Repair error [%s]: %s\n", failure.AppID, failure.Error)
+	}
+}
@@ -0,0 +1,135 @@
+package repair
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandValidatesArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{name: "none", args: nil},
+		{name: "id and all", args: []string{"example-app", "--all"}},
+		{name: "too many", args: []string{"one", "two"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			service := &fakeService{}
+			cmd := NewCommand(clienv.New(stdout, stderr), service)
+			cmd.SetOut(stdout)
+			cmd.SetErr(stderr)
+			cmd.SetArgs(tc.args)
+
+			if err := cmd.ExecuteContext(context.Background()); err == nil {
+				t.Fatal("ExecuteContext() error = nil, want validation error")
+			}
+			if service.called {
+				t.Fatal("service.Repair called for invalid args")
+			}
+		})
+	}
+}
+
+func TestCommandPassesTargetAndPrintsTextSuccess(t *testing.T) {
+	service := &fakeService{result: app.RepairResult{Repaired: []string{"example-app"}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"example-app"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	if got, want := service.req.ID, "example-app"; got != want {
+		t.Fatalf("RepairRequest.ID = %q, want %q", got, want)
+	}
+	if service.req.All {
+		t.Fatal("RepairRequest.All = true, want false")
+	}
+	if service.req.Activity == nil {
+		t.Fatal("RepairRequest.Activity = nil, want reporter")
+	}
+	if !strings.Contains(stdout.String(), "Successfully repaired example-app!") {
+		t.Fatalf("stdout = %q, want success message", stdout.String())
+	}
+}
+
+func TestCommandPrintsAllJSONWithFailures(t *testing.T) {
+	service := &fakeService{result: app.RepairResult{
+		Repaired: []string{"example-app"},
+		Failures: []app.RepairFailure{{AppID: "broken-app", Error: "boom"}},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--all"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	if !service.req.All {
+		t.Fatal("RepairRequest.All = false, want true")
+	}
+
+	var payload struct {
+		Status   string              `json:"status"`
+		Action   string              `json:"action"`
+		Repaired []string            `json:"repaired"`
+		Failures []app.RepairFailure `json:"failures"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "repair" || len(payload.Repaired) != 1 || len(payload.Failures) != 1 {
+		t.Fatalf("payload = %#v, want repair result", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want no activity output in JSON mode", stderr.String())
+	}
+}
+
+func TestCommandReturnsServiceError(t *testing.T) {
+	wantErr := errors.New("repair failed")
+	service := &fakeService{err: wantErr}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"example-app"})
+
+	if err := cmd.ExecuteContext(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("ExecuteContext() error = %v, want %v", err, wantErr)
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.RepairRequest
+	result app.RepairResult
+	err    error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Repair(ctx context.Context, req app.RepairRequest) (app.RepairResult, error) {
+	s.called = true
+	s.req = req
+	return s.result, s.err
+}
@@ -0,0 +1,34 @@
+// Package run provides the run command, which launches an installed app.
+package run
+
+import (
+	"context"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+
+	"github.com/spf13/cobra"
+)
+
+type service interface {
+	Run(ctx context.Context, req app.RunRequest) error
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <id> [-- args...]",
+		Short: "Launch an app",
+		Long: "Launch an installed app with its configured arguments, environment, and sandbox, followed by args. " +
+			"aim is replaced by the app, so the command works from scripts and terminals regardless of where the AppImage is stored. " +
+			"Put app arguments that start with - after --.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return service.Run(cmd.Context(), app.RunRequest{
+				ID:   args[0],
+				Args: args[1:],
+			})
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPassesArgumentsAfterDash(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"editor", "--", "--new-window", "notes.txt"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want := app.RunRequest{ID: "editor", Args: []string{"--new-window", "notes.txt"}}
+	if !reflect.DeepEqual(service.req, want) {
+		t.Fatalf("RunRequest = %#v, want %#v", service.req, want)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout = %q, want empty", stdout.String())
+	}
+}
+
+func TestCommandRequiresAppID(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("ExecuteContext() error = nil, want missing id error")
+	}
+	if service.called {
+		t.Fatal("service.Run called without an app id")
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.RunRequest
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Run(ctx context.Context, req app.RunRequest) error {
+	s.called = true
+	s.req = req
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package sandbox provides the sandbox command, which confines an app with
+// bubblewrap or firejail.
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+	"github.com/slobbe/appimage-manager/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+// builtInProfiles are passed to the service by name; anything else is the
+// path of a TOML profile.
+var builtInProfiles = []string{"strict", "network-only"}
+
+type service interface {
+	SetSandbox(ctx context.Context, req app.SetSandboxRequest) (app.SetSandboxResult, error)
+}
+
+type sandboxJSON struct {
+	Profile       string   `json:"profile"`
+	Backend       string   `json:"backend"`
+	Network       bool     `json:"network"`
+	Home          string   `json:"home,omitempty"`
+	Binds         []string `json:"binds,omitempty"`
+	ReadOnlyBinds []string `json:"read_only_binds,omitempty"`
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var profile string
+	var backend string
+	var off bool
+
+	cmd := &cobra.Command{
+		Use:   "sandbox <id> (--profile <profile> | --off)",
+		Short: "Launch an app inside a sandbox",
+		Long: "Rewrite the Exec lines of an app's desktop entry to launch it with bubblewrap or firejail. " +
+			"The strict profile denies network access and gives the app a private home directory; network-only allows the network but keeps the private home. " +
+			"A custom TOML profile sets backend, network, private_home, binds, and read_only_binds. " +
+			"The sandbox is kept across updates and ID changes until --off restores the plain Exec lines.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("requires exactly one app id")
+			}
+			if off && (strings.TrimSpace(profile) != "" || strings.TrimSpace(backend) != "") {
+				return fmt.Errorf("provide either --profile or --off, not both")
+			}
+			if !off && strings.TrimSpace(profile) == "" {
+				return fmt.Errorf("requires --profile or --off")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, err := resolveProfile(profile, off)
+			if err != nil {
+				return err
+			}
+
+			result, err := service.SetSandbox(cmd.Context(), app.SetSandboxRequest{
+				ID:      args[0],
+				Profile: profile,
+				Backend: domain.SandboxBackend(strings.TrimSpace(backend)),
+				Off:     off,
+			})
+			if err != nil {
+				return err
+			}
+
+			var payload *sandboxJSON
+			if result.Sandbox.Enabled() {
+				payload = &sandboxJSON{
+					Profile:       result.Sandbox.Profile,
+					Backend:       string(result.Sandbox.Backend),
+					Network:       result.Sandbox.Network,
+					Home:          result.Sandbox.Home,
+					Binds:         result.Sandbox.Binds,
+					ReadOnlyBinds: result.Sandbox.ReadOnlyBinds,
+				}
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status  string       `json:"status"`
+					Action  string       `json:"action"`
+					ID      string       `json:"id"`
+					Sandbox *sandboxJSON `json:"sandbox"`
+				}{
+					Status:  "ok",
+					Action:  "set_sandbox",
+					ID:      result.ID,
+					Sandbox: payload,
+				},
+				func(w io.Writer) error {
+					if !result.Sandbox.Enabled() {
+						_, err := fmt.Fprintf(w, "%s%s now runs without a sandbox%s\n", green, result.ID, reset)
+						return err
+					}
+					_, err := fmt.Fprintf(w, "%s%s now runs in the %s sandbox with %s%s\n", green, result.ID, result.Sandbox.Profile, result.Sandbox.Backend, reset)
+					return err
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "sandbox profile: strict, network-only, or the path of a TOML profile")
+	cmd.Flags().StringVar(&backend, "backend", "", "sandbox backend: bwrap or firejail (default: the profile's, or whichever is installed)")
+	cmd.Flags().BoolVar(&off, "off", false, "launch the app without a sandbox again")
+
+	return cmd
+}
+
+func resolveProfile(profile string, off bool) (string, error) {
+	profile = strings.TrimSpace(profile)
+	if off {
+		return "", nil
+	}
+	for _, name := range builtInProfiles {
+		if profile == name {
+			return profile, nil
+		}
+	}
+	return userpath.Abs(profile)
+}
@@ -0,0 +1,110 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestCommandValidatesFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"chat"},
+		{"chat", "--profile", "strict", "--off"},
+		{"--profile", "strict"},
+	} {
+		service := &fakeService{}
+		stdout := &bytes.Buffer{}
+		cmd := NewCommand(clienv.New(stdout, stdout), service)
+		cmd.SetOut(stdout)
+		cmd.SetErr(stdout)
+		cmd.SetArgs(args)
+
+		if err := cmd.ExecuteContext(context.Background()); err == nil {
+			t.Fatalf("ExecuteContext(%q) error = nil, want validation error", args)
+		}
+		if service.called {
+			t.Fatalf("service.SetSandbox called for %q", args)
+		}
+	}
+}
+
+func TestCommandPassesBuiltInProfileAndPrintsResult(t *testing.T) {
+	service := &fakeService{result: app.SetSandboxResult{ID: "chat", Sandbox: domain.Sandbox{Profile: "network-only", Backend: domain.SandboxBackendBubblewrap, Network: true}}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"chat", "--profile", "network-only", "--backend", "bwrap"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want := app.SetSandboxRequest{ID: "chat", Profile: "network-only", Backend: domain.SandboxBackendBubblewrap}
+	if service.req != want {
+		t.Fatalf("SetSandboxRequest = %#v, want %#v", service.req, want)
+	}
+	if !strings.Contains(stdout.String(), "chat now runs in the network-only sandbox with bwrap") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestCommandPassesAbsoluteCustomProfileAndOffJSON(t *testing.T) {
+	service := &fakeService{result: app.SetSandboxResult{ID: "tool"}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stdout), service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"tool", "--profile", "profiles/tool.toml"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	want, err := filepath.Abs("profiles/tool.toml")
+	if err != nil {
+		t.Fatalf("filepath.Abs() error = %v", err)
+	}
+	if service.req.Profile != want {
+		t.Fatalf("Profile = %q, want %q", service.req.Profile, want)
+	}
+
+	stdout.Reset()
+	rt := clienv.New(stdout, stdout)
+	rt.Config.JSON = true
+	cmd = NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"tool", "--off"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext(--off) error = %v", err)
+	}
+	if !service.req.Off || service.req.Profile != "" {
+		t.Fatalf("SetSandboxRequest = %#v, want off", service.req)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload["action"] != "set_sandbox" || payload["sandbox"] != nil {
+		t.Fatalf("payload = %#v, want set_sandbox without sandbox", payload)
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.SetSandboxRequest
+	result app.SetSandboxResult
+	err    error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) SetSandbox(ctx context.Context, req app.SetSandboxRequest) (app.SetSandboxResult, error) {
+	s.called = true
+	s.req = req
+	return s.result, s.err
+}
@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	bold  = "\033[1m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Scan(ctx context.Context, req app.ScanRequest) (app.ScanResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var unmanagedOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "scan <dir>...",
+		Short: "Find AppImages on disk",
+		Long:  "Recursively search directories for AppImage files by their magic bytes and show which of them are not managed by aim. Use aim adopt to integrate unmanaged AppImages.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dirs := make([]string, 0, len(args))
+			for _, arg := range args {
+				dir, err := userpath.Abs(arg)
+				if err != nil {
+					return err
+				}
+				dirs = append(dirs, dir)
+			}
+
+			result, err := service.Scan(cmd.Context(), app.ScanRequest{Dirs: dirs})
+			if err != nil {
+				return err
+			}
+			if unmanagedOnly {
+				result.Items = unmanaged(result.Items)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				result,
+				func(w io.Writer) error {
+					if len(result.Items) == 0 {
+						_, err := fmt.Fprintln(w, "No AppImages found")
+						return err
+					}
+					return writeTable(w, result.Items)
+				},
+			)
+		},
+	}
+
+	cmd.Flags().BoolVar(&unmanagedOnly, "unmanaged", false, "only show AppImages not managed by aim")
+
+	return cmd
+}
+
+func unmanaged(items []app.ScanItem) []app.ScanItem {
+	result := make([]app.ScanItem, 0, len(items))
+	for _, item := range items {
+		if !item.Managed {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func writeTable(w io.Writer, items []app.ScanItem) error {
+	statusWidth := len("Status")
+	for _, item := range items {
+		statusWidth = max(statusWidth, len(status(item)))
+	}
+
+	const gap = 2
+	format := fmt.Sprintf("%%-%ds%%s\n", statusWidth+gap)
+
+	fmt.Fprintf(w, bold+format+reset, "Status", "Path")
+	for _, item := range items {
+		fmt.Fprintf(w, format, status(item), item.Path)
+	}
+
+	return nil
+}
+
+func status(item app.ScanItem) string {
+	if !item.Managed {
+		return "unmanaged"
+	}
+	return "managed [" + item.AppID + "]"
+}
@@ -0,0 +1,102 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandRequiresDirectory(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	service := &fakeService{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err == nil {
+		t.Fatal("ExecuteContext() error = nil, want arg validation error")
+	}
+	if service.called {
+		t.Fatal("service.Scan called for invalid args")
+	}
+}
+
+func TestCommandPassesAbsoluteDirsAndPrintsTable(t *testing.T) {
+	service := &fakeService{result: app.ScanResult{Items: []app.ScanItem{
+		{Path: "/opt/Managed.AppImage", Managed: true, AppID: "managed-app"},
+		{Path: "/opt/Other.AppImage"},
+	}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"downloads", "/opt"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	wantFirst, err := filepath.Abs("downloads")
+	if err != nil {
+		t.Fatalf("filepath.Abs() error = %v", err)
+	}
+	if len(service.req.Dirs) != 2 || service.req.Dirs[0] != wantFirst || service.req.Dirs[1] != "/opt" {
+		t.Fatalf("ScanRequest.Dirs = %q, want [%q /opt]", service.req.Dirs, wantFirst)
+	}
+	for _, want := range []string{"managed [managed-app]", "/opt/Managed.AppImage", "unmanaged", "/opt/Other.AppImage"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestCommandFiltersUnmanagedJSON(t *testing.T) {
+	service := &fakeService{result: app.ScanResult{Items: []app.ScanItem{
+		{Path: "/opt/Managed.AppImage", Managed: true, AppID: "managed-app"},
+		{Path: "/opt/Other.AppImage"},
+	}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/opt", "--unmanaged"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload app.ScanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if len(payload.Items) != 1 || payload.Items[0].Path != "/opt/Other.AppImage" {
+		t.Fatalf("payload = %#v, want only unmanaged item", payload)
+	}
+}
+
+type fakeService struct {
+	called bool
+	req    app.ScanRequest
+	result app.ScanResult
+	err    error
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Scan(ctx context.Context, req app.ScanRequest) (app.ScanResult, error) {
+	s.called = true
+	s.req = req
+	return s.result, s.err
+}
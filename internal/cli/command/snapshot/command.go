@@ -0,0 +1,134 @@
+// Package snapshot provides the export and import commands, which write the
+// installed app set to a file and reinstall it elsewhere.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type exportService interface {
+	Export(ctx context.Context, req app.ExportRequest) (app.ExportResult, error)
+}
+
+type importService interface {
+	Import(ctx context.Context, req app.ImportRequest) (app.ImportResult, error)
+}
+
+func NewExportCommand(rt *clienv.Runtime, service exportService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <file>",
+		Short: "Write the installed apps and their sources to a file",
+		Long:  "Write every installed app with the release, asset, and update source it was installed from to a JSON file that aim import can reinstall on another machine.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := userpath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := service.Export(cmd.Context(), app.ExportRequest{Path: path})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status string   `json:"status"`
+					Action string   `json:"action"`
+					Path   string   `json:"path"`
+					Apps   []string `json:"apps"`
+				}{
+					Status: "ok",
+					Action: "export",
+					Path:   result.Path,
+					Apps:   result.Apps,
+				},
+				func(w io.Writer) error {
+					_, err := fmt.Fprintf(w, "%sExported %d apps to %s%s\n", green, len(result.Apps), result.Path, reset)
+					return err
+				},
+			)
+		},
+	}
+}
+
+func NewImportCommand(rt *clienv.Runtime, service importService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Install the apps listed in an export file",
+		Long:  "Install the apps from a file written by aim export. GitHub apps are installed from the exact release and asset that was exported, not the latest release. Apps that are already installed or were integrated from a local file are skipped.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := userpath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+			result, err := service.Import(cmd.Context(), app.ImportRequest{Path: path, Activity: reporter})
+			if err != nil {
+				reporter.Wait()
+				return err
+			}
+			reporter.Wait()
+			if !rt.Config.JSON {
+				writeImportFailures(cmd.ErrOrStderr(), result.Failures)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status   string              `json:"status"`
+					Action   string              `json:"action"`
+					Path     string              `json:"path"`
+					Imported []app.ImportedApp   `json:"imported"`
+					Skipped  []app.ImportSkip    `json:"skipped"`
+					Failures []app.ImportFailure `json:"failures"`
+				}{
+					Status:   "ok",
+					Action:   "import",
+					Path:     path,
+					Imported: result.Imported,
+					Skipped:  result.Skipped,
+					Failures: result.Failures,
+				},
+				func(w io.Writer) error {
+					for _, imported := range result.Imported {
+						fmt.Fprintf(w, "%sImported %s (v%s)%s\n", green, imported.ID, imported.Version, reset)
+					}
+					for _, skipped := range result.Skipped {
+						fmt.Fprintf(w, "Skipped %s: %s\n", skipped.AppID, skipped.Reason)
+					}
+					if len(result.Failures) > 0 {
+						fmt.Fprintf(w, "Finished importing; %d import errors.\n", len(result.Failures))
+					}
+					return nil
+				},
+			)
+		},
+	}
+}
+
+func writeImportFailures(w io.Writer, failures []app.ImportFailure) {
+	for _, failure := range failures {
+		fmt.Fprintf(w, "Import error [%s]: %s\n", failure.AppID, failure.Error)
+	}
+}
@@ -0,0 +1,117 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestExportCommandResolvesPath(t *testing.T) {
+	service := &fakeService{exported: []string{"example", "other"}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewExportCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !filepath.IsAbs(service.exportReq.Path) || filepath.Base(service.exportReq.Path) != "apps.json" {
+		t.Fatalf("ExportRequest.Path = %q, want absolute apps.json", service.exportReq.Path)
+	}
+	if got, want := stdout.String(), green+"Exported 2 apps to "+service.exportReq.Path+reset+"\n"; got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestImportCommandPrintsResults(t *testing.T) {
+	service := &fakeService{importResult: app.ImportResult{
+		Imported: []app.ImportedApp{{ID: "example", Version: "1.0.0"}},
+		Skipped:  []app.ImportSkip{{AppID: "local", Reason: "integrated from a local file"}},
+		Failures: []app.ImportFailure{{AppID: "broken", Error: "release not found"}},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewImportCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/tmp/apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got, want := service.importReq.Path, "/tmp/apps.json"; got != want {
+		t.Fatalf("ImportRequest.Path = %q, want %q", got, want)
+	}
+	wantOut := green + "Imported example (v1.0.0)" + reset + "\n" +
+		"Skipped local: integrated from a local file\n" +
+		"Finished importing; 1 import errors.\n"
+	if got := stdout.String(); got != wantOut {
+		t.Fatalf("stdout = %q, want %q", got, wantOut)
+	}
+	if got, want := stderr.String(), "Import error [broken]: release not found\n"; got != want {
+		t.Fatalf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestImportCommandWritesJSON(t *testing.T) {
+	service := &fakeService{importResult: app.ImportResult{Imported: []app.ImportedApp{{ID: "example", Version: "1.0.0"}}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewImportCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"/tmp/apps.json"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status   string            `json:"status"`
+		Action   string            `json:"action"`
+		Imported []app.ImportedApp `json:"imported"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "import" || len(payload.Imported) != 1 {
+		t.Fatalf("payload = %#v, want one imported app", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty in JSON mode", stderr.String())
+	}
+}
+
+type fakeService struct {
+	exportReq    app.ExportRequest
+	exported     []string
+	importReq    app.ImportRequest
+	importResult app.ImportResult
+}
+
+var (
+	_ exportService = (*fakeService)(nil)
+	_ importService = (*fakeService)(nil)
+)
+
+func (s *fakeService) Export(ctx context.Context, req app.ExportRequest) (app.ExportResult, error) {
+	s.exportReq = req
+	return app.ExportResult{Path: req.Path, Apps: s.exported}, nil
+}
+
+func (s *fakeService) Import(ctx context.Context, req app.ImportRequest) (app.ImportResult, error) {
+	s.importReq = req
+	return s.importResult, nil
+}
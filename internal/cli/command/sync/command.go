@@ -0,0 +1,177 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/activity"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/prompt"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Sync(ctx context.Context, req app.SyncRequest) (app.SyncResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var manifest string
+	var prune bool
+	var dryRun bool
+	var locked bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Install and adjust apps to match a manifest",
+		Long:  "Read a TOML manifest, install missing apps, adjust update sources and pinned versions of installed apps, and optionally remove apps the manifest does not list. With --locked, the exact artifacts recorded in aim.lock next to the manifest are installed and downloads whose SHA-256 differs are rejected. Without --manifest, the manifest.toml next to config.toml is used.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location, err := manifestLocation(manifest)
+			if err != nil {
+				return err
+			}
+
+			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
+			result, err := service.Sync(cmd.Context(), app.SyncRequest{
+				Manifest: location,
+				Prune:    prune,
+				DryRun:   dryRun,
+				Locked:   locked,
+				Activity: reporter,
+				Confirmation: syncPrompter{
+					in:          cmd.InOrStdin(),
+					out:         cmd.OutOrStdout(),
+					autoConfirm: yes || rt.Config.JSON,
+				},
+			})
+			if err != nil {
+				reporter.Wait()
+				return err
+			}
+			reporter.Wait()
+			if !rt.Config.JSON {
+				writeSyncFailures(cmd.ErrOrStderr(), result.Failures)
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status   string            `json:"status"`
+					Action   string            `json:"action"`
+					Manifest string            `json:"manifest"`
+					DryRun   bool              `json:"dry_run"`
+					Applied  bool              `json:"applied"`
+					Actions  []app.SyncAction  `json:"actions"`
+					Failures []app.SyncFailure `json:"failures"`
+				}{
+					Status:   "ok",
+					Action:   "sync",
+					Manifest: result.Manifest,
+					DryRun:   dryRun,
+					Applied:  result.Applied,
+					Actions:  result.Actions,
+					Failures: result.Failures,
+				},
+				func(w io.Writer) error {
+					if len(result.Actions) == 0 {
+						fmt.Fprintln(w, "All apps match the manifest")
+						return nil
+					}
+					if dryRun {
+						fmt.Fprintln(w, "Planned changes:")
+						writeSyncActions(w, result.Actions)
+						return nil
+					}
+					if !result.Applied {
+						fmt.Fprintln(w, "Sync canceled")
+						return nil
+					}
+					if len(result.Failures) > 0 {
+						fmt.Fprintf(w, "Finished syncing; %d sync errors.\n", len(result.Failures))
+						return nil
+					}
+					fmt.Fprintf(w, "%sSuccessfully synced %d apps!%s\n", green, len(result.Actions), reset)
+					return nil
+				},
+			)
+		},
+	}
+
+	cmd.Flags().StringVar(&manifest, "manifest", "", "manifest file path or http(s) URL")
+	cmd.Flags().BoolVar(&prune, "prune", false, "remove apps that are not in the manifest")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without changing anything")
+	cmd.Flags().BoolVar(&locked, "locked", false, "install the artifacts pinned in aim.lock")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "apply the plan without asking for confirmation")
+
+	return cmd
+}
+
+func manifestLocation(manifest string) (string, error) {
+	manifest = strings.TrimSpace(manifest)
+	if manifest == "" || strings.HasPrefix(manifest, "https://") || strings.HasPrefix(manifest, "http://") {
+		return manifest, nil
+	}
+	return userpath.Abs(manifest)
+}
+
+type syncPrompter struct {
+	in          io.Reader
+	out         io.Writer
+	autoConfirm bool
+}
+
+func (p syncPrompter) ConfirmSync(ctx context.Context, actions []app.SyncAction) (bool, error) {
+	if !p.autoConfirm {
+		writeSyncActions(p.out, actions)
+		fmt.Fprintln(p.out)
+	}
+	return prompt.ConfirmYesNo(ctx, p.in, p.out, "Apply these changes? (y/n) ", p.autoConfirm)
+}
+
+func writeSyncActions(w io.Writer, actions []app.SyncAction) {
+	for _, action := range actions {
+		fmt.Fprintf(w, "  %s\n", describeSyncAction(action))
+	}
+}
+
+func describeSyncAction(action app.SyncAction) string {
+	source := action.GitHubRepo
+	if source == "" {
+		source = action.URL
+	}
+	if action.Version != "" {
+		source += " " + action.Version
+	}
+
+	switch action.Kind {
+	case app.SyncActionInstall:
+		return fmt.Sprintf("install %s from %s", action.AppID, source)
+	case app.SyncActionInstallVersion:
+		return fmt.Sprintf("install %s from %s", action.AppID, source)
+	case app.SyncActionSetUpdateSource:
+		return fmt.Sprintf("set update source of %s to %s", action.AppID, source)
+	case app.SyncActionRemove:
+		return fmt.Sprintf("remove %s", action.AppID)
+	default:
+		return fmt.Sprintf("%s %s", action.Kind, action.AppID)
+	}
+}
+
+func writeSyncFailures(w io.Writer, failures []app.SyncFailure) {
+	for _, failure := range failures {
+		fmt.Fprintf(w, "Sync error [%s]: %s\n", failure.AppID, failure.Error)
+	}
+}
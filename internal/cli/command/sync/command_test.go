@@ -0,0 +1,171 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandDryRunPrintsPlan(t *testing.T) {
+	service := &fakeService{actions: []app.SyncAction{
+		{Kind: app.SyncActionInstall, AppID: "obsidian", GitHubRepo: "obsidianmd/obsidian-releases", Version: "v1.6.7"},
+		{Kind: app.SyncActionSetUpdateSource, AppID: "example", GitHubRepo: "owner/example"},
+		{Kind: app.SyncActionRemove, AppID: "extra"},
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--manifest", "team.toml", "--prune", "--dry-run"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !filepath.IsAbs(service.req.Manifest) || filepath.Base(service.req.Manifest) != "team.toml" {
+		t.Fatalf("SyncRequest.Manifest = %q, want absolute team.toml", service.req.Manifest)
+	}
+	if !service.req.Prune || !service.req.DryRun || service.req.Locked {
+		t.Fatalf("SyncRequest = %#v, want unlocked prune dry run", service.req)
+	}
+	want := "Planned changes:\n" +
+		"  install obsidian from obsidianmd/obsidian-releases v1.6.7\n" +
+		"  set update source of example to owner/example\n" +
+		"  remove extra\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestCommandKeepsManifestURL(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--manifest", "https://example.test/manifest.toml"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got, want := service.req.Manifest, "https://example.test/manifest.toml"; got != want {
+		t.Fatalf("SyncRequest.Manifest = %q, want %q", got, want)
+	}
+	if got, want := stdout.String(), "All apps match the manifest\n"; got != want {
+		t.Fatalf("stdout = %q, want %q", got, want)
+	}
+}
+
+func TestCommandPassesLocked(t *testing.T) {
+	service := &fakeService{}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"--locked"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !service.req.Locked {
+		t.Fatalf("SyncRequest = %#v, want locked", service.req)
+	}
+}
+
+func TestCommandPromptsAndCancels(t *testing.T) {
+	service := &fakeService{actions: []app.SyncAction{{Kind: app.SyncActionRemove, AppID: "extra"}}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"--prune"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if service.req.Manifest != "" {
+		t.Fatalf("SyncRequest.Manifest = %q, want default", service.req.Manifest)
+	}
+	for _, want := range []string{"  remove extra\n", "Apply these changes? (y/n)", "Sync canceled\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout = %q, want it to contain %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestCommandWritesJSON(t *testing.T) {
+	service := &fakeService{
+		actions:  []app.SyncAction{{Kind: app.SyncActionInstall, AppID: "example", URL: "https://example.test/Example.AppImage"}},
+		failures: []app.SyncFailure{{AppID: "example", Error: "download failed"}},
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var payload struct {
+		Status   string            `json:"status"`
+		Action   string            `json:"action"`
+		Applied  bool              `json:"applied"`
+		Actions  []app.SyncAction  `json:"actions"`
+		Failures []app.SyncFailure `json:"failures"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Status != "ok" || payload.Action != "sync" || !payload.Applied || len(payload.Actions) != 1 || len(payload.Failures) != 1 {
+		t.Fatalf("payload = %#v, want applied sync with one failure", payload)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty in JSON mode", stderr.String())
+	}
+}
+
+type fakeService struct {
+	req      app.SyncRequest
+	actions  []app.SyncAction
+	failures []app.SyncFailure
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Sync(ctx context.Context, req app.SyncRequest) (app.SyncResult, error) {
+	s.req = req
+	result := app.SyncResult{Manifest: req.Manifest, Actions: s.actions}
+	if req.DryRun || len(s.actions) == 0 {
+		result.Applied = !req.DryRun
+		return result, nil
+	}
+	confirmed, err := req.Confirmation.ConfirmSync(ctx, s.actions)
+	if err != nil {
+		return app.SyncResult{}, err
+	}
+	if !confirmed {
+		return result, nil
+	}
+	result.Applied = true
+	result.Failures = s.failures
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package undo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/prompt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Undo(ctx context.Context, req app.UndoRequest) (app.UndoResult, error)
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the most recent add, update, removal, or ID change",
+		Long:  "Revert the most recent add, update, removal, or ID change after confirmation. Removed apps get their files and record back, ID changes are renamed back, and updates go back to the previous AppImage and desktop entry. Replaced files are kept for undo_retention (7 days by default); running undo again reverts the operation before.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := service.Undo(cmd.Context(), app.UndoRequest{
+				Confirmation: undoPrompter{
+					in:          cmd.InOrStdin(),
+					out:         cmd.OutOrStdout(),
+					autoConfirm: yes || rt.Config.JSON,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			return output.Write(
+				cmd.OutOrStdout(),
+				rt.Config.JSON,
+				struct {
+					Status  string              `json:"status"`
+					Action  string              `json:"action"`
+					Applied bool                `json:"applied"`
+					Undone  app.UndoneOperation `json:"undone"`
+				}{
+					Status:  "ok",
+					Action:  "undo",
+					Applied: result.Applied,
+					Undone:  result.Operation,
+				},
+				func(w io.Writer) error {
+					if !result.Applied {
+						fmt.Fprintln(w, "Undo canceled")
+						return nil
+					}
+					_, err := fmt.Fprintf(w, "%sUndid the %s%s\n", green, describe(result.Operation), reset)
+					return err
+				},
+			)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "undo without asking for confirmation")
+
+	return cmd
+}
+
+type undoPrompter struct {
+	in          io.Reader
+	out         io.Writer
+	autoConfirm bool
+}
+
+func (p undoPrompter) ConfirmUndo(ctx context.Context, operation app.UndoneOperation) (bool, error) {
+	if !p.autoConfirm {
+		fmt.Fprintf(p.out, "Last operation: the %s at %s\n\n", describe(operation), operation.At.Local().Format("2006-01-02 15:04"))
+	}
+	return prompt.ConfirmYesNo(ctx, p.in, p.out, "Undo it? (y/n) ", p.autoConfirm)
+}
+
+func describe(operation app.UndoneOperation) string {
+	switch operation.Operation {
+	case app.HistoryOperationAdd:
+		return fmt.Sprintf("add of %s %s", operation.AppID, operation.Version)
+	case app.HistoryOperationRemove:
+		return fmt.Sprintf("removal of %s %s", operation.RestoredID, operation.RestoredVersion)
+	case app.HistoryOperationUpdate:
+		return fmt.Sprintf("update of %s from %s to %s", operation.AppID, operation.RestoredVersion, operation.Version)
+	case app.HistoryOperationSetID:
+		return fmt.Sprintf("ID change from %s to %s", operation.RestoredID, operation.AppID)
+	default:
+		return fmt.Sprintf("%s of %s", operation.Operation, operation.AppID)
+	}
+}
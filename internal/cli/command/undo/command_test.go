@@ -0,0 +1,114 @@
+package undo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+)
+
+func TestCommandPromptsAndPrintsUndoneOperation(t *testing.T) {
+	service := &fakeService{operation: app.UndoneOperation{
+		Operation:       app.HistoryOperationUpdate,
+		At:              time.Date(2026, 5, 1, 10, 0, 0, 0, time.Local),
+		AppID:           "example",
+		Version:         "2.0.0",
+		RestoredID:      "example",
+		RestoredVersion: "1.0.0",
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader("y\n"))
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Last operation: the update of example from 1.0.0 to 2.0.0 at 2026-05-01 10:00",
+		"Undo it? (y/n) ",
+		"Undid the update of example from 1.0.0 to 2.0.0",
+	} {
+		if got := stdout.String(); !strings.Contains(got, want) {
+			t.Fatalf("stdout = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCommandPrintsCanceled(t *testing.T) {
+	service := &fakeService{operation: app.UndoneOperation{Operation: app.HistoryOperationRemove, RestoredID: "example", RestoredVersion: "1.0.0"}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if got := stdout.String(); !strings.Contains(got, "the removal of example 1.0.0") || !strings.Contains(got, "Undo canceled") {
+		t.Fatalf("stdout = %q, want removal prompt and cancellation", got)
+	}
+}
+
+func TestCommandWritesJSONWithoutPrompt(t *testing.T) {
+	service := &fakeService{operation: app.UndoneOperation{Operation: app.HistoryOperationSetID, AppID: "renamed", RestoredID: "example"}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	var got struct {
+		Status  string `json:"status"`
+		Action  string `json:"action"`
+		Applied bool   `json:"applied"`
+		Undone  struct {
+			Operation  string `json:"operation"`
+			AppID      string `json:"app_id"`
+			RestoredID string `json:"restored_id"`
+		} `json:"undone"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if got.Status != "ok" || got.Action != "undo" || !got.Applied || got.Undone.Operation != "set_id" || got.Undone.RestoredID != "example" {
+		t.Fatalf("JSON = %#v, want applied undo of the ID change", got)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty", stderr.String())
+	}
+}
+
+type fakeService struct {
+	operation app.UndoneOperation
+}
+
+var _ service = (*fakeService)(nil)
+
+func (f *fakeService) Undo(ctx context.Context, req app.UndoRequest) (app.UndoResult, error) {
+	confirmed, err := req.Confirmation.ConfirmUndo(ctx, f.operation)
+	if err != nil {
+		return app.UndoResult{}, err
+	}
+	return app.UndoResult{Applied: confirmed, Operation: f.operation}, nil
+}
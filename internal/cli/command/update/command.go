@@ -33,6 +33,8 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 	var embedded bool
 	var prerelease bool
 	var checkOnly bool
+	var notify bool
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:     "update [appimage]",
@@ -41,6 +43,9 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 		Long:    "Check integrated AppImages for updates and optionally update them.",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if notify && (setID != "" || unsetID != "" || githubRepo != "" || assetPattern != "" || embedded || prerelease) {
+				return fmt.Errorf("--notify cannot be combined with update source flags")
+			}
 			if checkOnly && (setID != "" || unsetID != "" || githubRepo != "" || assetPattern != "" || embedded || prerelease) {
 				return fmt.Errorf("--check cannot be combined with update source flags")
 			}
@@ -58,12 +63,13 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 			reporter := activity.NewReporter(cmd.ErrOrStderr(), !rt.Config.JSON)
 
 			req := app.UpdateRequest{
-				CheckOnly: checkOnly,
+				CheckOnly: checkOnly || (notify && !yes),
+				Notify:    notify,
 				Activity:  reporter,
 				Confirmation: updatePrompter{
 					in:          cmd.InOrStdin(),
 					out:         cmd.OutOrStdout(),
-					autoConfirm: rt.Config.JSON,
+					autoConfirm: yes || rt.Config.JSON,
 				},
 			}
 			if len(args) > 0 {
@@ -138,6 +144,8 @@ func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
 	cmd.Flags().BoolVar(&embedded, "embedded", false, "set update source from embedded AppImage update information")
 	cmd.Flags().BoolVar(&prerelease, "prerelease", false, "include prereleases for GitHub update source")
 	cmd.Flags().BoolVar(&checkOnly, "check", false, "check for updates without applying them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "apply updates without asking for confirmation")
+	cmd.Flags().BoolVar(&notify, "notify", false, "report updates with a desktop notification; only checks unless --yes is given")
 
 	return cmd
 }
@@ -163,6 +163,66 @@ func TestCommandJSONCheckOnlyIncludesUpdatesWithoutPrompting(t *testing.T) {
 	}
 }
 
+func TestCommandNotifyChecksWithoutPrompting(t *testing.T) {
+	candidate := app.UpdateCandidate{ID: "example-app", CurrentVersion: "1.2.3", NewVersion: "2.0.0"}
+	service := &fakeService{updateCandidates: []app.UpdateCandidate{candidate}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SetArgs([]string{"--notify"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !service.checkOnly || !service.notify {
+		t.Fatalf("UpdateRequest CheckOnly = %v, Notify = %v, want both true", service.checkOnly, service.notify)
+	}
+	if service.confirmationCalled {
+		t.Fatal("service called update confirmation for notify check")
+	}
+	if !strings.Contains(stdout.String(), "Updates available:\n") {
+		t.Fatalf("stdout = %q, want available updates", stdout.String())
+	}
+}
+
+func TestCommandNotifyWithYesAppliesUpdates(t *testing.T) {
+	service := &fakeService{updateResult: app.UpdateResult{Applied: true}}
+	stdout := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, &bytes.Buffer{}), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SetArgs([]string{"--yes", "--notify"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if service.checkOnly || !service.notify {
+		t.Fatalf("UpdateRequest CheckOnly = %v, Notify = %v, want false and true", service.checkOnly, service.notify)
+	}
+}
+
+func TestCommandNotifyRejectsUpdateSourceFlags(t *testing.T) {
+	service := &fakeService{}
+	cmd := NewCommand(clienv.New(&bytes.Buffer{}, &bytes.Buffer{}), service)
+	cmd.SetArgs([]string{"--notify", "--set", "example-app", "--github", "owner/repo"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "--notify cannot be combined") {
+		t.Fatalf("ExecuteContext() error = %v, want --notify conflict", err)
+	}
+	if service.setReq.ID != "" {
+		t.Fatalf("SetUpdateSource request = %#v, want none", service.setReq)
+	}
+}
+
 func TestCommandJSONIncludesTarget(t *testing.T) {
 	service := &fakeService{updateResult: app.UpdateResult{Applied: true}}
 	stdout := &bytes.Buffer{}
@@ -390,6 +450,32 @@ func TestCommandJSONIncludesBulkUpdateFailures(t *testing.T) {
 	}
 }
 
+func TestCommandYesAppliesUpdatesWithoutPrompting(t *testing.T) {
+	candidate := app.UpdateCandidate{ID: "example-app", CurrentVersion: "1.2.3", NewVersion: "2.0.0"}
+	service := &fakeService{updateCandidates: []app.UpdateCandidate{candidate}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SetArgs([]string{"--yes"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if !service.confirmed {
+		t.Fatal("confirmation = false, want true with --yes")
+	}
+	if strings.Contains(stdout.String(), "(y/n)") {
+		t.Fatalf("stdout = %q, want no prompt", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "Successfully updated all apps!") {
+		t.Fatalf("stdout = %q, want success message", stdout.String())
+	}
+}
+
 func TestCommandJSONAutoConfirmsUpdates(t *testing.T) {
 	candidate := app.UpdateCandidate{ID: "example-app", CurrentVersion: "1.2.3", NewVersion: "2.0.0"}
 	service := &fakeService{updateCandidates: []app.UpdateCandidate{candidate}}
@@ -441,6 +527,7 @@ type fakeService struct {
 	updateErr          error
 	target             string
 	checkOnly          bool
+	notify             bool
 	setReq             app.SetUpdateSourceRequest
 	unsetReq           app.UnsetUpdateSourceRequest
 	confirmationCalled bool
@@ -469,6 +556,7 @@ func (s *fakeService) UnsetUpdateSource(ctx context.Context, req app.UnsetUpdate
 func (s *fakeService) Update(ctx context.Context, req app.UpdateRequest) (app.UpdateResult, error) {
 	s.target = req.Target
 	s.checkOnly = req.CheckOnly
+	s.notify = req.Notify
 	if s.updateErr != nil {
 		return app.UpdateResult{}, s.updateErr
 	}
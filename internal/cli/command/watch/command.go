@@ -0,0 +1,110 @@
+// Package watch provides the watch command, which integrates AppImages as
+// they are downloaded.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/cli/output"
+	"github.com/slobbe/appimage-manager/internal/cli/userpath"
+	"github.com/slobbe/appimage-manager/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	green = "\033[32m"
+	red   = "\033[31m"
+	reset = "\033[0m"
+)
+
+type service interface {
+	Watch(ctx context.Context, req app.WatchRequest) error
+}
+
+func NewCommand(rt *clienv.Runtime, service service) *cobra.Command {
+	var autoAdd bool
+
+	cmd := &cobra.Command{
+		Use:   "watch [dir]...",
+		Short: "Integrate AppImages as they are downloaded",
+		Long:  "Watch directories, by default download_dir from the config file or the XDG download directory, for new AppImages. Once a new file is fully written and has the AppImage magic bytes, a desktop notification offers to integrate it, or with --auto it is integrated right away. Runs until interrupted, so it can run as a systemd user service.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dirs := make([]string, 0, len(args))
+			for _, arg := range args {
+				dir, err := userpath.Abs(arg)
+				if err != nil {
+					return err
+				}
+				dirs = append(dirs, dir)
+			}
+
+			return service.Watch(cmd.Context(), app.WatchRequest{
+				Dirs:    dirs,
+				AutoAdd: autoAdd,
+				Events:  &reporter{w: cmd.OutOrStdout(), json: rt.Config.JSON},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&autoAdd, "auto", false, "integrate new AppImages without asking")
+
+	return cmd
+}
+
+// event is the JSON form of what reporter prints, one object per event.
+type event struct {
+	Event string   `json:"event"`
+	Dirs  []string `json:"dirs,omitempty"`
+	Path  string   `json:"path,omitempty"`
+	AppID string   `json:"app_id,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// reporter prints watch events as they happen. Watch may report from several
+// goroutines, so writes are serialized.
+type reporter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	json bool
+}
+
+func (r *reporter) Watching(dirs []string) {
+	r.write(event{Event: "watching", Dirs: dirs}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Watching %s for new AppImages\n", strings.Join(dirs, ", "))
+		return err
+	})
+}
+
+func (r *reporter) Found(path string) {
+	r.write(event{Event: "found", Path: path}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Found %s\n", path)
+		return err
+	})
+}
+
+func (r *reporter) Added(path string, app domain.App) {
+	r.write(event{Event: "added", Path: path, AppID: app.ID}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%sIntegrated %s as %s%s\n", green, path, app.ID, reset)
+		return err
+	})
+}
+
+func (r *reporter) Failed(path string, err error) {
+	r.write(event{Event: "failed", Path: path, Error: err.Error()}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%sFailed to integrate %s: %v%s\n", red, path, err, reset)
+		return err
+	})
+}
+
+func (r *reporter) write(jsonValue event, writeText func(io.Writer) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = output.Write(r.w, r.json, jsonValue, writeText)
+}
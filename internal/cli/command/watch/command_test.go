@@ -0,0 +1,94 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/cli/clienv"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestCommandPassesAbsoluteDirsAndPrintsEvents(t *testing.T) {
+	service := &fakeService{report: func(events app.WatchReporter) {
+		events.Watching([]string{"/downloads"})
+		events.Found("/downloads/Example.AppImage")
+		events.Added("/downloads/Example.AppImage", domain.App{ID: "example"})
+		events.Failed("/downloads/Broken.AppImage", errors.New("bad squashfs"))
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := NewCommand(clienv.New(stdout, stderr), service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{"downloads", "--auto"})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	want, err := filepath.Abs("downloads")
+	if err != nil {
+		t.Fatalf("filepath.Abs() error = %v", err)
+	}
+	if len(service.req.Dirs) != 1 || service.req.Dirs[0] != want || !service.req.AutoAdd {
+		t.Fatalf("WatchRequest = %#v, want auto add of %q", service.req, want)
+	}
+	for _, want := range []string{
+		"Watching /downloads",
+		"Found /downloads/Example.AppImage",
+		"Integrated /downloads/Example.AppImage as example",
+		"Failed to integrate /downloads/Broken.AppImage: bad squashfs",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+		}
+	}
+}
+
+func TestCommandDefaultsToConfiguredDirAndPrintsJSON(t *testing.T) {
+	service := &fakeService{report: func(events app.WatchReporter) {
+		events.Added("/downloads/Example.AppImage", domain.App{ID: "example"})
+	}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	rt := clienv.New(stdout, stderr)
+	rt.Config.JSON = true
+	cmd := NewCommand(rt, service)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs(nil)
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+
+	if len(service.req.Dirs) != 0 || service.req.AutoAdd {
+		t.Fatalf("WatchRequest = %#v, want defaults", service.req)
+	}
+	var payload event
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v; stdout = %q", err, stdout.String())
+	}
+	if payload.Event != "added" || payload.Path != "/downloads/Example.AppImage" || payload.AppID != "example" {
+		t.Fatalf("payload = %#v", payload)
+	}
+}
+
+type fakeService struct {
+	req    app.WatchRequest
+	report func(events app.WatchReporter)
+}
+
+var _ service = (*fakeService)(nil)
+
+func (s *fakeService) Watch(ctx context.Context, req app.WatchRequest) error {
+	s.req = req
+	s.report(req.Events)
+	return nil
+}
@@ -9,12 +9,15 @@ import (
 type InfoJSON struct {
 	ID           string           `json:"id,omitempty"`
 	Name         string           `json:"name"`
+	GenericName  string           `json:"generic_name,omitempty"`
+	Comment      string           `json:"comment,omitempty"`
 	Version      string           `json:"version"`
 	ExecPath     string           `json:"exec_path"`
 	Installed    bool             `json:"installed"`
 	TargetKind   string           `json:"target_kind"`
 	Source       SourceJSON       `json:"source"`
 	UpdateSource UpdateSourceJSON `json:"update_source"`
+	Translations app.Translations `json:"translations"`
 }
 
 type UpdateSourceJSON struct {
@@ -35,6 +38,7 @@ type SourceJSON struct {
 	Kind          string                   `json:"kind"`
 	LocalFile     *LocalFileSourceJSON     `json:"local_file,omitempty"`
 	GitHubRelease *GitHubReleaseSourceJSON `json:"github_release,omitempty"`
+	URL           *URLSourceJSON           `json:"url,omitempty"`
 }
 
 type LocalFileSourceJSON struct {
@@ -51,16 +55,24 @@ type GitHubReleaseSourceJSON struct {
 	DownloadedAt string `json:"downloaded_at,omitempty"`
 }
 
+type URLSourceJSON struct {
+	URL          string `json:"url"`
+	DownloadedAt string `json:"downloaded_at,omitempty"`
+}
+
 func InfoResultJSON(info app.InfoResult) InfoJSON {
 	return InfoJSON{
 		ID:           info.ID,
 		Name:         info.Name,
+		GenericName:  info.GenericName,
+		Comment:      info.Comment,
 		Version:      info.Version,
 		ExecPath:     info.ExecPath,
 		Installed:    info.Installed,
 		TargetKind:   info.TargetKind,
 		Source:       sourceJSON(info),
 		UpdateSource: updateSourceJSON(info),
+		Translations: info.Translations,
 	}
 }
 
@@ -99,6 +111,11 @@ func sourceJSON(info app.InfoResult) SourceJSON {
 			SizeBytes:    source.GitHubRelease.SizeBytes,
 			DownloadedAt: FormatSourceTime(source.GitHubRelease.DownloadedAt),
 		}
+	case "url":
+		result.URL = &URLSourceJSON{
+			URL:          source.URL.URL,
+			DownloadedAt: FormatSourceTime(source.URL.DownloadedAt),
+		}
 	}
 
 	return result
@@ -9,14 +9,32 @@ import (
 	"github.com/slobbe/appimage-manager/internal/cli/clienv"
 
 	"github.com/slobbe/appimage-manager/internal/cli/command/add"
+	"github.com/slobbe/appimage-manager/internal/cli/command/adopt"
+	"github.com/slobbe/appimage-manager/internal/cli/command/autoupdate"
+	"github.com/slobbe/appimage-manager/internal/cli/command/bin"
+	"github.com/slobbe/appimage-manager/internal/cli/command/configapp"
+	"github.com/slobbe/appimage-manager/internal/cli/command/db"
+	"github.com/slobbe/appimage-manager/internal/cli/command/defaultapp"
 	"github.com/slobbe/appimage-manager/internal/cli/command/gen"
+	"github.com/slobbe/appimage-manager/internal/cli/command/history"
 	"github.com/slobbe/appimage-manager/internal/cli/command/id"
 	"github.com/slobbe/appimage-manager/internal/cli/command/info"
 	"github.com/slobbe/appimage-manager/internal/cli/command/list"
+	"github.com/slobbe/appimage-manager/internal/cli/command/lock"
+	"github.com/slobbe/appimage-manager/internal/cli/command/migrate"
 	"github.com/slobbe/appimage-manager/internal/cli/command/paths"
+	"github.com/slobbe/appimage-manager/internal/cli/command/recovery"
 	"github.com/slobbe/appimage-manager/internal/cli/command/remove"
+	"github.com/slobbe/appimage-manager/internal/cli/command/repair"
+	"github.com/slobbe/appimage-manager/internal/cli/command/run"
+	"github.com/slobbe/appimage-manager/internal/cli/command/sandbox"
+	"github.com/slobbe/appimage-manager/internal/cli/command/scan"
 	"github.com/slobbe/appimage-manager/internal/cli/command/selfupdate"
+	"github.com/slobbe/appimage-manager/internal/cli/command/snapshot"
+	"github.com/slobbe/appimage-manager/internal/cli/command/sync"
+	"github.com/slobbe/appimage-manager/internal/cli/command/undo"
 	"github.com/slobbe/appimage-manager/internal/cli/command/update"
+	"github.com/slobbe/appimage-manager/internal/cli/command/watch"
 
 	"github.com/spf13/cobra"
 )
@@ -66,12 +84,35 @@ func NewRootCommand(rt *clienv.Runtime, service app.Service, version string) *co
 
 	cmd.CompletionOptions.HiddenDefaultCmd = true
 
+	cmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		recovery.RecoverInterrupted(cmd, rt, service)
+	}
+
 	cmd.AddCommand(add.NewCommand(rt, service))
 	cmd.AddCommand(remove.NewCommand(rt, service))
+	cmd.AddCommand(undo.NewCommand(rt, service))
 	cmd.AddCommand(update.NewCommand(rt, service))
+	cmd.AddCommand(run.NewCommand(rt, service))
+	cmd.AddCommand(autoupdate.NewCommand(rt, service))
 	cmd.AddCommand(id.NewCommand(rt, service))
+	cmd.AddCommand(sandbox.NewCommand(rt, service))
+	cmd.AddCommand(configapp.NewCommand(rt, service))
+	cmd.AddCommand(bin.NewCommand(rt, service))
+	cmd.AddCommand(defaultapp.NewCommand(rt, service))
+	cmd.AddCommand(repair.NewCommand(rt, service))
+	cmd.AddCommand(recovery.NewCommand(rt, service))
+	cmd.AddCommand(scan.NewCommand(rt, service))
+	cmd.AddCommand(adopt.NewCommand(rt, service))
+	cmd.AddCommand(migrate.NewCommand(rt, service))
+	cmd.AddCommand(watch.NewCommand(rt, service))
+	cmd.AddCommand(sync.NewCommand(rt, service))
+	cmd.AddCommand(lock.NewCommand(rt, service))
+	cmd.AddCommand(snapshot.NewExportCommand(rt, service))
+	cmd.AddCommand(snapshot.NewImportCommand(rt, service))
+	cmd.AddCommand(db.NewCommand(rt, service))
 	cmd.AddCommand(list.NewCommand(rt, service))
 	cmd.AddCommand(info.NewCommand(rt, service))
+	cmd.AddCommand(history.NewCommand(rt, service))
 	cmd.AddCommand(selfupdate.NewCommand(rt, service))
 	cmd.AddCommand(paths.NewCommand(rt, service))
 	cmd.AddCommand(gen.NewCommand(cmd))
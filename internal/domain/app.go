@@ -16,12 +16,14 @@ const (
 	SourceKindUnknown SourceKind = ""
 	SourceKindLocal   SourceKind = "local"
 	SourceKindGitHub  SourceKind = "github"
+	SourceKindURL     SourceKind = "url"
 )
 
 type Source struct {
 	Kind          SourceKind
 	LocalFile     LocalFileSource
 	GitHubRelease GitHubReleaseSource
+	URL           URLSource
 }
 
 type LocalFileSource struct {
@@ -38,6 +40,11 @@ type GitHubReleaseSource struct {
 	DownloadedAt time.Time
 }
 
+type URLSource struct {
+	URL          string
+	DownloadedAt time.Time
+}
+
 type UpdateSourceKind string
 
 const (
@@ -150,6 +157,16 @@ func NewGitHubReleaseSource(repo string, tag string, asset string, downloadURL s
 	}
 }
 
+func NewURLSource(url string, downloadedAt time.Time) Source {
+	return Source{
+		Kind: SourceKindURL,
+		URL: URLSource{
+			URL:          strings.TrimSpace(url),
+			DownloadedAt: normalizeSourceTime(downloadedAt),
+		},
+	}
+}
+
 func normalizeSourceTime(value time.Time) time.Time {
 	if value.IsZero() {
 		return time.Time{}
@@ -159,14 +176,31 @@ func normalizeSourceTime(value time.Time) time.Time {
 }
 
 type App struct {
-	ID               string
-	Name             string
+	ID   string
+	Name string
+	// NameTranslations are the translations of Name by locale.
+	NameTranslations map[string]string
+	GenericName      LocalizedString
+	Comment          LocalizedString
 	Version          Version
 	AppImagePath     string
 	DesktopEntryPath string
 	IconPath         string
+	// IconSizePaths are further sizes of the icon installed into the hicolor
+	// theme besides IconPath, which the desktop entry uses.
+	IconSizePaths []string
+	// GeneratedIcon reports that IconPath is a placeholder drawn by aim
+	// because the AppImage bundles no icon.
+	GeneratedIcon bool
+	// BinPath is the command that puts the app on PATH, if it has one.
+	BinPath string
+	// MimePackagePaths are the MIME type definitions the app bundles,
+	// installed into the user's shared MIME database.
+	MimePackagePaths []string
 	Source           Source
 	UpdateSource     UpdateSource
+	Sandbox          Sandbox
+	Launch           LaunchOptions
 }
 
 // NewApp creates an App and derives its ID from the name when no explicit ID is
@@ -183,19 +217,37 @@ func NewApp(input AppInput) App {
 	return App{
 		ID:               id,
 		Name:             name,
+		NameTranslations: input.NameTranslations,
+		GenericName:      input.GenericName,
+		Comment:          input.Comment,
 		Version:          input.Version,
 		AppImagePath:     strings.TrimSpace(input.AppImagePath),
 		DesktopEntryPath: strings.TrimSpace(input.DesktopEntryPath),
 		IconPath:         strings.TrimSpace(input.IconPath),
+		IconSizePaths:    input.IconSizePaths,
+		GeneratedIcon:    input.GeneratedIcon,
+		BinPath:          strings.TrimSpace(input.BinPath),
+		MimePackagePaths: input.MimePackagePaths,
 		Source:           input.Source,
 		UpdateSource:     input.UpdateSource,
+		Sandbox:          input.Sandbox,
+		Launch:           input.Launch,
 	}
 }
 
 // NewAppFromDesktopEntry creates an App from parsed desktop metadata and
 // installation/use-case input.
+//
+// Name translations come from Name[locale] keys unless X-AppImage-Name
+// overrides Name, since they translate a different name then.
 func NewAppFromDesktopEntry(entry DesktopEntry, input AppInput) App {
 	input.Name = entry.Name
+	input.NameTranslations = nil
+	if name := entry.Localized("Name"); name.Value == entry.Name {
+		input.NameTranslations = name.Translations
+	}
+	input.GenericName = entry.Localized("GenericName")
+	input.Comment = entry.Localized("Comment")
 	input.Version = entry.Version
 	return NewApp(input)
 }
@@ -204,12 +256,43 @@ func NewAppFromDesktopEntry(entry DesktopEntry, input AppInput) App {
 type AppInput struct {
 	ID               string
 	Name             string
+	NameTranslations map[string]string
+	GenericName      LocalizedString
+	Comment          LocalizedString
 	Version          Version
 	AppImagePath     string
 	DesktopEntryPath string
 	IconPath         string
+	IconSizePaths    []string
+	GeneratedIcon    bool
+	BinPath          string
+	MimePackagePaths []string
 	Source           Source
 	UpdateSource     UpdateSource
+	Sandbox          Sandbox
+	Launch           LaunchOptions
+}
+
+// LocalizedName returns the name of the app translated for locale; see
+// LocalizedString.Localize.
+func (a App) LocalizedName(locale string) string {
+	return LocalizedString{Value: a.Name, Translations: a.NameTranslations}.Localize(locale)
+}
+
+// LaunchCommand returns the command line that launches the app as configured,
+// without its environment variables; see LaunchOptions.Environ.
+func (a App) LaunchCommand() []string {
+	return append(a.Sandbox.Command(a.AppImagePath), a.Launch.Args...)
+}
+
+// DesktopCommand returns LaunchCommand prefixed with env(1) when the app has
+// environment variables, since desktop entries cannot set them otherwise.
+func (a App) DesktopCommand() []string {
+	environ := a.Launch.Environ()
+	if len(environ) == 0 {
+		return a.LaunchCommand()
+	}
+	return append(append([]string{"env"}, environ...), a.LaunchCommand()...)
 }
 
 // HasUpdate reports whether candidate is newer than the app's current version.
@@ -130,6 +130,56 @@ func TestNewAppFromDesktopEntry(t *testing.T) {
 	}
 }
 
+func TestNewAppFromDesktopEntryKeepsTranslations(t *testing.T) {
+	t.Parallel()
+
+	entry, err := ParseDesktopEntry([]byte(`
+[Desktop Entry]
+Name=Example App
+Name[de]=Beispiel-App
+GenericName=Text Editor
+GenericName[de]=Texteditor
+Comment=Edit text
+Comment[de]=Text bearbeiten
+`))
+	if err != nil {
+		t.Fatalf("ParseDesktopEntry() error = %v", err)
+	}
+
+	app := NewAppFromDesktopEntry(entry, AppInput{})
+	if got, want := app.LocalizedName("de_DE.UTF-8"), "Beispiel-App"; got != want {
+		t.Fatalf("App.LocalizedName(de_DE.UTF-8) = %q, want %q", got, want)
+	}
+	if got, want := app.LocalizedName("en_US.UTF-8"), "Example App"; got != want {
+		t.Fatalf("App.LocalizedName(en_US.UTF-8) = %q, want %q", got, want)
+	}
+	if got, want := app.GenericName.Localize("de"), "Texteditor"; got != want {
+		t.Fatalf("App.GenericName.Localize(de) = %q, want %q", got, want)
+	}
+	if got, want := app.Comment.Localize("de"), "Text bearbeiten"; got != want {
+		t.Fatalf("App.Comment.Localize(de) = %q, want %q", got, want)
+	}
+}
+
+func TestNewAppFromDesktopEntryDropsNameTranslationsForAppImageName(t *testing.T) {
+	t.Parallel()
+
+	entry, err := ParseDesktopEntry([]byte(`
+[Desktop Entry]
+Name=Example
+Name[de]=Beispiel
+X-AppImage-Name=Example Pro
+`))
+	if err != nil {
+		t.Fatalf("ParseDesktopEntry() error = %v", err)
+	}
+
+	app := NewAppFromDesktopEntry(entry, AppInput{})
+	if got, want := app.LocalizedName("de"), "Example Pro"; got != want {
+		t.Fatalf("App.LocalizedName(de) = %q, want %q", got, want)
+	}
+}
+
 func TestNewAppUsesExplicitSlugifiedID(t *testing.T) {
 	t.Parallel()
 
@@ -114,6 +114,48 @@ func (d DesktopEntry) WithIcon(icon string) DesktopEntry {
 	return d.withField("Icon", icon, "")
 }
 
+// MimeTypes returns the MIME types listed in the MimeType key, including
+// x-scheme-handler/* URL schemes.
+func (d DesktopEntry) MimeTypes() []string {
+	var mimeTypes []string
+	for _, mimeType := range strings.Split(d.Fields["MimeType"], ";") {
+		if mimeType = strings.TrimSpace(mimeType); mimeType != "" {
+			mimeTypes = append(mimeTypes, mimeType)
+		}
+	}
+	return mimeTypes
+}
+
+// Localized returns the value of key with the translations given by
+// key[locale] keys, such as Name[de] for Name.
+func (d DesktopEntry) Localized(key string) LocalizedString {
+	localized := LocalizedString{Value: d.Fields[key]}
+	for field, value := range d.Fields {
+		locale, ok := strings.CutPrefix(field, key+"[")
+		if !ok || !strings.HasSuffix(locale, "]") || value == "" {
+			continue
+		}
+		if localized.Translations == nil {
+			localized.Translations = make(map[string]string)
+		}
+		localized.Translations[normalizeLocale(strings.TrimSuffix(locale, "]"))] = value
+	}
+	return localized
+}
+
+// ValidMimeType reports whether mimeType has the type/subtype form that
+// desktop entries and mimeapps.list accept, such as text/html or
+// x-scheme-handler/https.
+func ValidMimeType(mimeType string) bool {
+	mediaType, subtype, ok := strings.Cut(mimeType, "/")
+	if !ok || mediaType == "" || subtype == "" || strings.Contains(subtype, "/") {
+		return false
+	}
+	return !strings.ContainsFunc(mimeType, func(r rune) bool {
+		return r == ';' || r == '=' || r == '[' || r == ']' || unicode.IsSpace(r) || unicode.IsControl(r)
+	})
+}
+
 // Bytes serializes the desktop entry while preserving raw comments, groups, and
 // field ordering from the parsed input. Mutated fields are rewritten as key=value.
 func (d DesktopEntry) Bytes() []byte {
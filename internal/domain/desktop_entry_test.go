@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -52,6 +53,40 @@ Exec=ignored
 	}
 }
 
+func TestDesktopEntryLocalized(t *testing.T) {
+	t.Parallel()
+
+	entry, err := ParseDesktopEntry([]byte(`
+[Desktop Entry]
+Name=Example App
+Name[de]=Beispiel-App
+Name[pt_BR.UTF-8]=Aplicativo de exemplo
+Name[fr]=
+NameOther=ignored
+Comment=An example app
+
+[Desktop Action NewWindow]
+Name[de]=Neues Fenster
+`))
+	if err != nil {
+		t.Fatalf("ParseDesktopEntry() error = %v", err)
+	}
+
+	want := LocalizedString{
+		Value: "Example App",
+		Translations: map[string]string{
+			"de":    "Beispiel-App",
+			"pt_BR": "Aplicativo de exemplo",
+		},
+	}
+	if got := entry.Localized("Name"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Localized(Name) = %#v, want %#v", got, want)
+	}
+	if got, want := entry.Localized("Comment"), (LocalizedString{Value: "An example app"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Localized(Comment) = %#v, want %#v", got, want)
+	}
+}
+
 func TestParseDesktopEntryPrefersAppImageNameAndVersion(t *testing.T) {
 	t.Parallel()
 
@@ -338,3 +373,39 @@ func TestParseDesktopEntryRejectsMalformedLine(t *testing.T) {
 		t.Fatalf("ParseDesktopEntry() error = %q, want missing '='", err.Error())
 	}
 }
+
+func TestDesktopEntryMimeTypes(t *testing.T) {
+	t.Parallel()
+
+	entry, err := ParseDesktopEntry([]byte("[Desktop Entry]\nName=Example\nMimeType=text/html; x-scheme-handler/https;;\n"))
+	if err != nil {
+		t.Fatalf("ParseDesktopEntry() error = %v", err)
+	}
+
+	if got, want := entry.MimeTypes(), []string{"text/html", "x-scheme-handler/https"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MimeTypes() = %#v, want %#v", got, want)
+	}
+}
+
+func TestValidMimeType(t *testing.T) {
+	t.Parallel()
+
+	for mimeType, want := range map[string]bool{
+		"text/html":                   true,
+		"x-scheme-handler/https":      true,
+		"application/vnd.foo+json":    true,
+		"":                            false,
+		"text":                        false,
+		"text/":                       false,
+		"/html":                       false,
+		"text/html/extra":             false,
+		"text/html;":                  false,
+		"text/html=example.desktop":   false,
+		"text/plain charset":          false,
+		"[Default Applications]/html": false,
+	} {
+		if got := ValidMimeType(mimeType); got != want {
+			t.Errorf("ValidMimeType(%q) = %t, want %t", mimeType, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LaunchOptions are the arguments and environment variables an app is
+// launched with, for example --ozone-platform=wayland or GDK_SCALE=2.
+type LaunchOptions struct {
+	// Args are passed to the AppImage before the arguments of the desktop
+	// entry's Exec line.
+	Args []string
+	Env  map[string]string
+}
+
+// IsZero reports whether the app is launched without extra arguments or
+// environment variables.
+func (o LaunchOptions) IsZero() bool {
+	return len(o.Args) == 0 && len(o.Env) == 0
+}
+
+// Environ returns Env as KEY=VALUE pairs sorted by key.
+func (o LaunchOptions) Environ() []string {
+	if len(o.Env) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(o.Env))
+	for key := range o.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	environ := make([]string, 0, len(keys))
+	for _, key := range keys {
+		environ = append(environ, key+"="+o.Env[key])
+	}
+	return environ
+}
+
+// ValidEnvName reports whether name can be set as an environment variable
+// through env(1).
+func ValidEnvName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "= \t\n\x00")
+}
+
+// ParseEnv splits a KEY=VALUE assignment.
+func ParseEnv(assignment string) (string, string, error) {
+	key, value, ok := strings.Cut(assignment, "=")
+	if !ok || !ValidEnvName(key) {
+		return "", "", fmt.Errorf("invalid environment variable %q; use KEY=VALUE", assignment)
+	}
+	return key, value, nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppDesktopCommandAddsLaunchOptions(t *testing.T) {
+	t.Parallel()
+
+	app := App{
+		AppImagePath: "/apps/example.AppImage",
+		Sandbox:      Sandbox{Backend: SandboxBackendFirejail, Network: true},
+		Launch: LaunchOptions{
+			Args: []string{"--no-sandbox"},
+			Env:  map[string]string{"GDK_SCALE": "2", "ELECTRON_OZONE_PLATFORM_HINT": "wayland"},
+		},
+	}
+
+	wantLaunch := []string{"firejail", "--appimage", "/apps/example.AppImage", "--no-sandbox"}
+	if got := app.LaunchCommand(); !reflect.DeepEqual(got, wantLaunch) {
+		t.Fatalf("LaunchCommand() = %q, want %q", got, wantLaunch)
+	}
+	wantDesktop := append([]string{"env", "ELECTRON_OZONE_PLATFORM_HINT=wayland", "GDK_SCALE=2"}, wantLaunch...)
+	if got := app.DesktopCommand(); !reflect.DeepEqual(got, wantDesktop) {
+		t.Fatalf("DesktopCommand() = %q, want %q", got, wantDesktop)
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	t.Parallel()
+
+	key, value, err := ParseEnv("GDK_SCALE=2=x")
+	if err != nil || key != "GDK_SCALE" || value != "2=x" {
+		t.Fatalf("ParseEnv() = %q, %q, %v; want GDK_SCALE, 2=x", key, value, err)
+	}
+	for _, assignment := range []string{"GDK_SCALE", "=2", "BAD NAME=1"} {
+		if _, _, err := ParseEnv(assignment); err == nil {
+			t.Fatalf("ParseEnv(%q) error = nil, want error", assignment)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"maps"
+	"strings"
+)
+
+// LocalizedString is a desktop entry value with its translations, keyed by
+// the locale of the key they come from, such as "pt_BR" for Name[pt_BR].
+type LocalizedString struct {
+	Value        string
+	Translations map[string]string
+}
+
+// Localize returns the translation that matches locale, a POSIX locale such
+// as "de_DE.UTF-8@euro", or Value when none does.
+//
+// Translations are tried in the order the desktop entry specification gives:
+// lang_COUNTRY@MODIFIER, lang_COUNTRY, lang@MODIFIER, then lang.
+func (s LocalizedString) Localize(locale string) string {
+	for _, candidate := range localeCandidates(locale) {
+		if translation := s.Translations[candidate]; translation != "" {
+			return translation
+		}
+	}
+	return s.Value
+}
+
+// IsZero reports whether s has neither a value nor translations.
+func (s LocalizedString) IsZero() bool {
+	return s.Value == "" && len(s.Translations) == 0
+}
+
+// Equal reports whether s and other have the same value and translations.
+func (s LocalizedString) Equal(other LocalizedString) bool {
+	return s.Value == other.Value && maps.Equal(s.Translations, other.Translations)
+}
+
+func localeCandidates(locale string) []string {
+	lang, country, modifier := parseLocale(locale)
+	if lang == "" || lang == "C" || lang == "POSIX" {
+		return nil
+	}
+
+	candidates := make([]string, 0, 4)
+	if country != "" && modifier != "" {
+		candidates = append(candidates, lang+"_"+country+"@"+modifier)
+	}
+	if country != "" {
+		candidates = append(candidates, lang+"_"+country)
+	}
+	if modifier != "" {
+		candidates = append(candidates, lang+"@"+modifier)
+	}
+	return append(candidates, lang)
+}
+
+// parseLocale splits lang_COUNTRY.ENCODING@MODIFIER, dropping the encoding.
+func parseLocale(locale string) (lang string, country string, modifier string) {
+	locale, modifier, _ = strings.Cut(strings.TrimSpace(locale), "@")
+	locale, _, _ = strings.Cut(locale, ".")
+	lang, country, _ = strings.Cut(locale, "_")
+	return lang, country, modifier
+}
+
+// normalizeLocale drops the encoding from a locale, which the desktop entry
+// specification ignores when matching.
+func normalizeLocale(locale string) string {
+	lang, country, modifier := parseLocale(locale)
+	if country != "" {
+		lang += "_" + country
+	}
+	if modifier != "" {
+		lang += "@" + modifier
+	}
+	return lang
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "testing"
+
+func TestLocalizedStringLocalize(t *testing.T) {
+	t.Parallel()
+
+	value := LocalizedString{
+		Value: "Example",
+		Translations: map[string]string{
+			"sr":          "Primer",
+			"sr@latin":    "Primer latinica",
+			"sr_RS":       "Пример",
+			"sr_RS@latin": "Primer Srbija",
+			"de":          "Beispiel",
+		},
+	}
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "sr_RS.UTF-8@latin", want: "Primer Srbija"},
+		{locale: "sr_RS.UTF-8", want: "Пример"},
+		{locale: "sr_ME@latin", want: "Primer latinica"},
+		{locale: "sr_ME.UTF-8", want: "Primer"},
+		{locale: "de_AT.UTF-8", want: "Beispiel"},
+		{locale: "fr_FR.UTF-8", want: "Example"},
+		{locale: "C.UTF-8", want: "Example"},
+		{locale: "POSIX", want: "Example"},
+		{locale: "", want: "Example"},
+	}
+	for _, tt := range tests {
+		if got := value.Localize(tt.locale); got != tt.want {
+			t.Fatalf("Localize(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
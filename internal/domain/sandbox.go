@@ -0,0 +1,116 @@
+package domain
+
+import "strings"
+
+// SandboxBackend is the program that confines a sandboxed app.
+type SandboxBackend string
+
+const (
+	SandboxBackendNone       SandboxBackend = ""
+	SandboxBackendBubblewrap SandboxBackend = "bwrap"
+	SandboxBackendFirejail   SandboxBackend = "firejail"
+)
+
+// Sandbox confines an app when it is launched from its desktop entry.
+//
+// All paths are absolute; expanding ~ or resolving profiles belongs to the
+// app/infra layers. The zero Sandbox runs the app unconfined.
+type Sandbox struct {
+	// Profile names the profile the sandbox was created from: strict,
+	// network-only, or the path of a custom profile.
+	Profile string
+	Backend SandboxBackend
+	Network bool
+	// Home replaces the user's home directory inside the sandbox, so the app
+	// keeps its settings apart from the rest of home. Empty shares the
+	// user's home read-only.
+	Home string
+	// UserHome is the user's home directory, where bubblewrap mounts Home.
+	UserHome string
+	// Binds are shared with the app read-write; ReadOnlyBinds read-only.
+	Binds         []string
+	ReadOnlyBinds []string
+}
+
+// Enabled reports whether the app runs confined.
+func (s Sandbox) Enabled() bool {
+	return s.Backend != SandboxBackendNone
+}
+
+// Command returns the command line that runs appImagePath inside the sandbox,
+// or appImagePath alone when the sandbox is disabled.
+//
+// Sandboxed AppImages cannot mount themselves with FUSE, so bubblewrap runs
+// them with APPIMAGE_EXTRACT_AND_RUN and firejail with its --appimage mode.
+func (s Sandbox) Command(appImagePath string) []string {
+	switch s.Backend {
+	case SandboxBackendBubblewrap:
+		args := []string{
+			"bwrap",
+			"--ro-bind", "/", "/",
+			"--dev", "/dev",
+			"--proc", "/proc",
+			"--tmpfs", "/tmp",
+			"--ro-bind-try", "/tmp/.X11-unix", "/tmp/.X11-unix",
+			"--unshare-pid",
+		}
+		if !s.Network {
+			args = append(args, "--unshare-net")
+		}
+		if s.Home != "" && s.UserHome != "" {
+			args = append(args, "--bind", s.Home, s.UserHome)
+		}
+		for _, path := range s.Binds {
+			args = append(args, "--bind", path, path)
+		}
+		for _, path := range s.ReadOnlyBinds {
+			args = append(args, "--ro-bind", path, path)
+		}
+		if s.Home != "" && s.UserHome != "" {
+			// The private home hides the AppImage when it lives under the
+			// user's home, as aim's default library does.
+			args = append(args, "--ro-bind", appImagePath, appImagePath)
+		}
+		return append(args, "--die-with-parent", "--setenv", "APPIMAGE_EXTRACT_AND_RUN", "1", appImagePath)
+	case SandboxBackendFirejail:
+		args := []string{"firejail", "--appimage"}
+		if !s.Network {
+			args = append(args, "--net=none")
+		}
+		if s.Home != "" {
+			// Firejail mounts the AppImage before it builds the sandbox, so
+			// the private home does not hide it.
+			args = append(args, "--private="+s.Home)
+		}
+		for _, path := range s.Binds {
+			args = append(args, "--whitelist="+path)
+		}
+		for _, path := range s.ReadOnlyBinds {
+			args = append(args, "--whitelist="+path, "--read-only="+path)
+		}
+		return append(args, appImagePath)
+	default:
+		return []string{appImagePath}
+	}
+}
+
+// DesktopExec joins args into the value of a desktop entry Exec key, quoting
+// and escaping them as the desktop entry specification requires.
+func DesktopExec(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, desktopExecReservedChars) {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = `"` + desktopExecQuoteEscaper.Replace(arg) + `"`
+	}
+
+	// Exec values are strings, so backslashes are escaped once more, and
+	// percent signs would otherwise start field codes.
+	return strings.NewReplacer(`\`, `\\`, "%", "%%").Replace(strings.Join(quoted, " "))
+}
+
+const desktopExecReservedChars = " \t\n\"'\\><~|&;$*?#()`"
+
+var desktopExecQuoteEscaper = strings.NewReplacer(`"`, `\"`, "`", "\\`", "$", `\$`, `\`, `\\`)
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSandboxCommand(t *testing.T) {
+	t.Parallel()
+
+	const appImage = "/apps/example.AppImage"
+	tests := map[string]struct {
+		sandbox Sandbox
+		want    string
+		// appImage defaults to the AppImage outside home.
+		appImage string
+	}{
+		"disabled": {
+			sandbox: Sandbox{},
+			want:    appImage,
+		},
+		"bubblewrap strict": {
+			sandbox: Sandbox{Backend: SandboxBackendBubblewrap, Home: "/home/user/.local/share/aim/sandbox/example", UserHome: "/home/user", ReadOnlyBinds: []string{"/home/user/Documents"}},
+			want: "bwrap --ro-bind / / --dev /dev --proc /proc --tmpfs /tmp --ro-bind-try /tmp/.X11-unix /tmp/.X11-unix --unshare-pid --unshare-net " +
+				"--bind /home/user/.local/share/aim/sandbox/example /home/user --ro-bind /home/user/Documents /home/user/Documents " +
+				"--ro-bind " + appImage + " " + appImage + " --die-with-parent --setenv APPIMAGE_EXTRACT_AND_RUN 1 " + appImage,
+		},
+		"bubblewrap private home keeps appimage under home": {
+			sandbox: Sandbox{Backend: SandboxBackendBubblewrap, Network: true, Home: "/home/user/.local/share/aim/sandbox/example", UserHome: "/home/user"},
+			want: "bwrap --ro-bind / / --dev /dev --proc /proc --tmpfs /tmp --ro-bind-try /tmp/.X11-unix /tmp/.X11-unix --unshare-pid " +
+				"--bind /home/user/.local/share/aim/sandbox/example /home/user " +
+				"--ro-bind /home/user/.local/share/aim/appimages/example.AppImage /home/user/.local/share/aim/appimages/example.AppImage " +
+				"--die-with-parent --setenv APPIMAGE_EXTRACT_AND_RUN 1 /home/user/.local/share/aim/appimages/example.AppImage",
+			appImage: "/home/user/.local/share/aim/appimages/example.AppImage",
+		},
+		"bubblewrap shared home": {
+			sandbox: Sandbox{Backend: SandboxBackendBubblewrap, Network: true},
+			want: "bwrap --ro-bind / / --dev /dev --proc /proc --tmpfs /tmp --ro-bind-try /tmp/.X11-unix /tmp/.X11-unix --unshare-pid " +
+				"--die-with-parent --setenv APPIMAGE_EXTRACT_AND_RUN 1 " + appImage,
+		},
+		"firejail private home": {
+			sandbox: Sandbox{Backend: SandboxBackendFirejail, Home: "/home/user/.local/share/aim/sandbox/example"},
+			want:    "firejail --appimage --net=none --private=/home/user/.local/share/aim/sandbox/example " + appImage,
+		},
+		"firejail with network": {
+			sandbox: Sandbox{Backend: SandboxBackendFirejail, Network: true, Binds: []string{"/home/user/Downloads"}},
+			want:    "firejail --appimage --whitelist=/home/user/Downloads " + appImage,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := appImage
+			if tt.appImage != "" {
+				path = tt.appImage
+			}
+			if got := strings.Join(tt.sandbox.Command(path), " "); got != tt.want {
+				t.Fatalf("Command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppLaunchCommandUsesSandbox(t *testing.T) {
+	t.Parallel()
+
+	app := App{AppImagePath: "/apps/example.AppImage", Sandbox: Sandbox{Backend: SandboxBackendFirejail}}
+	want := []string{"firejail", "--appimage", "--net=none", "/apps/example.AppImage"}
+	if got := app.LaunchCommand(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("LaunchCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestDesktopExecQuotesReservedCharacters(t *testing.T) {
+	t.Parallel()
+
+	got := DesktopExec([]string{"/home/user/My Apps/example.AppImage", `--title="$HOME"`, "100%", ""})
+	want := `"/home/user/My Apps/example.AppImage" "--title=\\"\\$HOME\\"" 100%% ""`
+	if got != want {
+		t.Fatalf("DesktopExec() = %q, want %q", got, want)
+	}
+}
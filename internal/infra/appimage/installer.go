@@ -54,3 +54,24 @@ func (i Installer) Install(ctx context.Context, sourcePath string, appID string)
 
 	return destination, nil
 }
+
+// Adopt resolves path to an absolute path and ensures the AppImage is
+// owner-executable so it can be integrated in place.
+func (i Installer) Adopt(ctx context.Context, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("appimage path is required")
+	}
+
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve appimage path %q: %w", path, err)
+	}
+	if err := ensureOwnerExecutable(absolutePath); err != nil {
+		return "", err
+	}
+
+	return absolutePath, nil
+}
@@ -0,0 +1,117 @@
+package appimage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+// appImageMagicOffset is where AppImages store their type marker inside the
+// ELF identification padding: "AI" followed by the type byte (1 or 2).
+const appImageMagicOffset = 8
+
+var elfMagic = []byte{0x7f, 'E', 'L', 'F'}
+
+// Scanner finds AppImages by reading file headers instead of trusting names.
+type Scanner struct{}
+
+var _ app.AppImageScanner = Scanner{}
+
+// Scan walks dirs recursively and returns regular files carrying the AppImage
+// magic bytes, sorted by path. Unreadable directories and files are skipped
+// because scan roots such as /opt commonly contain entries owned by other
+// users.
+func (Scanner) Scan(ctx context.Context, dirs []string) ([]app.ScannedAppImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, errors.New("scan directory is required")
+	}
+
+	seen := make(map[string]struct{})
+	var result []app.ScannedAppImage
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			return nil, errors.New("scan directory is required")
+		}
+		root, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve scan directory %q: %w", dir, err)
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("stat scan directory %q: %w", root, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("scan path %q is not a directory", root)
+		}
+
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				if entry != nil && entry.IsDir() && path != root {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			if _, ok := seen[path]; ok {
+				return nil
+			}
+			if !IsAppImage(path) {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+
+			seen[path] = struct{}{}
+			result = append(result, app.ScannedAppImage{Path: path, SizeBytes: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan directory %q: %w", root, err)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result, nil
+}
+
+// IsAppImage reports whether path starts with an ELF header carrying the
+// AppImage type 1 or type 2 magic bytes.
+func IsAppImage(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, appImageMagicOffset+3)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+
+	return bytes.Equal(header[:len(elfMagic)], elfMagic) &&
+		header[appImageMagicOffset] == 'A' &&
+		header[appImageMagicOffset+1] == 'I' &&
+		(header[appImageMagicOffset+2] == 1 || header[appImageMagicOffset+2] == 2)
+}
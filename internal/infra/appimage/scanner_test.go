@@ -0,0 +1,119 @@
+package appimage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScannerFindsAppImagesByMagicBytes(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "opt", "example")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir nested: %v", err)
+	}
+	typeTwo := writeScanFile(t, filepath.Join(nested, "example"), appImageHeader(2))
+	typeOne := writeScanFile(t, filepath.Join(root, "Legacy.AppImage"), appImageHeader(1))
+	writeScanFile(t, filepath.Join(root, "Fake.AppImage"), []byte("#!/bin/sh\necho not an appimage\n"))
+	writeScanFile(t, filepath.Join(root, "plain-elf"), append([]byte{0x7f, 'E', 'L', 'F'}, make([]byte, 12)...))
+
+	found, err := Scanner{}.Scan(context.Background(), []string{root})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(found) != 2 {
+		t.Fatalf("Scan() = %#v, want two AppImages", found)
+	}
+	if found[0].Path != typeOne || found[1].Path != typeTwo {
+		t.Fatalf("Scan() paths = %q, %q; want %q, %q", found[0].Path, found[1].Path, typeOne, typeTwo)
+	}
+	if got, want := found[1].SizeBytes, int64(len(appImageHeader(2))); got != want {
+		t.Fatalf("SizeBytes = %d, want %d", got, want)
+	}
+}
+
+func TestScannerDeduplicatesOverlappingDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeScanFile(t, filepath.Join(root, "Example.AppImage"), appImageHeader(2))
+
+	found, err := Scanner{}.Scan(context.Background(), []string{root, root})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("Scan() = %#v, want one AppImage", found)
+	}
+}
+
+func TestScannerValidatesDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	file := writeScanFile(t, filepath.Join(root, "file"), []byte("x"))
+	for _, dirs := range [][]string{nil, {""}, {filepath.Join(root, "missing")}, {file}} {
+		if _, err := (Scanner{}).Scan(context.Background(), dirs); err == nil {
+			t.Fatalf("Scan(%q) error = nil, want error", dirs)
+		}
+	}
+}
+
+func TestScannerRespectsCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (Scanner{}).Scan(ctx, []string{t.TempDir()}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Scan() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestInstallerAdoptMakesAppImageExecutableInPlace(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := writeScanFile(t, filepath.Join(root, "Example.AppImage"), appImageHeader(2))
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	adopted, err := NewInstaller(filepath.Join(root, "library")).Adopt(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Adopt() error = %v", err)
+	}
+	if adopted != path {
+		t.Fatalf("Adopt() = %q, want %q", adopted, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode()&0o100 == 0 {
+		t.Fatalf("mode = %v, want owner executable", info.Mode())
+	}
+	if _, err := os.Stat(filepath.Join(root, "library")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("library stat error = %v, want not exist", err)
+	}
+}
+
+func appImageHeader(appImageType byte) []byte {
+	header := make([]byte, 64)
+	copy(header, []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0, 'A', 'I', appImageType})
+	return header
+}
+
+func writeScanFile(t *testing.T, path string, content []byte) string {
+	t.Helper()
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
@@ -0,0 +1,161 @@
+package appimage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultSettleDelay is how long a new file must stay unchanged before the
+// Watcher from NewWatcher reports it.
+const DefaultSettleDelay = 2 * time.Second
+
+// inotifyEventSize is the size of struct inotify_event without its name.
+const inotifyEventSize = 16
+
+// partialDownloadSuffixes mark files browsers are still downloading. They are
+// renamed to their final name when complete, which is reported instead.
+var partialDownloadSuffixes = []string{".part", ".crdownload", ".download", ".partial", ".tmp"}
+
+// Watcher reports AppImages written or moved into directories using inotify.
+type Watcher struct {
+	// SettleDelay is how long a file must keep its size and modification time
+	// after it was closed or moved in before it counts as fully written.
+	SettleDelay time.Duration
+}
+
+// NewWatcher creates a Watcher with DefaultSettleDelay.
+func NewWatcher() Watcher {
+	return Watcher{SettleDelay: DefaultSettleDelay}
+}
+
+var _ app.AppImageWatcher = Watcher{}
+
+type pendingFile struct {
+	size    int64
+	modTime time.Time
+	due     time.Time
+}
+
+func (w Watcher) Watch(ctx context.Context, dirs []string, found func(path string)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("initialize inotify: %w", err)
+	}
+	defer unix.Close(fd)
+
+	watches := make(map[int32]string, len(dirs))
+	for _, dir := range dirs {
+		wd, err := unix.InotifyAddWatch(fd, dir, unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO|unix.IN_ONLYDIR)
+		if err != nil {
+			return fmt.Errorf("watch %q: %w", dir, err)
+		}
+		watches[int32(wd)] = dir
+	}
+
+	pending := make(map[string]pendingFile)
+	buf := make([]byte, 64*1024)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Poll with a short timeout so cancellation and settled files are
+		// noticed without an event.
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		ready, err := unix.Poll(fds, 200)
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return fmt.Errorf("wait for inotify events: %w", err)
+		}
+		if ready > 0 {
+			n, err := unix.Read(fd, buf)
+			if err != nil && !errors.Is(err, unix.EAGAIN) && !errors.Is(err, unix.EINTR) {
+				return fmt.Errorf("read inotify events: %w", err)
+			}
+			for _, event := range parseInotifyEvents(buf[:max(n, 0)]) {
+				if event.mask&unix.IN_IGNORED != 0 {
+					delete(watches, event.wd)
+					if len(watches) == 0 {
+						return errors.New("all watched directories were removed")
+					}
+					continue
+				}
+				dir, ok := watches[event.wd]
+				if !ok || event.name == "" || isPartialDownload(event.name) {
+					continue
+				}
+				path := filepath.Join(dir, event.name)
+				if info, err := os.Stat(path); err == nil {
+					pending[path] = pendingFile{size: info.Size(), modTime: info.ModTime(), due: time.Now().Add(w.SettleDelay)}
+				}
+			}
+		}
+
+		now := time.Now()
+		for path, file := range pending {
+			if now.Before(file.due) {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				delete(pending, path)
+				continue
+			}
+			if info.Size() != file.size || !info.ModTime().Equal(file.modTime) {
+				pending[path] = pendingFile{size: info.Size(), modTime: info.ModTime(), due: now.Add(w.SettleDelay)}
+				continue
+			}
+			delete(pending, path)
+			if info.Mode().IsRegular() && IsAppImage(path) {
+				found(path)
+			}
+		}
+	}
+}
+
+type inotifyEvent struct {
+	wd   int32
+	mask uint32
+	name string
+}
+
+func parseInotifyEvents(buf []byte) []inotifyEvent {
+	var events []inotifyEvent
+	for len(buf) >= inotifyEventSize {
+		nameLength := int(binary.NativeEndian.Uint32(buf[12:16]))
+		if len(buf) < inotifyEventSize+nameLength {
+			break
+		}
+		events = append(events, inotifyEvent{
+			wd:   int32(binary.NativeEndian.Uint32(buf[0:4])),
+			mask: binary.NativeEndian.Uint32(buf[4:8]),
+			name: strings.TrimRight(string(buf[inotifyEventSize:inotifyEventSize+nameLength]), "\x00"),
+		})
+		buf = buf[inotifyEventSize+nameLength:]
+	}
+	return events
+}
+
+// isPartialDownload reports whether name belongs to a download in progress,
+// including the hidden temporary files some browsers write first.
+func isPartialDownload(name string) bool {
+	for _, suffix := range partialDownloadSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, ".")
+}
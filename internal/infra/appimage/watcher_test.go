@@ -0,0 +1,78 @@
+package appimage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherReportsCompletedAppImages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	found := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watcher{SettleDelay: 50 * time.Millisecond}.Watch(ctx, []string{dir}, func(path string) {
+			found <- path
+		})
+	}()
+	// Give the watcher time to add its inotify watch.
+	time.Sleep(100 * time.Millisecond)
+
+	writeScanFile(t, filepath.Join(dir, "notes.txt"), []byte("not an appimage"))
+	writeScanFile(t, filepath.Join(dir, "Example.AppImage.part"), appImageHeader(2))
+	if err := os.Rename(filepath.Join(dir, "Example.AppImage.part"), filepath.Join(dir, "Example.AppImage")); err != nil {
+		t.Fatalf("rename download: %v", err)
+	}
+
+	select {
+	case path := <-found:
+		if want := filepath.Join(dir, "Example.AppImage"); path != want {
+			t.Fatalf("found %q, want %q", path, want)
+		}
+	case err := <-done:
+		t.Fatalf("Watch() returned early: %v", err)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the AppImage")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Watch() error = %v, want context.Canceled", err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("unexpected extra reports: %q", <-found)
+	}
+}
+
+func TestWatcherRejectsMissingDir(t *testing.T) {
+	t.Parallel()
+
+	err := NewWatcher().Watch(context.Background(), []string{filepath.Join(t.TempDir(), "missing")}, func(string) {})
+	if err == nil {
+		t.Fatal("Watch() error = nil, want error for missing directory")
+	}
+}
+
+func TestIsPartialDownload(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{
+		"Example.AppImage":            false,
+		"Example.AppImage.crdownload": true,
+		"Example.AppImage.part":       true,
+		".com.google.Chrome.abc123":   true,
+		"Example-x86_64.AppImage.tmp": true,
+	} {
+		if got := isPartialDownload(name); got != want {
+			t.Errorf("isPartialDownload(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+// Package autoupdate schedules unattended aim update runs with a systemd user
+// timer, or with a crontab entry where no systemd user instance is running.
+package autoupdate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const (
+	serviceUnit = "aim-update.service"
+	timerUnit   = "aim-update.timer"
+	cronMarker  = "# aim autoupdate"
+
+	backendSystemd = "systemd"
+	backendCron    = "cron"
+)
+
+// Runner runs an external command with stdin and returns its standard
+// output.
+type Runner func(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error)
+
+// Scheduler manages the aim-update systemd units in UnitDir and falls back to
+// the user's crontab. Both run Executable with "update --yes --notify", or
+// with "update --notify" for check-only schedules.
+type Scheduler struct {
+	UnitDir    string
+	Executable string
+	// RuntimeDir is set as XDG_RUNTIME_DIR for cron runs so notifications
+	// reach the session bus; systemd user services get it already.
+	RuntimeDir string
+	Run        Runner
+}
+
+// NewScheduler creates a Scheduler that writes units to unitDir and runs
+// executable.
+func NewScheduler(unitDir string, executable string) Scheduler {
+	return Scheduler{
+		UnitDir:    unitDir,
+		Executable: executable,
+		RuntimeDir: os.Getenv("XDG_RUNTIME_DIR"),
+		Run:        RunCommand,
+	}
+}
+
+var _ app.UpdateScheduler = Scheduler{}
+
+func (s Scheduler) Enable(ctx context.Context, schedule app.UpdateSchedule) (app.ScheduledUpdates, error) {
+	if err := ctx.Err(); err != nil {
+		return app.ScheduledUpdates{}, err
+	}
+
+	backend := backendCron
+	if s.systemdAvailable(ctx) {
+		backend = backendSystemd
+		if err := s.enableTimer(ctx, schedule); err != nil {
+			return app.ScheduledUpdates{}, err
+		}
+		if _, err := s.removeCronEntry(ctx); err != nil {
+			return app.ScheduledUpdates{}, err
+		}
+	} else {
+		if err := s.enableCronEntry(ctx, schedule); err != nil {
+			return app.ScheduledUpdates{}, err
+		}
+		if _, err := s.removeUnits(); err != nil {
+			return app.ScheduledUpdates{}, err
+		}
+	}
+
+	return app.ScheduledUpdates{Enabled: true, Backend: backend, Schedule: schedule}, nil
+}
+
+func (s Scheduler) Disable(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	systemd := s.systemdAvailable(ctx)
+	if systemd && s.unitsExist() {
+		if _, err := s.systemctl(ctx, "disable", "--now", timerUnit); err != nil {
+			return false, err
+		}
+	}
+	unitsRemoved, err := s.removeUnits()
+	if err != nil {
+		return false, err
+	}
+	if systemd && unitsRemoved {
+		if _, err := s.systemctl(ctx, "daemon-reload"); err != nil {
+			return false, err
+		}
+	}
+
+	cronRemoved, err := s.removeCronEntry(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return unitsRemoved || cronRemoved, nil
+}
+
+func (s Scheduler) Status(ctx context.Context) (app.ScheduledUpdates, error) {
+	if err := ctx.Err(); err != nil {
+		return app.ScheduledUpdates{}, err
+	}
+
+	timer, err := os.ReadFile(filepath.Join(s.UnitDir, timerUnit))
+	if err == nil {
+		service, err := os.ReadFile(filepath.Join(s.UnitDir, serviceUnit))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return app.ScheduledUpdates{}, fmt.Errorf("read %q: %w", serviceUnit, err)
+		}
+		return app.ScheduledUpdates{
+			Enabled: true,
+			Backend: backendSystemd,
+			Schedule: app.UpdateSchedule{
+				Interval:  app.UpdateInterval(unitValue(string(timer), "OnCalendar")),
+				CheckOnly: checkOnlyCommand(unitValue(string(service), "ExecStart")),
+			},
+			LastRun: s.lastServiceRun(ctx),
+		}, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return app.ScheduledUpdates{}, fmt.Errorf("read %q: %w", timerUnit, err)
+	}
+
+	lines, err := s.readCrontab(ctx)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return app.ScheduledUpdates{}, nil
+		}
+		return app.ScheduledUpdates{}, err
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, cronMarker) {
+			continue
+		}
+		fields := strings.Fields(line)
+		return app.ScheduledUpdates{
+			Enabled: true,
+			Backend: backendCron,
+			Schedule: app.UpdateSchedule{
+				Interval:  app.UpdateInterval(strings.TrimPrefix(fields[0], "@")),
+				CheckOnly: checkOnlyCommand(line),
+			},
+		}, nil
+	}
+
+	return app.ScheduledUpdates{}, nil
+}
+
+func (s Scheduler) systemdAvailable(ctx context.Context) bool {
+	_, err := s.systemctl(ctx, "show-environment")
+	return err == nil
+}
+
+func (s Scheduler) systemctl(ctx context.Context, args ...string) ([]byte, error) {
+	return s.Run(ctx, nil, "systemctl", append([]string{"--user"}, args...)...)
+}
+
+func (s Scheduler) enableTimer(ctx context.Context, schedule app.UpdateSchedule) error {
+	if err := os.MkdirAll(s.UnitDir, 0o755); err != nil {
+		return fmt.Errorf("create systemd unit directory %q: %w", s.UnitDir, err)
+	}
+
+	units := []struct {
+		name    string
+		content string
+	}{
+		{serviceUnit, s.serviceUnit(schedule)},
+		{timerUnit, timerUnitContent(schedule)},
+	}
+	for _, unit := range units {
+		path := filepath.Join(s.UnitDir, unit.name)
+		if err := os.WriteFile(path, []byte(unit.content), 0o644); err != nil {
+			return fmt.Errorf("write systemd unit %q: %w", path, err)
+		}
+	}
+
+	// Restarting rather than starting the timer applies a changed interval
+	// to a timer that is already running.
+	for _, args := range [][]string{
+		{"daemon-reload"},
+		{"enable", timerUnit},
+		{"restart", timerUnit},
+	} {
+		if _, err := s.systemctl(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s Scheduler) serviceUnit(schedule app.UpdateSchedule) string {
+	return strings.Join([]string{
+		"# Managed by aim autoupdate; changes are overwritten.",
+		"[Unit]",
+		"Description=Update AppImages managed by aim",
+		"Wants=network-online.target",
+		"After=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=" + systemdQuote(s.Executable) + " " + updateArgs(schedule),
+		"",
+	}, "\n")
+}
+
+func timerUnitContent(schedule app.UpdateSchedule) string {
+	return strings.Join([]string{
+		"# Managed by aim autoupdate; changes are overwritten.",
+		"[Unit]",
+		"Description=Run aim update " + string(schedule.Interval),
+		"",
+		"[Timer]",
+		"OnCalendar=" + string(schedule.Interval),
+		"Persistent=true",
+		"RandomizedDelaySec=10min",
+		"",
+		"[Install]",
+		"WantedBy=timers.target",
+		"",
+	}, "\n")
+}
+
+func (s Scheduler) unitsExist() bool {
+	for _, name := range []string{serviceUnit, timerUnit} {
+		if _, err := os.Lstat(filepath.Join(s.UnitDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scheduler) removeUnits() (bool, error) {
+	removed := false
+	for _, name := range []string{timerUnit, serviceUnit} {
+		path := filepath.Join(s.UnitDir, name)
+		err := os.Remove(path)
+		if err == nil {
+			removed = true
+			continue
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return removed, fmt.Errorf("remove systemd unit %q: %w", path, err)
+		}
+	}
+	return removed, nil
+}
+
+// lastServiceRun reads the outcome of the last run from systemd. It returns
+// nil when the service never ran or systemd cannot tell.
+func (s Scheduler) lastServiceRun(ctx context.Context) *app.ScheduledRun {
+	out, err := s.systemctl(ctx, "show", serviceUnit, "--timestamp=unix", "--property=Result,ExecMainStatus,ExecMainExitTimestamp")
+	if err != nil {
+		return nil
+	}
+
+	properties := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			properties[key] = value
+		}
+	}
+	seconds, err := strconv.ParseInt(strings.TrimPrefix(properties["ExecMainExitTimestamp"], "@"), 10, 64)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+
+	run := &app.ScheduledRun{
+		Time:      time.Unix(seconds, 0).UTC(),
+		Succeeded: properties["Result"] == "success",
+		Result:    properties["Result"],
+	}
+	if status := properties["ExecMainStatus"]; status != "" && status != "0" {
+		run.Result = fmt.Sprintf("%s (status %s)", run.Result, status)
+	}
+	return run
+}
+
+func (s Scheduler) enableCronEntry(ctx context.Context, schedule app.UpdateSchedule) error {
+	lines, err := s.readCrontab(ctx)
+	if err != nil {
+		return err
+	}
+
+	lines = withoutCronEntry(lines)
+	lines = append(lines, s.cronEntry(schedule))
+	return s.writeCrontab(ctx, lines)
+}
+
+func (s Scheduler) removeCronEntry(ctx context.Context) (bool, error) {
+	lines, err := s.readCrontab(ctx)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	kept := withoutCronEntry(lines)
+	if len(kept) == len(lines) {
+		return false, nil
+	}
+	return true, s.writeCrontab(ctx, kept)
+}
+
+// cronEntry runs aim with standard output discarded, so cron only mails the
+// errors of a run.
+func (s Scheduler) cronEntry(schedule app.UpdateSchedule) string {
+	command := shellQuote(s.Executable) + " " + updateArgs(schedule) + " >/dev/null"
+	if s.RuntimeDir != "" {
+		command = "XDG_RUNTIME_DIR=" + shellQuote(s.RuntimeDir) + " " + command
+	}
+	// cron turns unescaped percent signs into newlines.
+	command = strings.ReplaceAll(command, "%", `\%`)
+	return "@" + string(schedule.Interval) + " " + command + " " + cronMarker
+}
+
+func (s Scheduler) readCrontab(ctx context.Context) ([]string, error) {
+	out, err := s.Run(ctx, nil, "crontab", "-l")
+	if err != nil {
+		if strings.Contains(err.Error(), "no crontab for") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	content := strings.TrimRight(string(out), "\n")
+	if content == "" {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
+
+func (s Scheduler) writeCrontab(ctx context.Context, lines []string) error {
+	content := ""
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n") + "\n"
+	}
+	_, err := s.Run(ctx, []byte(content), "crontab", "-")
+	return err
+}
+
+func withoutCronEntry(lines []string) []string {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if !strings.HasSuffix(line, cronMarker) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func updateArgs(schedule app.UpdateSchedule) string {
+	if schedule.CheckOnly {
+		return "update --notify"
+	}
+	return "update --yes --notify"
+}
+
+func checkOnlyCommand(command string) bool {
+	return strings.Contains(command, " update --notify")
+}
+
+// unitValue returns the value of the first key= line in a unit file.
+func unitValue(content string, key string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), key+"="); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// systemdQuote quotes a path for ExecStart. Percent signs start specifiers in
+// unit files and are doubled.
+func systemdQuote(value string) string {
+	value = strings.ReplaceAll(value, "%", "%%")
+	if !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// RunCommand runs name with stdin and returns its standard output. Errors
+// include what the command wrote to standard error.
+func RunCommand(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		command := strings.TrimSpace(name + " " + strings.Join(args, " "))
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return out, fmt.Errorf("run %s: %w: %s", command, err, message)
+		}
+		return out, fmt.Errorf("run %s: %w", command, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,265 @@
+package autoupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestSchedulerEnablesSystemdTimer(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{systemd: true, crontab: "MAILTO=me\n@daily '/old/aim' update --yes >/dev/null # aim autoupdate\n"}
+	scheduler := host.scheduler(t)
+
+	scheduled, err := scheduler.Enable(context.Background(), app.UpdateSchedule{Interval: app.UpdateIntervalWeekly, CheckOnly: true})
+	if err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+
+	if scheduled != (app.ScheduledUpdates{Enabled: true, Backend: "systemd", Schedule: app.UpdateSchedule{Interval: app.UpdateIntervalWeekly, CheckOnly: true}}) {
+		t.Fatalf("Enable() = %#v", scheduled)
+	}
+	service := readFile(t, filepath.Join(scheduler.UnitDir, serviceUnit))
+	if !strings.Contains(service, "\nExecStart=\"/opt/my apps/aim\" update --notify\n") {
+		t.Fatalf("service unit = %q, want quoted check-only ExecStart", service)
+	}
+	timer := readFile(t, filepath.Join(scheduler.UnitDir, timerUnit))
+	if !strings.Contains(timer, "\nOnCalendar=weekly\n") || !strings.Contains(timer, "\nWantedBy=timers.target\n") {
+		t.Fatalf("timer unit = %q, want weekly timer", timer)
+	}
+	wantCalls := []string{
+		"systemctl --user show-environment",
+		"systemctl --user daemon-reload",
+		"systemctl --user enable aim-update.timer",
+		"systemctl --user restart aim-update.timer",
+		"crontab -l",
+		"crontab -",
+	}
+	if !reflect.DeepEqual(host.calls, wantCalls) {
+		t.Fatalf("commands = %#v, want %#v", host.calls, wantCalls)
+	}
+	if host.crontab != "MAILTO=me\n" {
+		t.Fatalf("crontab = %q, want previous cron entry removed", host.crontab)
+	}
+}
+
+func TestSchedulerFallsBackToCrontab(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{crontab: "MAILTO=me\n"}
+	scheduler := host.scheduler(t)
+
+	scheduled, err := scheduler.Enable(context.Background(), app.UpdateSchedule{Interval: app.UpdateIntervalDaily})
+	if err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+
+	if scheduled.Backend != "cron" {
+		t.Fatalf("Enable().Backend = %q, want cron", scheduled.Backend)
+	}
+	want := "MAILTO=me\n@daily XDG_RUNTIME_DIR='/run/user/1000' '/opt/my apps/aim' update --yes --notify >/dev/null # aim autoupdate\n"
+	if host.crontab != want {
+		t.Fatalf("crontab = %q, want %q", host.crontab, want)
+	}
+	if _, err := os.Stat(filepath.Join(scheduler.UnitDir, timerUnit)); !os.IsNotExist(err) {
+		t.Fatalf("timer unit stat error = %v, want not exist", err)
+	}
+
+	status, err := scheduler.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status != (app.ScheduledUpdates{Enabled: true, Backend: "cron", Schedule: app.UpdateSchedule{Interval: app.UpdateIntervalDaily}}) {
+		t.Fatalf("Status() = %#v", status)
+	}
+}
+
+func TestSchedulerStatusReadsSystemdLastRun(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{
+		systemd: true,
+		show:    "Result=exit-code\nExecMainStatus=1\nExecMainExitTimestamp=@1777777777\n",
+	}
+	scheduler := host.scheduler(t)
+	if _, err := scheduler.Enable(context.Background(), app.UpdateSchedule{Interval: app.UpdateIntervalHourly}); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+
+	status, err := scheduler.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if !status.Enabled || status.Backend != "systemd" || status.Schedule != (app.UpdateSchedule{Interval: app.UpdateIntervalHourly}) {
+		t.Fatalf("Status() = %#v", status)
+	}
+	want := &app.ScheduledRun{Time: time.Unix(1777777777, 0).UTC(), Result: "exit-code (status 1)"}
+	if !reflect.DeepEqual(status.LastRun, want) {
+		t.Fatalf("Status().LastRun = %#v, want %#v", status.LastRun, want)
+	}
+}
+
+func TestSchedulerStatusWithoutRunOrSchedule(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{systemd: true, show: "Result=success\nExecMainStatus=0\nExecMainExitTimestamp=\n"}
+	scheduler := host.scheduler(t)
+
+	status, err := scheduler.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Enabled {
+		t.Fatalf("Status() = %#v, want disabled", status)
+	}
+
+	if _, err := scheduler.Enable(context.Background(), app.UpdateSchedule{Interval: app.UpdateIntervalDaily}); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	status, err = scheduler.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.LastRun != nil {
+		t.Fatalf("Status().LastRun = %#v, want nil before the first run", status.LastRun)
+	}
+}
+
+func TestSchedulerDisableRemovesTimerAndCronEntry(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{systemd: true}
+	scheduler := host.scheduler(t)
+	if _, err := scheduler.Enable(context.Background(), app.UpdateSchedule{Interval: app.UpdateIntervalDaily}); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	host.crontab = "@daily '/old/aim' update --yes >/dev/null # aim autoupdate\n"
+	host.calls = nil
+
+	disabled, err := scheduler.Disable(context.Background())
+	if err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+
+	if !disabled {
+		t.Fatal("Disable() = false, want true")
+	}
+	if scheduler.unitsExist() {
+		t.Fatal("systemd units still exist after Disable()")
+	}
+	if host.crontab != "" {
+		t.Fatalf("crontab = %q, want empty", host.crontab)
+	}
+	wantCalls := []string{
+		"systemctl --user show-environment",
+		"systemctl --user disable --now aim-update.timer",
+		"systemctl --user daemon-reload",
+		"crontab -l",
+		"crontab -",
+	}
+	if !reflect.DeepEqual(host.calls, wantCalls) {
+		t.Fatalf("commands = %#v, want %#v", host.calls, wantCalls)
+	}
+
+	disabled, err = scheduler.Disable(context.Background())
+	if err != nil || disabled {
+		t.Fatalf("second Disable() = %v, %v, want false, nil", disabled, err)
+	}
+}
+
+func TestSchedulerDisableWithoutCrontab(t *testing.T) {
+	t.Parallel()
+
+	host := &fakeHost{noCrontab: true}
+	scheduler := host.scheduler(t)
+
+	disabled, err := scheduler.Disable(context.Background())
+	if err != nil || disabled {
+		t.Fatalf("Disable() = %v, %v, want false, nil", disabled, err)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"/usr/bin/aim":       "/usr/bin/aim",
+		"/opt/my apps/aim":   `"/opt/my apps/aim"`,
+		`/opt/"quoted"/aim`:  `"/opt/\"quoted\"/aim"`,
+		"/opt/100%/aim":      "/opt/100%%/aim",
+		`/opt/back\slash/ai`: `"/opt/back\\slash/ai"`,
+	}
+	for value, want := range tests {
+		if got := systemdQuote(value); got != want {
+			t.Fatalf("systemdQuote(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// fakeHost stands in for systemctl and crontab.
+type fakeHost struct {
+	systemd   bool
+	noCrontab bool
+	crontab   string
+	show      string
+	calls     []string
+}
+
+func (h *fakeHost) scheduler(t *testing.T) Scheduler {
+	t.Helper()
+	return Scheduler{
+		UnitDir:    filepath.Join(t.TempDir(), "systemd", "user"),
+		Executable: "/opt/my apps/aim",
+		RuntimeDir: "/run/user/1000",
+		Run:        h.run,
+	}
+}
+
+func (h *fakeHost) run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	h.calls = append(h.calls, strings.Join(append([]string{name}, args...), " "))
+
+	switch name {
+	case "systemctl":
+		if !h.systemd {
+			return nil, errors.New("run systemctl --user: exit status 1: Failed to connect to bus")
+		}
+		if args[1] == "show" && len(args) > 2 && args[2] == serviceUnit {
+			return []byte(h.show), nil
+		}
+		return nil, nil
+	case "crontab":
+		if h.noCrontab {
+			return nil, fmt.Errorf("run crontab: %w", exec.ErrNotFound)
+		}
+		if args[0] == "-" {
+			h.crontab = string(stdin)
+			return nil, nil
+		}
+		if h.crontab == "" {
+			return nil, errors.New("run crontab -l: exit status 1: no crontab for user")
+		}
+		return []byte(h.crontab), nil
+	}
+
+	return nil, fmt.Errorf("unexpected command %s", name)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) error = %v", path, err)
+	}
+	return string(content)
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/infra/xdg"
@@ -13,16 +15,41 @@ import (
 	"github.com/pelletier/go-toml/v2"
 )
 
+// DefaultUndoRetention is how long replaced and removed artifacts are kept for
+// aim undo unless undo_retention says otherwise. Their disk space is only
+// freed once it has passed.
+const DefaultUndoRetention = 7 * 24 * time.Hour
+
 type fileConfig struct {
-	AppImageDir string `toml:"appimage_dir"`
+	AppImageDir      string      `toml:"appimage_dir"`
+	DownloadDir      string      `toml:"download_dir"`
+	BinDir           string      `toml:"bin_dir"`
+	TrashRemovedApps bool        `toml:"trash_removed_apps"`
+	RasterizeIcons   bool        `toml:"rasterize_icons"`
+	UndoRetention    string      `toml:"undo_retention"`
+	Hooks            hooksConfig `toml:"hooks"`
+}
+
+type hooksConfig struct {
+	PreAdd     string `toml:"pre_add"`
+	PostAdd    string `toml:"post_add"`
+	PreUpdate  string `toml:"pre_update"`
+	PostUpdate string `toml:"post_update"`
+	PreRemove  string `toml:"pre_remove"`
+	PostRemove string `toml:"post_remove"`
 }
 
 func DefaultAppConfig(dirs xdg.Dirs) app.Config {
 	return app.Config{
-		ConfigFile:  xdg.ConfigFile(dirs),
-		AppImageDir: xdg.DefaultAppImageDir(dirs),
-		DesktopDir:  xdg.DesktopDir(dirs),
-		IconDir:     xdg.IconDir(dirs),
+		ConfigFile:    xdg.ConfigFile(dirs),
+		ManifestFile:  xdg.ManifestFile(dirs),
+		AppImageDir:   xdg.DefaultAppImageDir(dirs),
+		DesktopDir:    xdg.DesktopDir(dirs),
+		IconDir:       xdg.IconDir(dirs),
+		DownloadDir:   xdg.DownloadDir(dirs),
+		BinDir:        xdg.BinDir(dirs),
+		Locale:        xdg.Locale(),
+		UndoRetention: DefaultUndoRetention,
 	}
 }
 
@@ -52,9 +79,69 @@ func Load(path string, dirs xdg.Dirs) (app.Config, error) {
 		cfg.AppImageDir = resolved
 	}
 
+	if fileCfg.DownloadDir != "" {
+		resolved, err := resolveUserPath(fileCfg.DownloadDir)
+		if err != nil {
+			return app.Config{}, fmt.Errorf("resolve download_dir: %w", err)
+		}
+
+		cfg.DownloadDir = resolved
+	}
+
+	if fileCfg.BinDir != "" {
+		resolved, err := resolveUserPath(fileCfg.BinDir)
+		if err != nil {
+			return app.Config{}, fmt.Errorf("resolve bin_dir: %w", err)
+		}
+
+		cfg.BinDir = resolved
+	}
+
+	cfg.TrashRemovedApps = fileCfg.TrashRemovedApps
+	cfg.RasterizeIcons = fileCfg.RasterizeIcons
+	cfg.Hooks = app.Hooks{
+		PreAdd:     strings.TrimSpace(fileCfg.Hooks.PreAdd),
+		PostAdd:    strings.TrimSpace(fileCfg.Hooks.PostAdd),
+		PreUpdate:  strings.TrimSpace(fileCfg.Hooks.PreUpdate),
+		PostUpdate: strings.TrimSpace(fileCfg.Hooks.PostUpdate),
+		PreRemove:  strings.TrimSpace(fileCfg.Hooks.PreRemove),
+		PostRemove: strings.TrimSpace(fileCfg.Hooks.PostRemove),
+	}
+
+	if fileCfg.UndoRetention != "" {
+		retention, err := parseRetention(fileCfg.UndoRetention)
+		if err != nil {
+			return app.Config{}, fmt.Errorf("parse undo_retention: %w", err)
+		}
+
+		cfg.UndoRetention = retention
+	}
+
 	return cfg, nil
 }
 
+// parseRetention accepts a Go duration or a number of days such as "14d".
+// "0" turns retention off.
+func parseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	retention, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if retention < 0 {
+		return 0, fmt.Errorf("negative duration %q", value)
+	}
+	return retention, nil
+}
+
 func resolveUserPath(path string) (string, error) {
 	path = strings.TrimSpace(path)
 	if path == "" {
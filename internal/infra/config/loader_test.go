@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/infra/xdg"
@@ -15,10 +16,15 @@ func TestDefaultAppConfigMapsXDGDirs(t *testing.T) {
 
 	got := DefaultAppConfig(dirs)
 	want := app.Config{
-		ConfigFile:  filepath.Join(dirs.ConfigHome, xdg.AppName, "config.toml"),
-		AppImageDir: filepath.Join(dirs.DataHome, xdg.AppName, "appimages"),
-		DesktopDir:  filepath.Join(dirs.DataHome, "applications"),
-		IconDir:     filepath.Join(dirs.DataHome, "icons"),
+		ConfigFile:    filepath.Join(dirs.ConfigHome, xdg.AppName, "config.toml"),
+		ManifestFile:  filepath.Join(dirs.ConfigHome, xdg.AppName, "manifest.toml"),
+		AppImageDir:   filepath.Join(dirs.DataHome, xdg.AppName, "appimages"),
+		DesktopDir:    filepath.Join(dirs.DataHome, "applications"),
+		IconDir:       filepath.Join(dirs.DataHome, "icons"),
+		DownloadDir:   xdg.DownloadDir(dirs),
+		BinDir:        xdg.BinDir(dirs),
+		Locale:        xdg.Locale(),
+		UndoRetention: DefaultUndoRetention,
 	}
 
 	if got != want {
@@ -57,6 +63,83 @@ func TestLoadOverridesOnlyAppImageDir(t *testing.T) {
 	}
 }
 
+func TestLoadEnablesTrashForRemovedApps(t *testing.T) {
+	dirs := testDirs(t)
+	path := writeConfigFile(t, "trash_removed_apps = true\n")
+
+	got, err := Load(path, dirs)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := DefaultAppConfig(dirs)
+	want.TrashRemovedApps = true
+	if got != want {
+		t.Fatalf("Load() = %#v, want %#v", got, want)
+	}
+}
+
+func TestLoadEnablesIconRasterizing(t *testing.T) {
+	dirs := testDirs(t)
+	path := writeConfigFile(t, "rasterize_icons = true\n")
+
+	got, err := Load(path, dirs)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := DefaultAppConfig(dirs)
+	want.RasterizeIcons = true
+	if got != want {
+		t.Fatalf("Load() = %#v, want %#v", got, want)
+	}
+}
+
+func TestLoadReadsHooks(t *testing.T) {
+	dirs := testDirs(t)
+	path := writeConfigFile(t, strings.Join([]string{
+		"[hooks]",
+		"pre_add = \"/usr/local/bin/check-app\"",
+		"post_update = \"endpoint-manager register \\\"$AIM_APP_ID\\\"\"",
+		"",
+	}, "\n"))
+
+	got, err := Load(path, dirs)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := app.Hooks{PreAdd: "/usr/local/bin/check-app", PostUpdate: `endpoint-manager register "$AIM_APP_ID"`}
+	if got.Hooks != want {
+		t.Fatalf("Hooks = %#v, want %#v", got.Hooks, want)
+	}
+}
+
+func TestLoadParsesUndoRetention(t *testing.T) {
+	dirs := testDirs(t)
+	tests := map[string]time.Duration{
+		"14d": 14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"0":   0,
+	}
+	for value, want := range tests {
+		path := writeConfigFile(t, "undo_retention = \""+value+"\"\n")
+
+		got, err := Load(path, dirs)
+		if err != nil {
+			t.Fatalf("Load(%q) error = %v", value, err)
+		}
+		if got.UndoRetention != want {
+			t.Fatalf("UndoRetention for %q = %v, want %v", value, got.UndoRetention, want)
+		}
+	}
+
+	path := writeConfigFile(t, "undo_retention = \"a week\"\n")
+	if _, err := Load(path, dirs); err == nil || !strings.Contains(err.Error(), "undo_retention") {
+		t.Fatalf("Load() error = %v, want undo_retention error", err)
+	}
+}
+
 func TestLoadExpandsHomeRelativeAppImageDir(t *testing.T) {
 	dirs := testDirs(t)
 	home := t.TempDir()
@@ -74,6 +157,40 @@ func TestLoadExpandsHomeRelativeAppImageDir(t *testing.T) {
 	}
 }
 
+func TestLoadExpandsHomeRelativeDownloadDir(t *testing.T) {
+	dirs := testDirs(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeConfigFile(t, "download_dir = \"~/Incoming\"\n")
+
+	got, err := Load(path, dirs)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := filepath.Join(home, "Incoming")
+	if got.DownloadDir != want {
+		t.Fatalf("DownloadDir = %q, want %q", got.DownloadDir, want)
+	}
+}
+
+func TestLoadExpandsHomeRelativeBinDir(t *testing.T) {
+	dirs := testDirs(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeConfigFile(t, "bin_dir = \"~/bin\"\n")
+
+	got, err := Load(path, dirs)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := filepath.Join(home, "bin")
+	if got.BinDir != want {
+		t.Fatalf("BinDir = %q, want %q", got.BinDir, want)
+	}
+}
+
 func TestLoadMalformedTOMLReturnsParseError(t *testing.T) {
 	dirs := testDirs(t)
 	path := writeConfigFile(t, "appimage_dir = [\n")
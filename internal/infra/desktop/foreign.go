@@ -0,0 +1,196 @@
+package desktop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+// AppImageLauncher and appimaged both name their desktop entries and icons
+// appimagekit_<md5>...; Gear Lever uses gearlever_<name>_<hash>.
+const (
+	appImageKitPrefix = "appimagekit_"
+	gearLeverPrefix   = "gearlever_"
+)
+
+// ForeignFinder finds desktop entries created by other AppImage integration
+// tools in the user's applications directory.
+type ForeignFinder struct {
+	DesktopDir string
+	IconDir    string
+}
+
+// NewForeignFinder creates a finder for foreign desktop integrations.
+func NewForeignFinder(desktopDir string, iconDir string) ForeignFinder {
+	return ForeignFinder{DesktopDir: desktopDir, IconDir: iconDir}
+}
+
+var _ app.ForeignIntegrationFinder = ForeignFinder{}
+
+// Find returns the AppImages tool has integrated, sorted by desktop entry
+// path. Entries whose AppImage no longer exists are skipped because there is
+// nothing left to migrate.
+func (f ForeignFinder) Find(ctx context.Context, tool app.MigrationTool) ([]app.ForeignIntegration, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(f.DesktopDir) == "" {
+		return nil, errors.New("desktop entry directory is required")
+	}
+
+	entries, err := os.ReadDir(f.DesktopDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read desktop entry directory %q: %w", f.DesktopDir, err)
+	}
+
+	var result []app.ForeignIntegration
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".desktop") {
+			continue
+		}
+
+		path := filepath.Join(f.DesktopDir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read desktop entry %q: %w", path, err)
+		}
+		desktopEntry, err := domain.ParseDesktopEntry(content)
+		if err != nil || !integratedBy(tool, entry.Name(), desktopEntry.Fields) {
+			continue
+		}
+
+		appImagePath := foreignAppImagePath(desktopEntry)
+		if !regularFile(appImagePath) {
+			continue
+		}
+
+		iconPaths, err := f.foreignIconPaths(tool, desktopEntry.Icon)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, app.ForeignIntegration{
+			Tool:             tool,
+			DesktopEntryPath: path,
+			DesktopEntry:     content,
+			AppImagePath:     appImagePath,
+			IconPaths:        iconPaths,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DesktopEntryPath < result[j].DesktopEntryPath
+	})
+
+	return result, nil
+}
+
+func integratedBy(tool app.MigrationTool, name string, fields map[string]string) bool {
+	launcher := hasFieldPrefix(fields, "X-AppImageLauncher") || fields["X-AppImage-Integrate"] != ""
+	switch tool {
+	case app.MigrationToolAppImageLauncher:
+		return strings.HasPrefix(name, appImageKitPrefix) && launcher
+	case app.MigrationToolAppImaged:
+		return strings.HasPrefix(name, appImageKitPrefix) && !launcher
+	case app.MigrationToolGearLever:
+		return strings.HasPrefix(name, gearLeverPrefix) || hasFieldPrefix(fields, "X-GearLever")
+	default:
+		return false
+	}
+}
+
+func hasFieldPrefix(fields map[string]string, prefix string) bool {
+	for key := range fields {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignAppImagePath prefers TryExec, which all supported tools set to the
+// AppImage, and falls back to the program of the Exec line.
+func foreignAppImagePath(entry domain.DesktopEntry) string {
+	if tryExec := strings.TrimSpace(entry.Fields["TryExec"]); filepath.IsAbs(tryExec) {
+		return filepath.Clean(tryExec)
+	}
+
+	program := desktopExecProgram(entry.Exec)
+	if !filepath.IsAbs(program) {
+		return ""
+	}
+	return filepath.Clean(program)
+}
+
+func desktopExecProgram(exec string) string {
+	exec = strings.TrimSpace(exec)
+	if strings.HasPrefix(exec, `"`) {
+		if end := strings.Index(exec[1:], `"`); end >= 0 {
+			return exec[1 : end+1]
+		}
+		return ""
+	}
+	program, _, _ := strings.Cut(exec, " ")
+	return program
+}
+
+// foreignIconPaths returns the icons the tool installed for iconName. Only
+// icons carrying the tool's own prefix are returned so shared theme icons are
+// never removed during migration.
+func (f ForeignFinder) foreignIconPaths(tool app.MigrationTool, iconName string) ([]string, error) {
+	iconName = strings.TrimSpace(iconName)
+	prefix := appImageKitPrefix
+	if tool == app.MigrationToolGearLever {
+		prefix = gearLeverPrefix
+	}
+
+	if filepath.IsAbs(iconName) {
+		if strings.HasPrefix(filepath.Base(iconName), prefix) && regularFile(iconName) {
+			return []string{filepath.Clean(iconName)}, nil
+		}
+		return nil, nil
+	}
+	if !strings.HasPrefix(iconName, prefix) || strings.TrimSpace(f.IconDir) == "" {
+		return nil, nil
+	}
+
+	patterns := []string{
+		filepath.Join(f.IconDir, "hicolor", "*", "apps", iconName+".*"),
+		filepath.Join(f.IconDir, iconName+".*"),
+	}
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("find icons for %q: %w", iconName, err)
+		}
+		for _, match := range matches {
+			if regularFile(match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func regularFile(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
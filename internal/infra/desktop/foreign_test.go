@@ -0,0 +1,104 @@
+package desktop
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestForeignFinderFindsAppImageLauncherIntegrations(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	desktopDir := filepath.Join(root, "applications")
+	iconDir := filepath.Join(root, "icons")
+	appImage := filepath.Join(root, "Applications", "Example_abc.AppImage")
+	writeFile(t, appImage, "appimage")
+	launcherEntry := filepath.Join(desktopDir, "appimagekit_abc-Example.desktop")
+	writeFile(t, launcherEntry, "[Desktop Entry]\nName=Example\nExec="+appImage+" %U\nTryExec="+appImage+"\nIcon=appimagekit_abc_example\nX-AppImage-Integrate=true\n")
+	launcherIcon := filepath.Join(iconDir, "hicolor", "128x128", "apps", "appimagekit_abc_example.png")
+	writeFile(t, launcherIcon, "png")
+	writeFile(t, filepath.Join(iconDir, "hicolor", "128x128", "apps", "example.png"), "shared")
+	writeFile(t, filepath.Join(desktopDir, "appimagekit_def-Other.desktop"), "[Desktop Entry]\nName=Other\nExec="+appImage+"\n")
+	writeFile(t, filepath.Join(desktopDir, "example.desktop"), "[Desktop Entry]\nName=Example\nExec="+appImage+"\nX-AppImage-Integrate=true\n")
+
+	found, err := NewForeignFinder(desktopDir, iconDir).Find(context.Background(), app.MigrationToolAppImageLauncher)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if len(found) != 1 {
+		t.Fatalf("Find() = %#v, want one integration", found)
+	}
+	if got, want := found[0].DesktopEntryPath, launcherEntry; got != want {
+		t.Fatalf("DesktopEntryPath = %q, want %q", got, want)
+	}
+	if got, want := found[0].AppImagePath, appImage; got != want {
+		t.Fatalf("AppImagePath = %q, want %q", got, want)
+	}
+	if len(found[0].IconPaths) != 1 || found[0].IconPaths[0] != launcherIcon {
+		t.Fatalf("IconPaths = %q, want [%q]", found[0].IconPaths, launcherIcon)
+	}
+}
+
+func TestForeignFinderSeparatesAppImagedFromAppImageLauncher(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	desktopDir := filepath.Join(root, "applications")
+	appImage := filepath.Join(root, "Example.AppImage")
+	writeFile(t, appImage, "appimage")
+	writeFile(t, filepath.Join(desktopDir, "appimagekit_abc-Example.desktop"), "[Desktop Entry]\nName=Example\nExec=\""+appImage+"\" %U\n")
+	writeFile(t, filepath.Join(desktopDir, "appimagekit_def-Launcher.desktop"), "[Desktop Entry]\nName=Launcher\nExec="+appImage+"\nX-AppImageLauncher-Version=2.2.0\n")
+
+	found, err := NewForeignFinder(desktopDir, "").Find(context.Background(), app.MigrationToolAppImaged)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if len(found) != 1 || filepath.Base(found[0].DesktopEntryPath) != "appimagekit_abc-Example.desktop" {
+		t.Fatalf("Find() = %#v, want only the appimaged entry", found)
+	}
+	if got, want := found[0].AppImagePath, appImage; got != want {
+		t.Fatalf("AppImagePath = %q, want %q", got, want)
+	}
+}
+
+func TestForeignFinderFindsGearLeverIntegrationsAndSkipsMissingAppImages(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	desktopDir := filepath.Join(root, "applications")
+	appImage := filepath.Join(root, "AppImages", "example.appimage")
+	writeFile(t, appImage, "appimage")
+	icon := filepath.Join(root, "AppImages", ".icons", "gearlever_example_1a2b.png")
+	writeFile(t, icon, "png")
+	writeFile(t, filepath.Join(desktopDir, "gearlever_example_1a2b.desktop"), "[Desktop Entry]\nName=Example\nExec="+appImage+"\nIcon="+icon+"\n")
+	writeFile(t, filepath.Join(desktopDir, "gearlever_missing_3c4d.desktop"), "[Desktop Entry]\nName=Missing\nExec="+filepath.Join(root, "missing.appimage")+"\n")
+
+	found, err := NewForeignFinder(desktopDir, "").Find(context.Background(), app.MigrationToolGearLever)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if len(found) != 1 || found[0].AppImagePath != appImage {
+		t.Fatalf("Find() = %#v, want gear lever integration for %s", found, appImage)
+	}
+	if len(found[0].IconPaths) != 1 || found[0].IconPaths[0] != icon {
+		t.Fatalf("IconPaths = %q, want [%q]", found[0].IconPaths, icon)
+	}
+}
+
+func TestForeignFinderIgnoresMissingDesktopDir(t *testing.T) {
+	t.Parallel()
+
+	found, err := NewForeignFinder(filepath.Join(t.TempDir(), "missing"), "").Find(context.Background(), app.MigrationToolAppImaged)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("Find() = %#v, want none", found)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 
@@ -16,11 +17,14 @@ import (
 type Refresher struct {
 	DesktopDir string
 	IconDir    string
+	// MimeDir is the user's shared MIME database, rebuilt once it has
+	// packages.
+	MimeDir string
 }
 
 // NewRefresher creates a desktop integration refresher.
-func NewRefresher(desktopDir string, iconDir string) Refresher {
-	return Refresher{DesktopDir: desktopDir, IconDir: iconDir}
+func NewRefresher(desktopDir string, iconDir string, mimeDir string) Refresher {
+	return Refresher{DesktopDir: desktopDir, IconDir: iconDir, MimeDir: mimeDir}
 }
 
 var _ app.DesktopIntegrationRefresher = Refresher{}
@@ -33,8 +37,16 @@ func (r Refresher) Refresh(ctx context.Context) error {
 		return err
 	}
 
+	// update-mime-database fails for a database without packages, which
+	// users who never added an app bundling MIME types do not have.
+	mimeDir := r.MimeDir
+	if _, err := os.Stat(filepath.Join(mimeDir, "packages")); mimeDir != "" && err != nil {
+		mimeDir = ""
+	}
+
 	commands := []refreshCommand{
 		{name: "update-desktop-database", args: []string{r.DesktopDir}, requiredPath: &r.DesktopDir},
+		{name: "update-mime-database", args: []string{mimeDir}, requiredPath: &mimeDir},
 		{name: "gtk-update-icon-cache", args: []string{"-f", "-t", filepath.Join(r.IconDir, "hicolor")}, requiredPath: &r.IconDir},
 		{name: "xdg-desktop-menu", args: []string{"forceupdate"}},
 		{name: "xdg-icon-resource", args: []string{"forceupdate"}},
@@ -65,6 +65,34 @@ func TestRefresherSkipsPathSpecificCommandsWhenPathsMissing(t *testing.T) {
 	}
 }
 
+func TestRefresherUpdatesMimeDatabaseOnceItHasPackages(t *testing.T) {
+	binDir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "refresh.log")
+	writeRefreshCommand(t, binDir, "update-mime-database", logPath, "exit 0")
+	writeRefreshCommand(t, binDir, "xdg-desktop-menu", logPath, "exit 0")
+	t.Setenv("PATH", binDir)
+	mimeDir := filepath.Join(t.TempDir(), "mime")
+	refresher := Refresher{MimeDir: mimeDir}
+
+	if err := refresher.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got, want := readRefreshLog(t, logPath), []string{"xdg-desktop-menu forceupdate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls without packages = %#v, want %#v", got, want)
+	}
+
+	if err := os.MkdirAll(filepath.Join(mimeDir, "packages"), 0o755); err != nil {
+		t.Fatalf("create packages directory: %v", err)
+	}
+	if err := refresher.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	want := []string{"xdg-desktop-menu forceupdate", "update-mime-database " + mimeDir, "xdg-desktop-menu forceupdate"}
+	if got := readRefreshLog(t, logPath); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %#v, want %#v", got, want)
+	}
+}
+
 func TestRefresherReturnsCommandFailures(t *testing.T) {
 	binDir := t.TempDir()
 	logPath := filepath.Join(t.TempDir(), "refresh.log")
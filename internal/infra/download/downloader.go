@@ -2,6 +2,8 @@ package download
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -66,7 +68,8 @@ func (d Downloader) Download(ctx context.Context, source app.DownloadSource, des
 		reader = io.LimitReader(resp.Body, source.SizeBytes+1)
 	}
 
-	written, copyErr := copyWithProgress(ctx, destination, reader, progress)
+	hash := sha256.New()
+	written, copyErr := copyWithProgress(ctx, io.MultiWriter(destination, hash), reader, progress)
 	closeErr := destination.Close()
 	if copyErr != nil {
 		_ = os.Remove(temporaryPath)
@@ -80,6 +83,11 @@ func (d Downloader) Download(ctx context.Context, source app.DownloadSource, des
 		_ = os.Remove(temporaryPath)
 		return app.DownloadedFile{}, fmt.Errorf("download %q: size mismatch: expected %d bytes, wrote %d bytes", source.URL, source.SizeBytes, written)
 	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if expected := strings.TrimSpace(source.SHA256); expected != "" && !strings.EqualFold(expected, digest) {
+		_ = os.Remove(temporaryPath)
+		return app.DownloadedFile{}, fmt.Errorf("download %q: sha256 mismatch: expected %s, got %s", source.URL, strings.ToLower(expected), digest)
+	}
 	if err := ctx.Err(); err != nil {
 		_ = os.Remove(temporaryPath)
 		return app.DownloadedFile{}, err
@@ -89,7 +97,7 @@ func (d Downloader) Download(ctx context.Context, source app.DownloadSource, des
 		return app.DownloadedFile{}, fmt.Errorf("replace download %q: %w", destinationPath, err)
 	}
 
-	return app.DownloadedFile{Path: destinationPath, SizeBytes: written}, nil
+	return app.DownloadedFile{Path: destinationPath, SizeBytes: written, SHA256: digest}, nil
 }
 
 func copyWithProgress(ctx context.Context, dst io.Writer, src io.Reader, progress app.DownloadProgress) (int64, error) {
@@ -2,6 +2,8 @@ package download
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
@@ -55,6 +57,39 @@ func TestDownloaderDownloadsFile(t *testing.T) {
 	}
 }
 
+func TestDownloaderVerifiesSHA256(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello appimage")
+	}))
+	defer server.Close()
+
+	digest := sha256.Sum256([]byte("hello appimage"))
+	want := hex.EncodeToString(digest[:])
+	destination := filepath.Join(t.TempDir(), "Example.AppImage")
+
+	result, err := (Downloader{}).Download(context.Background(), app.DownloadSource{URL: server.URL, SHA256: strings.ToUpper(want)}, destination, nil)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if result.SHA256 != want {
+		t.Fatalf("DownloadedFile.SHA256 = %q, want %q", result.SHA256, want)
+	}
+
+	mismatched := filepath.Join(t.TempDir(), "Other.AppImage")
+	_, err = (Downloader{}).Download(context.Background(), app.DownloadSource{URL: server.URL, SHA256: strings.Repeat("0", 64)}, mismatched, nil)
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("Download() error = %v, want sha256 mismatch", err)
+	}
+	if _, err := os.Stat(mismatched); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("destination stat error = %v, want not exist", err)
+	}
+	if _, err := os.Stat(mismatched + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("temporary file stat error = %v, want not exist", err)
+	}
+}
+
 func TestDownloaderReturnsHTTPError(t *testing.T) {
 	t.Parallel()
 
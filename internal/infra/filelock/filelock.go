@@ -0,0 +1,100 @@
+// Package filelock provides advisory flock-based locks shared between aim
+// processes.
+package filelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultTimeout bounds how long a lock is waited for before giving up.
+const DefaultTimeout = 10 * time.Second
+
+const pollInterval = 50 * time.Millisecond
+
+// Acquire takes an exclusive advisory lock on path, creating the file if
+// needed. It polls until the lock is free, ctx is done, or timeout elapses, in
+// which case the error wraps app.ErrBusy.
+func Acquire(ctx context.Context, path string, timeout time.Duration) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create lock directory %q: %w", filepath.Dir(path), err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file %q: %w", path, err)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if err == nil {
+			return func() {
+				_ = unix.Flock(int(file.Fd()), unix.LOCK_UN)
+				_ = file.Close()
+			}, nil
+		}
+		if !errors.Is(err, unix.EWOULDBLOCK) && !errors.Is(err, unix.EINTR) {
+			_ = file.Close()
+			return nil, fmt.Errorf("lock %q: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = file.Close()
+			return nil, ctx.Err()
+		case <-deadline.C:
+			_ = file.Close()
+			return nil, fmt.Errorf("%w: gave up waiting for %s after %s", app.ErrBusy, path, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// AppLocker locks individual apps with one lock file per app ID in Dir.
+type AppLocker struct {
+	Dir     string
+	Timeout time.Duration
+}
+
+// NewAppLocker creates an app locker that keeps lock files in dir.
+func NewAppLocker(dir string) AppLocker {
+	return AppLocker{Dir: dir, Timeout: DefaultTimeout}
+}
+
+var _ app.AppLocker = AppLocker{}
+
+func (l AppLocker) LockApp(ctx context.Context, id string) (func(), error) {
+	id = strings.TrimSpace(id)
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return nil, fmt.Errorf("lock app: invalid app id %q", id)
+	}
+	if strings.TrimSpace(l.Dir) == "" {
+		return nil, errors.New("lock directory is required")
+	}
+
+	unlock, err := Acquire(ctx, filepath.Join(l.Dir, id+".lock"), l.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("lock app %s: %w", id, err)
+	}
+	return unlock, nil
+}
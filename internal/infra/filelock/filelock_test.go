@@ -0,0 +1,73 @@
+package filelock
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestAcquireTimesOutWhileLocked(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "apps.json.lock")
+	unlock, err := Acquire(context.Background(), path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	if _, err := Acquire(context.Background(), path, 100*time.Millisecond); !errors.Is(err, app.ErrBusy) {
+		t.Fatalf("second Acquire() error = %v, want ErrBusy", err)
+	}
+
+	unlock()
+	unlockAgain, err := Acquire(context.Background(), path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() after unlock error = %v", err)
+	}
+	unlockAgain()
+}
+
+func TestAcquireHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "apps.json.lock")
+	unlock, err := Acquire(context.Background(), path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, path, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() error = %v, want context deadline", err)
+	}
+}
+
+func TestAppLockerLocksPerApp(t *testing.T) {
+	t.Parallel()
+
+	locker := AppLocker{Dir: t.TempDir(), Timeout: 100 * time.Millisecond}
+	unlock, err := locker.LockApp(context.Background(), "example")
+	if err != nil {
+		t.Fatalf("LockApp() error = %v", err)
+	}
+	defer unlock()
+
+	other, err := locker.LockApp(context.Background(), "other")
+	if err != nil {
+		t.Fatalf("LockApp(other) error = %v", err)
+	}
+	other()
+
+	if _, err := locker.LockApp(context.Background(), "example"); !errors.Is(err, app.ErrBusy) {
+		t.Fatalf("LockApp(example) error = %v, want ErrBusy", err)
+	}
+	if _, err := locker.LockApp(context.Background(), "../example"); err == nil {
+		t.Fatal("LockApp() accepted an ID with a path separator")
+	}
+}
@@ -225,6 +225,7 @@ type githubReleaseAssetResponse struct {
 	BrowserDownloadURL string `json:"browser_download_url"`
 	ContentType        string `json:"content_type"`
 	Size               int64  `json:"size"`
+	Digest             string `json:"digest"`
 }
 
 func (r githubReleaseResponse) toAppRelease(repo string) app.GitHubRelease {
@@ -235,6 +236,7 @@ func (r githubReleaseResponse) toAppRelease(repo string) app.GitHubRelease {
 			DownloadURL: asset.BrowserDownloadURL,
 			ContentType: asset.ContentType,
 			SizeBytes:   asset.Size,
+			SHA256:      sha256Digest(asset.Digest),
 		})
 	}
 
@@ -248,3 +250,13 @@ func (r githubReleaseResponse) toAppRelease(repo string) app.GitHubRelease {
 		Assets:     assets,
 	}
 }
+
+// sha256Digest returns the hex SHA-256 from a GitHub asset digest such as
+// "sha256:<hex>". Other or missing digests yield an empty string.
+func sha256Digest(digest string) string {
+	algorithm, value, ok := strings.Cut(strings.TrimSpace(digest), ":")
+	if !ok || !strings.EqualFold(algorithm, "sha256") {
+		return ""
+	}
+	return strings.ToLower(value)
+}
@@ -37,7 +37,8 @@ func TestClientLatestReleaseMapsGitHubResponse(t *testing.T) {
 					"name": "Example-x86_64.AppImage",
 					"browser_download_url": "https://downloads.example/Example-x86_64.AppImage",
 					"content_type": "application/octet-stream",
-					"size": 12345
+					"size": 12345,
+					"digest": "sha256:ABC123"
 				}
 			]
 		}`)
@@ -83,6 +84,9 @@ func TestClientLatestReleaseMapsGitHubResponse(t *testing.T) {
 	if got, want := asset.SizeBytes, int64(12345); got != want {
 		t.Fatalf("asset.SizeBytes = %d, want %d", got, want)
 	}
+	if got, want := asset.SHA256, "abc123"; got != want {
+		t.Fatalf("asset.SHA256 = %q, want %q", got, want)
+	}
 }
 
 func TestClientLatestReleaseIncludesPrereleases(t *testing.T) {
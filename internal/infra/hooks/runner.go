@@ -0,0 +1,47 @@
+// Package hooks runs the lifecycle hook commands configured in config.toml.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+// Runner runs hook commands with /bin/sh -c. Both output streams of a hook go
+// to Output so they never mix with aim's own stdout, which may carry JSON.
+type Runner struct {
+	Output io.Writer
+}
+
+// NewRunner creates a Runner that writes hook output to output.
+func NewRunner(output io.Writer) Runner {
+	return Runner{Output: output}
+}
+
+var _ app.HookRunner = Runner{}
+
+func (r Runner) Run(ctx context.Context, command string, env app.HookEnv) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Env = append(cmd.Environ(), env.Variables()...)
+	cmd.Stdout = r.Output
+	cmd.Stderr = r.Output
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%q exited with status %d", command, exitErr.ExitCode())
+		}
+		return fmt.Errorf("run %q: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestRunnerPassesAppEnvironment(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	err := NewRunner(output).Run(context.Background(), `echo "$AIM_HOOK $AIM_APP_ID $AIM_APPIMAGE_PATH $AIM_OLD_VERSION->$AIM_NEW_VERSION"`, app.HookEnv{
+		Event:        app.HookPostUpdate,
+		AppID:        "example",
+		AppImagePath: "/apps/example.AppImage",
+		OldVersion:   "1.0.0",
+		NewVersion:   "1.1.0",
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got, want := output.String(), "post_update example /apps/example.AppImage 1.0.0->1.1.0\n"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRunnerReportsExitStatus(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	err := NewRunner(output).Run(context.Background(), "echo refusing >&2; exit 3", app.HookEnv{Event: app.HookPreRemove})
+	if err == nil || !strings.Contains(err.Error(), "exited with status 3") {
+		t.Fatalf("Run() error = %v, want exit status 3", err)
+	}
+	if got := output.String(); got != "refusing\n" {
+		t.Fatalf("output = %q, want stderr of the hook", got)
+	}
+}
@@ -0,0 +1,102 @@
+package icon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// StandardSizes are the hicolor sizes that icons are rasterized to when an
+// Installer has RasterSizes set to them.
+var StandardSizes = []int{16, 24, 32, 48, 64, 128, 256}
+
+// convertedExtensions are the formats that are installed as PNG, since most
+// desktops do not render them from an icon theme.
+var convertedExtensions = map[string]func([]byte) (image.Image, error){
+	".ico": decodeICO,
+	".xpm": decodeXPM,
+}
+
+func decodePNG(data []byte) (image.Image, error) {
+	return png.Decode(bytes.NewReader(data))
+}
+
+func decodeIcon(path string, decode func([]byte) (image.Image, error)) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode icon %q: %w", path, err)
+	}
+	return img, nil
+}
+
+// scaleIcon scales img down to fit a size x size square by averaging the
+// pixels each target pixel covers, centering icons that are not square.
+func scaleIcon(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	longest := max(bounds.Dx(), bounds.Dy())
+	width := max(1, bounds.Dx()*size/longest)
+	height := max(1, bounds.Dy()*size/longest)
+	offsetX, offsetY := (size-width)/2, (size-height)/2
+
+	scaled := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := range height {
+		top, bottom := bounds.Min.Y+y*bounds.Dy()/height, bounds.Min.Y+(y+1)*bounds.Dy()/height
+		for x := range width {
+			left, right := bounds.Min.X+x*bounds.Dx()/width, bounds.Min.X+(x+1)*bounds.Dx()/width
+			var r, g, b, a, count uint32
+			for sy := top; sy < max(bottom, top+1); sy++ {
+				for sx := left; sx < max(right, left+1); sx++ {
+					// RGBA returns alpha-premultiplied values, which average
+					// without darkening transparent edges.
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, count = r+pr, g+pg, b+pb, a+pa, count+1
+				}
+			}
+			scaled.SetRGBA(offsetX+x, offsetY+y, color.RGBA{
+				R: uint8(r / count >> 8),
+				G: uint8(g / count >> 8),
+				B: uint8(b / count >> 8),
+				A: uint8(a / count >> 8),
+			})
+		}
+	}
+	return scaled
+}
+
+// encodePNG encodes img to destination through a temporary file, like
+// fileutil.CopyFile.
+func encodePNG(ctx context.Context, img image.Image, destination string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	temporaryDestination := destination + ".tmp"
+	file, err := os.OpenFile(temporaryDestination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	encodeErr := png.Encode(file, img)
+	closeErr := file.Close()
+	if encodeErr != nil {
+		_ = os.Remove(temporaryDestination)
+		return encodeErr
+	}
+	if closeErr != nil {
+		_ = os.Remove(temporaryDestination)
+		return closeErr
+	}
+
+	if err := os.Rename(temporaryDestination, destination); err != nil {
+		_ = os.Remove(temporaryDestination)
+		return err
+	}
+	return nil
+}
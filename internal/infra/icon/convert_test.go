@@ -0,0 +1,284 @@
+package icon
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeICOChoosesLargestImage(t *testing.T) {
+	t.Parallel()
+
+	large := &bytes.Buffer{}
+	if err := png.Encode(large, image.NewNRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	data := icoFile(icoBitmap(16, color.NRGBA{R: 0xff, A: 0xff}), large.Bytes(), icoBitmap(32, color.NRGBA{B: 0xff, A: 0xff}))
+
+	img, err := decodeICO(data)
+	if err != nil {
+		t.Fatalf("decodeICO() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(64, 64) {
+		t.Fatalf("decodeICO() size = %v, want 64x64", got)
+	}
+}
+
+func TestDecodeICOReadsBitmaps(t *testing.T) {
+	t.Parallel()
+
+	img, err := decodeICO(icoFile(icoBitmap(32, color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0x80})))
+	if err != nil {
+		t.Fatalf("decodeICO() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(32, 32) {
+		t.Fatalf("decodeICO() size = %v, want 32x32", got)
+	}
+	if got, want := color.NRGBAModel.Convert(img.At(3, 5)), (color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0x80}); got != want {
+		t.Fatalf("pixel = %#v, want %#v", got, want)
+	}
+}
+
+func TestDecodeICORejectsOtherFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range [][]byte{nil, []byte("not an icon"), {0, 0, 1, 0, 0, 0}} {
+		if _, err := decodeICO(data); err == nil {
+			t.Fatalf("decodeICO(%q) error = nil, want error", data)
+		}
+	}
+}
+
+func TestDecodeXPM(t *testing.T) {
+	t.Parallel()
+
+	img, err := decodeXPM([]byte(`/* XPM */
+static char *example[] = {
+/* columns rows colors chars-per-pixel */
+"3 2 3 2",
+".. c None",
+"ab s fg c #ff0000 m black",
+"cd c gray50",
+"..abcd",
+"cdab..",
+};
+`))
+	if err != nil {
+		t.Fatalf("decodeXPM() error = %v", err)
+	}
+
+	want := map[image.Point]color.NRGBA{
+		{0, 0}: {},
+		{1, 0}: {R: 0xff, A: 0xff},
+		{2, 0}: {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+		{1, 1}: {R: 0xff, A: 0xff},
+	}
+	if got := img.Bounds().Size(); got != image.Pt(3, 2) {
+		t.Fatalf("decodeXPM() size = %v, want 3x2", got)
+	}
+	for point, c := range want {
+		if got := color.NRGBAModel.Convert(img.At(point.X, point.Y)); got != c {
+			t.Fatalf("pixel %v = %#v, want %#v", point, got, c)
+		}
+	}
+}
+
+func TestDecodeXPMRejectsUnknownPixels(t *testing.T) {
+	t.Parallel()
+
+	if _, err := decodeXPM([]byte(`"2 1 1 1", "a c #fff", "ab"`)); err == nil {
+		t.Fatal("decodeXPM() error = nil, want error")
+	}
+}
+
+func TestDecodeXPMRejectsHugeHeaderCounts(t *testing.T) {
+	t.Parallel()
+
+	for _, header := range []string{
+		"1 1 9223372036854775807 1",
+		"1 9223372036854775807 1 1",
+		"9223372036854775807 1 1 1",
+		"1 1 1 9223372036854775807",
+		"1 1 1 5",
+		"1 1 65537 1",
+	} {
+		data := []byte(`"` + header + `", "a c #fff", "a"`)
+		if _, err := decodeXPM(data); err == nil {
+			t.Fatalf("decodeXPM(%q) error = nil, want error", header)
+		}
+	}
+}
+
+func FuzzDecodeXPM(f *testing.F) {
+	f.Add([]byte(`"2 1 2 1", "a c #ff0000", "b c None", "ab"`))
+	f.Add([]byte("! XPM2\n2 2 1 2\nxx c gray50\nxxxx\nxxxx\n"))
+	f.Add([]byte(`"1 1 9223372036854775807 1", "a c #fff", "a"`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		img, err := decodeXPM(data)
+		if err != nil {
+			return
+		}
+		if size := img.Bounds().Size(); size.X <= 0 || size.Y <= 0 || size.X > xpmMaxSize || size.Y > xpmMaxSize {
+			t.Fatalf("decodeXPM() size = %v, want within 1..%d", size, xpmMaxSize)
+		}
+	})
+}
+
+func TestInstallerConvertsICOAndXPMToPNG(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	icoSource := filepath.Join(root, "example.ico")
+	if err := os.WriteFile(icoSource, icoFile(icoBitmap(16, color.NRGBA{A: 0xff}), icoBitmap(48, color.NRGBA{A: 0xff})), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	xpmSource := filepath.Join(root, "other.xpm")
+	if err := os.WriteFile(xpmSource, []byte(`"2 2 1 1", "x c #00ff00", "xx", "xx"`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	installer := NewInstaller(filepath.Join(root, "icons"))
+
+	for _, tt := range []struct {
+		source string
+		appID  string
+		want   string
+	}{
+		{source: icoSource, appID: "example", want: filepath.Join(root, "icons", "hicolor", "48x48", "apps", "example.png")},
+		{source: xpmSource, appID: "other", want: filepath.Join(root, "icons", "hicolor", "2x2", "apps", "other.png")},
+	} {
+		destination, err := installer.Install(context.Background(), tt.source, tt.appID)
+		if err != nil {
+			t.Fatalf("Install(%q) error = %v", tt.source, err)
+		}
+		if destination != tt.want {
+			t.Fatalf("Install(%q) = %q, want %q", tt.source, destination, tt.want)
+		}
+		file, err := os.Open(destination)
+		if err != nil {
+			t.Fatalf("open destination: %v", err)
+		}
+		_, err = png.DecodeConfig(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("destination is not a png: %v", err)
+		}
+	}
+}
+
+func TestInstallerRasterizesMissingSizes(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	large := filepath.Join(root, "large.png")
+	small := filepath.Join(root, "small.png")
+	writePNG(t, large, 64, 64)
+	writePNG(t, small, 32, 32)
+	installer := NewInstaller(filepath.Join(root, "icons"))
+	installer.RasterSizes = []int{16, 32, 48, 128}
+
+	paths, err := installer.InstallSizes(context.Background(), []string{large, small}, "example")
+	if err != nil {
+		t.Fatalf("InstallSizes() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "icons", "hicolor", "64x64", "apps", "example.png"),
+		filepath.Join(root, "icons", "hicolor", "32x32", "apps", "example.png"),
+		filepath.Join(root, "icons", "hicolor", "16x16", "apps", "example.png"),
+		filepath.Join(root, "icons", "hicolor", "48x48", "apps", "example.png"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("InstallSizes() = %q, want %q", paths, want)
+	}
+	file, err := os.Open(want[3])
+	if err != nil {
+		t.Fatalf("open rasterized icon: %v", err)
+	}
+	defer file.Close()
+	config, err := png.DecodeConfig(file)
+	if err != nil || config.Width != 48 || config.Height != 48 {
+		t.Fatalf("rasterized icon = %dx%d, %v; want 48x48", config.Width, config.Height, err)
+	}
+}
+
+func TestInstallerDoesNotRasterizeNextToScalableIcons(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	scalable := filepath.Join(root, "example.svg")
+	if err := os.WriteFile(scalable, []byte("<svg/>"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	large := filepath.Join(root, "large.png")
+	writePNG(t, large, 64, 64)
+	installer := NewInstaller(filepath.Join(root, "icons"))
+	installer.RasterSizes = StandardSizes
+
+	paths, err := installer.InstallSizes(context.Background(), []string{scalable, large}, "example")
+	if err != nil {
+		t.Fatalf("InstallSizes() error = %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("InstallSizes() = %q, want only the bundled icons", paths)
+	}
+}
+
+func TestScaleIconAveragesPixels(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	img.SetNRGBA(1, 1, color.NRGBA{R: 0xff, A: 0xff})
+
+	got := color.NRGBAModel.Convert(scaleIcon(img, 1).At(0, 0)).(color.NRGBA)
+	if got.R != 0xff || got.A < 0x7f || got.A > 0x80 {
+		t.Fatalf("scaled pixel = %#v, want half transparent red", got)
+	}
+}
+
+// icoFile builds an ICO file from PNG or bitmap images, reading the
+// dimensions of bitmaps from their header.
+func icoFile(images ...[]byte) []byte {
+	header := make([]byte, icoHeaderSize+len(images)*icoEntrySize)
+	binary.LittleEndian.PutUint16(header[2:], 1)
+	binary.LittleEndian.PutUint16(header[4:], uint16(len(images)))
+	offset := len(header)
+	var body []byte
+	for i, data := range images {
+		entry := header[icoHeaderSize+i*icoEntrySize:]
+		size := 0
+		if bytes.HasPrefix(data, pngSignature) {
+			config, _ := png.DecodeConfig(bytes.NewReader(data))
+			size = config.Width
+		} else {
+			size = int(binary.LittleEndian.Uint32(data[4:]))
+		}
+		entry[0], entry[1] = byte(size), byte(size)
+		binary.LittleEndian.PutUint16(entry[6:], 32)
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(entry[12:], uint32(offset+len(body)))
+		body = append(body, data...)
+	}
+	return append(header, body...)
+}
+
+// icoBitmap builds a 32-bit ICO bitmap of one color with an empty mask.
+func icoBitmap(size int, c color.NRGBA) []byte {
+	data := make([]byte, bitmapInfoSize, bitmapInfoSize+size*size*4+(size+31)/32*4*size)
+	binary.LittleEndian.PutUint32(data[0:], bitmapInfoSize)
+	binary.LittleEndian.PutUint32(data[4:], uint32(size))
+	binary.LittleEndian.PutUint32(data[8:], uint32(size*2))
+	binary.LittleEndian.PutUint16(data[12:], 1)
+	binary.LittleEndian.PutUint16(data[14:], 32)
+	for range size * size {
+		data = append(data, c.B, c.G, c.R, c.A)
+	}
+	return append(data, make([]byte, (size+31)/32*4*size)...)
+}
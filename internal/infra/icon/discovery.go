@@ -47,7 +47,7 @@ func (Discoverer) Discover(ctx context.Context, rootDir string, iconName string)
 		if path, ok, err := resolveExplicitIconPath(rootDir, iconName); err != nil {
 			return app.IconFile{}, err
 		} else if ok {
-			return app.IconFile{Path: path}, nil
+			return withSizes(ctx, rootDir, app.IconFile{Path: path})
 		}
 	}
 
@@ -56,10 +56,55 @@ func (Discoverer) Discover(ctx context.Context, rootDir string, iconName string)
 		return app.IconFile{}, err
 	}
 	if len(candidates) == 0 {
-		return app.IconFile{}, fmt.Errorf("find icon under %q: no supported icon files found", rootDir)
+		return app.IconFile{}, fmt.Errorf("find icon under %q: %w", rootDir, app.ErrIconNotFound)
 	}
 
-	return app.IconFile{Path: candidates[0]}, nil
+	return withSizes(ctx, rootDir, app.IconFile{Path: candidates[0]})
+}
+
+// withSizes adds the icons in usr/share/icons/hicolor/*/apps that have the
+// same name as icon.Path, largest first.
+func withSizes(ctx context.Context, rootDir string, icon app.IconFile) (app.IconFile, error) {
+	wantedBase := normalizedIconBase(icon.Path)
+	if isDirIcon(icon.Path) {
+		return icon, nil
+	}
+
+	themeDir := filepath.Join(rootDir, "usr", "share", "icons", "hicolor")
+	sizeDirs, err := os.ReadDir(themeDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return icon, nil
+	}
+	if err != nil {
+		return app.IconFile{}, fmt.Errorf("read icon theme %q: %w", themeDir, err)
+	}
+
+	for _, sizeDir := range sizeDirs {
+		if err := ctx.Err(); err != nil {
+			return app.IconFile{}, err
+		}
+		appsDir := filepath.Join(themeDir, sizeDir.Name(), "apps")
+		entries, err := os.ReadDir(appsDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(appsDir, entry.Name())
+			if entry.IsDir() || path == icon.Path || !isSupportedIconPath(path) || normalizedIconBase(path) != wantedBase {
+				continue
+			}
+			icon.Sizes = append(icon.Sizes, path)
+		}
+	}
+
+	sort.SliceStable(icon.Sizes, func(i, j int) bool {
+		left, right := iconSizeScore(icon.Sizes[i]), iconSizeScore(icon.Sizes[j])
+		if left != right {
+			return left > right
+		}
+		return iconExtensionScore(icon.Sizes[i]) > iconExtensionScore(icon.Sizes[j])
+	})
+	return icon, nil
 }
 
 func resolveExplicitIconPath(rootDir string, iconName string) (string, bool, error) {
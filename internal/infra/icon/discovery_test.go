@@ -5,8 +5,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
 )
 
 func TestDiscovererUsesAbsoluteIconPathInsideRoot(t *testing.T) {
@@ -153,6 +156,32 @@ func TestDiscovererPrefersLargerThemeIcon(t *testing.T) {
 	}
 }
 
+func TestDiscovererReturnsOtherThemeSizes(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	hicolor := filepath.Join(root, "usr", "share", "icons", "hicolor")
+	small := filepath.Join(hicolor, "48x48", "apps", "example.png")
+	medium := filepath.Join(hicolor, "128x128", "apps", "example.png")
+	large := filepath.Join(hicolor, "256x256", "apps", "example.png")
+	scalable := filepath.Join(hicolor, "scalable", "apps", "example.svg")
+	for _, path := range []string{small, medium, large, scalable} {
+		writeIcon(t, path)
+	}
+	writeIcon(t, filepath.Join(hicolor, "48x48", "apps", "other.png"))
+
+	file, err := Discoverer{}.Discover(context.Background(), root, "example")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if got, want := file.Path, large; got != want {
+		t.Fatalf("File.Path = %q, want %q", got, want)
+	}
+	if want := []string{medium, small, scalable}; !reflect.DeepEqual(file.Sizes, want) {
+		t.Fatalf("File.Sizes = %q, want %q", file.Sizes, want)
+	}
+}
+
 func TestDiscovererUsesDirIconAsFallback(t *testing.T) {
 	t.Parallel()
 
@@ -197,8 +226,8 @@ func TestDiscovererRequiresSupportedIcon(t *testing.T) {
 	if err == nil {
 		t.Fatal("Discover() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "no supported icon files found") {
-		t.Fatalf("Discover() error = %q, want no supported icon files", err.Error())
+	if !errors.Is(err, app.ErrIconNotFound) {
+		t.Fatalf("Discover() error = %v, want app.ErrIconNotFound", err)
 	}
 }
 
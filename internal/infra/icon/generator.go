@@ -0,0 +1,194 @@
+package icon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const (
+	generatedIconSize   = 256
+	generatedIconMargin = 8
+	generatedIconRadius = 40
+	// generatedTextWidth and generatedTextHeight bound the initials.
+	generatedTextWidth  = 160
+	generatedTextHeight = 112
+	glyphWidth          = 5
+	glyphHeight         = 7
+)
+
+// glyphs is a 5x7 pixel font for the characters initials are made of.
+var glyphs = map[rune][glyphHeight]string{
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"###..", "#..#.", "#...#", "#...#", "#...#", "#..#.", "###.."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", "#...#", ".#.#.", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	'?': {".###.", "#...#", "....#", "...#.", "..#..", ".....", "..#.."},
+}
+
+// Generator draws placeholder icons: the initials of the app name in white
+// on a rounded square whose color is derived from the app ID.
+type Generator struct{}
+
+var _ app.IconGenerator = Generator{}
+
+// Generate writes a generated icon for name and appID to destination.
+func (Generator) Generate(ctx context.Context, destination string, name string, appID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(destination) == "" {
+		return errors.New("icon destination is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return fmt.Errorf("create icon directory for %q: %w", destination, err)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, generatedIconSize, generatedIconSize))
+	drawRoundedSquare(img, backgroundColor(appID))
+	drawInitials(img, initials(name, appID))
+
+	if err := encodePNG(ctx, img, destination); err != nil {
+		return fmt.Errorf("write generated icon %q: %w", destination, err)
+	}
+	return nil
+}
+
+// initials returns the first letter or digit of up to two words of name, or
+// of appID when name has none. Characters the font lacks become "?".
+func initials(name string, appID string) string {
+	for _, value := range []string{name, appID} {
+		var letters []rune
+		for _, word := range strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			letter := unicode.ToUpper([]rune(word)[0])
+			if _, ok := glyphs[letter]; !ok {
+				letter = '?'
+			}
+			letters = append(letters, letter)
+			if len(letters) == 2 {
+				break
+			}
+		}
+		if len(letters) > 0 {
+			return string(letters)
+		}
+	}
+	return "?"
+}
+
+// backgroundColor picks a hue from a hash of appID at a fixed saturation and
+// lightness, so white initials stay readable on every color.
+func backgroundColor(appID string) color.NRGBA {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(appID))
+	hue := float64(hash.Sum32() % 360)
+
+	const saturation, lightness = 0.55, 0.45
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g = chroma, x
+	case hue < 120:
+		r, g = x, chroma
+	case hue < 180:
+		g, b = chroma, x
+	case hue < 240:
+		g, b = x, chroma
+	case hue < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := lightness - chroma/2
+	channel := func(value float64) uint8 { return uint8(math.Round((value + m) * 255)) }
+	return color.NRGBA{R: channel(r), G: channel(g), B: channel(b), A: 0xff}
+}
+
+func drawRoundedSquare(img *image.NRGBA, c color.NRGBA) {
+	low, high := generatedIconMargin, generatedIconSize-generatedIconMargin
+	for y := low; y < high; y++ {
+		for x := low; x < high; x++ {
+			// Corner pixels are drawn when they are inside the quarter circle
+			// around the nearest corner center.
+			cx := min(max(x, low+generatedIconRadius), high-generatedIconRadius-1)
+			cy := min(max(y, low+generatedIconRadius), high-generatedIconRadius-1)
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= generatedIconRadius*generatedIconRadius {
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func drawInitials(img *image.NRGBA, text string) {
+	letters := []rune(text)
+	// Letters are one glyph pixel apart.
+	width := len(letters)*(glyphWidth+1) - 1
+	scale := min(generatedTextWidth/width, generatedTextHeight/glyphHeight)
+	left := (generatedIconSize - width*scale) / 2
+	top := (generatedIconSize - glyphHeight*scale) / 2
+
+	white := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	for i, letter := range letters {
+		glyph := glyphs[letter]
+		for row, line := range glyph {
+			for column, pixel := range line {
+				if pixel != '#' {
+					continue
+				}
+				x := left + (i*(glyphWidth+1)+column)*scale
+				y := top + row*scale
+				for dy := range scale {
+					for dx := range scale {
+						img.SetNRGBA(x+dx, y+dy, white)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package icon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeneratorDrawsDeterministicIcon(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	first := filepath.Join(root, "first", "icon.png")
+	second := filepath.Join(root, "second", "icon.png")
+	for _, path := range []string{first, second} {
+		if err := (Generator{}).Generate(context.Background(), path, "Example App", "example-app"); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	firstContent, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatalf("read icon: %v", err)
+	}
+	secondContent, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatalf("read icon: %v", err)
+	}
+	if !bytes.Equal(firstContent, secondContent) {
+		t.Fatal("Generate() drew different icons for the same app")
+	}
+	img, err := png.Decode(bytes.NewReader(firstContent))
+	if err != nil {
+		t.Fatalf("decode icon: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != generatedIconSize || size.Y != generatedIconSize {
+		t.Fatalf("icon size = %v, want %dx%d", size, generatedIconSize, generatedIconSize)
+	}
+	if _, _, _, alpha := img.At(0, 0).RGBA(); alpha != 0 {
+		t.Fatalf("corner alpha = %d, want transparent corners", alpha)
+	}
+	if got, want := img.At(generatedIconSize/2, generatedIconMargin+1), backgroundColor("example-app"); got != want {
+		t.Fatalf("background = %#v, want %#v", got, want)
+	}
+}
+
+func TestBackgroundColorDependsOnAppID(t *testing.T) {
+	t.Parallel()
+
+	if backgroundColor("example-app") == backgroundColor("other-app") {
+		t.Fatal("backgroundColor() is the same for different app IDs")
+	}
+}
+
+func TestInitials(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		appID string
+		want  string
+	}{
+		{name: "Example App", appID: "example-app", want: "EA"},
+		{name: "visual studio code", appID: "code", want: "VS"},
+		{name: "Krita", appID: "krita", want: "K"},
+		{name: "0 A.D.", appID: "0-a-d", want: "0A"},
+		{name: "Élan", appID: "elan", want: "?"},
+		{name: "", appID: "example-app", want: "EA"},
+		{name: "...", appID: "", want: "?"},
+	}
+	for _, tt := range tests {
+		if got := initials(tt.name, tt.appID); got != tt.want {
+			t.Fatalf("initials(%q, %q) = %q, want %q", tt.name, tt.appID, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratorRespectsCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := (Generator{}).Generate(ctx, filepath.Join(t.TempDir(), "icon.png"), "Example", "example")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Generate() error = %v, want context.Canceled", err)
+	}
+}
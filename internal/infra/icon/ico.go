@@ -0,0 +1,180 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+const (
+	icoHeaderSize      = 6
+	icoEntrySize       = 16
+	bitmapInfoSize     = 40
+	bitmapCompressNone = 0
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+type icoEntry struct {
+	width    int
+	height   int
+	bitCount int
+	size     int
+	offset   int
+}
+
+// decodeICO decodes the largest image of an ICO file. Images are either
+// embedded PNGs or uncompressed bitmaps with 1, 4, 8, 24 or 32 bits per pixel.
+func decodeICO(data []byte) (image.Image, error) {
+	if len(data) < icoHeaderSize {
+		return nil, errors.New("ico header is truncated")
+	}
+	if binary.LittleEndian.Uint16(data[0:]) != 0 || binary.LittleEndian.Uint16(data[2:]) != 1 {
+		return nil, errors.New("not an ico file")
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if count == 0 {
+		return nil, errors.New("ico file has no images")
+	}
+	if len(data) < icoHeaderSize+count*icoEntrySize {
+		return nil, errors.New("ico directory is truncated")
+	}
+
+	var best icoEntry
+	for i := range count {
+		raw := data[icoHeaderSize+i*icoEntrySize:]
+		entry := icoEntry{
+			width:    int(raw[0]),
+			height:   int(raw[1]),
+			bitCount: int(binary.LittleEndian.Uint16(raw[6:])),
+			size:     int(binary.LittleEndian.Uint32(raw[8:])),
+			offset:   int(binary.LittleEndian.Uint32(raw[12:])),
+		}
+		// A stored dimension of 0 means 256.
+		if entry.width == 0 {
+			entry.width = 256
+		}
+		if entry.height == 0 {
+			entry.height = 256
+		}
+		if entry.offset < 0 || entry.size <= 0 || entry.offset > len(data) || entry.size > len(data)-entry.offset {
+			continue
+		}
+		if area, bestArea := entry.width*entry.height, best.width*best.height; area > bestArea || (area == bestArea && entry.bitCount > best.bitCount) {
+			best = entry
+		}
+	}
+	if best.size == 0 {
+		return nil, errors.New("ico file has no readable images")
+	}
+
+	frame := data[best.offset : best.offset+best.size]
+	if bytes.HasPrefix(frame, pngSignature) {
+		return decodePNG(frame)
+	}
+	return decodeICOBitmap(frame)
+}
+
+// decodeICOBitmap decodes a BMP image without its file header as stored in
+// ICO files. Its height covers both the color bitmap and the transparency
+// mask that follows it.
+func decodeICOBitmap(data []byte) (image.Image, error) {
+	if len(data) < bitmapInfoSize {
+		return nil, errors.New("ico bitmap header is truncated")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[0:]))
+	width := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	height := int(int32(binary.LittleEndian.Uint32(data[8:]))) / 2
+	bitCount := int(binary.LittleEndian.Uint16(data[14:]))
+	compression := binary.LittleEndian.Uint32(data[16:])
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:]))
+	if headerSize < bitmapInfoSize || headerSize > len(data) {
+		return nil, fmt.Errorf("unsupported ico bitmap header size %d", headerSize)
+	}
+	if width <= 0 || height <= 0 || width > 1024 || height > 1024 {
+		return nil, fmt.Errorf("unsupported ico bitmap size %dx%d", width, height)
+	}
+	if compression != bitmapCompressNone {
+		return nil, fmt.Errorf("unsupported ico bitmap compression %d", compression)
+	}
+
+	var palette []color.NRGBA
+	offset := headerSize
+	switch bitCount {
+	case 1, 4, 8:
+		if colorsUsed == 0 || colorsUsed > 1<<bitCount {
+			colorsUsed = 1 << bitCount
+		}
+		if len(data) < offset+colorsUsed*4 {
+			return nil, errors.New("ico bitmap palette is truncated")
+		}
+		palette = make([]color.NRGBA, colorsUsed)
+		for i := range palette {
+			entry := data[offset+i*4:]
+			palette[i] = color.NRGBA{R: entry[2], G: entry[1], B: entry[0], A: 0xff}
+		}
+		offset += colorsUsed * 4
+	case 24, 32:
+	default:
+		return nil, fmt.Errorf("unsupported ico bitmap depth %d", bitCount)
+	}
+
+	stride := (width*bitCount + 31) / 32 * 4
+	maskStride := (width + 31) / 32 * 4
+	pixels := data[offset:]
+	if len(pixels) < stride*height {
+		return nil, errors.New("ico bitmap pixels are truncated")
+	}
+	// Some 32-bit icons leave out the mask, which their alpha channel
+	// makes redundant.
+	mask := pixels[stride*height:]
+	if len(mask) < maskStride*height {
+		mask = nil
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for y := range height {
+		// Rows are stored bottom-up.
+		row := pixels[(height-1-y)*stride:]
+		for x := range width {
+			var c color.NRGBA
+			switch bitCount {
+			case 32:
+				c = color.NRGBA{R: row[x*4+2], G: row[x*4+1], B: row[x*4], A: row[x*4+3]}
+				hasAlpha = hasAlpha || c.A != 0
+			case 24:
+				c = color.NRGBA{R: row[x*3+2], G: row[x*3+1], B: row[x*3], A: 0xff}
+			default:
+				bit := x * bitCount
+				index := int(row[bit/8]>>(8-bitCount-bit%8)) & (1<<bitCount - 1)
+				if index < len(palette) {
+					c = palette[index]
+				}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	// 32-bit icons without any alpha rely on the mask like the others.
+	if bitCount == 32 && !hasAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	if hasAlpha || mask == nil {
+		return img, nil
+	}
+	for y := range height {
+		row := mask[(height-1-y)*maskStride:]
+		for x := range width {
+			if row[x/8]&(0x80>>(x%8)) != 0 {
+				img.SetNRGBA(x, y, color.NRGBA{})
+			}
+		}
+	}
+	return img, nil
+}
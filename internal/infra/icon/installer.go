@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,9 @@ import (
 // Installer installs icon files into the configured icon theme root.
 type Installer struct {
 	Dir string
+	// RasterSizes are the sizes InstallSizes scales the largest raster icon
+	// down to when no icon of that size was installed.
+	RasterSizes []int
 }
 
 // NewInstaller creates an icon installer rooted at dir.
@@ -24,33 +28,173 @@ func NewInstaller(dir string) Installer {
 
 var _ app.IconInstaller = Installer{}
 
-// Install copies sourcePath into the hicolor icon theme as <appID><source extension>.
+// preparedIcon is an icon source with the size directory and extension it is
+// installed with. ICO and XPM icons are decoded to be installed as PNG.
+type preparedIcon struct {
+	sourcePath string
+	sizeDir    string
+	extension  string
+	// size is the larger dimension of raster icons, or 0 if unknown.
+	size  int
+	image image.Image
+}
+
+// Install installs sourcePath into the hicolor icon theme as <appID><source
+// extension>, in the size directory that matches its dimensions. ICO and XPM
+// icons are converted to PNG, using the largest image of an ICO.
 func (i Installer) Install(ctx context.Context, sourcePath string, appID string) (string, error) {
-	if err := ctx.Err(); err != nil {
+	if err := i.validate(ctx, appID); err != nil {
 		return "", err
 	}
-	if strings.TrimSpace(sourcePath) == "" {
-		return "", errors.New("icon source path is required")
+	icon, err := prepareIcon(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	return i.install(ctx, icon, appID)
+}
+
+// InstallSizes installs the first icon of each size in sourcePaths like
+// Install, then scales the largest raster icon down to the RasterSizes that
+// are still missing unless a scalable icon was installed. Icons installed
+// before a failure are removed again.
+func (i Installer) InstallSizes(ctx context.Context, sourcePaths []string, appID string) ([]string, error) {
+	if err := i.validate(ctx, appID); err != nil {
+		return nil, err
+	}
+
+	var installed []string
+	fail := func(err error) ([]string, error) {
+		for _, path := range installed {
+			_ = fileutil.RemoveArtifact(context.WithoutCancel(ctx), path)
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(sourcePaths))
+	sizeDirs := make(map[string]bool, len(sourcePaths))
+	var largest preparedIcon
+	for _, sourcePath := range sourcePaths {
+		icon, err := prepareIcon(sourcePath)
+		if err != nil {
+			return fail(err)
+		}
+		if seen[icon.sizeDir+icon.extension] {
+			continue
+		}
+		seen[icon.sizeDir+icon.extension] = true
+		sizeDirs[icon.sizeDir] = true
+		if icon.size > largest.size {
+			largest = icon
+		}
+
+		destination, err := i.install(ctx, icon, appID)
+		if err != nil {
+			return fail(err)
+		}
+		installed = append(installed, destination)
+	}
+
+	rasterized, err := i.rasterize(ctx, largest, sizeDirs, appID)
+	if err != nil {
+		return fail(err)
+	}
+	return append(installed, rasterized...), nil
+}
+
+// rasterize scales largest down to the RasterSizes that are not in sizeDirs
+// yet, unless a scalable icon was installed.
+func (i Installer) rasterize(ctx context.Context, largest preparedIcon, sizeDirs map[string]bool, appID string) ([]string, error) {
+	if len(i.RasterSizes) == 0 || largest.size == 0 || sizeDirs["scalable"] {
+		return nil, nil
+	}
+	source := largest.image
+	if source == nil {
+		// Rasterizing is best effort; a PNG that cannot be decoded is still
+		// installed as it is.
+		img, err := decodeIcon(largest.sourcePath, decodePNG)
+		if err != nil {
+			return nil, nil
+		}
+		source = img
+	}
+
+	var installed []string
+	for _, size := range i.RasterSizes {
+		dir := sizeDirName(size)
+		if size >= largest.size || sizeDirs[dir] {
+			continue
+		}
+		sizeDirs[dir] = true
+		destination, err := i.install(ctx, preparedIcon{sizeDir: dir, extension: ".png", size: size, image: scaleIcon(source, size)}, appID)
+		if err != nil {
+			for _, path := range installed {
+				_ = fileutil.RemoveArtifact(context.WithoutCancel(ctx), path)
+			}
+			return nil, err
+		}
+		installed = append(installed, destination)
+	}
+	return installed, nil
+}
+
+func (i Installer) validate(ctx context.Context, appID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 	if strings.TrimSpace(appID) == "" {
-		return "", errors.New("app id is required")
+		return errors.New("app id is required")
 	}
 	if strings.TrimSpace(i.Dir) == "" {
-		return "", errors.New("icon install directory is required")
+		return errors.New("icon install directory is required")
+	}
+	return nil
+}
+
+func prepareIcon(sourcePath string) (preparedIcon, error) {
+	if strings.TrimSpace(sourcePath) == "" {
+		return preparedIcon{}, errors.New("icon source path is required")
 	}
 	if !isSupportedIconPath(sourcePath) && !isDirIcon(sourcePath) {
-		return "", fmt.Errorf("icon source path %q has unsupported extension", sourcePath)
+		return preparedIcon{}, fmt.Errorf("icon source path %q has unsupported extension", sourcePath)
 	}
 
 	extension := installedIconExtension(sourcePath)
-	destinationDir := filepath.Join(i.Dir, "hicolor", iconThemeSizeDir(extension), "apps")
+	if decode, ok := convertedExtensions[extension]; ok {
+		// Icons that cannot be decoded are installed as they are.
+		if img, err := decodeIcon(sourcePath, decode); err == nil {
+			size := max(img.Bounds().Dx(), img.Bounds().Dy())
+			return preparedIcon{sourcePath: sourcePath, sizeDir: sizeDirName(size), extension: ".png", size: size, image: img}, nil
+		}
+	}
+
+	icon := preparedIcon{sourcePath: sourcePath, sizeDir: defaultSizeDir, extension: extension}
+	switch extension {
+	case ".svg", ".svgz":
+		icon.sizeDir = "scalable"
+	default:
+		if width, height, ok := iconSize(sourcePath, extension); ok {
+			icon.size = max(width, height)
+			icon.sizeDir = sizeDirName(icon.size)
+		}
+	}
+	return icon, nil
+}
+
+func (i Installer) install(ctx context.Context, icon preparedIcon, appID string) (string, error) {
+	destinationDir := filepath.Join(i.Dir, "hicolor", icon.sizeDir, "apps")
 	if err := os.MkdirAll(destinationDir, 0o755); err != nil {
 		return "", fmt.Errorf("create icon install directory %q: %w", destinationDir, err)
 	}
 
-	destination := filepath.Join(destinationDir, appID+extension)
-	if err := fileutil.CopyFile(ctx, sourcePath, destination); err != nil {
-		return "", fmt.Errorf("install icon %q to %q: %w", sourcePath, destination, err)
+	destination := filepath.Join(destinationDir, appID+icon.extension)
+	if icon.image != nil {
+		if err := encodePNG(ctx, icon.image, destination); err != nil {
+			return "", fmt.Errorf("write icon %q: %w", destination, err)
+		}
+		return destination, nil
+	}
+	if err := fileutil.CopyFile(ctx, icon.sourcePath, destination); err != nil {
+		return "", fmt.Errorf("install icon %q to %q: %w", icon.sourcePath, destination, err)
 	}
 
 	return destination, nil
@@ -63,12 +207,3 @@ func installedIconExtension(sourcePath string) string {
 
 	return strings.ToLower(filepath.Ext(sourcePath))
 }
-
-func iconThemeSizeDir(extension string) string {
-	switch extension {
-	case ".svg", ".svgz":
-		return "scalable"
-	default:
-		return "256x256"
-	}
-}
@@ -3,8 +3,11 @@ package icon
 import (
 	"context"
 	"errors"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -55,6 +58,58 @@ func TestInstallerInstallsDirIconAsPng(t *testing.T) {
 	}
 }
 
+func TestInstallerUsesIconDimensions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	pngSource := filepath.Join(root, "example.png")
+	writePNG(t, pngSource, 48, 48)
+	xpmSource := filepath.Join(root, "example.xpm")
+	if err := os.WriteFile(xpmSource, []byte("/* XPM */\nstatic char *example[] = {\n\"16 16 2 1\",\n\"  c None\",\n"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	installer := NewInstaller(filepath.Join(root, "icons"))
+
+	for source, want := range map[string]string{
+		pngSource: filepath.Join(root, "icons", "hicolor", "48x48", "apps", "example.png"),
+		// The truncated XPM cannot be converted and is installed as it is.
+		xpmSource: filepath.Join(root, "icons", "hicolor", "16x16", "apps", "example.xpm"),
+	} {
+		destination, err := installer.Install(context.Background(), source, "example")
+		if err != nil {
+			t.Fatalf("Install(%q) error = %v", source, err)
+		}
+		if destination != want {
+			t.Fatalf("Install(%q) = %q, want %q", source, destination, want)
+		}
+	}
+}
+
+func TestInstallerInstallsOneIconPerSize(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	large := filepath.Join(root, "large.png")
+	small := filepath.Join(root, "small.png")
+	otherSmall := filepath.Join(root, "other-small.png")
+	writePNG(t, large, 128, 128)
+	writePNG(t, small, 32, 32)
+	writePNG(t, otherSmall, 32, 32)
+
+	paths, err := NewInstaller(filepath.Join(root, "icons")).InstallSizes(context.Background(), []string{large, small, otherSmall}, "example")
+	if err != nil {
+		t.Fatalf("InstallSizes() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(root, "icons", "hicolor", "128x128", "apps", "example.png"),
+		filepath.Join(root, "icons", "hicolor", "32x32", "apps", "example.png"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("InstallSizes() = %q, want %q", paths, want)
+	}
+}
+
 func TestInstallerRejectsUnsupportedIcon(t *testing.T) {
 	t.Parallel()
 
@@ -105,3 +160,15 @@ func TestInstallerRespectsCanceledContext(t *testing.T) {
 		t.Fatalf("Install() error = %v, want context.Canceled", err)
 	}
 }
+
+func writePNG(t *testing.T, path string, width int, height int) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create %s: %v", path, err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode %s: %v", path, err)
+	}
+}
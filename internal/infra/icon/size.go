@@ -0,0 +1,74 @@
+package icon
+
+import (
+	"bufio"
+	"fmt"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultSizeDir is used for raster icons whose dimensions cannot be read.
+const defaultSizeDir = "256x256"
+
+// iconSize reads the dimensions of a PNG or XPM icon from its header. It
+// reports false for other formats and unreadable files.
+func iconSize(path string, extension string) (int, int, bool) {
+	switch extension {
+	case ".png":
+		return pngSize(path)
+	case ".xpm":
+		return xpmSize(path)
+	default:
+		return 0, 0, false
+	}
+}
+
+func pngSize(path string) (int, int, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	config, err := png.DecodeConfig(file)
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return 0, 0, false
+	}
+	return config.Width, config.Height, true
+}
+
+// xpmSize reads the values line of an XPM, the first string in the file,
+// which starts with "<width> <height> <colors> <chars per pixel>".
+func xpmSize(path string) (int, int, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lines := 0; scanner.Scan() && lines < 64; lines++ {
+		_, rest, ok := strings.Cut(scanner.Text(), `"`)
+		if !ok {
+			continue
+		}
+		values, _, _ := strings.Cut(rest, `"`)
+		fields := strings.Fields(values)
+		if len(fields) < 4 {
+			return 0, 0, false
+		}
+		width, widthErr := strconv.Atoi(fields[0])
+		height, heightErr := strconv.Atoi(fields[1])
+		if widthErr != nil || heightErr != nil || width <= 0 || height <= 0 {
+			return 0, 0, false
+		}
+		return width, height, true
+	}
+	return 0, 0, false
+}
+
+func sizeDirName(size int) string {
+	return fmt.Sprintf("%dx%d", size, size)
+}
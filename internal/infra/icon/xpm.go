@@ -0,0 +1,191 @@
+package icon
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// The header of an XPM comes from an untrusted AppImage, so its counts are
+// bounded before they size anything. Colors of more than four characters per
+// pixel are not used in practice.
+const (
+	xpmMaxSize          = 1024
+	xpmMaxColors        = 1 << 16
+	xpmMaxCharsPerPixel = 4
+)
+
+// xpmColorKeys are the XPM color contexts in order of preference: color,
+// grayscale, four-level grayscale and monochrome.
+var xpmColorKeys = []string{"c", "g", "g4", "m"}
+
+var xpmNamedColors = map[string]color.NRGBA{
+	"black":   {A: 0xff},
+	"white":   {R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	"red":     {R: 0xff, A: 0xff},
+	"green":   {G: 0xff, A: 0xff},
+	"blue":    {B: 0xff, A: 0xff},
+	"yellow":  {R: 0xff, G: 0xff, A: 0xff},
+	"cyan":    {G: 0xff, B: 0xff, A: 0xff},
+	"magenta": {R: 0xff, B: 0xff, A: 0xff},
+	"gray":    {R: 0xbe, G: 0xbe, B: 0xbe, A: 0xff},
+	"grey":    {R: 0xbe, G: 0xbe, B: 0xbe, A: 0xff},
+}
+
+// decodeXPM decodes an XPM2 or XPM3 image.
+func decodeXPM(data []byte) (image.Image, error) {
+	lines := xpmStrings(string(data))
+	if len(lines) == 0 {
+		return nil, errors.New("xpm has no values")
+	}
+
+	values := strings.Fields(lines[0])
+	if len(values) < 4 {
+		return nil, fmt.Errorf("invalid xpm values %q", lines[0])
+	}
+	var numbers [4]int
+	for i := range numbers {
+		number, err := strconv.Atoi(values[i])
+		if err != nil || number <= 0 {
+			return nil, fmt.Errorf("invalid xpm values %q", lines[0])
+		}
+		numbers[i] = number
+	}
+	width, height, colorCount, charsPerPixel := numbers[0], numbers[1], numbers[2], numbers[3]
+	if width > xpmMaxSize || height > xpmMaxSize {
+		return nil, fmt.Errorf("unsupported xpm size %dx%d", width, height)
+	}
+	if colorCount > xpmMaxColors || charsPerPixel > xpmMaxCharsPerPixel {
+		return nil, fmt.Errorf("unsupported xpm colors %q", lines[0])
+	}
+	// Written so that none of the sums can overflow.
+	if height > len(lines)-1 || colorCount > len(lines)-1-height {
+		return nil, errors.New("xpm is truncated")
+	}
+
+	colors := make(map[string]color.NRGBA, colorCount)
+	for _, line := range lines[1 : 1+colorCount] {
+		if len(line) < charsPerPixel {
+			return nil, fmt.Errorf("invalid xpm color %q", line)
+		}
+		c, err := xpmColor(line[charsPerPixel:])
+		if err != nil {
+			return nil, err
+		}
+		colors[line[:charsPerPixel]] = c
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y, row := range lines[1+colorCount : 1+colorCount+height] {
+		if width > len(row)/charsPerPixel {
+			return nil, fmt.Errorf("xpm row %d is too short", y+1)
+		}
+		for x := range width {
+			key := row[x*charsPerPixel : (x+1)*charsPerPixel]
+			c, ok := colors[key]
+			if !ok {
+				return nil, fmt.Errorf("xpm pixel %q has no color", key)
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img, nil
+}
+
+// xpmStrings returns the C strings of an XPM3 file, or its lines for XPM2.
+func xpmStrings(data string) []string {
+	if strings.HasPrefix(data, "! XPM2") {
+		lines := strings.Split(data, "\n")
+		return lines[1:]
+	}
+
+	var strs []string
+	for {
+		start := strings.IndexByte(data, '"')
+		if start < 0 {
+			return strs
+		}
+		end := strings.IndexByte(data[start+1:], '"')
+		if end < 0 {
+			return strs
+		}
+		strs = append(strs, data[start+1:start+1+end])
+		data = data[start+end+2:]
+	}
+}
+
+// xpmColor parses the color definition that follows the pixel characters,
+// such as "c #ff0000 m black" or "s background c None".
+func xpmColor(definition string) (color.NRGBA, error) {
+	fields := strings.Fields(definition)
+	contexts := make(map[string]string)
+	for i := 0; i < len(fields); {
+		key := fields[i]
+		i++
+		var value []string
+		for i < len(fields) && !isXPMContextKey(fields[i]) {
+			value = append(value, fields[i])
+			i++
+		}
+		contexts[key] = strings.Join(value, " ")
+	}
+
+	for _, key := range xpmColorKeys {
+		if value, ok := contexts[key]; ok {
+			return parseXPMColorValue(value)
+		}
+	}
+	return color.NRGBA{}, fmt.Errorf("xpm color %q has no color value", definition)
+}
+
+func isXPMContextKey(field string) bool {
+	return field == "s" || field == "c" || field == "g" || field == "g4" || field == "m"
+}
+
+func parseXPMColorValue(value string) (color.NRGBA, error) {
+	lower := strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	if lower == "none" {
+		return color.NRGBA{}, nil
+	}
+	if hex, ok := strings.CutPrefix(lower, "#"); ok {
+		return parseHexColor(hex)
+	}
+	if c, ok := xpmNamedColors[lower]; ok {
+		return c, nil
+	}
+	// X11 names grays by percentage, such as gray50.
+	for _, prefix := range []string{"gray", "grey"} {
+		if percent, ok := strings.CutPrefix(lower, prefix); ok {
+			if level, err := strconv.Atoi(percent); err == nil && level >= 0 && level <= 100 {
+				shade := uint8((level*255 + 50) / 100)
+				return color.NRGBA{R: shade, G: shade, B: shade, A: 0xff}, nil
+			}
+		}
+	}
+	return color.NRGBA{}, fmt.Errorf("unsupported xpm color %q", value)
+}
+
+// parseHexColor parses #RGB, #RRGGBB, #RRRGGGBBB or #RRRRGGGGBBBB without
+// the leading #, keeping the most significant byte of each channel.
+func parseHexColor(hex string) (color.NRGBA, error) {
+	if len(hex) == 0 || len(hex)%3 != 0 || len(hex) > 12 {
+		return color.NRGBA{}, fmt.Errorf("invalid xpm color #%s", hex)
+	}
+	digits := len(hex) / 3
+	var channels [3]uint8
+	for i := range channels {
+		value, err := strconv.ParseUint(hex[i*digits:(i+1)*digits], 16, 16)
+		if err != nil {
+			return color.NRGBA{}, fmt.Errorf("invalid xpm color #%s", hex)
+		}
+		if digits == 1 {
+			channels[i] = uint8(value * 0x11)
+		} else {
+			channels[i] = uint8(value >> (4 * (digits - 2)))
+		}
+	}
+	return color.NRGBA{R: channels[0], G: channels[1], B: channels[2], A: 0xff}, nil
+}
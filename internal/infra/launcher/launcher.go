@@ -0,0 +1,74 @@
+// Package launcher starts installed apps in place of the aim process.
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+// Launcher execs apps with execve(2), so the app keeps aim's process ID,
+// terminal, and signals, and aim does not linger while the app runs.
+type Launcher struct {
+	lookPath func(file string) (string, error)
+	environ  func() []string
+	execve   func(argv0 string, argv []string, envv []string) error
+}
+
+// New creates a Launcher for the current process.
+func New() Launcher {
+	return Launcher{
+		lookPath: exec.LookPath,
+		environ:  os.Environ,
+		execve:   syscall.Exec,
+	}
+}
+
+var _ app.AppLauncher = Launcher{}
+
+func (l Launcher) Exec(ctx context.Context, argv []string, env []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(argv) == 0 || strings.TrimSpace(argv[0]) == "" {
+		return errors.New("command is required")
+	}
+
+	path, err := l.lookPath(argv[0])
+	if err != nil {
+		return err
+	}
+	if err := l.execve(path, argv, mergeEnv(l.environ(), env)); err != nil {
+		return fmt.Errorf("exec %s: %w", path, err)
+	}
+	return nil
+}
+
+// mergeEnv returns base with the variables of overrides replacing those of
+// the same name, since programs disagree on which duplicate wins.
+func mergeEnv(base []string, overrides []string) []string {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	overridden := make(map[string]bool, len(overrides))
+	for _, entry := range overrides {
+		key, _, _ := strings.Cut(entry, "=")
+		overridden[key] = true
+	}
+
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if !overridden[key] {
+			merged = append(merged, entry)
+		}
+	}
+	return append(merged, overrides...)
+}
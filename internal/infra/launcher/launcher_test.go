@@ -0,0 +1,54 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLauncherExecResolvesCommandAndMergesEnvironment(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotArgv, gotEnv []string
+	launcher := Launcher{
+		lookPath: func(file string) (string, error) { return "/usr/bin/" + file, nil },
+		environ:  func() []string { return []string{"HOME=/home/user", "GDK_SCALE=1", "PATH=/usr/bin"} },
+		execve: func(argv0 string, argv []string, envv []string) error {
+			gotPath, gotArgv, gotEnv = argv0, argv, envv
+			return nil
+		},
+	}
+
+	argv := []string{"bwrap", "--unshare-pid", "/apps/example.AppImage", "--no-sandbox"}
+	if err := launcher.Exec(context.Background(), argv, []string{"GDK_SCALE=2"}); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if gotPath != "/usr/bin/bwrap" || !reflect.DeepEqual(gotArgv, argv) {
+		t.Fatalf("execve(%q, %q), want /usr/bin/bwrap, %q", gotPath, gotArgv, argv)
+	}
+	if want := []string{"HOME=/home/user", "PATH=/usr/bin", "GDK_SCALE=2"}; !reflect.DeepEqual(gotEnv, want) {
+		t.Fatalf("env = %q, want %q", gotEnv, want)
+	}
+}
+
+func TestLauncherExecReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	launcher := Launcher{
+		lookPath: func(file string) (string, error) { return file, nil },
+		environ:  func() []string { return nil },
+		execve:   func(string, []string, []string) error { return errors.New("permission denied") },
+	}
+
+	err := launcher.Exec(context.Background(), []string{"/apps/example.AppImage"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "exec /apps/example.AppImage: permission denied") {
+		t.Fatalf("Exec() error = %v, want exec failure", err)
+	}
+	if err := launcher.Exec(context.Background(), nil, nil); err == nil {
+		t.Fatal("Exec(nil) error = nil, want error")
+	}
+}
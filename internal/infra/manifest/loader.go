@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// maxManifestBytes bounds manifests fetched over HTTP.
+const maxManifestBytes = 1 << 20
+
+// Loader reads TOML app manifests from local files or http(s) URLs.
+type Loader struct {
+	HTTPClient *http.Client
+}
+
+// NewLoader creates a manifest loader that uses the default HTTP client.
+func NewLoader() Loader {
+	return Loader{}
+}
+
+var _ app.ManifestLoader = Loader{}
+
+type manifestFile struct {
+	Apps []manifestAppRecord `toml:"apps"`
+}
+
+type manifestAppRecord struct {
+	ID         string `toml:"id"`
+	GitHub     string `toml:"github"`
+	URL        string `toml:"url"`
+	Asset      string `toml:"asset"`
+	Prerelease bool   `toml:"prerelease"`
+	Version    string `toml:"version"`
+}
+
+func (l Loader) Load(ctx context.Context, location string) (app.Manifest, error) {
+	if err := ctx.Err(); err != nil {
+		return app.Manifest{}, err
+	}
+
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return app.Manifest{}, errors.New("manifest location is required")
+	}
+
+	var content []byte
+	var err error
+	if isRemote(location) {
+		content, err = l.fetch(ctx, location)
+	} else {
+		content, err = os.ReadFile(location)
+		if err != nil {
+			err = fmt.Errorf("read manifest %q: %w", location, err)
+		}
+	}
+	if err != nil {
+		return app.Manifest{}, err
+	}
+
+	var file manifestFile
+	decoder := toml.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return app.Manifest{}, fmt.Errorf("parse manifest %q: %w", location, err)
+	}
+
+	manifest := app.Manifest{Apps: make([]app.ManifestApp, 0, len(file.Apps))}
+	for _, record := range file.Apps {
+		manifest.Apps = append(manifest.Apps, app.ManifestApp{
+			ID:           record.ID,
+			GitHubRepo:   record.GitHub,
+			URL:          record.URL,
+			AssetPattern: record.Asset,
+			Prerelease:   record.Prerelease,
+			Version:      record.Version,
+		})
+	}
+
+	return manifest, nil
+}
+
+func (l Loader) fetch(ctx context.Context, location string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create manifest request %q: %w", location, err)
+	}
+
+	client := l.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetch manifest %q: %w", location, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch manifest %q: unexpected status %s", location, response.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxManifestBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest %q: %w", location, err)
+	}
+	if len(content) > maxManifestBytes {
+		return nil, fmt.Errorf("fetch manifest %q: manifest exceeds %d bytes", location, maxManifestBytes)
+	}
+
+	return content, nil
+}
+
+func isRemote(location string) bool {
+	return strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://")
+}
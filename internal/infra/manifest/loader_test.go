@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const testManifest = `
+[[apps]]
+id = "obsidian"
+github = "obsidianmd/obsidian-releases"
+asset = "Obsidian-*.AppImage"
+version = "v1.6.7"
+
+[[apps]]
+id = "example"
+url = "https://example.test/Example.AppImage"
+`
+
+func TestLoaderReadsFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.toml")
+	if err := os.WriteFile(path, []byte(testManifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	manifest, err := NewLoader().Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	assertTestManifest(t, manifest)
+}
+
+func TestLoaderFetchesURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team/manifest.toml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testManifest))
+	}))
+	defer server.Close()
+
+	loader := Loader{HTTPClient: server.Client()}
+	manifest, err := loader.Load(context.Background(), server.URL+"/team/manifest.toml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	assertTestManifest(t, manifest)
+
+	if _, err := loader.Load(context.Background(), server.URL+"/missing.toml"); err == nil {
+		t.Fatal("Load() error = nil, want status error")
+	}
+}
+
+func TestLoaderRejectsUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.toml")
+	if err := os.WriteFile(path, []byte("[[apps]]\nid = \"example\"\nrepo = \"owner/repo\"\n"), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	if _, err := NewLoader().Load(context.Background(), path); err == nil {
+		t.Fatal("Load() error = nil, want unknown key error")
+	}
+}
+
+func TestLoaderReturnsMissingFileError(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewLoader().Load(context.Background(), filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Fatal("Load() error = nil, want missing file error")
+	}
+}
+
+func assertTestManifest(t *testing.T, manifest app.Manifest) {
+	t.Helper()
+
+	want := []app.ManifestApp{
+		{ID: "obsidian", GitHubRepo: "obsidianmd/obsidian-releases", AssetPattern: "Obsidian-*.AppImage", Version: "v1.6.7"},
+		{ID: "example", URL: "https://example.test/Example.AppImage"},
+	}
+	if len(manifest.Apps) != len(want) {
+		t.Fatalf("Apps = %#v, want %#v", manifest.Apps, want)
+	}
+	for i := range want {
+		if manifest.Apps[i] != want[i] {
+			t.Fatalf("Apps[%d] = %#v, want %#v", i, manifest.Apps[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const lockFileHeader = "# Generated by aim lock. Do not edit by hand.\n\n"
+
+// LockStore reads and writes aim.lock files in TOML.
+type LockStore struct{}
+
+var _ app.LockFileStore = LockStore{}
+
+type lockFile struct {
+	Apps []lockedAppRecord `toml:"apps"`
+}
+
+type lockedAppRecord struct {
+	ID     string `toml:"id"`
+	GitHub string `toml:"github,omitempty"`
+	Tag    string `toml:"tag,omitempty"`
+	Asset  string `toml:"asset,omitempty"`
+	URL    string `toml:"url"`
+	Size   int64  `toml:"size"`
+	SHA256 string `toml:"sha256"`
+}
+
+func (LockStore) Read(ctx context.Context, path string) (app.LockFile, error) {
+	if err := ctx.Err(); err != nil {
+		return app.LockFile{}, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return app.LockFile{}, fmt.Errorf("read lock file %q: %w", path, app.ErrLockFileNotFound)
+		}
+		return app.LockFile{}, fmt.Errorf("read lock file %q: %w", path, err)
+	}
+
+	var file lockFile
+	decoder := toml.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return app.LockFile{}, fmt.Errorf("parse lock file %q: %w", path, err)
+	}
+
+	lock := app.LockFile{Apps: make([]app.LockedApp, 0, len(file.Apps))}
+	for _, record := range file.Apps {
+		lock.Apps = append(lock.Apps, app.LockedApp{
+			ID:         strings.TrimSpace(record.ID),
+			GitHubRepo: strings.TrimSpace(record.GitHub),
+			Tag:        strings.TrimSpace(record.Tag),
+			AssetName:  strings.TrimSpace(record.Asset),
+			URL:        strings.TrimSpace(record.URL),
+			SizeBytes:  record.Size,
+			SHA256:     strings.ToLower(strings.TrimSpace(record.SHA256)),
+		})
+	}
+
+	return lock, nil
+}
+
+func (LockStore) Write(ctx context.Context, path string, lock app.LockFile) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(path) == "" {
+		return errors.New("lock file path is required")
+	}
+
+	file := lockFile{Apps: make([]lockedAppRecord, 0, len(lock.Apps))}
+	for _, locked := range lock.Apps {
+		file.Apps = append(file.Apps, lockedAppRecord{
+			ID:     locked.ID,
+			GitHub: locked.GitHubRepo,
+			Tag:    locked.Tag,
+			Asset:  locked.AssetName,
+			URL:    locked.URL,
+			Size:   locked.SizeBytes,
+			SHA256: locked.SHA256,
+		})
+	}
+
+	encoded, err := toml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("encode lock file: %w", err)
+	}
+	content := append([]byte(lockFileHeader), encoded...)
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create lock file directory %q: %w", dir, err)
+		}
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, content, 0o644); err != nil {
+		return fmt.Errorf("write lock file %q: %w", path, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("write lock file %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestLockStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "team", "aim.lock")
+	lock := app.LockFile{Apps: []app.LockedApp{
+		{
+			ID:         "obsidian",
+			GitHubRepo: "obsidianmd/obsidian-releases",
+			Tag:        "v1.6.7",
+			AssetName:  "Obsidian-1.6.7.AppImage",
+			URL:        "https://github.com/obsidianmd/obsidian-releases/releases/download/v1.6.7/Obsidian-1.6.7.AppImage",
+			SizeBytes:  123,
+			SHA256:     "abc123",
+		},
+		{ID: "example", URL: "https://example.test/Example.AppImage", SizeBytes: 8, SHA256: "def456"},
+	}}
+
+	store := LockStore{}
+	if err := store.Write(context.Background(), path, lock); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read lock file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), lockFileHeader) {
+		t.Fatalf("lock file = %q, want header", content)
+	}
+
+	got, err := store.Read(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, lock) {
+		t.Fatalf("Read() = %#v, want %#v", got, lock)
+	}
+}
+
+func TestLockStoreReportsMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := LockStore{}.Read(context.Background(), filepath.Join(t.TempDir(), "aim.lock"))
+	if !errors.Is(err, app.ErrLockFileNotFound) {
+		t.Fatalf("Read() error = %v, want ErrLockFileNotFound", err)
+	}
+}
@@ -0,0 +1,260 @@
+package mime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const (
+	defaultApplicationsGroup = "Default Applications"
+	addedAssociationsGroup   = "Added Associations"
+	removedAssociationsGroup = "Removed Associations"
+)
+
+// Associations edits the user's mimeapps.list, usually
+// $XDG_CONFIG_HOME/mimeapps.list. Lines it does not change, including
+// comments and other groups, are kept as they are.
+type Associations struct {
+	Path string
+}
+
+// NewAssociations creates an editor for the mimeapps.list at path.
+func NewAssociations(path string) Associations {
+	return Associations{Path: path}
+}
+
+var _ app.MimeAssociations = Associations{}
+
+// SetDefault makes desktopFile the default application for mimeTypes and
+// adds it to their associations, so it is offered even when its desktop entry
+// does not list them.
+func (a Associations) SetDefault(ctx context.Context, desktopFile string, mimeTypes []string) error {
+	if strings.TrimSpace(desktopFile) == "" {
+		return errors.New("desktop file name is required")
+	}
+	return a.edit(ctx, func(list *mimeAppsList) {
+		for _, mimeType := range mimeTypes {
+			list.set(defaultApplicationsGroup, mimeType, func([]string) []string {
+				return []string{desktopFile}
+			})
+			list.set(addedAssociationsGroup, mimeType, func(values []string) []string {
+				return append([]string{desktopFile}, without(values, desktopFile)...)
+			})
+			list.set(removedAssociationsGroup, mimeType, func(values []string) []string {
+				return without(values, desktopFile)
+			})
+		}
+	})
+}
+
+// Rename points the defaults and associations of oldDesktopFile at
+// newDesktopFile.
+func (a Associations) Rename(ctx context.Context, oldDesktopFile string, newDesktopFile string) error {
+	if strings.TrimSpace(oldDesktopFile) == "" || strings.TrimSpace(newDesktopFile) == "" {
+		return errors.New("desktop file names are required")
+	}
+	return a.replace(ctx, oldDesktopFile, newDesktopFile)
+}
+
+// Forget removes desktopFile from all defaults and associations.
+func (a Associations) Forget(ctx context.Context, desktopFile string) error {
+	if strings.TrimSpace(desktopFile) == "" {
+		return errors.New("desktop file name is required")
+	}
+	return a.replace(ctx, desktopFile, "")
+}
+
+func (a Associations) replace(ctx context.Context, oldDesktopFile string, newDesktopFile string) error {
+	return a.edit(ctx, func(list *mimeAppsList) {
+		for _, group := range []string{defaultApplicationsGroup, addedAssociationsGroup, removedAssociationsGroup} {
+			list.each(group, func(values []string) []string {
+				replaced := make([]string, 0, len(values))
+				for _, value := range values {
+					if value == oldDesktopFile {
+						value = newDesktopFile
+					}
+					if value != "" && !slices.Contains(replaced, value) {
+						replaced = append(replaced, value)
+					}
+				}
+				return replaced
+			})
+		}
+	})
+}
+
+// edit applies change to the file and writes it back when it changed. A
+// missing file is treated as empty.
+func (a Associations) edit(ctx context.Context, change func(list *mimeAppsList)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(a.Path) == "" {
+		return errors.New("mimeapps.list path is required")
+	}
+
+	content, err := os.ReadFile(a.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read %q: %w", a.Path, err)
+	}
+	list := parseMimeAppsList(string(content))
+	change(list)
+	updated := list.String()
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.Path), 0o755); err != nil {
+		return fmt.Errorf("create directory of %q: %w", a.Path, err)
+	}
+	temporaryPath := a.Path + ".tmp"
+	if err := os.WriteFile(temporaryPath, []byte(updated), 0o644); err != nil {
+		return fmt.Errorf("write %q: %w", a.Path, err)
+	}
+	if err := os.Rename(temporaryPath, a.Path); err != nil {
+		_ = os.Remove(temporaryPath)
+		return fmt.Errorf("write %q: %w", a.Path, err)
+	}
+	return nil
+}
+
+// mimeAppsList is a mimeapps.list as lines, edited in place.
+type mimeAppsList struct {
+	lines []string
+}
+
+func parseMimeAppsList(content string) *mimeAppsList {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return &mimeAppsList{}
+	}
+	return &mimeAppsList{lines: strings.Split(content, "\n")}
+}
+
+func (l *mimeAppsList) String() string {
+	if len(l.lines) == 0 {
+		return ""
+	}
+	return strings.Join(l.lines, "\n") + "\n"
+}
+
+// each replaces the values of every key in group with change(values). Keys
+// left without values are removed.
+func (l *mimeAppsList) each(group string, change func(values []string) []string) {
+	current := ""
+	for i := 0; i < len(l.lines); i++ {
+		if name, ok := groupName(l.lines[i]); ok {
+			current = name
+			continue
+		}
+		key, values, ok := entry(l.lines[i])
+		if current != group || !ok {
+			continue
+		}
+		if changed := change(values); !slices.Equal(changed, values) && !l.replaceLine(i, key, changed) {
+			i--
+		}
+	}
+}
+
+// set replaces the values of key in group with change(values), adding the key
+// and the group when they are missing.
+func (l *mimeAppsList) set(group string, key string, change func(values []string) []string) {
+	current := ""
+	// end is the index after the last entry of group.
+	end := -1
+	for i, line := range l.lines {
+		if name, ok := groupName(line); ok {
+			current = name
+			if name == group {
+				end = i + 1
+			}
+			continue
+		}
+		if current != group {
+			continue
+		}
+		if lineKey, values, ok := entry(line); ok {
+			if lineKey == key {
+				if changed := change(values); !slices.Equal(changed, values) {
+					l.replaceLine(i, key, changed)
+				}
+				return
+			}
+			end = i + 1
+		}
+	}
+
+	values := change(nil)
+	if len(values) == 0 {
+		return
+	}
+	line := formatEntry(key, values)
+	if end < 0 {
+		if len(l.lines) > 0 && strings.TrimSpace(l.lines[len(l.lines)-1]) != "" {
+			l.lines = append(l.lines, "")
+		}
+		l.lines = append(l.lines, "["+group+"]", line)
+		return
+	}
+	l.lines = append(l.lines[:end], append([]string{line}, l.lines[end:]...)...)
+}
+
+// replaceLine sets line i to key with values, or removes it when values is
+// empty. It reports whether the line was kept.
+func (l *mimeAppsList) replaceLine(i int, key string, values []string) bool {
+	if len(values) == 0 {
+		l.lines = append(l.lines[:i], l.lines[i+1:]...)
+		return false
+	}
+	l.lines[i] = formatEntry(key, values)
+	return true
+}
+
+func groupName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return line[1 : len(line)-1], true
+}
+
+func entry(line string) (string, []string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", nil, false
+	}
+	key, value, ok := strings.Cut(trimmed, "=")
+	if !ok {
+		return "", nil, false
+	}
+
+	var values []string
+	for _, value := range strings.Split(value, ";") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return strings.TrimSpace(key), values, true
+}
+
+func formatEntry(key string, values []string) string {
+	return key + "=" + strings.Join(values, ";") + ";"
+}
+
+func without(values []string, value string) []string {
+	kept := make([]string, 0, len(values))
+	for _, candidate := range values {
+		if candidate != value {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
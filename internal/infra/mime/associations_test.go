@@ -0,0 +1,119 @@
+package mime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAssociationsSetDefaultCreatesFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config", "mimeapps.list")
+
+	if err := NewAssociations(path).SetDefault(context.Background(), "example.desktop", []string{"text/html", "x-scheme-handler/https"}); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+
+	want := `[Default Applications]
+text/html=example.desktop;
+x-scheme-handler/https=example.desktop;
+
+[Added Associations]
+text/html=example.desktop;
+x-scheme-handler/https=example.desktop;
+`
+	assertFile(t, path, want)
+}
+
+func TestAssociationsSetDefaultKeepsOtherEntries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mimeapps.list")
+	writeFile(t, path, `# managed by hand
+[Default Applications]
+text/html=firefox.desktop
+image/png=eog.desktop;
+
+[Added Associations]
+text/html=firefox.desktop;
+
+[Removed Associations]
+text/html=example.desktop;
+`)
+
+	if err := NewAssociations(path).SetDefault(context.Background(), "example.desktop", []string{"text/html"}); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+
+	assertFile(t, path, `# managed by hand
+[Default Applications]
+text/html=example.desktop;
+image/png=eog.desktop;
+
+[Added Associations]
+text/html=example.desktop;firefox.desktop;
+
+[Removed Associations]
+`)
+}
+
+func TestAssociationsRenameAndForget(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mimeapps.list")
+	writeFile(t, path, `[Default Applications]
+text/html=old.desktop;
+image/png=eog.desktop;
+
+[Added Associations]
+text/html=new.desktop;old.desktop;firefox.desktop;
+`)
+	associations := NewAssociations(path)
+
+	if err := associations.Rename(context.Background(), "old.desktop", "new.desktop"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	assertFile(t, path, `[Default Applications]
+text/html=new.desktop;
+image/png=eog.desktop;
+
+[Added Associations]
+text/html=new.desktop;firefox.desktop;
+`)
+
+	if err := associations.Forget(context.Background(), "new.desktop"); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	assertFile(t, path, `[Default Applications]
+image/png=eog.desktop;
+
+[Added Associations]
+text/html=firefox.desktop;
+`)
+}
+
+func TestAssociationsForgetWithoutFileWritesNothing(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "mimeapps.list")
+
+	if err := NewAssociations(path).Forget(context.Background(), "example.desktop"); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("stat error = %v, want no file", err)
+	}
+}
+
+func assertFile(t *testing.T, path string, want string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(content) != want {
+		t.Fatalf("%s =\n%s\nwant\n%s", path, content, want)
+	}
+}
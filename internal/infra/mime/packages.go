@@ -0,0 +1,160 @@
+// Package mime registers the MIME types AppImages bundle with the shared MIME
+// database and edits the default applications in mimeapps.list.
+package mime
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/infra/fileutil"
+)
+
+// packagesDir is where AppImages and the shared MIME database keep MIME
+// package files, relative to the AppImage root and the database directory.
+const packagesDir = "packages"
+
+// PackageDiscoverer finds the MIME packages bundled in extracted AppImage
+// filesystems.
+type PackageDiscoverer struct{}
+
+var _ app.MimePackageDiscoverer = PackageDiscoverer{}
+
+// Discover returns the usr/share/mime/packages/*.xml files under rootDir in
+// name order. An AppImage without them has none.
+func (PackageDiscoverer) Discover(ctx context.Context, rootDir string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if rootDir == "" {
+		return nil, errors.New("mime package discovery root directory is required")
+	}
+
+	dir := filepath.Join(rootDir, "usr", "share", "mime", packagesDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read mime packages %q: %w", dir, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !strings.EqualFold(filepath.Ext(entry.Name()), ".xml") {
+			continue
+		}
+		// Symlinks pointing outside the AppImage are skipped.
+		path := filepath.Join(dir, entry.Name())
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() || !withinRoot(rootDir, path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func withinRoot(rootDir string, path string) bool {
+	resolvedRoot, err := filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// PackageInstaller installs MIME packages into a shared MIME database
+// directory, usually $XDG_DATA_HOME/mime. update-mime-database has to run
+// afterwards for them to take effect.
+type PackageInstaller struct {
+	Dir string
+}
+
+// NewPackageInstaller creates a MIME package installer for the database in
+// dir.
+func NewPackageInstaller(dir string) PackageInstaller {
+	return PackageInstaller{Dir: dir}
+}
+
+var _ app.MimePackageInstaller = PackageInstaller{}
+
+// Install copies sourcePaths into the packages directory of the database as
+// <appID>.xml, <appID>.2.xml and so on; app IDs contain no dots, so packages
+// of different apps cannot collide. Packages installed before a failure are
+// removed again.
+func (i PackageInstaller) Install(ctx context.Context, appID string, sourcePaths []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(appID) == "" {
+		return nil, errors.New("app id is required")
+	}
+	if strings.TrimSpace(i.Dir) == "" {
+		return nil, errors.New("mime database directory is required")
+	}
+	if len(sourcePaths) == 0 {
+		return nil, nil
+	}
+	for _, sourcePath := range sourcePaths {
+		if err := checkPackage(sourcePath); err != nil {
+			return nil, err
+		}
+	}
+
+	destinationDir := filepath.Join(i.Dir, packagesDir)
+	if err := os.MkdirAll(destinationDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mime package directory %q: %w", destinationDir, err)
+	}
+
+	installed := make([]string, 0, len(sourcePaths))
+	for n, sourcePath := range sourcePaths {
+		name := appID + ".xml"
+		if n > 0 {
+			name = fmt.Sprintf("%s.%d.xml", appID, n+1)
+		}
+		destination := filepath.Join(destinationDir, name)
+		if err := fileutil.CopyFile(ctx, sourcePath, destination); err != nil {
+			for _, path := range installed {
+				_ = fileutil.RemoveArtifact(context.WithoutCancel(ctx), path)
+			}
+			return nil, fmt.Errorf("install mime package %q to %q: %w", sourcePath, destination, err)
+		}
+		installed = append(installed, destination)
+	}
+	return installed, nil
+}
+
+// checkPackage rejects files that are not shared-mime-info packages, since
+// update-mime-database would otherwise fail for every app.
+func checkPackage(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open mime package %q: %w", path, err)
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("mime package %q is not valid XML: %w", path, err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "mime-info" {
+				return fmt.Errorf("mime package %q has root element %q, want mime-info", path, start.Name.Local)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package mime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testPackage = `<?xml version="1.0" encoding="UTF-8"?>
+<mime-info xmlns="http://www.freedesktop.org/standards/shared-mime-info">
+  <mime-type type="application/x-example">
+    <comment>Example document</comment>
+    <glob pattern="*.example"/>
+  </mime-type>
+</mime-info>
+`
+
+func TestPackageDiscovererFindsBundledPackages(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "usr", "share", "mime", "packages")
+	writeFile(t, filepath.Join(dir, "b.xml"), testPackage)
+	writeFile(t, filepath.Join(dir, "a.xml"), testPackage)
+	writeFile(t, filepath.Join(dir, "README"), "not a package")
+	outside := filepath.Join(t.TempDir(), "outside.xml")
+	writeFile(t, outside, testPackage)
+	if err := os.Symlink(outside, filepath.Join(dir, "c.xml")); err != nil {
+		t.Fatalf("Symlink() error = %v", err)
+	}
+
+	paths, err := PackageDiscoverer{}.Discover(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "a.xml"), filepath.Join(dir, "b.xml")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Discover() = %#v, want %#v", paths, want)
+	}
+}
+
+func TestPackageDiscovererWithoutPackages(t *testing.T) {
+	t.Parallel()
+
+	paths, err := PackageDiscoverer{}.Discover(context.Background(), t.TempDir())
+	if err != nil || len(paths) != 0 {
+		t.Fatalf("Discover() = %#v, %v, want none", paths, err)
+	}
+}
+
+func TestPackageInstallerNamesPackagesAfterAppID(t *testing.T) {
+	t.Parallel()
+
+	source := t.TempDir()
+	first := filepath.Join(source, "example.xml")
+	second := filepath.Join(source, "example-extra.xml")
+	writeFile(t, first, testPackage)
+	writeFile(t, second, testPackage)
+	dir := filepath.Join(t.TempDir(), "mime")
+
+	paths, err := NewPackageInstaller(dir).Install(context.Background(), "example", []string{first, second})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "packages", "example.xml"), filepath.Join(dir, "packages", "example.2.xml")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Install() = %#v, want %#v", paths, want)
+	}
+	for _, path := range paths {
+		if content, err := os.ReadFile(path); err != nil || string(content) != testPackage {
+			t.Fatalf("installed %s = %q, %v", path, content, err)
+		}
+	}
+}
+
+func TestPackageInstallerRejectsOtherXML(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "example.xml")
+	writeFile(t, source, `<?xml version="1.0"?><component type="desktop-application"/>`)
+	dir := filepath.Join(t.TempDir(), "mime")
+
+	_, err := NewPackageInstaller(dir).Install(context.Background(), "example", []string{source})
+	if err == nil || !strings.Contains(err.Error(), "want mime-info") {
+		t.Fatalf("Install() error = %v, want mime-info error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "packages")); !os.IsNotExist(err) {
+		t.Fatalf("packages directory stat error = %v, want not created", err)
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,590 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// This file implements the small part of the D-Bus wire protocol needed to
+// call methods on the session bus: SASL EXTERNAL authentication, marshaling
+// of method calls and unmarshaling of their replies.
+
+const (
+	messageMethodCall   byte = 1
+	messageMethodReturn byte = 2
+	messageError        byte = 3
+	messageSignal       byte = 4
+)
+
+const (
+	fieldPath        byte = 1
+	fieldInterface   byte = 2
+	fieldMember      byte = 3
+	fieldErrorName   byte = 4
+	fieldReplySerial byte = 5
+	fieldDestination byte = 6
+	fieldSender      byte = 7
+	fieldSignature   byte = 8
+)
+
+// maxMessageSize is the largest message the D-Bus specification allows.
+const maxMessageSize = 1 << 27
+
+// DialSessionBus connects to the session bus named by
+// DBUS_SESSION_BUS_ADDRESS, or to $XDG_RUNTIME_DIR/bus when it is unset.
+func DialSessionBus(ctx context.Context) (net.Conn, error) {
+	address := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
+	if strings.TrimSpace(address) == "" {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if runtimeDir == "" {
+			return nil, errors.New("no session bus: DBUS_SESSION_BUS_ADDRESS and XDG_RUNTIME_DIR are unset")
+		}
+		address = "unix:path=" + filepath.Join(runtimeDir, "bus")
+	}
+
+	sockets, err := parseBusAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	var dialErr error
+	for _, socket := range sockets {
+		conn, err := dialer.DialContext(ctx, "unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = errors.Join(dialErr, err)
+	}
+
+	return nil, fmt.Errorf("connect to session bus: %w", dialErr)
+}
+
+// parseBusAddress returns the unix socket addresses in a D-Bus server address
+// list. Abstract sockets are returned with the leading "@" that net.Dial
+// expects.
+func parseBusAddress(address string) ([]string, error) {
+	var sockets []string
+	for _, entry := range strings.Split(address, ";") {
+		transport, params, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || transport != "unix" {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ",") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok {
+				continue
+			}
+			value, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("parse session bus address %q: %w", address, err)
+			}
+			switch key {
+			case "path":
+				sockets = append(sockets, value)
+			case "abstract":
+				sockets = append(sockets, "@"+value)
+			}
+		}
+	}
+	if len(sockets) == 0 {
+		return nil, fmt.Errorf("session bus address %q has no unix socket", address)
+	}
+
+	return sockets, nil
+}
+
+// busConn is an authenticated connection to a message bus.
+type busConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	serial uint32
+}
+
+// authenticate performs the SASL EXTERNAL handshake as the current user and
+// switches conn to the binary protocol.
+func authenticate(conn net.Conn) (*busConn, error) {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := io.WriteString(conn, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		return nil, fmt.Errorf("authenticate with session bus: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("authenticate with session bus: %w", err)
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return nil, fmt.Errorf("authenticate with session bus: %s", strings.TrimSpace(line))
+	}
+	if _, err := io.WriteString(conn, "BEGIN\r\n"); err != nil {
+		return nil, fmt.Errorf("authenticate with session bus: %w", err)
+	}
+
+	return &busConn{conn: conn, reader: reader}, nil
+}
+
+// call sends a method call and waits for its reply, skipping signals and
+// other messages the bus sends in between.
+func (c *busConn) call(destination, path, iface, member, signature string, body []byte) ([]any, error) {
+	c.serial++
+	call := message{
+		Type:        messageMethodCall,
+		Serial:      c.serial,
+		Path:        path,
+		Interface:   iface,
+		Member:      member,
+		Destination: destination,
+		Signature:   signature,
+		Body:        body,
+	}
+	if _, err := c.conn.Write(call.marshal()); err != nil {
+		return nil, fmt.Errorf("call %s.%s: %w", iface, member, err)
+	}
+
+	for {
+		reply, err := readMessage(c.reader)
+		if err != nil {
+			return nil, fmt.Errorf("call %s.%s: %w", iface, member, err)
+		}
+		if reply.ReplySerial != call.Serial || (reply.Type != messageMethodReturn && reply.Type != messageError) {
+			continue
+		}
+
+		values, err := reply.values()
+		if err != nil {
+			return nil, fmt.Errorf("call %s.%s: %w", iface, member, err)
+		}
+		if reply.Type == messageError {
+			if len(values) > 0 {
+				if text, ok := values[0].(string); ok {
+					return nil, fmt.Errorf("call %s.%s: %s: %s", iface, member, reply.ErrorName, text)
+				}
+			}
+			return nil, fmt.Errorf("call %s.%s: %s", iface, member, reply.ErrorName)
+		}
+		return values, nil
+	}
+}
+
+// message is a D-Bus message with its header fields. Body holds the
+// marshaled arguments described by Signature.
+type message struct {
+	Type        byte
+	Flags       byte
+	Serial      uint32
+	Path        string
+	Interface   string
+	Member      string
+	ErrorName   string
+	ReplySerial uint32
+	Destination string
+	Sender      string
+	Signature   string
+	Body        []byte
+
+	order binary.ByteOrder
+}
+
+// marshal encodes m in little-endian byte order.
+func (m message) marshal() []byte {
+	e := &encoder{}
+	e.byte('l')
+	e.byte(m.Type)
+	e.byte(m.Flags)
+	e.byte(1)
+	e.uint32(uint32(len(m.Body)))
+	e.uint32(m.Serial)
+	e.array(8, func() {
+		field := func(code byte, signature string, value func()) {
+			e.align(8)
+			e.byte(code)
+			e.signature(signature)
+			value()
+		}
+		texts := []struct {
+			code      byte
+			signature string
+			value     string
+		}{
+			{fieldPath, "o", m.Path},
+			{fieldInterface, "s", m.Interface},
+			{fieldMember, "s", m.Member},
+			{fieldErrorName, "s", m.ErrorName},
+			{fieldDestination, "s", m.Destination},
+			{fieldSender, "s", m.Sender},
+		}
+		for _, f := range texts {
+			if f.value != "" {
+				field(f.code, f.signature, func() { e.string(f.value) })
+			}
+		}
+		if m.ReplySerial != 0 {
+			field(fieldReplySerial, "u", func() { e.uint32(m.ReplySerial) })
+		}
+		if m.Signature != "" {
+			field(fieldSignature, "g", func() { e.signature(m.Signature) })
+		}
+	})
+	e.align(8)
+
+	return append(e.buf, m.Body...)
+}
+
+// values unmarshals the body of m according to its signature.
+func (m message) values() ([]any, error) {
+	d := &decoder{buf: m.Body, order: m.order}
+	values, err := d.values(m.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode %q body: %w", m.Signature, err)
+	}
+	return values, nil
+}
+
+func readMessage(r io.Reader) (message, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return message{}, err
+	}
+
+	var order binary.ByteOrder
+	switch fixed[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return message{}, fmt.Errorf("invalid message byte order %q", fixed[0])
+	}
+	bodyLength := order.Uint32(fixed[4:8])
+	fieldsLength := order.Uint32(fixed[12:16])
+	if bodyLength > maxMessageSize || fieldsLength > maxMessageSize {
+		return message{}, errors.New("message exceeds the maximum size")
+	}
+
+	headerLength := padded(16+int(fieldsLength), 8)
+	buf := make([]byte, headerLength+int(bodyLength))
+	copy(buf, fixed)
+	if _, err := io.ReadFull(r, buf[16:]); err != nil {
+		return message{}, err
+	}
+
+	m := message{
+		Type:   fixed[1],
+		Flags:  fixed[2],
+		Serial: order.Uint32(fixed[8:12]),
+		Body:   buf[headerLength:],
+		order:  order,
+	}
+	d := &decoder{buf: buf[:headerLength], pos: 12, order: order}
+	fields, err := d.values("a(yv)")
+	if err != nil {
+		return message{}, fmt.Errorf("decode message header: %w", err)
+	}
+	for _, field := range fields[0].([]any) {
+		parts := field.([]any)
+		code, _ := parts[0].(byte)
+		value := parts[1].(variant).Value
+		switch code {
+		case fieldPath:
+			m.Path, _ = value.(string)
+		case fieldInterface:
+			m.Interface, _ = value.(string)
+		case fieldMember:
+			m.Member, _ = value.(string)
+		case fieldErrorName:
+			m.ErrorName, _ = value.(string)
+		case fieldReplySerial:
+			m.ReplySerial, _ = value.(uint32)
+		case fieldDestination:
+			m.Destination, _ = value.(string)
+		case fieldSender:
+			m.Sender, _ = value.(string)
+		case fieldSignature:
+			m.Signature, _ = value.(string)
+		}
+	}
+
+	return m, nil
+}
+
+func padded(n int, alignment int) int {
+	return (n + alignment - 1) / alignment * alignment
+}
+
+// encoder marshals values in little-endian byte order. Alignment is relative
+// to the start of buf, so a message body must be encoded on its own.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) align(alignment int) {
+	for len(e.buf)%alignment != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) byte(value byte) {
+	e.buf = append(e.buf, value)
+}
+
+func (e *encoder) uint32(value uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, value)
+}
+
+func (e *encoder) int32(value int32) {
+	e.uint32(uint32(value))
+}
+
+func (e *encoder) string(value string) {
+	e.uint32(uint32(len(value)))
+	e.buf = append(e.buf, value...)
+	e.buf = append(e.buf, 0)
+}
+
+func (e *encoder) signature(value string) {
+	e.byte(byte(len(value)))
+	e.buf = append(e.buf, value...)
+	e.buf = append(e.buf, 0)
+}
+
+// array writes the elements added by elements, which must be aligned to
+// elementAlignment, preceded by their byte length.
+func (e *encoder) array(elementAlignment int, elements func()) {
+	e.align(4)
+	lengthAt := len(e.buf)
+	e.buf = append(e.buf, 0, 0, 0, 0)
+	e.align(elementAlignment)
+	start := len(e.buf)
+	elements()
+	binary.LittleEndian.PutUint32(e.buf[lengthAt:], uint32(len(e.buf)-start))
+}
+
+// variant is an unmarshaled D-Bus variant.
+type variant struct {
+	Signature string
+	Value     any
+}
+
+// decoder unmarshals values. Arrays become []any, structs and dict entries
+// become []any of their fields, and variants become variant.
+type decoder struct {
+	buf   []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (d *decoder) values(signature string) ([]any, error) {
+	var values []any
+	for signature != "" {
+		single, rest, err := splitSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.value(single)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		signature = rest
+	}
+	return values, nil
+}
+
+func (d *decoder) value(signature string) (any, error) {
+	if err := d.align(alignment(signature[0])); err != nil {
+		return nil, err
+	}
+
+	switch signature[0] {
+	case 'y':
+		b, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0], nil
+	case 'b':
+		v, err := d.uint32()
+		return v != 0, err
+	case 'n':
+		b, err := d.take(2)
+		if err != nil {
+			return nil, err
+		}
+		return int16(d.order.Uint16(b)), nil
+	case 'q':
+		b, err := d.take(2)
+		if err != nil {
+			return nil, err
+		}
+		return d.order.Uint16(b), nil
+	case 'i':
+		v, err := d.uint32()
+		return int32(v), err
+	case 'u', 'h':
+		return d.uint32()
+	case 'x', 't', 'd':
+		b, err := d.take(8)
+		if err != nil {
+			return nil, err
+		}
+		v := d.order.Uint64(b)
+		switch signature[0] {
+		case 'x':
+			return int64(v), nil
+		case 'd':
+			return math.Float64frombits(v), nil
+		}
+		return v, nil
+	case 's', 'o':
+		length, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		return d.text(int(length))
+	case 'g':
+		b, err := d.take(1)
+		if err != nil {
+			return nil, err
+		}
+		return d.text(int(b[0]))
+	case 'v':
+		inner, err := d.value("g")
+		if err != nil {
+			return nil, err
+		}
+		innerSignature := inner.(string)
+		if single, rest, err := splitSignature(innerSignature); err != nil || rest != "" || single == "" {
+			return nil, fmt.Errorf("invalid variant signature %q", innerSignature)
+		}
+		value, err := d.value(innerSignature)
+		if err != nil {
+			return nil, err
+		}
+		return variant{Signature: innerSignature, Value: value}, nil
+	case 'a':
+		length, err := d.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.align(alignment(signature[1])); err != nil {
+			return nil, err
+		}
+		end := d.pos + int(length)
+		if end > len(d.buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		elements := []any{}
+		for d.pos < end {
+			element, err := d.value(signature[1:])
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		return elements, nil
+	case '(', '{':
+		return d.values(signature[1 : len(signature)-1])
+	}
+
+	return nil, fmt.Errorf("unsupported type %q", signature)
+}
+
+func (d *decoder) align(alignment int) error {
+	next := padded(d.pos, alignment)
+	if next > len(d.buf) {
+		return io.ErrUnexpectedEOF
+	}
+	d.pos = next
+	return nil
+}
+
+func (d *decoder) take(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.buf) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	b, err := d.take(4)
+	if err != nil {
+		return 0, err
+	}
+	return d.order.Uint32(b), nil
+}
+
+// text reads length bytes of text followed by a nul byte.
+func (d *decoder) text(length int) (string, error) {
+	b, err := d.take(length + 1)
+	if err != nil {
+		return "", err
+	}
+	return string(b[:length]), nil
+}
+
+func alignment(code byte) int {
+	switch code {
+	case 'n', 'q':
+		return 2
+	case 'b', 'i', 'u', 'h', 's', 'o', 'a':
+		return 4
+	case 'x', 't', 'd', '(', '{':
+		return 8
+	}
+	return 1
+}
+
+// splitSignature splits the first complete type off signature.
+func splitSignature(signature string) (string, string, error) {
+	if signature == "" {
+		return "", "", errors.New("empty signature")
+	}
+
+	switch signature[0] {
+	case 'a':
+		element, rest, err := splitSignature(signature[1:])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid signature %q: %w", signature, err)
+		}
+		return "a" + element, rest, nil
+	case '(', '{':
+		closing := byte(')')
+		if signature[0] == '{' {
+			closing = '}'
+		}
+		rest := signature[1:]
+		for rest != "" && rest[0] != closing {
+			_, next, err := splitSignature(rest)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid signature %q: %w", signature, err)
+			}
+			rest = next
+		}
+		if rest == "" {
+			return "", "", fmt.Errorf("invalid signature %q: missing %q", signature, closing)
+		}
+		length := len(signature) - len(rest) + 1
+		return signature[:length], signature[length:], nil
+	case 'y', 'b', 'n', 'q', 'i', 'u', 'h', 'x', 't', 'd', 's', 'o', 'g', 'v':
+		return signature[:1], signature[1:], nil
+	}
+
+	return "", "", fmt.Errorf("invalid signature %q", signature)
+}
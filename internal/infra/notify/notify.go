@@ -0,0 +1,173 @@
+// Package notify shows desktop notifications through the
+// org.freedesktop.Notifications service on the D-Bus session bus.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const (
+	busName    = "org.freedesktop.DBus"
+	busPath    = "/org/freedesktop/DBus"
+	serverName = "org.freedesktop.Notifications"
+	serverPath = "/org/freedesktop/Notifications"
+)
+
+// Notifier sends each notification over a new session bus connection, so it
+// holds no connection between the rare notifications aim shows.
+type Notifier struct {
+	AppName string
+	Icon    string
+	// Dial opens the connection to the session bus.
+	Dial func(ctx context.Context) (net.Conn, error)
+}
+
+// New creates a Notifier that connects to the user's session bus.
+func New(appName string) Notifier {
+	return Notifier{AppName: appName, Dial: DialSessionBus}
+}
+
+var _ app.Notifier = Notifier{}
+
+func (n Notifier) Notify(ctx context.Context, notification app.Notification) error {
+	return n.withBus(ctx, notification, func(bus *busConn) error {
+		_, err := n.send(bus, notification, nil)
+		return err
+	})
+}
+
+func (n Notifier) Ask(ctx context.Context, notification app.Notification, actions []app.NotificationAction) (string, error) {
+	var key string
+	err := n.withBus(ctx, notification, func(bus *busConn) error {
+		// Subscribe before showing the notification so no signal about it can
+		// be missed.
+		match := &encoder{}
+		match.string("type='signal',sender='" + serverName + "',path='" + serverPath + "',interface='" + serverName + "'")
+		if _, err := bus.call(busName, busPath, busName, "AddMatch", "s", match.buf); err != nil {
+			return err
+		}
+
+		id, err := n.send(bus, notification, actions)
+		if err != nil {
+			return err
+		}
+		key, err = waitForAction(bus, id)
+		return err
+	})
+	return key, err
+}
+
+// withBus runs fn on a new authenticated session bus connection that is
+// closed when ctx is done.
+func (n Notifier) withBus(ctx context.Context, notification app.Notification, fn func(bus *busConn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conn, err := n.Dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	err = func() error {
+		bus, err := authenticate(conn)
+		if err != nil {
+			return err
+		}
+		if _, err := bus.call(busName, busPath, busName, "Hello", "", nil); err != nil {
+			return err
+		}
+		return fn(bus)
+	}()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("show notification %q: %w", notification.Summary, err)
+	}
+
+	return nil
+}
+
+// send shows notification and returns the ID the server assigned to it.
+func (n Notifier) send(bus *busConn, notification app.Notification, actions []app.NotificationAction) (uint32, error) {
+	body := &encoder{}
+	body.string(n.AppName)
+	body.uint32(0) // replaces_id
+	body.string(n.Icon)
+	body.string(notification.Summary)
+	body.string(notification.Body)
+	body.array(4, func() {
+		for _, action := range actions {
+			body.string(action.Key)
+			body.string(action.Label)
+		}
+	})
+	body.array(8, func() {
+		body.align(8)
+		body.string("urgency")
+		body.signature("y")
+		body.byte(byte(notification.Urgency))
+	})
+	body.int32(-1) // expire_timeout: server default
+
+	values, err := bus.call(serverName, serverPath, serverName, "Notify", "susssasa{sv}i", body.buf)
+	if err != nil {
+		return 0, err
+	}
+	id, ok := firstValue[uint32](values)
+	if !ok {
+		return 0, fmt.Errorf("unexpected Notify reply %v", values)
+	}
+	return id, nil
+}
+
+// waitForAction reads signals until notification id is acted on or closed.
+func waitForAction(bus *busConn, id uint32) (string, error) {
+	for {
+		signal, err := readMessage(bus.reader)
+		if err != nil {
+			return "", fmt.Errorf("wait for notification action: %w", err)
+		}
+		if signal.Type != messageSignal || signal.Interface != serverName {
+			continue
+		}
+		values, err := signal.values()
+		if err != nil {
+			return "", fmt.Errorf("wait for notification action: %w", err)
+		}
+		if signalID, ok := firstValue[uint32](values); !ok || signalID != id {
+			continue
+		}
+
+		switch signal.Member {
+		case "ActionInvoked":
+			if len(values) > 1 {
+				if key, ok := values[1].(string); ok {
+					return key, nil
+				}
+			}
+		case "NotificationClosed":
+			return "", nil
+		}
+	}
+}
+
+func firstValue[T any](values []any) (T, bool) {
+	var zero T
+	if len(values) == 0 {
+		return zero, false
+	}
+	value, ok := values[0].(T)
+	return value, ok
+}
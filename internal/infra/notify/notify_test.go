@@ -0,0 +1,304 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestNotifierSendsNotifyCall(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+
+	err := notifier.Notify(context.Background(), app.Notification{
+		Summary: "1 app update available",
+		Body:    "example-app 1.2.3 → 2.0.0",
+		Urgency: app.NotificationUrgencyCritical,
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	call := bus.wait(t)
+	if call.Destination != serverName || call.Path != serverPath || call.Interface != serverName || call.Member != "Notify" {
+		t.Fatalf("Notify call = %#v", call)
+	}
+	args, err := call.values()
+	if err != nil {
+		t.Fatalf("decode Notify arguments: %v", err)
+	}
+	want := []any{
+		"aim",
+		uint32(0),
+		"",
+		"1 app update available",
+		"example-app 1.2.3 → 2.0.0",
+		[]any{},
+		[]any{[]any{"urgency", variant{Signature: "y", Value: byte(2)}}},
+		int32(-1),
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("Notify arguments = %#v, want %#v", args, want)
+	}
+}
+
+func TestNotifierAskReturnsInvokedAction(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	bus.signals = []fakeSignal{
+		{id: 3, member: "ActionInvoked", key: "other"},
+		{id: 7, member: "ActionInvoked", key: "add"},
+	}
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+
+	key, err := notifier.Ask(context.Background(), app.Notification{Summary: "New AppImage"}, []app.NotificationAction{{Key: "add", Label: "Integrate"}})
+	if err != nil {
+		t.Fatalf("Ask() error = %v", err)
+	}
+	if key != "add" {
+		t.Fatalf("Ask() = %q, want add", key)
+	}
+
+	args, err := bus.wait(t).values()
+	if err != nil {
+		t.Fatalf("decode Notify arguments: %v", err)
+	}
+	if got, want := args[5], []any{"add", "Integrate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Notify actions = %#v, want %#v", got, want)
+	}
+}
+
+func TestNotifierAskReturnsEmptyKeyWhenDismissed(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	bus.signals = []fakeSignal{{id: 7, member: "NotificationClosed"}}
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+
+	key, err := notifier.Ask(context.Background(), app.Notification{Summary: "New AppImage"}, []app.NotificationAction{{Key: "add", Label: "Integrate"}})
+	if err != nil || key != "" {
+		t.Fatalf("Ask() = %q, %v, want empty key", key, err)
+	}
+}
+
+func TestNotifierAskStopsWhenContextIsCanceled(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-bus.calls
+		cancel()
+	}()
+
+	if _, err := notifier.Ask(ctx, app.Notification{Summary: "New AppImage"}, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Ask() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNotifierReturnsErrorReply(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	bus.notifyError = "org.freedesktop.DBus.Error.ServiceUnknown"
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+
+	err := notifier.Notify(context.Background(), app.Notification{Summary: "Updated 1 app"})
+	if err == nil || !strings.Contains(err.Error(), "org.freedesktop.DBus.Error.ServiceUnknown: no notification server") {
+		t.Fatalf("Notify() error = %v, want ServiceUnknown error", err)
+	}
+}
+
+func TestNotifierReturnsRejectedAuthentication(t *testing.T) {
+	t.Parallel()
+
+	bus := newFakeBus(t)
+	bus.rejectAuth = true
+	notifier := Notifier{AppName: "aim", Dial: bus.dial}
+
+	err := notifier.Notify(context.Background(), app.Notification{Summary: "Updated 1 app"})
+	if err == nil || !strings.Contains(err.Error(), "authenticate with session bus: REJECTED EXTERNAL") {
+		t.Fatalf("Notify() error = %v, want rejected authentication", err)
+	}
+}
+
+func TestNotifierReturnsDialError(t *testing.T) {
+	t.Parallel()
+
+	dialErr := errors.New("no session bus")
+	notifier := Notifier{Dial: func(context.Context) (net.Conn, error) { return nil, dialErr }}
+
+	if err := notifier.Notify(context.Background(), app.Notification{Summary: "Updated 1 app"}); !errors.Is(err, dialErr) {
+		t.Fatalf("Notify() error = %v, want %v", err, dialErr)
+	}
+}
+
+func TestParseBusAddress(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseBusAddress("tcp:host=localhost,port=1;unix:path=/run/user/1000/bus;unix:abstract=/tmp/dbus-x%2cy,guid=abc")
+	if err != nil {
+		t.Fatalf("parseBusAddress() error = %v", err)
+	}
+	want := []string{"/run/user/1000/bus", "@/tmp/dbus-x,y"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseBusAddress() = %#v, want %#v", got, want)
+	}
+
+	if _, err := parseBusAddress("tcp:host=localhost,port=1"); err == nil {
+		t.Fatal("parseBusAddress() error = nil, want error for address without unix socket")
+	}
+}
+
+func TestReadMessageDecodesBigEndianReply(t *testing.T) {
+	t.Parallel()
+
+	// A method return for serial 1 with body (u 7), as sent by a big-endian bus.
+	raw := []byte{
+		'B', messageMethodReturn, 0, 1, 0, 0, 0, 4, 0, 0, 0, 9, 0, 0, 0, 15,
+		fieldReplySerial, 1, 'u', 0, 0, 0, 0, 1,
+		fieldSignature, 1, 'g', 0, 1, 'u', 0, 0,
+		0, 0, 0, 7,
+	}
+
+	m, err := readMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("readMessage() error = %v", err)
+	}
+	values, err := m.values()
+	if err != nil {
+		t.Fatalf("values() error = %v", err)
+	}
+	if m.Serial != 9 || m.ReplySerial != 1 || !reflect.DeepEqual(values, []any{uint32(7)}) {
+		t.Fatalf("message = %#v with values %#v", m, values)
+	}
+}
+
+// fakeBus is a session bus that authenticates one client, answers Hello and
+// AddMatch, and records the Notify call.
+type fakeBus struct {
+	rejectAuth  bool
+	notifyError string
+	// signals are sent after the Notify reply, as (notification ID, member,
+	// action key) triples.
+	signals []fakeSignal
+	calls   chan message
+}
+
+type fakeSignal struct {
+	id     uint32
+	member string
+	key    string
+}
+
+func newFakeBus(t *testing.T) *fakeBus {
+	t.Helper()
+	return &fakeBus{calls: make(chan message, 1)}
+}
+
+func (b *fakeBus) dial(ctx context.Context) (net.Conn, error) {
+	client, server := net.Pipe()
+	go b.serve(server)
+	return client, nil
+}
+
+func (b *fakeBus) wait(t *testing.T) message {
+	t.Helper()
+	select {
+	case call := <-b.calls:
+		return call
+	default:
+		t.Fatal("no Notify call received")
+		return message{}
+	}
+}
+
+func (b *fakeBus) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	if nul, err := reader.ReadByte(); err != nil || nul != 0 {
+		return
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "AUTH EXTERNAL ") {
+		return
+	}
+	if b.rejectAuth {
+		_, _ = io.WriteString(conn, "REJECTED EXTERNAL\r\n")
+		return
+	}
+	if _, err := io.WriteString(conn, "OK 0123456789abcdef0123456789abcdef\r\n"); err != nil {
+		return
+	}
+	if line, err := reader.ReadString('\n'); err != nil || line != "BEGIN\r\n" {
+		return
+	}
+
+	var serial uint32
+	reply := func(m message) bool {
+		serial++
+		m.Serial = serial
+		m.Sender = busName
+		_, err := conn.Write(m.marshal())
+		return err == nil
+	}
+	for {
+		call, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+
+		switch call.Member {
+		case "Hello":
+			name := &encoder{}
+			name.string(":1.42")
+			acquired := message{Type: messageSignal, Path: busPath, Interface: busName, Member: "NameAcquired", Signature: "s", Body: name.buf}
+			if !reply(acquired) || !reply(message{Type: messageMethodReturn, ReplySerial: call.Serial, Signature: "s", Body: name.buf}) {
+				return
+			}
+		case "AddMatch":
+			if !reply(message{Type: messageMethodReturn, ReplySerial: call.Serial}) {
+				return
+			}
+		case "Notify":
+			b.calls <- call
+			if b.notifyError != "" {
+				text := &encoder{}
+				text.string("no notification server")
+				reply(message{Type: messageError, ReplySerial: call.Serial, ErrorName: b.notifyError, Signature: "s", Body: text.buf})
+				return
+			}
+			id := &encoder{}
+			id.uint32(7)
+			if !reply(message{Type: messageMethodReturn, ReplySerial: call.Serial, Signature: "u", Body: id.buf}) {
+				return
+			}
+			for _, signal := range b.signals {
+				body := &encoder{}
+				body.uint32(signal.id)
+				signature := "uu"
+				if signal.member == "ActionInvoked" {
+					signature = "us"
+					body.string(signal.key)
+				} else {
+					body.uint32(2)
+				}
+				if !reply(message{Type: messageSignal, Path: serverPath, Interface: serverName, Member: signal.member, Signature: signature, Body: body.buf}) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Package sandbox resolves sandbox profiles for apps launched through
+// bubblewrap or firejail.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/domain"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	// ProfileStrict runs an app without network access and with a private
+	// home directory.
+	ProfileStrict = "strict"
+	// ProfileNetworkOnly runs an app with network access but a private home
+	// directory, for apps such as chat clients that only need the network.
+	ProfileNetworkOnly = "network-only"
+)
+
+// Resolver resolves the built-in profiles and TOML profile files.
+type Resolver struct {
+	// Dir holds the private home directories of sandboxed apps, one per app
+	// ID.
+	Dir string
+	// Home is the user's home directory; empty uses os.UserHomeDir.
+	Home string
+	// LookPath finds backends; nil uses exec.LookPath.
+	LookPath func(file string) (string, error)
+}
+
+// NewResolver creates a Resolver that keeps private homes in dir.
+func NewResolver(dir string) Resolver {
+	return Resolver{Dir: dir}
+}
+
+var _ app.SandboxProfileResolver = Resolver{}
+
+// profileFile is a custom profile, for example:
+//
+//	backend = "bwrap"
+//	network = true
+//	private_home = true
+//	binds = ["~/Downloads"]
+//	read_only_binds = ["~/Documents"]
+type profileFile struct {
+	Backend       string   `toml:"backend"`
+	Network       bool     `toml:"network"`
+	PrivateHome   bool     `toml:"private_home"`
+	Binds         []string `toml:"binds"`
+	ReadOnlyBinds []string `toml:"read_only_binds"`
+}
+
+func (r Resolver) Resolve(ctx context.Context, appID string, profile string, backend domain.SandboxBackend) (domain.Sandbox, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Sandbox{}, err
+	}
+	if strings.TrimSpace(appID) == "" {
+		return domain.Sandbox{}, errors.New("app id is required")
+	}
+
+	home := r.Home
+	if home == "" {
+		var err error
+		home, err = os.UserHomeDir()
+		if err != nil {
+			return domain.Sandbox{}, fmt.Errorf("resolve home directory: %w", err)
+		}
+	}
+
+	var file profileFile
+	switch profile = strings.TrimSpace(profile); profile {
+	case ProfileStrict:
+		file = profileFile{PrivateHome: true}
+	case ProfileNetworkOnly:
+		file = profileFile{Network: true, PrivateHome: true}
+	case "":
+		return domain.Sandbox{}, errors.New("sandbox profile is required")
+	default:
+		var err error
+		file, err = readProfile(profile)
+		if err != nil {
+			return domain.Sandbox{}, err
+		}
+	}
+
+	if backend == domain.SandboxBackendNone {
+		backend = domain.SandboxBackend(strings.TrimSpace(file.Backend))
+	}
+	backend, err := r.backend(backend)
+	if err != nil {
+		return domain.Sandbox{}, err
+	}
+
+	sandbox := domain.Sandbox{
+		Profile:  profile,
+		Backend:  backend,
+		Network:  file.Network,
+		UserHome: home,
+	}
+	if sandbox.Binds, err = resolvePaths(file.Binds, home); err != nil {
+		return domain.Sandbox{}, fmt.Errorf("sandbox profile %q: binds: %w", profile, err)
+	}
+	if sandbox.ReadOnlyBinds, err = resolvePaths(file.ReadOnlyBinds, home); err != nil {
+		return domain.Sandbox{}, fmt.Errorf("sandbox profile %q: read_only_binds: %w", profile, err)
+	}
+
+	if file.PrivateHome {
+		if backend == domain.SandboxBackendFirejail {
+			// firejail's --private cannot be combined with sharing other paths
+			// of the real home directory.
+			for _, path := range append(sandbox.Binds, sandbox.ReadOnlyBinds...) {
+				if within(path, home) {
+					return domain.Sandbox{}, fmt.Errorf("sandbox profile %q: firejail cannot share %q with a private home; use bwrap", profile, path)
+				}
+			}
+		}
+		if strings.TrimSpace(r.Dir) == "" {
+			return domain.Sandbox{}, errors.New("sandbox home directory is required")
+		}
+		sandbox.Home = filepath.Join(r.Dir, appID)
+		if err := os.MkdirAll(sandbox.Home, 0o700); err != nil {
+			return domain.Sandbox{}, fmt.Errorf("create sandbox home %q: %w", sandbox.Home, err)
+		}
+	}
+
+	return sandbox, nil
+}
+
+// backend returns the requested backend once it is known to be installed, or
+// the first installed one, preferring bubblewrap.
+func (r Resolver) backend(requested domain.SandboxBackend) (domain.SandboxBackend, error) {
+	lookPath := r.LookPath
+	if lookPath == nil {
+		lookPath = exec.LookPath
+	}
+
+	switch requested {
+	case domain.SandboxBackendNone:
+		for _, candidate := range []domain.SandboxBackend{domain.SandboxBackendBubblewrap, domain.SandboxBackendFirejail} {
+			if _, err := lookPath(string(candidate)); err == nil {
+				return candidate, nil
+			}
+		}
+		return domain.SandboxBackendNone, errors.New("neither bwrap nor firejail is installed")
+	case domain.SandboxBackendBubblewrap, domain.SandboxBackendFirejail:
+		if _, err := lookPath(string(requested)); err != nil {
+			return domain.SandboxBackendNone, fmt.Errorf("sandbox backend %s is not installed: %w", requested, err)
+		}
+		return requested, nil
+	default:
+		return domain.SandboxBackendNone, fmt.Errorf("unknown sandbox backend %q; use bwrap or firejail", requested)
+	}
+}
+
+func readProfile(path string) (profileFile, error) {
+	if !filepath.IsAbs(path) {
+		return profileFile{}, fmt.Errorf("unknown sandbox profile %q; use %s, %s, or the path of a TOML profile", path, ProfileStrict, ProfileNetworkOnly)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return profileFile{}, fmt.Errorf("read sandbox profile %q: %w", path, err)
+	}
+
+	var file profileFile
+	decoder := toml.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return profileFile{}, fmt.Errorf("parse sandbox profile %q: %w", path, err)
+	}
+	return file, nil
+}
+
+// resolvePaths expands a leading ~ to home and requires absolute paths, since
+// desktop entries are not run through a shell.
+func resolvePaths(paths []string, home string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "~" {
+			path = home
+		} else if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			path = filepath.Join(home, rest)
+		}
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("path %q must be absolute or start with ~/", path)
+		}
+		resolved = append(resolved, filepath.Clean(path))
+	}
+	return resolved, nil
+}
+
+func within(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestResolverResolvesBuiltInProfiles(t *testing.T) {
+	t.Parallel()
+
+	resolver := testResolver(t, "bwrap", "firejail")
+	tests := map[string]bool{ProfileStrict: false, ProfileNetworkOnly: true}
+	for profile, network := range tests {
+		sandbox, err := resolver.Resolve(context.Background(), "chat", profile, domain.SandboxBackendNone)
+		if err != nil {
+			t.Fatalf("Resolve(%q) error = %v", profile, err)
+		}
+
+		want := domain.Sandbox{
+			Profile:  profile,
+			Backend:  domain.SandboxBackendBubblewrap,
+			Network:  network,
+			Home:     filepath.Join(resolver.Dir, "chat"),
+			UserHome: resolver.Home,
+		}
+		if !reflect.DeepEqual(sandbox, want) {
+			t.Fatalf("Resolve(%q) = %#v, want %#v", profile, sandbox, want)
+		}
+		if info, err := os.Stat(want.Home); err != nil || !info.IsDir() {
+			t.Fatalf("sandbox home %q not created: %v", want.Home, err)
+		}
+	}
+}
+
+func TestResolverReadsCustomProfile(t *testing.T) {
+	t.Parallel()
+
+	resolver := testResolver(t, "bwrap", "firejail")
+	path := filepath.Join(t.TempDir(), "tool.toml")
+	content := strings.Join([]string{
+		`backend = "firejail"`,
+		`network = true`,
+		`binds = ["~/Downloads"]`,
+		`read_only_binds = ["/srv/data"]`,
+		"",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write profile: %v", err)
+	}
+
+	sandbox, err := resolver.Resolve(context.Background(), "tool", path, domain.SandboxBackendNone)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := domain.Sandbox{
+		Profile:       path,
+		Backend:       domain.SandboxBackendFirejail,
+		Network:       true,
+		UserHome:      resolver.Home,
+		Binds:         []string{filepath.Join(resolver.Home, "Downloads")},
+		ReadOnlyBinds: []string{"/srv/data"},
+	}
+	if !reflect.DeepEqual(sandbox, want) {
+		t.Fatalf("Resolve() = %#v, want %#v", sandbox, want)
+	}
+}
+
+func TestResolverRejectsInvalidProfiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write profile: %v", err)
+		}
+		return path
+	}
+
+	tests := map[string]struct {
+		profile   string
+		backend   domain.SandboxBackend
+		installed []string
+		want      string
+	}{
+		"unknown name":     {profile: "loose", installed: []string{"bwrap"}, want: "unknown sandbox profile"},
+		"no backend":       {profile: ProfileStrict, want: "neither bwrap nor firejail is installed"},
+		"missing backend":  {profile: ProfileStrict, backend: domain.SandboxBackendFirejail, installed: []string{"bwrap"}, want: "firejail is not installed"},
+		"unknown field":    {profile: write("typo.toml", "netwrk = true\n"), installed: []string{"bwrap"}, want: "parse sandbox profile"},
+		"relative bind":    {profile: write("relative.toml", "binds = [\"Downloads\"]\n"), installed: []string{"bwrap"}, want: "must be absolute"},
+		"firejail sharing": {profile: write("share.toml", "private_home = true\nbinds = [\"~/Downloads\"]\n"), backend: domain.SandboxBackendFirejail, installed: []string{"firejail"}, want: "use bwrap"},
+		"unknown backend":  {profile: ProfileStrict, backend: "docker", installed: []string{"bwrap"}, want: "unknown sandbox backend"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := testResolver(t, tt.installed...).Resolve(context.Background(), "tool", tt.profile, tt.backend)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Resolve() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func testResolver(t *testing.T, installed ...string) Resolver {
+	t.Helper()
+
+	root := t.TempDir()
+	return Resolver{
+		Dir:  filepath.Join(root, "data", "aim", "sandbox"),
+		Home: filepath.Join(root, "home"),
+		LookPath: func(file string) (string, error) {
+			for _, name := range installed {
+				if name == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("executable file not found in $PATH")
+		},
+	}
+}
@@ -0,0 +1,121 @@
+// Package shim installs the commands that put apps on PATH.
+package shim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+// scriptHeader starts every wrapper script, and marks it as written by aim so
+// it can be replaced later.
+const scriptHeader = "#!/bin/sh\n# Generated by aim; changes are overwritten.\n"
+
+// Installer installs commands into the configured bin directory. A command
+// that only runs an AppImage is a symlink to it; one with a sandbox,
+// arguments, or environment variables is a small shell script.
+type Installer struct {
+	Dir string
+}
+
+// NewInstaller creates a command installer rooted at dir.
+func NewInstaller(dir string) Installer {
+	return Installer{Dir: dir}
+}
+
+var _ app.CommandInstaller = Installer{}
+
+func (i Installer) Install(ctx context.Context, name string, command []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") {
+		return "", fmt.Errorf("invalid command name %q", name)
+	}
+	if len(command) == 0 || strings.TrimSpace(command[0]) == "" {
+		return "", errors.New("command is required")
+	}
+	if strings.TrimSpace(i.Dir) == "" {
+		return "", errors.New("bin directory is required")
+	}
+
+	if err := os.MkdirAll(i.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("create bin directory %q: %w", i.Dir, err)
+	}
+
+	destination := filepath.Join(i.Dir, name)
+	owned, err := createdByAim(destination)
+	if err != nil {
+		return "", err
+	}
+	if !owned {
+		return "", fmt.Errorf("%s already exists and was not created by aim; choose another name", destination)
+	}
+
+	temporaryDestination := destination + ".aim-tmp"
+	_ = os.Remove(temporaryDestination)
+	if len(command) == 1 {
+		err = os.Symlink(command[0], temporaryDestination)
+	} else {
+		err = os.WriteFile(temporaryDestination, script(command), 0o755)
+	}
+	if err != nil {
+		return "", fmt.Errorf("install command %q: %w", destination, err)
+	}
+	if err := os.Rename(temporaryDestination, destination); err != nil {
+		_ = os.Remove(temporaryDestination)
+		return "", fmt.Errorf("install command %q: %w", destination, err)
+	}
+
+	return destination, nil
+}
+
+// createdByAim reports whether path is missing or a command aim may replace:
+// a symlink to an AppImage or a script with scriptHeader.
+func createdByAim(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("inspect %q: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, fmt.Errorf("inspect %q: %w", path, err)
+		}
+		return strings.EqualFold(filepath.Ext(target), ".appimage"), nil
+	}
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("inspect %q: %w", path, err)
+	}
+	defer file.Close()
+	header := make([]byte, len(scriptHeader))
+	n, _ := file.Read(header)
+	return bytes.Equal(header[:n], []byte(scriptHeader)), nil
+}
+
+func script(command []string) []byte {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	return []byte(scriptHeader + "exec " + strings.Join(quoted, " ") + ` "$@"` + "\n")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,83 @@
+package shim
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallerLinksPlainAppImage(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "bin")
+	path, err := NewInstaller(dir).Install(context.Background(), "nvim", []string{"/apps/nvim.AppImage"})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if want := filepath.Join(dir, "nvim"); path != want {
+		t.Fatalf("Install() = %q, want %q", path, want)
+	}
+	if target, err := os.Readlink(path); err != nil || target != "/apps/nvim.AppImage" {
+		t.Fatalf("Readlink() = %q, %v; want /apps/nvim.AppImage", target, err)
+	}
+}
+
+func TestInstallerWritesWrapperScriptAndReplacesIt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	installer := NewInstaller(dir)
+	if _, err := installer.Install(context.Background(), "tool", []string{"/apps/tool.AppImage"}); err != nil {
+		t.Fatalf("Install(symlink) error = %v", err)
+	}
+
+	argsFile := filepath.Join(t.TempDir(), "args")
+	path, err := installer.Install(context.Background(), "tool", []string{"/bin/sh", "-c", `printf '%s|' "$GREETING" "$@" > "$0"`, argsFile})
+	if err != nil {
+		t.Fatalf("Install(script) error = %v", err)
+	}
+	if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("Lstat() = %v, %v; want executable script", info, err)
+	}
+
+	cmd := exec.Command(path, "it's", "two words")
+	cmd.Env = append(os.Environ(), "GREETING=hi")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("run script error = %v; output = %q", err, output)
+	}
+	got, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if want := "hi|it's|two words|"; string(got) != want {
+		t.Fatalf("script args = %q, want %q", got, want)
+	}
+}
+
+func TestInstallerRefusesForeignFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	foreign := filepath.Join(dir, "nvim")
+	if err := os.WriteFile(foreign, []byte("#!/bin/sh\necho mine\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewInstaller(dir).Install(context.Background(), "nvim", []string{"/apps/nvim.AppImage"})
+	if err == nil || !strings.Contains(err.Error(), "not created by aim") {
+		t.Fatalf("Install() error = %v, want foreign file error", err)
+	}
+	if content, _ := os.ReadFile(foreign); string(content) != "#!/bin/sh\necho mine\n" {
+		t.Fatalf("foreign file = %q, want untouched", content)
+	}
+
+	for _, name := range []string{"", "..", "sub/nvim"} {
+		if _, err := NewInstaller(dir).Install(context.Background(), name, []string{"/apps/nvim.AppImage"}); err == nil {
+			t.Fatalf("Install(%q) error = nil, want invalid name", name)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+var _ app.DatabaseBackups = Repository{}
+
+// BackupDir is where timestamped backups of the database are kept.
+func (r Repository) BackupDir() string {
+	return filepath.Join(filepath.Dir(r.Path), "backups")
+}
+
+// Backup copies the database to path, or to a timestamped file in BackupDir
+// when path is empty.
+func (r Repository) Backup(ctx context.Context, path string) (app.DatabaseBackup, error) {
+	if err := ctx.Err(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+	if err := r.validate(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return app.DatabaseBackup{}, err
+	}
+	defer unlock()
+
+	if strings.TrimSpace(path) == "" {
+		path = r.newBackupPath("")
+	}
+	return r.copyToBackup(ctx, path)
+}
+
+// Inspect reads a backup and reports what it contains.
+func (r Repository) Inspect(ctx context.Context, path string) (app.DatabaseBackup, error) {
+	if err := ctx.Err(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("read backup %q: %w", path, err)
+	}
+	return describeDatabase(path, bytes)
+}
+
+// Restore replaces the database with the backup at path after backing up the
+// current database.
+func (r Repository) Restore(ctx context.Context, path string) (app.DatabaseBackup, error) {
+	if err := ctx.Err(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+	if err := r.validate(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("read backup %q: %w", path, err)
+	}
+	if _, err := describeDatabase(path, bytes); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return app.DatabaseBackup{}, err
+	}
+	defer unlock()
+
+	var previous app.DatabaseBackup
+	if _, err := os.Stat(r.Path); err == nil {
+		previous, err = r.copyToBackup(ctx, r.newBackupPath("before-restore"))
+		if err != nil {
+			return app.DatabaseBackup{}, fmt.Errorf("back up app database before restoring: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return app.DatabaseBackup{}, fmt.Errorf("stat app database %q: %w", r.Path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("create app database directory %q: %w", filepath.Dir(r.Path), err)
+	}
+	if err := writeFileDurably(r.Path, bytes); err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("restore app database %q: %w", r.Path, err)
+	}
+
+	return previous, nil
+}
+
+// copyToBackup copies the database file to path. Callers must hold the
+// repository lock.
+func (r Repository) copyToBackup(ctx context.Context, path string) (app.DatabaseBackup, error) {
+	if err := ctx.Err(); err != nil {
+		return app.DatabaseBackup{}, err
+	}
+
+	bytes, err := os.ReadFile(r.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return app.DatabaseBackup{}, fmt.Errorf("app database %q does not exist", r.Path)
+		}
+		return app.DatabaseBackup{}, fmt.Errorf("read app database %q: %w", r.Path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("create backup directory %q: %w", filepath.Dir(path), err)
+	}
+	if err := writeFileDurably(path, bytes); err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("write backup %q: %w", path, err)
+	}
+
+	return describeDatabase(path, bytes)
+}
+
+func (r Repository) newBackupPath(label string) string {
+	name := strings.TrimSuffix(filepath.Base(r.Path), filepath.Ext(r.Path)) + "-" + time.Now().UTC().Format("20060102T150405.000Z")
+	if label != "" {
+		name += "-" + label
+	}
+	return filepath.Join(r.BackupDir(), name+".json")
+}
+
+func describeDatabase(path string, bytes []byte) (app.DatabaseBackup, error) {
+	db, version, err := decodeDatabase(bytes)
+	if err != nil {
+		return app.DatabaseBackup{}, fmt.Errorf("parse app database %q: %w", path, err)
+	}
+
+	backup := app.DatabaseBackup{Path: path, SchemaVersion: version, Apps: len(db.Apps)}
+	if info, err := os.Stat(path); err == nil {
+		backup.ModifiedAt = info.ModTime().UTC()
+	}
+	return backup, nil
+}
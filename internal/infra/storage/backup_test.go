@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchemaV1Database = `{"schema_version": 1, "apps": [{"id": "alpha", "name": "Alpha", "version": "1.0.0", "app_image_path": "/apps/alpha.AppImage"}]}`
+
+func TestRepositoryMigratesOlderSchemaAfterBackup(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "apps.json")
+	if err := os.WriteFile(path, []byte(testSchemaV1Database), 0o644); err != nil {
+		t.Fatalf("write database: %v", err)
+	}
+	repo := NewRepository(path)
+
+	if err := repo.Save(context.Background(), testApp(t, "bravo", "Bravo", "1.0.0")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stored, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != 2 || stored[0].ID != "alpha" {
+		t.Fatalf("List() = %#v, want migrated alpha and new bravo", stored)
+	}
+	var db databaseFile
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read database: %v", err)
+	}
+	if err := json.Unmarshal(bytes, &db); err != nil || db.SchemaVersion != currentSchemaVersion {
+		t.Fatalf("schema version = %d (%v), want %d", db.SchemaVersion, err, currentSchemaVersion)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(repo.BackupDir(), "apps-*-schema1.json"))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v (%v), want one schema1 backup", backups, err)
+	}
+	backup, err := os.ReadFile(backups[0])
+	if err != nil || string(backup) != testSchemaV1Database {
+		t.Fatalf("backup = %q (%v), want original database", backup, err)
+	}
+}
+
+func TestRepositoryRefusesNewerSchema(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "apps.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 99, "apps": []}`), 0o644); err != nil {
+		t.Fatalf("write database: %v", err)
+	}
+	repo := NewRepository(path)
+
+	_, err := repo.List(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "newer aim") {
+		t.Fatalf("List() error = %v, want newer aim error", err)
+	}
+	if err := repo.Save(context.Background(), testApp(t, "alpha", "Alpha", "1.0.0")); err == nil {
+		t.Fatal("Save() error = nil, want newer schema error")
+	}
+}
+
+func TestRepositoryBackupAndRestore(t *testing.T) {
+	t.Parallel()
+
+	repo := NewRepository(filepath.Join(t.TempDir(), "apps.json"))
+	if err := repo.Save(context.Background(), testApp(t, "alpha", "Alpha", "1.0.0")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	backup, err := repo.Backup(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if filepath.Dir(backup.Path) != repo.BackupDir() || backup.Apps != 1 || backup.SchemaVersion != currentSchemaVersion {
+		t.Fatalf("Backup() = %#v, want one app in %s", backup, repo.BackupDir())
+	}
+
+	if err := repo.Save(context.Background(), testApp(t, "bravo", "Bravo", "1.0.0")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	previous, err := repo.Restore(context.Background(), backup.Path)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if previous.Apps != 2 || !strings.HasSuffix(previous.Path, "-before-restore.json") {
+		t.Fatalf("Restore() previous = %#v, want backup of two apps", previous)
+	}
+
+	stored, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != "alpha" {
+		t.Fatalf("List() after restore = %#v, want alpha only", stored)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"schema_version": 99}`), 0o644); err != nil {
+		t.Fatalf("write invalid backup: %v", err)
+	}
+	if _, err := repo.Restore(context.Background(), invalid); err == nil {
+		t.Fatal("Restore() accepted a backup from a newer aim")
+	}
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+// HistoryLog appends one JSON object per line to Path. Lines are written with
+// a single O_APPEND write, so concurrent aim processes never interleave them.
+type HistoryLog struct {
+	Path string
+}
+
+// NewHistoryLog creates a history log stored at path.
+func NewHistoryLog(path string) HistoryLog {
+	return HistoryLog{Path: path}
+}
+
+var _ app.HistoryLog = HistoryLog{}
+
+type historyRecord struct {
+	Time       string `json:"time"`
+	Operation  string `json:"operation"`
+	AppID      string `json:"app_id"`
+	PreviousID string `json:"previous_id,omitempty"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Result     string `json:"result"`
+	Error      string `json:"error,omitempty"`
+	AimVersion string `json:"aim_version,omitempty"`
+}
+
+func (h HistoryLog) Append(ctx context.Context, event app.HistoryEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(historyRecord{
+		Time:       formatRecordTime(event.Time),
+		Operation:  string(event.Operation),
+		AppID:      event.AppID,
+		PreviousID: event.PreviousID,
+		OldVersion: event.OldVersion,
+		NewVersion: event.NewVersion,
+		Source:     event.Source,
+		Result:     string(event.Result),
+		Error:      event.Error,
+		AimVersion: event.AimVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("encode history event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return fmt.Errorf("create history directory %q: %w", filepath.Dir(h.Path), err)
+	}
+	file, err := os.OpenFile(h.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open history log %q: %w", h.Path, err)
+	}
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("append history log %q: %w", h.Path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close history log %q: %w", h.Path, err)
+	}
+	return nil
+}
+
+func (h HistoryLog) Read(ctx context.Context) ([]app.HistoryEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history log %q: %w", h.Path, err)
+	}
+
+	var events []app.HistoryEvent
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record historyRecord
+		// A line cut short by a crash is skipped rather than hiding the
+		// rest of the log.
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		events = append(events, app.HistoryEvent{
+			Time:       parseSourceTime(record.Time),
+			Operation:  app.HistoryOperation(record.Operation),
+			AppID:      record.AppID,
+			PreviousID: record.PreviousID,
+			OldVersion: record.OldVersion,
+			NewVersion: record.NewVersion,
+			Source:     record.Source,
+			Result:     app.HistoryOutcome(record.Result),
+			Error:      record.Error,
+			AimVersion: record.AimVersion,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history log %q: %w", h.Path, err)
+	}
+	return events, nil
+}
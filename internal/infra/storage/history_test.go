@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestHistoryLogAppendsAndReadsEventsInOrder(t *testing.T) {
+	t.Parallel()
+
+	log := NewHistoryLog(filepath.Join(t.TempDir(), "data", "history.jsonl"))
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	first := app.HistoryEvent{Time: at, Operation: app.HistoryOperationAdd, AppID: "example", NewVersion: "1.0.0", Source: "github:owner/repo@v1.0.0", Result: app.HistoryOutcomeSucceeded, AimVersion: "0.17.0"}
+	second := app.HistoryEvent{Time: at.Add(time.Hour), Operation: app.HistoryOperationUpdate, AppID: "example", OldVersion: "1.0.0", NewVersion: "1.1.0", Result: app.HistoryOutcomeFailed, Error: "download failed"}
+
+	for _, event := range []app.HistoryEvent{first, second} {
+		if err := log.Append(context.Background(), event); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	events, err := log.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Read() returned %d events, want 2", len(events))
+	}
+	if events[0] != first || events[1] != second {
+		t.Fatalf("Read() = %#v, want %#v", events, []app.HistoryEvent{first, second})
+	}
+}
+
+func TestHistoryLogSkipsTruncatedLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	content := `{"time":"2026-03-04T05:06:07Z","operation":"remove","app_id":"example","result":"succeeded"}` + "\n" + `{"time":"2026-03-04T`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	events, err := NewHistoryLog(path).Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Operation != app.HistoryOperationRemove {
+		t.Fatalf("Read() = %#v, want the complete remove event", events)
+	}
+}
+
+func TestHistoryLogReadsMissingFileAsEmpty(t *testing.T) {
+	t.Parallel()
+
+	events, err := NewHistoryLog(filepath.Join(t.TempDir(), "history.jsonl")).Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Read() = %#v, want no events", events)
+	}
+}
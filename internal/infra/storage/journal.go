@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"golang.org/x/sys/unix"
+)
+
+// Journal keeps one JSON file per in-flight operation in Dir. Each entry
+// records the process that owns it so a running aim's operations are never
+// treated as abandoned. Since PIDs are reused, notably after a reboot, the
+// owner is identified by the boot ID and its start time as well as its PID.
+type Journal struct {
+	Dir string
+}
+
+// NewJournal creates an operation journal stored in dir.
+func NewJournal(dir string) Journal {
+	return Journal{Dir: dir}
+}
+
+var _ app.OperationJournal = Journal{}
+
+type journalRecord struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	AppID string `json:"app_id"`
+	PID   int    `json:"pid"`
+	// BootID and ProcessStart tell the owner apart from a later process
+	// that got the same PID.
+	BootID       string     `json:"boot_id,omitempty"`
+	ProcessStart uint64     `json:"process_start,omitempty"`
+	StartedAt    string     `json:"started_at,omitempty"`
+	Created      []string   `json:"created,omitempty"`
+	Committed    bool       `json:"committed"`
+	Commit       *appRecord `json:"commit,omitempty"`
+	Cleanup      []string   `json:"cleanup,omitempty"`
+	Trash        []string   `json:"trash,omitempty"`
+}
+
+func (j Journal) Write(ctx context.Context, entry app.JournalEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, err := j.entryPath(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	record := journalRecord{
+		ID:        entry.ID,
+		Kind:      string(entry.Kind),
+		AppID:     entry.AppID,
+		PID:       os.Getpid(),
+		BootID:    bootID(),
+		StartedAt: formatRecordTime(entry.StartedAt),
+		Created:   entry.Created,
+		Committed: entry.Committed,
+		Cleanup:   entry.Cleanup,
+		Trash:     entry.Trash,
+	}
+	record.ProcessStart, _ = processStartTime(record.PID)
+	if entry.Commit != nil {
+		commit := recordFromDomainApp(*entry.Commit)
+		record.Commit = &commit
+	}
+
+	bytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode journal entry: %w", err)
+	}
+	bytes = append(bytes, '\n')
+
+	if err := os.MkdirAll(j.Dir, 0o755); err != nil {
+		return fmt.Errorf("create journal directory %q: %w", j.Dir, err)
+	}
+	if err := writeFileDurably(path, bytes); err != nil {
+		return fmt.Errorf("write journal entry %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func (j Journal) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, err := j.entryPath(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete journal entry %q: %w", path, err)
+	}
+	return nil
+}
+
+func (j Journal) Abandoned(ctx context.Context) ([]app.JournalEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(j.Dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list journal entries in %q: %w", j.Dir, err)
+	}
+	sort.Strings(paths)
+
+	entries := make([]app.JournalEntry, 0)
+	for _, path := range paths {
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read journal entry %q: %w", path, err)
+		}
+		var record journalRecord
+		if err := json.Unmarshal(bytes, &record); err != nil {
+			return nil, fmt.Errorf("parse journal entry %q: %w", path, err)
+		}
+		if ownerRunning(record) {
+			continue
+		}
+
+		entry := app.JournalEntry{
+			ID:        record.ID,
+			Kind:      app.JournalKind(record.Kind),
+			AppID:     record.AppID,
+			StartedAt: parseSourceTime(record.StartedAt),
+			Created:   record.Created,
+			Committed: record.Committed,
+			Cleanup:   record.Cleanup,
+			Trash:     record.Trash,
+		}
+		if record.Commit != nil {
+			commit, err := record.Commit.toDomainApp()
+			if err != nil {
+				return nil, fmt.Errorf("parse journal entry %q: %w", path, err)
+			}
+			entry.Commit = &commit
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (j Journal) entryPath(id string) (string, error) {
+	if strings.TrimSpace(j.Dir) == "" {
+		return "", errors.New("journal directory is required")
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid journal entry id %q", id)
+	}
+	return filepath.Join(j.Dir, id+".json"), nil
+}
+
+// ownerRunning reports whether the process that wrote record is still alive:
+// its PID is in use in the same boot by a process started at the same time.
+// Boot IDs and start times are compared only when both are known.
+func ownerRunning(record journalRecord) bool {
+	if !processRunning(record.PID) {
+		return false
+	}
+	if current := bootID(); record.BootID != "" && current != "" && record.BootID != current {
+		return false
+	}
+	if start, ok := processStartTime(record.PID); record.ProcessStart != 0 && ok && record.ProcessStart != start {
+		return false
+	}
+	return true
+}
+
+// bootID returns the kernel's random ID of the current boot, or "" where it
+// is unavailable.
+func bootID() string {
+	bytes, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(bytes))
+}
+
+// processStartTime returns when pid started, in clock ticks since boot, from
+// the 22nd field of /proc/<pid>/stat.
+func processStartTime(pid int) (uint64, bool) {
+	bytes, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+	// The command name in field 2 may contain spaces and parentheses, so
+	// fields are counted from the last closing parenthesis, after which
+	// field 3 starts.
+	index := strings.LastIndexByte(string(bytes), ')')
+	if index < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(bytes[index+1:]))
+	if len(fields) < 20 {
+		return 0, false
+	}
+	start, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+// processRunning reports whether pid belongs to a live process. Signal 0
+// performs the permission and existence checks without sending anything.
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
+
+// writeFileDurably replaces path with content and syncs both the file and its
+// directory so the write survives power loss.
+func writeFileDurably(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	temporaryFile, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	temporaryPath := temporaryFile.Name()
+	if _, err := temporaryFile.Write(content); err != nil {
+		_ = temporaryFile.Close()
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+	if err := temporaryFile.Sync(); err != nil {
+		_ = temporaryFile.Close()
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+	if err := temporaryFile.Close(); err != nil {
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+	if err := os.Rename(temporaryPath, path); err != nil {
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+
+	directory, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer directory.Close()
+	return directory.Sync()
+}
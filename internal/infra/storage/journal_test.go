@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+func TestJournalSkipsEntriesOfRunningProcesses(t *testing.T) {
+	t.Parallel()
+
+	journal := NewJournal(t.TempDir())
+	entry := app.JournalEntry{ID: "20260101T000000Z-add-alpha", Kind: app.JournalKindAdd, AppID: "alpha", StartedAt: testSourceTime()}
+	if err := journal.Write(context.Background(), entry); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	entries, err := journal.Abandoned(context.Background())
+	if err != nil {
+		t.Fatalf("Abandoned() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Abandoned() = %#v, want none while this process runs", entries)
+	}
+}
+
+func TestJournalReturnsAbandonedEntries(t *testing.T) {
+	t.Parallel()
+
+	journal := NewJournal(t.TempDir())
+	commit := testApp(t, "alpha", "Alpha", "2.0.0")
+	entry := app.JournalEntry{
+		ID:        "20260101T000000Z-update-alpha",
+		Kind:      app.JournalKindUpdate,
+		AppID:     "alpha",
+		StartedAt: testSourceTime(),
+		Created:   []string{"/apps/alpha-2-0-0.AppImage"},
+		Committed: true,
+		Commit:    &commit,
+		Cleanup:   []string{"/apps/alpha-2-0-0.AppImage"},
+		Trash:     []string{"/apps/alpha-1-0-0.AppImage"},
+	}
+	if err := journal.Write(context.Background(), entry); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	abandonJournalEntry(t, filepath.Join(journal.Dir, entry.ID+".json"))
+
+	entries, err := journal.Abandoned(context.Background())
+	if err != nil {
+		t.Fatalf("Abandoned() error = %v", err)
+	}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0], entry) {
+		t.Fatalf("Abandoned() = %#v, want %#v", entries, entry)
+	}
+
+	if err := journal.Delete(context.Background(), entry.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	entries, err = journal.Abandoned(context.Background())
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Abandoned() after Delete = %#v, %v; want none", entries, err)
+	}
+}
+
+func TestJournalReturnsEntriesWhosePIDWasReused(t *testing.T) {
+	t.Parallel()
+
+	if bootID() == "" {
+		t.Skip("boot ID is unavailable")
+	}
+	// The entries keep this process's PID, which is alive, but claim to come
+	// from another boot or from a process that started at another time.
+	tests := []struct {
+		name  string
+		field string
+		value any
+	}{
+		{name: "boot id", field: "boot_id", value: "00000000-0000-0000-0000-000000000000"},
+		{name: "process start", field: "process_start", value: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			journal := NewJournal(t.TempDir())
+			entry := app.JournalEntry{ID: "20260101T000000Z-add-alpha", Kind: app.JournalKindAdd, AppID: "alpha", StartedAt: testSourceTime()}
+			if err := journal.Write(context.Background(), entry); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			setJournalField(t, filepath.Join(journal.Dir, entry.ID+".json"), tt.field, tt.value)
+
+			entries, err := journal.Abandoned(context.Background())
+			if err != nil {
+				t.Fatalf("Abandoned() error = %v", err)
+			}
+			if len(entries) != 1 || entries[0].ID != entry.ID {
+				t.Fatalf("Abandoned() = %#v, want the entry of the earlier process", entries)
+			}
+		})
+	}
+}
+
+func TestProcessStartTimeReadsCurrentProcess(t *testing.T) {
+	t.Parallel()
+
+	if bootID() == "" {
+		t.Skip("/proc is unavailable")
+	}
+	start, ok := processStartTime(os.Getpid())
+	if !ok || start == 0 {
+		t.Fatalf("processStartTime() = %d, %t; want the start time of this process", start, ok)
+	}
+}
+
+// abandonJournalEntry clears the owner PID as if the writing process died.
+func abandonJournalEntry(t *testing.T, path string) {
+	t.Helper()
+
+	setJournalField(t, path, "pid", 0)
+}
+
+func setJournalField(t *testing.T, path string, field string, value any) {
+	t.Helper()
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal entry: %v", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		t.Fatalf("parse journal entry: %v", err)
+	}
+	record[field] = value
+	bytes, err = json.Marshal(record)
+	if err != nil {
+		t.Fatalf("encode journal entry: %v", err)
+	}
+	if err := os.WriteFile(path, bytes, 0o644); err != nil {
+		t.Fatalf("write journal entry: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schemaMigrations upgrades a decoded database document from the keyed schema
+// version to the next one. Every version below currentSchemaVersion needs an
+// entry; documents are migrated one version at a time.
+var schemaMigrations = map[int]func(document map[string]json.RawMessage) error{
+	1: migrateSchemaV1ToV2,
+	2: migrateSchemaV2ToV3,
+}
+
+// migrateSchemaV1ToV2 is a no-op: version 2 only added optional fields, so
+// version 1 documents decode unchanged.
+func migrateSchemaV1ToV2(document map[string]json.RawMessage) error {
+	return nil
+}
+
+// migrateSchemaV2ToV3 is a no-op: version 3 added optional fields of an app
+// that older aim versions do not know, such as its sandbox. It was raised so
+// that those versions refuse the database instead of dropping the fields when
+// they rewrite it; later optional fields reuse version 3.
+func migrateSchemaV2ToV3(document map[string]json.RawMessage) error {
+	return nil
+}
+
+// decodeDatabase parses a database document, migrating it to
+// currentSchemaVersion. It returns the schema version found on disk; documents
+// without one predate versioning and are treated as version 1.
+func decodeDatabase(bytes []byte) (databaseFile, int, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &document); err != nil {
+		return databaseFile{}, 0, err
+	}
+
+	version := 1
+	if raw, ok := document["schema_version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return databaseFile{}, 0, fmt.Errorf("schema_version: %w", err)
+		}
+		if version == 0 {
+			version = 1
+		}
+	}
+	if version > currentSchemaVersion {
+		return databaseFile{}, version, fmt.Errorf("schema version %d was written by a newer aim; this aim supports up to %d, so upgrade aim or restore an older backup with aim db restore", version, currentSchemaVersion)
+	}
+
+	for from := version; from < currentSchemaVersion; from++ {
+		migrate, ok := schemaMigrations[from]
+		if !ok {
+			return databaseFile{}, version, fmt.Errorf("no migration from schema version %d", from)
+		}
+		if err := migrate(document); err != nil {
+			return databaseFile{}, version, fmt.Errorf("migrate schema version %d: %w", from, err)
+		}
+	}
+
+	migrated, err := json.Marshal(document)
+	if err != nil {
+		return databaseFile{}, version, err
+	}
+	var db databaseFile
+	if err := json.Unmarshal(migrated, &db); err != nil {
+		return databaseFile{}, version, err
+	}
+	db.SchemaVersion = currentSchemaVersion
+
+	return db, version, nil
+}
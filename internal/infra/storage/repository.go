@@ -13,11 +13,17 @@ import (
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/domain"
+	"github.com/slobbe/appimage-manager/internal/infra/filelock"
 )
 
 // Repository persists integrated apps in a JSON file.
+//
+// Writes hold an advisory lock on Path+".lock" so concurrent aim processes do
+// not lose each other's updates. LockTimeout bounds the wait; zero uses
+// filelock.DefaultTimeout.
 type Repository struct {
-	Path string
+	Path        string
+	LockTimeout time.Duration
 }
 
 // NewRepository creates a JSON app repository backed by path.
@@ -27,10 +33,10 @@ func NewRepository(path string) Repository {
 
 var _ app.AppRepository = Repository{}
 
-const currentSchemaVersion = 2
+const currentSchemaVersion = 3
 
-// repositoryMu only serializes repository writes inside this process.
-// ponytail: concurrent CLI processes can still race and lose updates; add file locking if that becomes a real user problem.
+// repositoryMu serializes repository writes inside this process; the file lock
+// taken in lock serializes them across processes.
 var repositoryMu sync.Mutex
 
 type databaseFile struct {
@@ -41,18 +47,28 @@ type databaseFile struct {
 type appRecord struct {
 	ID               string              `json:"id"`
 	Name             string              `json:"name"`
+	NameTranslations map[string]string   `json:"name_translations,omitempty"`
+	GenericName      *localizedRecord    `json:"generic_name,omitempty"`
+	Comment          *localizedRecord    `json:"comment,omitempty"`
 	Version          string              `json:"version,omitempty"`
 	AppImagePath     string              `json:"app_image_path"`
 	DesktopEntryPath string              `json:"desktop_entry_path,omitempty"`
 	IconPath         string              `json:"icon_path,omitempty"`
+	IconSizePaths    []string            `json:"icon_size_paths,omitempty"`
+	GeneratedIcon    bool                `json:"generated_icon,omitempty"`
+	BinPath          string              `json:"bin_path,omitempty"`
+	MimePackagePaths []string            `json:"mime_package_paths,omitempty"`
 	Source           *sourceRecord       `json:"source,omitempty"`
 	UpdateSource     *updateSourceRecord `json:"update_source,omitempty"`
+	Sandbox          *sandboxRecord      `json:"sandbox,omitempty"`
+	Launch           *launchRecord       `json:"launch,omitempty"`
 }
 
 type sourceRecord struct {
 	Kind          string                     `json:"kind"`
 	LocalFile     *localFileSourceRecord     `json:"local_file,omitempty"`
 	GitHubRelease *githubReleaseSourceRecord `json:"github_release,omitempty"`
+	URL           *urlSourceRecord           `json:"url,omitempty"`
 }
 
 type updateSourceRecord struct {
@@ -69,6 +85,26 @@ type updateSourceRecord struct {
 	URL               string `json:"url,omitempty"`
 }
 
+type sandboxRecord struct {
+	Profile       string   `json:"profile,omitempty"`
+	Backend       string   `json:"backend"`
+	Network       bool     `json:"network,omitempty"`
+	Home          string   `json:"home,omitempty"`
+	UserHome      string   `json:"user_home,omitempty"`
+	Binds         []string `json:"binds,omitempty"`
+	ReadOnlyBinds []string `json:"read_only_binds,omitempty"`
+}
+
+type localizedRecord struct {
+	Value        string            `json:"value,omitempty"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+type launchRecord struct {
+	Args []string          `json:"args,omitempty"`
+	Env  map[string]string `json:"env,omitempty"`
+}
+
 type localFileSourceRecord struct {
 	Path         string `json:"path"`
 	IntegratedAt string `json:"integrated_at,omitempty"`
@@ -83,6 +119,11 @@ type githubReleaseSourceRecord struct {
 	DownloadedAt string `json:"downloaded_at,omitempty"`
 }
 
+type urlSourceRecord struct {
+	URL          string `json:"url"`
+	DownloadedAt string `json:"downloaded_at,omitempty"`
+}
+
 // Save inserts or replaces an app by ID.
 func (r Repository) Save(ctx context.Context, domainApp domain.App) error {
 	if err := ctx.Err(); err != nil {
@@ -101,7 +142,7 @@ func (r Repository) Save(ctx context.Context, domainApp domain.App) error {
 	}
 	defer unlock()
 
-	db, err := r.load(ctx)
+	db, err := r.loadForWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -198,7 +239,7 @@ func (r Repository) Delete(ctx context.Context, id string) error {
 	}
 	defer unlock()
 
-	db, err := r.load(ctx)
+	db, err := r.loadForWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -232,31 +273,64 @@ func (r Repository) lock(ctx context.Context) (func(), error) {
 		return nil, err
 	}
 
-	return repositoryMu.Unlock, nil
+	unlockFile, err := filelock.Acquire(ctx, r.Path+".lock", r.LockTimeout)
+	if err != nil {
+		repositoryMu.Unlock()
+		return nil, fmt.Errorf("lock app database %q: %w", r.Path, err)
+	}
+
+	return func() {
+		unlockFile()
+		repositoryMu.Unlock()
+	}, nil
 }
 
 func (r Repository) load(ctx context.Context) (databaseFile, error) {
-	if err := ctx.Err(); err != nil {
+	db, _, err := r.read(ctx)
+	return db, err
+}
+
+// loadForWrite loads the database for a read-modify-write. When the file uses
+// an older schema, it is backed up before the migrated version is written.
+// Callers must hold the repository lock.
+func (r Repository) loadForWrite(ctx context.Context) (databaseFile, error) {
+	db, version, err := r.read(ctx)
+	if err != nil {
 		return databaseFile{}, err
 	}
+	if version != 0 && version < currentSchemaVersion {
+		if _, err := r.copyToBackup(ctx, r.newBackupPath(fmt.Sprintf("schema%d", version))); err != nil {
+			return databaseFile{}, fmt.Errorf("back up app database before migrating: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// read returns the migrated database and the schema version stored on disk;
+// the version is 0 when there is no database yet.
+func (r Repository) read(ctx context.Context) (databaseFile, int, error) {
+	if err := ctx.Err(); err != nil {
+		return databaseFile{}, 0, err
+	}
 
 	bytes, err := os.ReadFile(r.Path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return databaseFile{}, nil
+			return databaseFile{}, 0, nil
 		}
-		return databaseFile{}, fmt.Errorf("read app database %q: %w", r.Path, err)
+		return databaseFile{}, 0, fmt.Errorf("read app database %q: %w", r.Path, err)
 	}
 	if len(bytes) == 0 {
-		return databaseFile{}, nil
+		return databaseFile{}, 0, nil
 	}
 
-	var db databaseFile
-	if err := json.Unmarshal(bytes, &db); err != nil {
-		return databaseFile{}, fmt.Errorf("parse app database %q: %w", r.Path, err)
+	db, version, err := decodeDatabase(bytes)
+	if err != nil {
+		return databaseFile{}, 0, fmt.Errorf("parse app database %q: %w", r.Path, err)
 	}
 
-	return db, nil
+	return db, version, nil
 }
 
 func (r Repository) save(ctx context.Context, db databaseFile) error {
@@ -312,12 +386,91 @@ func recordFromDomainApp(domainApp domain.App) appRecord {
 	return appRecord{
 		ID:               domainApp.ID,
 		Name:             domainApp.Name,
+		NameTranslations: domainApp.NameTranslations,
+		GenericName:      recordFromDomainLocalized(domainApp.GenericName),
+		Comment:          recordFromDomainLocalized(domainApp.Comment),
 		Version:          domainApp.Version.String(),
 		AppImagePath:     domainApp.AppImagePath,
 		DesktopEntryPath: domainApp.DesktopEntryPath,
 		IconPath:         domainApp.IconPath,
+		IconSizePaths:    domainApp.IconSizePaths,
+		GeneratedIcon:    domainApp.GeneratedIcon,
+		BinPath:          domainApp.BinPath,
+		MimePackagePaths: domainApp.MimePackagePaths,
 		Source:           recordFromDomainSource(domainApp.Source),
 		UpdateSource:     recordFromDomainUpdateSource(domainApp.UpdateSource),
+		Sandbox:          recordFromDomainSandbox(domainApp.Sandbox),
+		Launch:           recordFromDomainLaunch(domainApp.Launch),
+	}
+}
+
+func recordFromDomainLocalized(value domain.LocalizedString) *localizedRecord {
+	if value.IsZero() {
+		return nil
+	}
+	return &localizedRecord{
+		Value:        value.Value,
+		Translations: value.Translations,
+	}
+}
+
+func recordFromDomainSandbox(sandbox domain.Sandbox) *sandboxRecord {
+	if !sandbox.Enabled() {
+		return nil
+	}
+	return &sandboxRecord{
+		Profile:       sandbox.Profile,
+		Backend:       string(sandbox.Backend),
+		Network:       sandbox.Network,
+		Home:          sandbox.Home,
+		UserHome:      sandbox.UserHome,
+		Binds:         sandbox.Binds,
+		ReadOnlyBinds: sandbox.ReadOnlyBinds,
+	}
+}
+
+func (r *localizedRecord) toDomainLocalized() domain.LocalizedString {
+	if r == nil {
+		return domain.LocalizedString{}
+	}
+	return domain.LocalizedString{
+		Value:        r.Value,
+		Translations: r.Translations,
+	}
+}
+
+func (r *sandboxRecord) toDomainSandbox() domain.Sandbox {
+	if r == nil {
+		return domain.Sandbox{}
+	}
+	return domain.Sandbox{
+		Profile:       r.Profile,
+		Backend:       domain.SandboxBackend(r.Backend),
+		Network:       r.Network,
+		Home:          r.Home,
+		UserHome:      r.UserHome,
+		Binds:         r.Binds,
+		ReadOnlyBinds: r.ReadOnlyBinds,
+	}
+}
+
+func recordFromDomainLaunch(launch domain.LaunchOptions) *launchRecord {
+	if launch.IsZero() {
+		return nil
+	}
+	return &launchRecord{
+		Args: launch.Args,
+		Env:  launch.Env,
+	}
+}
+
+func (r *launchRecord) toDomainLaunch() domain.LaunchOptions {
+	if r == nil {
+		return domain.LaunchOptions{}
+	}
+	return domain.LaunchOptions{
+		Args: r.Args,
+		Env:  r.Env,
 	}
 }
 
@@ -386,6 +539,14 @@ func recordFromDomainSource(source domain.Source) *sourceRecord {
 				DownloadedAt: formatRecordTime(source.GitHubRelease.DownloadedAt),
 			},
 		}
+	case domain.SourceKindURL:
+		return &sourceRecord{
+			Kind: string(domain.SourceKindURL),
+			URL: &urlSourceRecord{
+				URL:          source.URL.URL,
+				DownloadedAt: formatRecordTime(source.URL.DownloadedAt),
+			},
+		}
 	default:
 		return nil
 	}
@@ -414,6 +575,11 @@ func (r *sourceRecord) toDomainSource() domain.Source {
 			r.GitHubRelease.SizeBytes,
 			parseSourceTime(r.GitHubRelease.DownloadedAt),
 		)
+	case domain.SourceKindURL:
+		if r.URL == nil {
+			return domain.Source{Kind: domain.SourceKindURL}
+		}
+		return domain.NewURLSource(r.URL.URL, parseSourceTime(r.URL.DownloadedAt))
 	default:
 		return domain.Source{}
 	}
@@ -452,12 +618,21 @@ func (r appRecord) toDomainApp() (domain.App, error) {
 	return domain.App{
 		ID:               r.ID,
 		Name:             r.Name,
+		NameTranslations: r.NameTranslations,
+		GenericName:      r.GenericName.toDomainLocalized(),
+		Comment:          r.Comment.toDomainLocalized(),
 		Version:          version,
 		AppImagePath:     r.AppImagePath,
 		DesktopEntryPath: r.DesktopEntryPath,
 		IconPath:         r.IconPath,
+		IconSizePaths:    r.IconSizePaths,
+		GeneratedIcon:    r.GeneratedIcon,
+		BinPath:          r.BinPath,
+		MimePackagePaths: r.MimePackagePaths,
 		Source:           r.Source.toDomainSource(),
 		UpdateSource:     r.UpdateSource.toDomainUpdateSource(),
+		Sandbox:          r.Sandbox.toDomainSandbox(),
+		Launch:           r.Launch.toDomainLaunch(),
 	}, nil
 }
 
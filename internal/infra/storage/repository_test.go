@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/slobbe/appimage-manager/internal/app"
 	"github.com/slobbe/appimage-manager/internal/domain"
+	"github.com/slobbe/appimage-manager/internal/infra/filelock"
 )
 
 func TestRepositorySaveAndFind(t *testing.T) {
@@ -49,8 +51,8 @@ func TestRepositorySaveWritesCurrentSchemaVersion(t *testing.T) {
 	if err := json.Unmarshal(bytes, &db); err != nil {
 		t.Fatalf("unmarshal database: %v", err)
 	}
-	if db.SchemaVersion != 2 {
-		t.Fatalf("SchemaVersion = %d, want 2", db.SchemaVersion)
+	if db.SchemaVersion != 3 {
+		t.Fatalf("SchemaVersion = %d, want 3", db.SchemaVersion)
 	}
 }
 
@@ -90,6 +92,75 @@ func TestRepositorySaveAndFindEmbeddedUpdateSource(t *testing.T) {
 	}
 }
 
+func TestRepositorySaveAndFindLaunchSettings(t *testing.T) {
+	t.Parallel()
+
+	repo := NewRepository(filepath.Join(t.TempDir(), "apps.json"))
+	stored := testApp(t, "example", "Example", "1.2.3")
+	stored.Sandbox = domain.Sandbox{
+		Profile:       "network-only",
+		Backend:       domain.SandboxBackendBubblewrap,
+		Network:       true,
+		Home:          "/home/user/.local/share/aim/sandbox/example",
+		UserHome:      "/home/user",
+		Binds:         []string{"/home/user/Downloads"},
+		ReadOnlyBinds: []string{"/home/user/Documents"},
+	}
+	stored.BinPath = "/home/user/.local/bin/example"
+	stored.IconSizePaths = []string{"/home/user/.local/share/icons/hicolor/48x48/apps/example.png"}
+	stored.GeneratedIcon = true
+	stored.NameTranslations = map[string]string{"de": "Beispiel"}
+	stored.GenericName = domain.LocalizedString{Value: "Text Editor", Translations: map[string]string{"de": "Texteditor"}}
+	stored.Comment = domain.LocalizedString{Value: "Edit text"}
+	stored.MimePackagePaths = []string{"/home/user/.local/share/mime/packages/example-example.xml"}
+	stored.Launch = domain.LaunchOptions{
+		Args: []string{"--ozone-platform=wayland"},
+		Env:  map[string]string{"GDK_SCALE": "2"},
+	}
+
+	if err := repo.Save(context.Background(), stored); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	found, err := repo.Find(context.Background(), "example")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	assertApp(t, found, stored)
+}
+
+func TestRepositorySaveAndFindURLSource(t *testing.T) {
+	t.Parallel()
+
+	repo := NewRepository(filepath.Join(t.TempDir(), "apps.json"))
+	stored := testApp(t, "example", "Example", "1.2.3")
+	stored.Source = domain.NewURLSource("https://example.test/Example.AppImage", testSourceTime())
+
+	if err := repo.Save(context.Background(), stored); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	found, err := repo.Find(context.Background(), "example")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	assertApp(t, found, stored)
+
+	bytes, err := os.ReadFile(repo.Path)
+	if err != nil {
+		t.Fatalf("read database: %v", err)
+	}
+	for _, want := range []string{
+		`"kind": "url"`,
+		`"url": "https://example.test/Example.AppImage"`,
+		`"downloaded_at": "2026-06-03T14:06:07Z"`,
+	} {
+		if !strings.Contains(string(bytes), want) {
+			t.Fatalf("database = %s, want field %s", bytes, want)
+		}
+	}
+}
+
 func TestRepositorySaveOmitsEmptyUpdateSource(t *testing.T) {
 	t.Parallel()
 
@@ -185,6 +256,26 @@ func TestRepositoryConcurrentSavesPreserveAllApps(t *testing.T) {
 	}
 }
 
+func TestRepositorySaveReportsBusyWhenAnotherProcessHoldsLock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "apps.json")
+	unlock, err := filelock.Acquire(context.Background(), path+".lock", time.Second)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer unlock()
+
+	repo := Repository{Path: path, LockTimeout: 100 * time.Millisecond}
+	err = repo.Save(context.Background(), testApp(t, "alpha", "Alpha", "1.0.0"))
+	if !errors.Is(err, app.ErrBusy) {
+		t.Fatalf("Save() error = %v, want ErrBusy", err)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Fatalf("app database written while locked: %v", statErr)
+	}
+}
+
 func TestRepositoryListReturnsAppsSortedByID(t *testing.T) {
 	t.Parallel()
 
@@ -362,12 +453,21 @@ func assertApp(t *testing.T, got domain.App, want domain.App) {
 
 	if got.ID != want.ID ||
 		got.Name != want.Name ||
+		!reflect.DeepEqual(got.NameTranslations, want.NameTranslations) ||
+		!reflect.DeepEqual(got.GenericName, want.GenericName) ||
+		!reflect.DeepEqual(got.Comment, want.Comment) ||
 		got.Version.String() != want.Version.String() ||
 		got.AppImagePath != want.AppImagePath ||
 		got.DesktopEntryPath != want.DesktopEntryPath ||
 		got.IconPath != want.IconPath ||
+		!reflect.DeepEqual(got.IconSizePaths, want.IconSizePaths) ||
+		got.GeneratedIcon != want.GeneratedIcon ||
+		got.BinPath != want.BinPath ||
+		!reflect.DeepEqual(got.MimePackagePaths, want.MimePackagePaths) ||
 		got.Source != want.Source ||
-		got.UpdateSource != want.UpdateSource {
+		got.UpdateSource != want.UpdateSource ||
+		!reflect.DeepEqual(got.Sandbox, want.Sandbox) ||
+		!reflect.DeepEqual(got.Launch, want.Launch) {
 		t.Fatalf("app = %#v, want %#v", got, want)
 	}
 }
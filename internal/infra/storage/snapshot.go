@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+)
+
+const currentSnapshotFormatVersion = 1
+
+// SnapshotStore writes app snapshots as JSON files that reuse the source
+// records of the app database.
+type SnapshotStore struct{}
+
+var _ app.SnapshotStore = SnapshotStore{}
+
+type snapshotFile struct {
+	FormatVersion int                 `json:"format_version"`
+	Apps          []snapshotAppRecord `json:"apps"`
+}
+
+type snapshotAppRecord struct {
+	ID           string              `json:"id"`
+	Name         string              `json:"name,omitempty"`
+	Version      string              `json:"version,omitempty"`
+	Source       *sourceRecord       `json:"source,omitempty"`
+	UpdateSource *updateSourceRecord `json:"update_source,omitempty"`
+}
+
+func (SnapshotStore) Write(ctx context.Context, path string, snapshot app.Snapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(path) == "" {
+		return fmt.Errorf("snapshot path is required")
+	}
+
+	file := snapshotFile{
+		FormatVersion: currentSnapshotFormatVersion,
+		Apps:          make([]snapshotAppRecord, 0, len(snapshot.Apps)),
+	}
+	for _, snapshotApp := range snapshot.Apps {
+		file.Apps = append(file.Apps, snapshotAppRecord{
+			ID:           snapshotApp.ID,
+			Name:         snapshotApp.Name,
+			Version:      snapshotApp.Version,
+			Source:       recordFromDomainSource(snapshotApp.Source),
+			UpdateSource: recordFromDomainUpdateSource(snapshotApp.UpdateSource),
+		})
+	}
+
+	bytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	bytes = append(bytes, '\n')
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create snapshot directory %q: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, bytes, 0o644); err != nil {
+		return fmt.Errorf("write snapshot %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func (SnapshotStore) Read(ctx context.Context, path string) (app.Snapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return app.Snapshot{}, err
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return app.Snapshot{}, fmt.Errorf("read snapshot %q: %w", path, err)
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return app.Snapshot{}, fmt.Errorf("parse snapshot %q: %w", path, err)
+	}
+	if file.FormatVersion > currentSnapshotFormatVersion {
+		return app.Snapshot{}, fmt.Errorf("snapshot %q uses format version %d; this aim supports up to %d", path, file.FormatVersion, currentSnapshotFormatVersion)
+	}
+
+	snapshot := app.Snapshot{Apps: make([]app.SnapshotApp, 0, len(file.Apps))}
+	for _, record := range file.Apps {
+		snapshot.Apps = append(snapshot.Apps, app.SnapshotApp{
+			ID:           strings.TrimSpace(record.ID),
+			Name:         record.Name,
+			Version:      record.Version,
+			Source:       record.Source.toDomainSource(),
+			UpdateSource: record.UpdateSource.toDomainUpdateSource(),
+		})
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestSnapshotStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "export", "aim.json")
+	want := app.Snapshot{Apps: []app.SnapshotApp{
+		{
+			ID:           "example",
+			Name:         "Example",
+			Version:      "1.2.3",
+			Source:       domain.NewGitHubReleaseSource("owner/repo", "v1.2.3", "Example.AppImage", "https://example.test/Example.AppImage", 123, testSourceTime()),
+			UpdateSource: domain.NewEmbeddedUpdateSource("gh-releases-zsync|owner|repo|latest|Example-*.AppImage.zsync"),
+		},
+		{
+			ID:     "other",
+			Name:   "Other",
+			Source: domain.NewURLSource("https://example.test/Other.AppImage", testSourceTime()),
+		},
+	}}
+
+	store := SnapshotStore{}
+	if err := store.Write(context.Background(), path, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := store.Read(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if len(got.Apps) != len(want.Apps) {
+		t.Fatalf("Apps = %#v, want %#v", got.Apps, want.Apps)
+	}
+	for i := range want.Apps {
+		if got.Apps[i] != want.Apps[i] {
+			t.Fatalf("Apps[%d] = %#v, want %#v", i, got.Apps[i], want.Apps[i])
+		}
+	}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if strings.Contains(string(bytes), "app_image_path") {
+		t.Fatalf("snapshot = %s, want no installation paths", bytes)
+	}
+	if !strings.Contains(string(bytes), `"format_version": 1`) {
+		t.Fatalf("snapshot = %s, want format version", bytes)
+	}
+}
+
+func TestSnapshotStoreRejectsNewerFormat(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "aim.json")
+	if err := os.WriteFile(path, []byte(`{"format_version": 99, "apps": []}`), 0o644); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	if _, err := (SnapshotStore{}).Read(context.Background(), path); err == nil {
+		t.Fatal("Read() error = nil, want format version error")
+	}
+}
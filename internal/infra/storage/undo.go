@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/domain"
+	"github.com/slobbe/appimage-manager/internal/infra/fileutil"
+)
+
+const undoEntryFile = "entry.json"
+
+// UndoArchive keeps one directory per undoable operation in Dir, holding the
+// retained artifacts and an entry.json describing the operation. Artifacts are
+// hard-linked when possible; installers replace files by renaming, so a link
+// keeps the old content without copying it.
+type UndoArchive struct {
+	Dir string
+}
+
+// NewUndoArchive creates an undo archive stored in dir.
+func NewUndoArchive(dir string) UndoArchive {
+	return UndoArchive{Dir: dir}
+}
+
+var _ app.UndoArchive = UndoArchive{}
+
+type undoRecord struct {
+	ID        string                  `json:"id"`
+	Operation string                  `json:"operation"`
+	CreatedAt string                  `json:"created_at"`
+	Previous  *appRecord              `json:"previous,omitempty"`
+	Current   *appRecord              `json:"current,omitempty"`
+	Artifacts []retainedArtifactEntry `json:"artifacts,omitempty"`
+}
+
+type retainedArtifactEntry struct {
+	Path         string `json:"path"`
+	RetainedPath string `json:"retained_path"`
+}
+
+func (a UndoArchive) Retain(ctx context.Context, id string, paths []string) ([]app.RetainedArtifact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dir, err := a.entryDir(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create undo directory %q: %w", dir, err)
+	}
+
+	retained := make([]app.RetainedArtifact, 0, len(paths))
+	for i, path := range paths {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		retainedPath := filepath.Join(dir, strconv.Itoa(i)+"-"+filepath.Base(path))
+		if err := os.Link(path, retainedPath); err != nil {
+			if err := fileutil.CopyFile(ctx, path, retainedPath); err != nil {
+				return nil, fmt.Errorf("retain %q: %w", path, err)
+			}
+		}
+		retained = append(retained, app.RetainedArtifact{Path: path, RetainedPath: retainedPath})
+	}
+	return retained, nil
+}
+
+func (a UndoArchive) Save(ctx context.Context, entry app.UndoEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dir, err := a.entryDir(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	record := undoRecord{
+		ID:        entry.ID,
+		Operation: string(entry.Operation),
+		CreatedAt: entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Previous:  optionalAppRecord(entry.Previous),
+		Current:   optionalAppRecord(entry.Current),
+	}
+	for _, artifact := range entry.Artifacts {
+		record.Artifacts = append(record.Artifacts, retainedArtifactEntry{Path: artifact.Path, RetainedPath: artifact.RetainedPath})
+	}
+
+	bytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode undo entry: %w", err)
+	}
+	bytes = append(bytes, '\n')
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create undo directory %q: %w", dir, err)
+	}
+	path := filepath.Join(dir, undoEntryFile)
+	if err := writeFileDurably(path, bytes); err != nil {
+		return fmt.Errorf("write undo entry %q: %w", path, err)
+	}
+	return nil
+}
+
+func (a UndoArchive) Latest(ctx context.Context) (app.UndoEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return app.UndoEntry{}, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(a.Dir, "*", undoEntryFile))
+	if err != nil {
+		return app.UndoEntry{}, fmt.Errorf("list undo entries in %q: %w", a.Dir, err)
+	}
+	// Entry IDs start with a fixed-width timestamp, so the last one sorted is
+	// the most recent.
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		return app.UndoEntry{}, app.ErrNothingToUndo
+	}
+
+	return readUndoEntry(paths[len(paths)-1])
+}
+
+func (a UndoArchive) Restore(ctx context.Context, artifact app.RetainedArtifact) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(artifact.Path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %q: %w", artifact.Path, err)
+	}
+
+	temporaryPath := artifact.Path + ".undo.tmp"
+	_ = os.Remove(temporaryPath)
+	if err := os.Link(artifact.RetainedPath, temporaryPath); err == nil {
+		if err := os.Rename(temporaryPath, artifact.Path); err != nil {
+			_ = os.Remove(temporaryPath)
+			return fmt.Errorf("restore %q: %w", artifact.Path, err)
+		}
+		return nil
+	}
+	if err := fileutil.CopyFile(ctx, artifact.RetainedPath, artifact.Path); err != nil {
+		return fmt.Errorf("restore %q: %w", artifact.Path, err)
+	}
+	return nil
+}
+
+func (a UndoArchive) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dir, err := a.entryDir(id)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("delete undo entry %q: %w", dir, err)
+	}
+	return nil
+}
+
+func (a UndoArchive) Prune(ctx context.Context, before time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dirEntries, err := os.ReadDir(a.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("list undo entries in %q: %w", a.Dir, err)
+	}
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(a.Dir, dirEntry.Name())
+		createdAt, err := undoEntryTime(dir)
+		if err != nil || !createdAt.Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("delete undo entry %q: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// undoEntryTime returns when the entry in dir was created, falling back to
+// the directory time for artifacts retained by an operation that never saved
+// its entry.
+func undoEntryTime(dir string) (time.Time, error) {
+	entry, err := readUndoEntry(filepath.Join(dir, undoEntryFile))
+	if err == nil {
+		return entry.CreatedAt, nil
+	}
+	info, statErr := os.Stat(dir)
+	if statErr != nil {
+		return time.Time{}, statErr
+	}
+	return info.ModTime(), nil
+}
+
+func readUndoEntry(path string) (app.UndoEntry, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return app.UndoEntry{}, fmt.Errorf("read undo entry %q: %w", path, err)
+	}
+	var record undoRecord
+	if err := json.Unmarshal(bytes, &record); err != nil {
+		return app.UndoEntry{}, fmt.Errorf("parse undo entry %q: %w", path, err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, record.CreatedAt)
+	if err != nil {
+		return app.UndoEntry{}, fmt.Errorf("parse undo entry %q: %w", path, err)
+	}
+
+	entry := app.UndoEntry{
+		ID:        record.ID,
+		Operation: app.HistoryOperation(record.Operation),
+		CreatedAt: createdAt,
+	}
+	if entry.Previous, err = optionalDomainApp(record.Previous); err != nil {
+		return app.UndoEntry{}, fmt.Errorf("parse undo entry %q: %w", path, err)
+	}
+	if entry.Current, err = optionalDomainApp(record.Current); err != nil {
+		return app.UndoEntry{}, fmt.Errorf("parse undo entry %q: %w", path, err)
+	}
+	for _, artifact := range record.Artifacts {
+		entry.Artifacts = append(entry.Artifacts, app.RetainedArtifact{Path: artifact.Path, RetainedPath: artifact.RetainedPath})
+	}
+	return entry, nil
+}
+
+func optionalAppRecord(domainApp domain.App) *appRecord {
+	if domainApp.ID == "" {
+		return nil
+	}
+	record := recordFromDomainApp(domainApp)
+	return &record
+}
+
+func optionalDomainApp(record *appRecord) (domain.App, error) {
+	if record == nil {
+		return domain.App{}, nil
+	}
+	return record.toDomainApp()
+}
+
+func (a UndoArchive) entryDir(id string) (string, error) {
+	if strings.TrimSpace(a.Dir) == "" {
+		return "", errors.New("undo directory is required")
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid undo entry id %q", id)
+	}
+	return filepath.Join(a.Dir, id), nil
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+	"github.com/slobbe/appimage-manager/internal/domain"
+)
+
+func TestUndoArchiveRetainsAndRestoresReplacedArtifacts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	archive := NewUndoArchive(filepath.Join(root, "undo"))
+	appImagePath := filepath.Join(root, "Applications", "example.AppImage")
+	writeTestFile(t, appImagePath, "old appimage")
+
+	artifacts, err := archive.Retain(context.Background(), "20260501T100000.000000000Z-update-example", []string{appImagePath, filepath.Join(root, "missing.desktop")})
+	if err != nil {
+		t.Fatalf("Retain() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Path != appImagePath {
+		t.Fatalf("Retain() = %#v, want only the existing AppImage", artifacts)
+	}
+
+	// Installers replace files by renaming a new file into place.
+	writeTestFile(t, appImagePath+".tmp", "new appimage")
+	if err := os.Rename(appImagePath+".tmp", appImagePath); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	if err := archive.Restore(context.Background(), artifacts[0]); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got, err := os.ReadFile(appImagePath); err != nil || string(got) != "old appimage" {
+		t.Fatalf("restored AppImage = %q, %v; want old appimage", got, err)
+	}
+}
+
+func TestUndoArchiveReturnsLatestSavedEntry(t *testing.T) {
+	t.Parallel()
+
+	archive := NewUndoArchive(filepath.Join(t.TempDir(), "undo"))
+	if _, err := archive.Latest(context.Background()); !errors.Is(err, app.ErrNothingToUndo) {
+		t.Fatalf("Latest() error = %v, want ErrNothingToUndo", err)
+	}
+
+	previous := domain.App{ID: "example", Name: "Example", AppImagePath: "/apps/example.AppImage"}
+	for _, entry := range []app.UndoEntry{
+		{ID: "20260501T100000.000000000Z-remove-example", Operation: app.HistoryOperationRemove, CreatedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), Previous: previous},
+		{ID: "20260502T100000.000000000Z-add-other", Operation: app.HistoryOperationAdd, CreatedAt: time.Date(2026, 5, 2, 10, 0, 0, 0, time.UTC), Current: domain.App{ID: "other"}},
+	} {
+		if _, err := archive.Retain(context.Background(), entry.ID, nil); err != nil {
+			t.Fatalf("Retain() error = %v", err)
+		}
+		if err := archive.Save(context.Background(), entry); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	// Artifacts retained by an operation that never finished are not undoable.
+	if _, err := archive.Retain(context.Background(), "20260503T100000.000000000Z-update-example", nil); err != nil {
+		t.Fatalf("Retain() error = %v", err)
+	}
+
+	latest, err := archive.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Operation != app.HistoryOperationAdd || latest.Current.ID != "other" || latest.Previous.ID != "" {
+		t.Fatalf("Latest() = %#v, want the add of other", latest)
+	}
+
+	if err := archive.Delete(context.Background(), latest.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	latest, err = archive.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.Operation != app.HistoryOperationRemove || !reflect.DeepEqual(latest.Previous, previous) {
+		t.Fatalf("Latest() = %#v, want the remove of example", latest)
+	}
+}
+
+func TestUndoArchivePruneDropsOldEntries(t *testing.T) {
+	t.Parallel()
+
+	archive := NewUndoArchive(filepath.Join(t.TempDir(), "undo"))
+	entry := app.UndoEntry{ID: "20260501T100000.000000000Z-remove-example", Operation: app.HistoryOperationRemove, CreatedAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), Previous: domain.App{ID: "example"}}
+	if err := archive.Save(context.Background(), entry); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := archive.Prune(context.Background(), entry.CreatedAt); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if _, err := archive.Latest(context.Background()); err != nil {
+		t.Fatalf("Latest() error = %v, want entry kept at the cutoff", err)
+	}
+
+	if err := archive.Prune(context.Background(), entry.CreatedAt.Add(time.Second)); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if _, err := archive.Latest(context.Background()); !errors.Is(err, app.ErrNothingToUndo) {
+		t.Fatalf("Latest() error = %v, want ErrNothingToUndo after pruning", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package trash moves files to the freedesktop.org Trash so they can be
+// restored from a file manager.
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slobbe/appimage-manager/internal/app"
+
+	"golang.org/x/sys/unix"
+)
+
+// Trash implements the XDG Trash specification. Files on the filesystem of
+// HomeTrash go there; files on other mounts go to the top directory trash of
+// that mount, $topdir/.Trash/$uid when the administrator provides one and
+// $topdir/.Trash-$uid otherwise.
+type Trash struct {
+	HomeTrash string
+	UID       int
+	// Now returns the deletion date; nil means time.Now.
+	Now func() time.Time
+}
+
+// New creates a Trash for the current user with its home trash at homeTrash.
+func New(homeTrash string) Trash {
+	return Trash{HomeTrash: homeTrash, UID: os.Getuid()}
+}
+
+var _ app.ArtifactRemover = Trash{}.Remove
+
+// Remove moves path to the trash. Missing files are ignored, like
+// fileutil.RemoveArtifact.
+func (t Trash) Remove(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(path) == "" {
+		return errors.New("artifact path is required")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve artifact path %q: %w", path, err)
+	}
+
+	var stat unix.Stat_t
+	if err := unix.Lstat(path, &stat); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("stat artifact %q: %w", path, err)
+	}
+
+	trashDir, infoPath, err := t.trashFor(path, uint64(stat.Dev))
+	if err != nil {
+		return fmt.Errorf("trash artifact %q: %w", path, err)
+	}
+	if err := t.moveToTrash(path, trashDir, infoPath); err != nil {
+		return fmt.Errorf("trash artifact %q: %w", path, err)
+	}
+	return nil
+}
+
+// trashFor returns the trash directory for a file on device dev and the path
+// to record in its .trashinfo: absolute for the home trash and relative to the
+// mount's top directory otherwise.
+func (t Trash) trashFor(path string, dev uint64) (string, string, error) {
+	if err := os.MkdirAll(t.HomeTrash, 0o700); err != nil {
+		return "", "", err
+	}
+	var homeStat unix.Stat_t
+	if err := unix.Stat(t.HomeTrash, &homeStat); err != nil {
+		return "", "", err
+	}
+	if uint64(homeStat.Dev) == dev {
+		return t.HomeTrash, path, nil
+	}
+
+	topDir, err := mountTopDir(path, dev)
+	if err != nil {
+		return "", "", err
+	}
+	relative, err := filepath.Rel(topDir, path)
+	if err != nil {
+		return "", "", err
+	}
+	uid := strconv.Itoa(t.UID)
+
+	shared := filepath.Join(topDir, ".Trash")
+	var sharedStat unix.Stat_t
+	if err := unix.Lstat(shared, &sharedStat); err == nil &&
+		sharedStat.Mode&unix.S_IFMT == unix.S_IFDIR && sharedStat.Mode&unix.S_ISVTX != 0 {
+		dir := filepath.Join(shared, uid)
+		if err := os.MkdirAll(dir, 0o700); err == nil {
+			return dir, relative, nil
+		}
+	}
+
+	dir := filepath.Join(topDir, ".Trash-"+uid)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", err
+	}
+	return dir, relative, nil
+}
+
+// mountTopDir walks up from path while the parent is still on device dev.
+func mountTopDir(path string, dev uint64) (string, error) {
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+		var stat unix.Stat_t
+		if err := unix.Stat(parent, &stat); err != nil {
+			return "", err
+		}
+		if uint64(stat.Dev) != dev {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
+
+// moveToTrash reserves a unique name by creating its .trashinfo exclusively,
+// as the specification requires, and then renames the file into place.
+func (t Trash) moveToTrash(path string, trashDir string, infoPath string) error {
+	filesDir := filepath.Join(trashDir, "files")
+	infoDir := filepath.Join(trashDir, "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+	info := "[Trash Info]\nPath=" + (&url.URL{Path: infoPath}).EscapedPath() + "\nDeletionDate=" + now().Format("2006-01-02T15:04:05") + "\n"
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for attempt := 1; ; attempt++ {
+		name := base
+		if attempt > 1 {
+			name = stem + "." + strconv.Itoa(attempt) + ext
+		}
+		trashInfoPath := filepath.Join(infoDir, name+".trashinfo")
+		file, err := os.OpenFile(trashInfoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := file.WriteString(info); err != nil {
+			_ = file.Close()
+			_ = os.Remove(trashInfoPath)
+			return err
+		}
+		if err := file.Close(); err != nil {
+			_ = os.Remove(trashInfoPath)
+			return err
+		}
+
+		trashedPath := filepath.Join(filesDir, name)
+		if _, err := os.Lstat(trashedPath); err == nil {
+			// A leftover file without .trashinfo still owns the name.
+			_ = os.Remove(trashInfoPath)
+			continue
+		}
+		if err := os.Rename(path, trashedPath); err != nil {
+			_ = os.Remove(trashInfoPath)
+			return err
+		}
+		return nil
+	}
+}
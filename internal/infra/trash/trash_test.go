@@ -0,0 +1,106 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTrashMovesFileToHomeTrashWithTrashInfo(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	trash := Trash{HomeTrash: filepath.Join(root, "Trash"), UID: 1000, Now: func() time.Time {
+		return time.Date(2026, 5, 1, 10, 30, 0, 0, time.Local)
+	}}
+	path := filepath.Join(root, "My Apps", "Example.AppImage")
+	writeFile(t, path, "appimage")
+
+	if err := trash.Remove(context.Background(), path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("stat original error = %v, want not exist", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(root, "Trash", "files", "Example.AppImage")); err != nil || string(got) != "appimage" {
+		t.Fatalf("trashed file = %q, %v; want appimage", got, err)
+	}
+	info, err := os.ReadFile(filepath.Join(root, "Trash", "info", "Example.AppImage.trashinfo"))
+	if err != nil {
+		t.Fatalf("ReadFile(trashinfo) error = %v", err)
+	}
+	want := "[Trash Info]\nPath=" + filepath.Join(root, "My%20Apps", "Example.AppImage") + "\nDeletionDate=2026-05-01T10:30:00\n"
+	if string(info) != want {
+		t.Fatalf("trashinfo = %q, want %q", info, want)
+	}
+}
+
+func TestTrashKeepsEarlierFilesWithTheSameName(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	trash := Trash{HomeTrash: filepath.Join(root, "Trash"), UID: 1000}
+	path := filepath.Join(root, "Example.AppImage")
+	for _, content := range []string{"first", "second"} {
+		writeFile(t, path, content)
+		if err := trash.Remove(context.Background(), path); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+	}
+
+	for name, want := range map[string]string{"Example.AppImage": "first", "Example.2.AppImage": "second"} {
+		if got, err := os.ReadFile(filepath.Join(root, "Trash", "files", name)); err != nil || string(got) != want {
+			t.Fatalf("trashed %s = %q, %v; want %q", name, got, err, want)
+		}
+		if _, err := os.Stat(filepath.Join(root, "Trash", "info", name+".trashinfo")); err != nil {
+			t.Fatalf("stat %s.trashinfo error = %v", name, err)
+		}
+	}
+}
+
+func TestTrashUsesTopDirectoryTrashOnOtherFilesystems(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	trash := Trash{HomeTrash: filepath.Join(root, "home", "Trash"), UID: 1000}
+	path := filepath.Join(root, "mnt", "Example.AppImage")
+	writeFile(t, path, "appimage")
+
+	// A device no directory is on stands in for another mount, whose top
+	// directory is then the file's own directory.
+	trashDir, infoPath, err := trash.trashFor(path, ^uint64(0))
+	if err != nil {
+		t.Fatalf("trashFor() error = %v", err)
+	}
+	if want := filepath.Join(root, "mnt", ".Trash-"+strconv.Itoa(1000)); trashDir != want {
+		t.Fatalf("trash dir = %q, want %q", trashDir, want)
+	}
+	if infoPath != "Example.AppImage" {
+		t.Fatalf("info path = %q, want path relative to the top directory", infoPath)
+	}
+}
+
+func TestTrashIgnoresMissingFile(t *testing.T) {
+	t.Parallel()
+
+	trash := Trash{HomeTrash: filepath.Join(t.TempDir(), "Trash"), UID: 1000}
+	if err := trash.Remove(context.Background(), filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
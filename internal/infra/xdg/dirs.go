@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const AppName = "aim"
@@ -42,6 +43,10 @@ func ConfigFile(dirs Dirs) string {
 	return filepath.Join(ConfigDir(dirs), "config.toml")
 }
 
+func ManifestFile(dirs Dirs) string {
+	return filepath.Join(ConfigDir(dirs), "manifest.toml")
+}
+
 func DataDir(dirs Dirs) string {
 	return filepath.Join(dirs.DataHome, AppName)
 }
@@ -57,3 +62,65 @@ func DesktopDir(dirs Dirs) string {
 func IconDir(dirs Dirs) string {
 	return filepath.Join(dirs.DataHome, "icons")
 }
+
+// MimeDir returns the user's shared MIME database.
+func MimeDir(dirs Dirs) string {
+	return filepath.Join(dirs.DataHome, "mime")
+}
+
+// MimeAppsFile returns the user's mimeapps.list, which holds their default
+// applications.
+func MimeAppsFile(dirs Dirs) string {
+	return filepath.Join(dirs.ConfigHome, "mimeapps.list")
+}
+
+func SandboxDir(dirs Dirs) string {
+	return filepath.Join(DataDir(dirs), "sandbox")
+}
+
+func TrashDir(dirs Dirs) string {
+	return filepath.Join(dirs.DataHome, "Trash")
+}
+
+func SystemdUserDir(dirs Dirs) string {
+	return filepath.Join(dirs.ConfigHome, "systemd", "user")
+}
+
+// BinDir returns ~/.local/bin, where the XDG base directory specification
+// puts user executables. It returns "" when the home directory is unknown.
+func BinDir(dirs Dirs) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "bin")
+}
+
+// DownloadDir returns XDG_DOWNLOAD_DIR from user-dirs.dirs, falling back to
+// ~/Downloads. It returns "" when neither can be resolved.
+func DownloadDir(dirs Dirs) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(dirs.ConfigHome, "user-dirs.dirs"))
+	if err == nil {
+		for _, line := range strings.Split(string(bytes), "\n") {
+			value, ok := strings.CutPrefix(strings.TrimSpace(line), "XDG_DOWNLOAD_DIR=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"`)
+			if rest, ok := strings.CutPrefix(value, "$HOME"); ok {
+				value = home + rest
+			}
+			// A value of $HOME alone disables the directory.
+			if filepath.IsAbs(value) && filepath.Clean(value) != filepath.Clean(home) {
+				return filepath.Clean(value)
+			}
+		}
+	}
+
+	return filepath.Join(home, "Downloads")
+}
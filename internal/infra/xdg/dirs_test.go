@@ -1,6 +1,7 @@
 package xdg
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -66,6 +67,10 @@ func TestDerivedPaths(t *testing.T) {
 		"DefaultAppImageDir": {got: DefaultAppImageDir(dirs), want: filepath.Join(dirs.DataHome, AppName, "appimages")},
 		"DesktopDir":         {got: DesktopDir(dirs), want: filepath.Join(dirs.DataHome, "applications")},
 		"IconDir":            {got: IconDir(dirs), want: filepath.Join(dirs.DataHome, "icons")},
+		"MimeDir":            {got: MimeDir(dirs), want: filepath.Join(dirs.DataHome, "mime")},
+		"MimeAppsFile":       {got: MimeAppsFile(dirs), want: filepath.Join(dirs.ConfigHome, "mimeapps.list")},
+		"SandboxDir":         {got: SandboxDir(dirs), want: filepath.Join(dirs.DataHome, AppName, "sandbox")},
+		"SystemdUserDir":     {got: SystemdUserDir(dirs), want: filepath.Join(dirs.ConfigHome, "systemd", "user")},
 	}
 
 	for name, tt := range tests {
@@ -76,3 +81,34 @@ func TestDerivedPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestBinDirIsInHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got, want := BinDir(Dirs{}), filepath.Join(home, ".local", "bin"); got != want {
+		t.Fatalf("BinDir() = %q, want %q", got, want)
+	}
+}
+
+func TestDownloadDirReadsUserDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dirs := Dirs{ConfigHome: filepath.Join(home, ".config")}
+
+	if got, want := DownloadDir(dirs), filepath.Join(home, "Downloads"); got != want {
+		t.Fatalf("DownloadDir() without user-dirs.dirs = %q, want %q", got, want)
+	}
+
+	if err := os.MkdirAll(dirs.ConfigHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	userDirs := "# written by xdg-user-dirs-update\nXDG_DESKTOP_DIR=\"$HOME/Desktop\"\nXDG_DOWNLOAD_DIR=\"$HOME/Herunterladen\"\n"
+	if err := os.WriteFile(filepath.Join(dirs.ConfigHome, "user-dirs.dirs"), []byte(userDirs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := DownloadDir(dirs), filepath.Join(home, "Herunterladen"); got != want {
+		t.Fatalf("DownloadDir() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,15 @@
+package xdg
+
+import "os"
+
+// Locale returns the locale messages are shown in: the first of LC_ALL,
+// LC_MESSAGES and LANG that is set, as POSIX locale resolution orders them.
+// It returns "" when none is set.
+func Locale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
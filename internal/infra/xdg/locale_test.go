@@ -0,0 +1,29 @@
+package xdg
+
+import "testing"
+
+func TestLocalePrefersLCAllThenLCMessagesThenLang(t *testing.T) {
+	tests := []struct {
+		name       string
+		lcAll      string
+		lcMessages string
+		lang       string
+		want       string
+	}{
+		{name: "lc_all", lcAll: "fr_FR.UTF-8", lcMessages: "de_DE.UTF-8", lang: "en_US.UTF-8", want: "fr_FR.UTF-8"},
+		{name: "lc_messages", lcMessages: "de_DE.UTF-8", lang: "en_US.UTF-8", want: "de_DE.UTF-8"},
+		{name: "lang", lang: "en_US.UTF-8", want: "en_US.UTF-8"},
+		{name: "unset", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+
+			if got := Locale(); got != tt.want {
+				t.Fatalf("Locale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}